@@ -0,0 +1,292 @@
+package add
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+)
+
+var (
+	addCategory     string
+	addTag          string
+	addRationale    string
+	addExpires      string
+	addTTLDays      int
+	addScope        string
+	addSupersedes   string
+	addContext      string
+	addOptions      []string
+	addConsequences string
+	addForce        bool
+	addStdin        bool
+	addInteractive  bool
+)
+
+func init() {
+	addCmd := &cobra.Command{
+		Use:   "add [content]",
+		Short: "Add an entry to the project memory",
+		Long: `Add an entry to .ohmymem/memory.md without going through an agent.
+When content is omitted, or with -i, an interactive wizard asks for the section, tag
+(suggesting existing tags), content and rationale, prefilled with the flags given.
+
+With --supersedes, the new entry replaces an active one, which is kept in the file,
+marked superseded and collapsed in reads. A decision that contradicts an active
+constraint is only added when it supersedes that constraint.
+
+Decisions can also record why they were made, like a lightweight ADR: --context for
+the forces at play, --option for each alternative considered and --consequences for
+what the decision makes easier or harder.
+
+With --stdin, the content is read from standard input; line breaks are joined with
+spaces. The input may start with a header giving fields not set by flags:
+
+  ---
+  category: decisions
+  tag: Storage
+  rationale: ACID compliance
+  expires: 2026-12-31
+  scope: internal/storage/**
+  supersedes: 0192f3a1
+  context: Need JSONB and transactions
+  options: [PostgreSQL, MongoDB]
+  consequences: Operations must run PostgreSQL
+  ---
+  Use Postgres`,
+		Example: `  ohmymem add --category decisions --tag Storage "Use Postgres" --rationale "ACID compliance"
+  git log -1 --pretty=%s | ohmymem add --category decisions --tag Release --stdin
+  ohmymem add -i --category patterns
+  ohmymem add --category constraints --tag API --scope "internal/api/**" "Handlers return problem+json errors"
+  ohmymem add --category decisions --tag Storage --supersedes 0192f3a1 "Use SQLite for local development"
+  ohmymem add --category decisions --tag Storage "Use PostgreSQL" --context "Need JSONB and transactions" \
+    --option PostgreSQL --option MongoDB --consequences "Operations must run PostgreSQL"`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runAdd,
+	}
+
+	addCmd.Flags().StringVarP(&addCategory, "category", "c", "", "Category: constraints, decisions, patterns, anti-patterns or note (default note)")
+	addCmd.Flags().StringVarP(&addTag, "tag", "t", "", "Tag without brackets; omit or pass 'auto' to have one suggested")
+	addCmd.Flags().StringVarP(&addRationale, "rationale", "r", "", "Optional reason/justification")
+	addCmd.Flags().StringVar(&addExpires, "expires", "", "Optional expiry (RFC3339 or YYYY-MM-DD)")
+	addCmd.Flags().IntVar(&addTTLDays, "ttl", 0, "Optional time-to-live in days, alternative to --expires")
+	addCmd.Flags().StringVar(&addScope, "scope", "", "Optional comma-separated path patterns the entry applies to (e.g. internal/api/**)")
+	addCmd.Flags().StringVar(&addSupersedes, "supersedes", "", "ID or unique ID prefix of an active entry the new one replaces")
+	addCmd.Flags().StringVar(&addContext, "context", "", "Decisions only: the context that led to the decision")
+	addCmd.Flags().StringArrayVar(&addOptions, "option", nil, "Decisions only: an option considered (repeatable, or semicolon-separated)")
+	addCmd.Flags().StringVar(&addConsequences, "consequences", "", "Decisions only: the consequences of the decision")
+	addCmd.Flags().BoolVarP(&addForce, "force", "f", false, "Add even if a similar entry already exists")
+	addCmd.Flags().BoolVarP(&addInteractive, "interactive", "i", false, "Fill in or review the entry in the interactive wizard")
+	addCmd.Flags().BoolVar(&addStdin, "stdin", false, "Read the content, and an optional header, from standard input")
+
+	cmd.RegisterTagCompletion(addCmd)
+
+	cmd.RootCmd.AddCommand(addCmd)
+}
+
+func runAdd(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	input := domain.AppendInput{
+		Category:   addCategory,
+		Tag:        addTag,
+		Rationale:  addRationale,
+		Supersedes: addSupersedes,
+		Author:     uc.UserAuthor(c.Context()),
+		ADR: domain.DecisionRecord{
+			Context:      strings.TrimSpace(addContext),
+			Options:      domain.ParseOptions(strings.Join(addOptions, ";")),
+			Consequences: strings.TrimSpace(addConsequences),
+		},
+	}
+	switch {
+	case addStdin:
+		if len(args) == 1 || addInteractive {
+			return fmt.Errorf("--stdin cannot be combined with a content argument or --interactive")
+		}
+		if err := readStdin(c, &input); err != nil {
+			return err
+		}
+	case len(args) == 1 && !addInteractive:
+		input.Content = args[0]
+	default:
+		if len(args) == 1 {
+			input.Content = args[0]
+		}
+		if err := runWizard(c, uc, &input); err != nil {
+			if errors.Is(err, huh.ErrCancelled) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return err
+		}
+	}
+	input.Content = strings.TrimSpace(input.Content)
+	input.Rationale = strings.TrimSpace(input.Rationale)
+
+	expiresAt, err := domain.ParseExpiry(addExpires, addTTLDays, uc.Now())
+	if err != nil {
+		return err
+	}
+	input.ExpiresAt = expiresAt
+	if input.Scope, err = domain.ParseScope(addScope); err != nil {
+		return err
+	}
+
+	result, err := uc.Add(c.Context(), input, usecase.AddOptions{Force: addForce})
+	if err != nil {
+		if errors.Is(err, domain.ErrDuplicateEntry) {
+			return fmt.Errorf("%w\nNothing was added. Use --force to add anyway", err)
+		}
+		if errors.Is(err, domain.ErrContradiction) {
+			return fmt.Errorf("%w\nNothing was added. Use --supersedes with the constraint ID to replace it", err)
+		}
+		return err
+	}
+
+	fmt.Printf("✅ Added %s to %s\n", result.Entry.ID, result.Section.Title())
+	fmt.Printf("   * **%s** %s\n", result.Entry.Tag, result.Entry.Content)
+	if record := domain.RenderDecisionRecord(result.Entry.ADR); record != "" {
+		fmt.Print(indent(record, "   "))
+	}
+	if result.AutoTagged {
+		fmt.Printf("   Tag %s was chosen automatically.\n", result.Entry.Tag)
+	}
+	if result.TagAlias != "" {
+		fmt.Printf("   Tag %s was normalized to %s.\n", result.TagAlias, result.Entry.Tag)
+	}
+	if result.Superseded != nil {
+		fmt.Printf("   Supersedes %s %s %s\n", result.Superseded.ID, result.Superseded.Tag, cmd.Truncate(result.Superseded.Content, 60))
+	}
+	return nil
+}
+
+// runWizard asks for the entry in a multi-step form prefilled with the flags and
+// argument given, suggesting existing tags and validating fields as they are typed
+func runWizard(c *cobra.Command, uc *usecase.MemoryUseCase, input *domain.AppendInput) error {
+	sections := make([]string, 0, len(domain.ValidSections()))
+	for _, section := range domain.ValidSections() {
+		sections = append(sections, string(section))
+	}
+	tags, err := uc.Service().ListTags(c.Context())
+	if err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+
+	capture := huh.CaptureInput{
+		Section:   input.Category,
+		Tag:       input.Tag,
+		Content:   input.Content,
+		Rationale: input.Rationale,
+	}
+	if capture.Section == "" {
+		capture.Section = string(domain.SectionNote)
+	}
+	if capture.Tag == "" {
+		capture.Tag = domain.AutoTag
+	}
+
+	err = huh.CaptureWizard(&capture, huh.CaptureOptions{
+		Sections: sections,
+		Tags:     tagNames,
+		ValidateTag: func(tag string) error {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || strings.EqualFold(tag, domain.AutoTag) {
+				return nil
+			}
+			return uc.Service().Rules().ValidateTag(strings.Trim(tag, "[]"))
+		},
+		ValidateContent: func(content string) error {
+			return uc.Service().Rules().ValidateEntryContent(joinLines(content))
+		},
+		ValidateRationale: func(rationale string) error {
+			return uc.Service().Rules().ValidateRationale(strings.TrimSpace(rationale))
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	input.Category = capture.Section
+	input.Tag = capture.Tag
+	input.Content = joinLines(capture.Content)
+	input.Rationale = capture.Rationale
+	return nil
+}
+
+// indent prefixes every line of text with prefix
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(strings.TrimSuffix(text, "\n"), "\n", "\n"+prefix) + "\n"
+}
+
+// joinLines joins the lines of multi-line text with single spaces
+func joinLines(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// readStdin fills the content, and fields not given as flags, from standard input
+func readStdin(c *cobra.Command, input *domain.AppendInput) error {
+	data, err := io.ReadAll(c.InOrStdin())
+	if err != nil {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+
+	fields, body, err := usecase.ParseStdin(string(data))
+	if err != nil {
+		return err
+	}
+	if !c.Flags().Changed("category") && fields.Category != "" {
+		input.Category = fields.Category
+	}
+	if !c.Flags().Changed("tag") && fields.Tag != "" {
+		input.Tag = fields.Tag
+	}
+	if !c.Flags().Changed("rationale") && fields.Rationale != "" {
+		input.Rationale = fields.Rationale
+	}
+	if !c.Flags().Changed("expires") && !c.Flags().Changed("ttl") && fields.Expires != "" {
+		addExpires = fields.Expires
+	}
+	if !c.Flags().Changed("scope") && fields.Scope != "" {
+		addScope = fields.Scope
+	}
+	if !c.Flags().Changed("supersedes") && fields.Supersedes != "" {
+		input.Supersedes = fields.Supersedes
+	}
+	if !c.Flags().Changed("context") && fields.Context != "" {
+		input.ADR.Context = strings.TrimSpace(fields.Context)
+	}
+	if !c.Flags().Changed("option") && len(fields.Options) > 0 {
+		input.ADR.Options = domain.ParseOptions(strings.Join(fields.Options, ";"))
+	}
+	if !c.Flags().Changed("consequences") && fields.Consequences != "" {
+		input.ADR.Consequences = strings.TrimSpace(fields.Consequences)
+	}
+
+	input.Content = joinLines(body)
+	if input.Content == "" {
+		return fmt.Errorf("no content on stdin")
+	}
+	return nil
+}
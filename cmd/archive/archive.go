@@ -0,0 +1,218 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+var (
+	archiveOlderThan  string
+	archiveExpired    bool
+	archiveDeprecated bool
+	archiveSection    string
+	archiveTag        string
+	archiveDryRun     bool
+	archiveYes        bool
+	archiveJSON       bool
+)
+
+// archivedJSON is the JSON view of an archived entry
+type archivedJSON struct {
+	usecase.EntryJSON
+	Archive string `json:"archive"`
+}
+
+func init() {
+	archiveCmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Move old, expired or deprecated entries to dated archive files",
+		Long: `Move entries out of .ohmymem/memory.md into .ohmymem/archive/YYYY-MM-DD.md, keeping
+them out of agent context while preserving them. An entry is archived when it matches
+any of --older-than, --expired and --deprecated, within the --section and --tag scope.
+
+Archived entries can be listed with 'ohmymem archive list' and moved back with
+'ohmymem archive restore <id>'.`,
+		Example: `  ohmymem archive --older-than 180d --section note
+  ohmymem archive --deprecated --yes
+  ohmymem archive restore 01a1`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runArchive,
+	}
+
+	archiveCmd.Flags().StringVar(&archiveOlderThan, "older-than", "", "Entries created before a period ago (180d, 4w) or a date (YYYY-MM-DD)")
+	archiveCmd.Flags().BoolVar(&archiveExpired, "expired", false, "Entries whose expiry has passed")
+	archiveCmd.Flags().BoolVar(&archiveDeprecated, "deprecated", false, "Entries marked deprecated or superseded")
+	archiveCmd.Flags().StringVarP(&archiveSection, "section", "s", "", "Only these sections (comma-separated)")
+	archiveCmd.Flags().StringVarP(&archiveTag, "tag", "t", "", "Only entries with this tag")
+	archiveCmd.Flags().BoolVar(&archiveDryRun, "dry-run", false, "List the entries that would be archived without moving them")
+	archiveCmd.Flags().BoolVarP(&archiveYes, "yes", "y", false, "Skip confirmation")
+
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List archived entries",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+	listCmd.Flags().BoolVar(&archiveJSON, "json", false, "Print archived entries as JSON")
+
+	restoreCmd := &cobra.Command{
+		Use:          "restore <id>...",
+		Short:        "Move archived entries back into the memory (a unique ID prefix is enough)",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runRestore,
+	}
+
+	archiveCmd.AddCommand(listCmd, restoreCmd)
+	cmd.RegisterTagCompletion(archiveCmd)
+
+	cmd.RootCmd.AddCommand(archiveCmd)
+}
+
+func runArchive(c *cobra.Command, args []string) error {
+	uc, err := openMemory()
+	if err != nil {
+		return err
+	}
+	svc := uc.Service()
+	ctx := c.Context()
+	now := uc.Now()
+
+	sections, err := domain.ParseSections(archiveSection)
+	if err != nil {
+		return err
+	}
+	olderThan, err := domain.ParseSince(archiveOlderThan, now)
+	if err != nil {
+		return err
+	}
+	criteria := domain.PruneCriteria{
+		OlderThan:  olderThan,
+		Expired:    archiveExpired,
+		Deprecated: archiveDeprecated,
+		Sections:   sections,
+		Tag:        archiveTag,
+		Now:        now,
+	}
+	if criteria.IsEmpty() {
+		return fmt.Errorf("specify at least one of --older-than, --expired or --deprecated")
+	}
+
+	selected, err := svc.SelectPrunable(ctx, criteria)
+	if err != nil {
+		return fmt.Errorf("select entries: %w", err)
+	}
+	if len(selected) == 0 {
+		fmt.Println("No entries to archive.")
+		return nil
+	}
+
+	if err := cmd.PrintEntryTable(os.Stdout, selected); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	archiveName := uc.ArchiveName()
+	archivePath := filepath.Join(persistence.DirName, persistence.ArchiveDirName, archiveName+".md")
+	if archiveDryRun {
+		fmt.Printf("Dry run: %d entries would be archived to %s.\n", len(selected), archivePath)
+		return nil
+	}
+
+	if !archiveYes {
+		confirmed, err := huh.Confirm(fmt.Sprintf("Archive %d entries?", len(selected)), false)
+		if err != nil {
+			if errors.Is(err, huh.ErrCancelled) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	archived, err := svc.ArchiveEntries(ctx, selected, archiveName)
+	if err != nil {
+		return fmt.Errorf("archive entries: %w", err)
+	}
+	fmt.Printf("✅ Archived %d entries to %s\n", len(archived), archivePath)
+	return nil
+}
+
+func runList(c *cobra.Command, args []string) error {
+	uc, err := openMemory()
+	if err != nil {
+		return err
+	}
+
+	archived, err := uc.ListArchived(c.Context())
+	if err != nil {
+		return fmt.Errorf("list archive: %w", err)
+	}
+
+	if archiveJSON {
+		views := make([]archivedJSON, 0, len(archived))
+		for _, entry := range archived {
+			views = append(views, archivedJSON{EntryJSON: usecase.ToEntryJSON(entry.Entry), Archive: entry.Archive})
+		}
+		return cmd.PrintJSON(views)
+	}
+
+	if len(archived) == 0 {
+		fmt.Println("No archived entries.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ARCHIVE\tID\tSECTION\tTAG\tCONTENT")
+	for _, entry := range archived {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", entry.Archive, entry.ID, entry.Section, entry.Tag, cmd.Truncate(entry.Content, 60))
+	}
+	return tw.Flush()
+}
+
+func runRestore(c *cobra.Command, args []string) error {
+	uc, err := openMemory()
+	if err != nil {
+		return err
+	}
+
+	restored, err := uc.Restore(c.Context(), args)
+	if err != nil {
+		return err
+	}
+	for _, entry := range restored {
+		fmt.Printf("✅ Restored %s to %s (from %s)\n", entry.ID, entry.Section.Title(), entry.Archive)
+	}
+	return nil
+}
+
+// openMemory opens the memory of the project in the working directory
+func openMemory() (*usecase.MemoryUseCase, error) {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return nil, err
+	}
+	return uc, nil
+}
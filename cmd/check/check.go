@@ -0,0 +1,73 @@
+package check
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+var (
+	checkSkipAgents bool
+	checkJSON       bool
+)
+
+func init() {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Fail when the memory is not fit to merge (for CI)",
+		Long: `Run the checks a CI pipeline should gate on and exit nonzero when any fails:
+
+  - .ohmymem/memory.md is invalid (see 'ohmymem validate')
+  - memory.md still has legacy entries to convert with 'ohmymem migrate'
+  - memory.md or AGENTS.md contain merge conflict markers
+  - the ohmymem block of AGENTS.md is missing or its boot protocol is outdated`,
+		Example:      "  ohmymem check\n  ohmymem check --skip-agents --json",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runCheck,
+	}
+
+	checkCmd.Flags().BoolVar(&checkSkipAgents, "skip-agents", false, "Do not check the AGENTS.md block")
+	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "Print the report as JSON")
+
+	cmd.RootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	report, err := uc.Check(c.Context(), usecase.CheckOptions{SkipAgents: checkSkipAgents})
+	if err != nil {
+		return err
+	}
+
+	if checkJSON {
+		if err := cmd.PrintJSON(report); err != nil {
+			return err
+		}
+	} else {
+		for _, problem := range report.Problems {
+			fmt.Println("❌ " + problem)
+		}
+	}
+
+	if !report.Passed() {
+		return fmt.Errorf("check failed: %d problems", len(report.Problems))
+	}
+	if !checkJSON {
+		fmt.Println("✅ All checks passed")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+// CompleteTags completes tag flags and arguments with the tags in use in the project
+// of the working directory, most used first, then the other canonical tags. A prefix
+// matching an alias completes to its canonical tag.
+func CompleteTags(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	svc := usecase.NewMemoryUseCase(rootPath).Service()
+
+	var names []string
+	if tags, err := svc.ListTags(c.Context()); err == nil {
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+	}
+
+	prefix := strings.ToLower(strings.TrimLeft(toComplete, "["))
+	seen := make(map[string]bool)
+	var completions []string
+	add := func(name string) {
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			completions = append(completions, name)
+		}
+	}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			add(name)
+		}
+	}
+	for _, definition := range svc.Tags().Taxonomy() {
+		matches := strings.HasPrefix(strings.ToLower(definition.Name), prefix)
+		for _, alias := range definition.Aliases {
+			matches = matches || prefix != "" && strings.HasPrefix(strings.ToLower(alias), prefix)
+		}
+		if matches {
+			add(definition.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// RegisterTagCompletion completes the --tag flag of c with CompleteTags
+func RegisterTagCompletion(c *cobra.Command) {
+	cobra.CheckErr(c.RegisterFlagCompletionFunc("tag", CompleteTags))
+}
@@ -0,0 +1,182 @@
+package configcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+)
+
+var (
+	configGlobal  bool
+	configProject bool
+)
+
+func init() {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get, set and list configuration values",
+		Long: `Manage configuration without editing YAML by hand. Values are read from the global
+file (~/.ohmymem/config.yaml), then the project file (.ohmymem/config.yaml), then
+environment variables; later sources win.`,
+	}
+
+	getCmd := &cobra.Command{
+		Use:          "get <key>",
+		Short:        "Print the value of a key",
+		Long:         "Print the effective value of a key, or its value in one file with --global or --project.",
+		Example:      "  ohmymem config get init.yes",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runGet,
+	}
+
+	setCmd := &cobra.Command{
+		Use:          "set <key> <value>",
+		Short:        "Set a key in the global or project config file",
+		Long:         "Write a key to the global config file, or to the project config file with --project.",
+		Example:      "  ohmymem config set init.yes true\n  ohmymem config set mcp.auth_token s3cret --project",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE:         runSet,
+	}
+
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List configuration values",
+		Long:         "List every key with its effective value and source, or only the keys set in one file with --global or --project. Tokens are masked.",
+		Example:      "  ohmymem config list\n  ohmymem config list --project",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+
+	for _, c := range []*cobra.Command{getCmd, setCmd, listCmd} {
+		c.Flags().BoolVar(&configGlobal, "global", false, "Use the global config file (~/.ohmymem/config.yaml)")
+		c.Flags().BoolVar(&configProject, "project", false, "Use the project config file (.ohmymem/config.yaml)")
+		c.MarkFlagsMutuallyExclusive("global", "project")
+		configCmd.AddCommand(c)
+	}
+
+	cmd.RootCmd.AddCommand(configCmd)
+}
+
+func runGet(c *cobra.Command, args []string) error {
+	key := args[0]
+	if path := scopePath(); path != "" {
+		values, err := config.ReadValues(path)
+		if err != nil {
+			return err
+		}
+		value, ok := values[key]
+		if !ok {
+			return fmt.Errorf("%s is not set in %s", key, path)
+		}
+		fmt.Println(value)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	value, err := cfg.Get(key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runSet(c *cobra.Command, args []string) error {
+	path := scopePath()
+	if path == "" {
+		path = config.GetConfigPath()
+	}
+
+	if err := config.SetValue(path, args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Set %s in %s\n", args[0], path)
+	return nil
+}
+
+func runList(c *cobra.Command, args []string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if path := scopePath(); path != "" {
+		values, err := config.ReadValues(path)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			fmt.Printf("No values set in %s\n", path)
+			return nil
+		}
+		fmt.Fprintln(tw, "KEY\tVALUE")
+		for _, key := range config.Keys() {
+			if value, ok := values[key]; ok {
+				fmt.Fprintf(tw, "%s\t%s\n", key, mask(key, value))
+			}
+		}
+		return tw.Flush()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	globalValues, err := config.ReadValues(config.GetConfigPath())
+	if err != nil {
+		return err
+	}
+	projectValues, err := config.ReadValues(config.ProjectConfigPath("."))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, key := range config.Keys() {
+		value, err := cfg.Get(key)
+		if err != nil {
+			return err
+		}
+		source := "default"
+		if _, ok := globalValues[key]; ok {
+			source = "global"
+		}
+		if _, ok := projectValues[key]; ok {
+			source = "project"
+		}
+		if env, ok := config.EnvKeys[key]; ok && os.Getenv(env) != "" {
+			source = "env " + env
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, mask(key, value), source)
+	}
+	return tw.Flush()
+}
+
+// scopePath returns the file selected by --global or --project, or "" for neither
+func scopePath() string {
+	switch {
+	case configGlobal:
+		return config.GetConfigPath()
+	case configProject:
+		return config.ProjectConfigPath(".")
+	default:
+		return ""
+	}
+}
+
+// mask hides the value of secret keys
+func mask(key, value string) string {
+	if value != "" && (strings.HasSuffix(key, "token") || strings.HasSuffix(key, "api_key")) {
+		return "****"
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package dedupe
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+)
+
+// Choices offered for each duplicate group
+const (
+	choiceMerge  = "Merge into the first entry (combine rationales)"
+	choiceDelete = "Keep the first entry, delete the others"
+	choiceSkip   = "Skip"
+	choiceQuit   = "Quit"
+)
+
+var (
+	dedupeThreshold float64
+	dedupeMethod    string
+	dedupeAuto      bool
+	dedupeDryRun    bool
+)
+
+func init() {
+	dedupeCmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Find and resolve near-duplicate entries",
+		Long: `Find near-duplicate entries across all sections and show each group side by side.
+For every group, choose to merge the duplicates into the first entry, delete them, or
+skip. The first entry is the one in the most important section, with a rationale, oldest.
+
+Entries are compared by the overlap of their normalized words. With --method minhash,
+the overlap is estimated from compact signatures and only entries whose signatures
+partly agree are compared, which is much faster on large memories.
+
+With --auto, every group is merged without prompting.`,
+		Example: `  ohmymem dedupe
+  ohmymem dedupe --threshold 0.6 --dry-run
+  ohmymem dedupe --method minhash
+  ohmymem dedupe --auto`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runDedupe,
+	}
+
+	dedupeCmd.Flags().Float64Var(&dedupeThreshold, "threshold", domain.DuplicateThreshold, "Similarity (0-1) at which entries count as duplicates")
+	dedupeCmd.Flags().StringVar(&dedupeMethod, "method", string(domain.DuplicateOverlap), "How entries are compared: overlap or minhash")
+	cobra.CheckErr(dedupeCmd.RegisterFlagCompletionFunc("method", cobra.FixedCompletions(
+		[]string{string(domain.DuplicateOverlap), string(domain.DuplicateMinHash)}, cobra.ShellCompDirectiveNoFileComp)))
+	dedupeCmd.Flags().BoolVar(&dedupeAuto, "auto", false, "Merge every group without prompting")
+	dedupeCmd.Flags().BoolVar(&dedupeDryRun, "dry-run", false, "Only show duplicate groups")
+
+	cmd.RootCmd.AddCommand(dedupeCmd)
+}
+
+func runDedupe(c *cobra.Command, args []string) error {
+	if dedupeThreshold <= 0 || dedupeThreshold > 1 {
+		return fmt.Errorf("--threshold must be between 0 and 1 (got %g)", dedupeThreshold)
+	}
+	detector, err := domain.NewDuplicateDetector(domain.DuplicateMethod(dedupeMethod), dedupeThreshold)
+	if err != nil {
+		return err
+	}
+
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+	compaction := uc.Compaction()
+	ctx := c.Context()
+
+	groups, err := compaction.FindDuplicateGroups(ctx, detector)
+	if err != nil {
+		return fmt.Errorf("find duplicates: %w", err)
+	}
+	if len(groups) == 0 {
+		fmt.Println("No duplicates found.")
+		return nil
+	}
+
+	merged, deleted := 0, 0
+	for i, group := range groups {
+		fmt.Printf("Group %d of %d (%.0f%% similar)\n", i+1, len(groups), group.Score*100)
+		if err := printGroup(group); err != nil {
+			return err
+		}
+		fmt.Println()
+
+		if dedupeDryRun {
+			continue
+		}
+
+		choice := choiceMerge
+		if !dedupeAuto {
+			_, choice, err = huh.SelectOne("Resolve this group", []string{choiceMerge, choiceDelete, choiceSkip, choiceQuit})
+			if err != nil {
+				if errors.Is(err, huh.ErrCancelled) {
+					choice = choiceQuit
+				} else {
+					return fmt.Errorf("selection failed: %w", err)
+				}
+			}
+		}
+
+		switch choice {
+		case choiceMerge:
+			if _, err := compaction.MergeGroup(ctx, group); err != nil {
+				return fmt.Errorf("merge group: %w", err)
+			}
+			merged++
+			deleted += len(group.Duplicates())
+		case choiceDelete:
+			removed, err := compaction.DeleteDuplicates(ctx, group)
+			if err != nil {
+				return fmt.Errorf("delete duplicates: %w", err)
+			}
+			deleted += len(removed)
+		case choiceQuit:
+			fmt.Printf("✅ Merged %d groups, removed %d entries.\n", merged, deleted)
+			return nil
+		}
+	}
+
+	if dedupeDryRun {
+		fmt.Printf("Dry run: %d duplicate groups found.\n", len(groups))
+		return nil
+	}
+	fmt.Printf("✅ Merged %d groups, removed %d entries.\n", merged, deleted)
+	return nil
+}
+
+// printGroup shows the entries of a group next to each other, marking the keeper
+func printGroup(group domain.DuplicateGroup) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tID\tSECTION\tTAG\tCONTENT\tRATIONALE")
+	for i, entry := range group.Entries {
+		marker := " "
+		if i == 0 {
+			marker = "*"
+		}
+		rationale := entry.Rationale
+		if rationale == "" {
+			rationale = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", marker, entry.ID, entry.Section, entry.Tag,
+			cmd.Truncate(entry.Content, 50), cmd.Truncate(rationale, 30))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,79 @@
+package detect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/detector"
+)
+
+var (
+	detectJSON    bool
+	detectExplain bool
+)
+
+func init() {
+	detectCmd := &cobra.Command{
+		Use:   "detect [path]",
+		Short: "Detect the project stack without initializing anything",
+		Long: `Detect the language, framework, database, project type and features of a project,
+the way init does, and print them without initializing the project: .ohmymem/stack.yaml
+is honored, but .ohmymem/project.yaml is neither read nor updated.
+
+The project is the current directory unless a path is given.`,
+		Example: `  ohmymem detect
+  ohmymem detect ../api --explain
+  ohmymem detect --json | jq -r .framework`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runDetect,
+	}
+
+	detectCmd.Flags().BoolVar(&detectJSON, "json", false, "Print the detected project as JSON")
+	detectCmd.Flags().BoolVar(&detectExplain, "explain", false, "Show which files and dependencies each detected value comes from")
+
+	cmd.RootCmd.AddCommand(detectCmd)
+}
+
+func runDetect(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	if len(args) == 1 {
+		if rootPath, err = filepath.Abs(args[0]); err != nil {
+			return fmt.Errorf("resolve %s: %w", args[0], err)
+		}
+		info, err := os.Stat(rootPath)
+		if err != nil {
+			return fmt.Errorf("project directory: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", rootPath)
+		}
+	}
+
+	info, err := detector.NewCompositeDetector().WithExplain(detectExplain).Detect(rootPath)
+	if err != nil {
+		return err
+	}
+
+	if detectJSON {
+		return cmd.PrintJSON(info)
+	}
+
+	if !info.IsDetected() {
+		fmt.Printf("Could not detect the project in %s.\n", rootPath)
+		return nil
+	}
+	cmd.PrintProjectInfo(os.Stdout, "", info)
+	if detectExplain && len(info.Evidence) > 0 {
+		fmt.Println()
+		return cmd.PrintEvidence(os.Stdout, "", info.Evidence)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+var (
+	diffJSON    bool
+	diffNoColor bool
+)
+
+// changeJSON is the JSON view of a changed entry
+type changeJSON struct {
+	Fields []string          `json:"fields"`
+	Old    usecase.EntryJSON `json:"old"`
+	New    usecase.EntryJSON `json:"new"`
+}
+
+// diffJSONView is the JSON view of an entry diff
+type diffJSONView struct {
+	Added   []usecase.EntryJSON `json:"added"`
+	Removed []usecase.EntryJSON `json:"removed"`
+	Changed []changeJSON        `json:"changed"`
+}
+
+func init() {
+	diffCmd := &cobra.Command{
+		Use:   "diff <old> [<new>]",
+		Short: "Show added, removed and changed entries between two memory versions",
+		Long: `Compare two versions of the memory file entry by entry instead of line by line.
+Each argument is a path to a memory file or a git revision (branch, tag or commit) whose
+.ohmymem/memory.md is read. Without <new>, the current memory file is used.
+
+Entries are matched by ID, so an edited entry shows up as changed with the fields
+that differ, not as a removal plus an addition.`,
+		Example: `  ohmymem diff HEAD
+  ohmymem diff main feature-branch
+  ohmymem diff backup/memory.md --json`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE:         runDiff,
+	}
+
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Print the diff as JSON")
+	diffCmd.Flags().BoolVar(&diffNoColor, "no-color", false, "Disable colored output")
+
+	cmd.RootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	newSource := ""
+	if len(args) == 2 {
+		newSource = args[1]
+	} else if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	diff, err := uc.Diff(c.Context(), args[0], newSource)
+	if err != nil {
+		return err
+	}
+
+	if diffJSON {
+		view := diffJSONView{
+			Added:   usecase.ToEntriesJSON(diff.Added),
+			Removed: usecase.ToEntriesJSON(diff.Removed),
+			Changed: []changeJSON{},
+		}
+		for _, change := range diff.Changed {
+			view.Changed = append(view.Changed, changeJSON{
+				Fields: change.Fields,
+				Old:    usecase.ToEntryJSON(change.Old),
+				New:    usecase.ToEntryJSON(change.New),
+			})
+		}
+		return cmd.PrintJSON(view)
+	}
+
+	if diff.IsEmpty() {
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	color := !diffNoColor && cmd.UseColor()
+	for _, entry := range diff.Added {
+		fmt.Println(cmd.Paint(color, cmd.ColorAdded, "+ "+describe(entry)))
+	}
+	for _, entry := range diff.Removed {
+		fmt.Println(cmd.Paint(color, cmd.ColorRemoved, "- "+describe(entry)))
+	}
+	for _, change := range diff.Changed {
+		fmt.Println(cmd.Paint(color, cmd.ColorChanged, "~ "+describe(change.New)))
+		for _, field := range change.Fields {
+			fmt.Printf("    %s: %s → %s\n", field, fieldValue(change.Old, field), fieldValue(change.New, field))
+		}
+	}
+
+	fmt.Printf("\n%d added, %d removed, %d changed.\n", len(diff.Added), len(diff.Removed), len(diff.Changed))
+	return nil
+}
+
+// describe renders an entry on one line
+func describe(entry domain.Entry) string {
+	id := entry.ID
+	if id == "" {
+		id = "(legacy)"
+	}
+	return fmt.Sprintf("%s  %s  %s %s", id, entry.Section, entry.Tag, entry.Content)
+}
+
+// fieldValue renders one field of an entry for a change line
+func fieldValue(entry domain.Entry, field string) string {
+	var value string
+	switch field {
+	case "section":
+		value = string(entry.Section)
+	case "tag":
+		value = entry.Tag
+	case "content":
+		value = entry.Content
+	case "rationale":
+		value = entry.Rationale
+	case "status":
+		value = entry.StatusLabel()
+	case "expires":
+		if !entry.ExpiresAt.IsZero() {
+			value = entry.ExpiresAt.Local().Format(time.DateOnly)
+		}
+	case "scope":
+		value = strings.Join(entry.Scope, ", ")
+	}
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%q", value)
+}
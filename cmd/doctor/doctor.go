@@ -0,0 +1,100 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+var (
+	doctorFix  bool
+	doctorJSON bool
+)
+
+func init() {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the ohmymem setup of this project",
+		Long: `Check the .ohmymem directory layout, memory.md frontmatter, entry format parse
+coverage, stale locks and temp files, the .cursorrules and CLAUDE.md symlinks, and
+the ohmymem block in AGENTS.md.
+
+With --fix, problems that can be repaired without touching existing entries are fixed.`,
+		Example:      "  ohmymem doctor --fix",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runDoctor,
+	}
+
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair what can be fixed safely")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Print the report as JSON")
+
+	cmd.RootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	report, err := usecase.NewDoctorUseCase(rootPath).Run(c.Context(), doctorFix)
+	if err != nil {
+		return err
+	}
+
+	if doctorJSON {
+		if err := cmd.PrintJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(report)
+	}
+
+	if !report.Healthy() {
+		return fmt.Errorf("doctor found problems")
+	}
+	return nil
+}
+
+// printReport writes one line per check, followed by a --fix hint
+func printReport(report *usecase.DoctorReport) {
+	width := 0
+	for _, check := range report.Checks {
+		width = max(width, len(check.Name))
+	}
+
+	fixable := 0
+	for _, check := range report.Checks {
+		detail := check.Detail
+		switch {
+		case check.Fixed:
+			detail += " (fixed)"
+		case check.Fixable:
+			fixable++
+		}
+		fmt.Printf("%s %-*s  %s\n", statusIcon(check), width, check.Name, detail)
+	}
+
+	if fixable > 0 {
+		fmt.Printf("\nRun 'ohmymem doctor --fix' to repair %d fixable problem(s).\n", fixable)
+	}
+}
+
+// statusIcon renders a check status
+func statusIcon(check usecase.DoctorCheck) string {
+	switch {
+	case check.Fixed:
+		return "🔧"
+	case check.Status == usecase.CheckOK:
+		return "✅"
+	case check.Status == usecase.CheckWarn:
+		return "⚠️"
+	default:
+		return "❌"
+	}
+}
@@ -0,0 +1,227 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+)
+
+// defaultEditor is used when neither $VISUAL nor $EDITOR is set
+const defaultEditor = "vi"
+
+func init() {
+	editCmd := &cobra.Command{
+		Use:   "edit <entry-id>",
+		Short: "Edit a memory entry in $EDITOR",
+		Long: `Open an entry's tag, content, rationale, expiry and scope in $EDITOR as YAML,
+with the context, options and consequences of decisions.
+The entry is validated on save and its anchored block rewritten atomically;
+ID, section and creation time are kept. Empty the buffer to abort.`,
+		Example:      "  ohmymem edit 01a14476-da35",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runEdit,
+	}
+
+	cmd.RootCmd.AddCommand(editCmd)
+}
+
+// editBuffer is the YAML document presented in the editor
+type editBuffer struct {
+	Tag       string `yaml:"tag"`
+	Content   string `yaml:"content"`
+	Rationale string `yaml:"rationale"`
+	ExpiresAt string `yaml:"expires_at"`
+	Scope     string `yaml:"scope"`
+
+	// Decision record fields, shown for decisions only
+	Context      string `yaml:"context,omitempty"`
+	Options      string `yaml:"options,omitempty"`
+	Consequences string `yaml:"consequences,omitempty"`
+}
+
+func runEdit(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+	svc := uc.Service()
+
+	entry, err := svc.FindEntry(c.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	buffer := editBuffer{
+		Tag:       entry.TagName,
+		Content:   entry.Content,
+		Rationale: entry.Rationale,
+		Scope:     strings.Join(entry.Scope, ", "),
+
+		Context:      entry.ADR.Context,
+		Options:      strings.Join(entry.ADR.Options, "; "),
+		Consequences: entry.ADR.Consequences,
+	}
+	if !entry.ExpiresAt.IsZero() {
+		buffer.ExpiresAt = entry.ExpiresAt.Format(time.RFC3339)
+	}
+	original := buffer
+
+	problem := ""
+	for {
+		edited, aborted, err := editInEditor(entry, buffer, problem)
+		if err != nil {
+			return err
+		}
+		if aborted || edited == original {
+			fmt.Println("No changes.")
+			return nil
+		}
+		buffer = edited
+
+		expiresAt, err := domain.ParseExpiry(buffer.ExpiresAt, 0, uc.Now())
+		var scope []string
+		if err == nil {
+			scope, err = domain.ParseScope(buffer.Scope)
+		}
+		if err == nil {
+			var updated *domain.Entry
+			updated, err = svc.EditEntry(c.Context(), *entry, domain.AppendInput{
+				Tag:       strings.TrimSpace(buffer.Tag),
+				Content:   strings.TrimSpace(buffer.Content),
+				Rationale: strings.TrimSpace(buffer.Rationale),
+				ExpiresAt: expiresAt,
+				Scope:     scope,
+				ADR: domain.DecisionRecord{
+					Context:      strings.TrimSpace(buffer.Context),
+					Options:      domain.ParseOptions(buffer.Options),
+					Consequences: strings.TrimSpace(buffer.Consequences),
+				},
+			})
+			if err == nil {
+				fmt.Printf("✅ Updated %s in %s\n", updated.ID, updated.Section.Title())
+				fmt.Printf("   * **%s** %s\n", updated.Tag, updated.Content)
+				return nil
+			}
+		}
+
+		// Validation failed: offer to fix the buffer instead of losing the edits
+		fmt.Printf("Invalid entry: %v\n", err)
+		retry, promptErr := huh.Confirm("Edit again?", true)
+		if promptErr != nil || !retry {
+			fmt.Println("Cancelled. The entry was not changed.")
+			return nil
+		}
+		problem = err.Error()
+	}
+}
+
+// editInEditor writes the buffer to a temp file, opens the editor and parses the result.
+// aborted is true when the user emptied the file.
+func editInEditor(entry *domain.Entry, buffer editBuffer, problem string) (editBuffer, bool, error) {
+	data, err := yaml.Marshal(buffer)
+	if err != nil {
+		return editBuffer{}, false, fmt.Errorf("encode entry: %w", err)
+	}
+
+	var doc bytes.Buffer
+	fmt.Fprintf(&doc, "# Editing entry %s (%s).\n", entry.ID, entry.Section)
+	doc.WriteString("# Save and close to apply. Delete everything to abort.\n")
+	doc.WriteString("# expires_at accepts RFC3339 or YYYY-MM-DD; leave empty for no expiry.\n")
+	doc.WriteString("# scope lists comma-separated path patterns (e.g. internal/api/**); leave empty for the whole project.\n")
+	if entry.Section == domain.SectionDecisions {
+		doc.WriteString("# Decisions may also set context, options (semicolon-separated) and consequences.\n")
+	}
+	if problem != "" {
+		fmt.Fprintf(&doc, "#\n# ERROR: %s\n", problem)
+	}
+	doc.Write(data)
+
+	f, err := os.CreateTemp("", "ohmymem-edit-*.yaml")
+	if err != nil {
+		return editBuffer{}, false, fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.Write(doc.Bytes()); err != nil {
+		f.Close()
+		return editBuffer{}, false, fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return editBuffer{}, false, fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		return editBuffer{}, false, err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return editBuffer{}, false, fmt.Errorf("read edited file: %w", err)
+	}
+	if len(bytes.TrimSpace(stripComments(edited))) == 0 {
+		return editBuffer{}, true, nil
+	}
+
+	var result editBuffer
+	if err := yaml.Unmarshal(edited, &result); err != nil {
+		return editBuffer{}, false, fmt.Errorf("parse edited entry: %w", err)
+	}
+	return result, false, nil
+}
+
+// runEditor opens path in $VISUAL, $EDITOR or vi, attached to the terminal
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	// Allow editors with arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	editorCmd := exec.Command(parts[0], append(parts[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("editor %q not found, set $EDITOR", parts[0])
+		}
+		return fmt.Errorf("run editor: %w", err)
+	}
+	return nil
+}
+
+// stripComments drops YAML comment lines
+func stripComments(data []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte("#")) {
+			continue
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
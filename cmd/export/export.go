@@ -0,0 +1,122 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+var (
+	exportFormat         string
+	exportOutput         string
+	exportSection        string
+	exportIncludeExpired bool
+)
+
+func init() {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export memory entries as JSON, YAML or CSV",
+		Long: `Serialize entries with their IDs, sections and timestamps for dashboards,
+spreadsheets or other knowledge tools.
+
+Writes to stdout unless --output is set. Without --format, the format is
+taken from the output file extension and falls back to JSON.`,
+		Example: `  ohmymem export > memory.json
+  ohmymem export --format csv --output memory.csv
+  ohmymem export -o decisions.yaml --section decisions`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runExport,
+	}
+
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "", "Output format: json, yaml or csv")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to this file instead of stdout")
+	exportCmd.Flags().StringVarP(&exportSection, "section", "s", "", "Only these sections (comma-separated)")
+	exportCmd.Flags().BoolVar(&exportIncludeExpired, "include-expired", true, "Include entries whose expiry has passed")
+
+	cmd.RootCmd.AddCommand(exportCmd)
+}
+
+func runExport(c *cobra.Command, args []string) error {
+	format, err := resolveFormat()
+	if err != nil {
+		return err
+	}
+
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	sections, err := domain.ParseSections(exportSection)
+	if err != nil {
+		return err
+	}
+
+	entries, err := uc.Service().ListEntries(c.Context(), domain.EntryFilter{
+		Sections:       sections,
+		IncludeExpired: exportIncludeExpired,
+		Now:            uc.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+
+	if exportOutput == "" {
+		return usecase.WriteEntries(os.Stdout, format, entries)
+	}
+
+	if err := writeFile(exportOutput, format, entries); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Exported %d entries to %s\n", len(entries), exportOutput)
+	return nil
+}
+
+// resolveFormat picks the format from --format, then the output extension, then JSON
+func resolveFormat() (usecase.ExchangeFormat, error) {
+	if exportFormat != "" {
+		format, err := usecase.ParseExchangeFormat(exportFormat)
+		if err == nil && format == usecase.FormatMarkdown {
+			return "", fmt.Errorf("markdown is an import-only format, use 'ohmymem show --plain' instead")
+		}
+		return format, err
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(exportOutput), "."); ext != "" {
+		if format, err := usecase.ParseExchangeFormat(ext); err == nil && format != usecase.FormatMarkdown {
+			return format, nil
+		}
+	}
+	return usecase.FormatJSON, nil
+}
+
+// writeFile serializes entries to path, replacing any existing file
+func writeFile(path string, format usecase.ExchangeFormat, entries []domain.Entry) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("close %s: %w", path, closeErr)
+		}
+	}()
+
+	if err := usecase.WriteEntries(file, format, entries); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package fmtcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+var fmtCheck bool
+
+func init() {
+	fmtCmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Normalize the layout of memory.md",
+		Long: `Rewrite .ohmymem/memory.md with a consistent layout: LF line endings, no trailing
+whitespace, sections in their standard order (repeated headers merged) and one blank
+line between entries. Entry text is never changed.
+
+With --check, nothing is written and the command exits nonzero when the file is not
+formatted, e.g. in a pre-commit hook or CI.`,
+		Example:      "  ohmymem fmt\n  ohmymem fmt --check",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runFmt,
+	}
+
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "Only report whether memory.md is formatted")
+
+	cmd.RootCmd.AddCommand(fmtCmd)
+}
+
+func runFmt(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	changed, err := uc.Format(c.Context(), fmtCheck)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(persistence.DirName, persistence.FileName)
+	switch {
+	case fmtCheck && changed:
+		return fmt.Errorf("%s is not formatted, run 'ohmymem fmt'", path)
+	case changed:
+		fmt.Printf("✅ Formatted %s\n", path)
+	default:
+		fmt.Printf("%s is already formatted.\n", path)
+	}
+	return nil
+}
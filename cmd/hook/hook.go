@@ -0,0 +1,136 @@
+package hook
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+var hookForce bool
+
+func init() {
+	hookCmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Manage the git hooks that check and trace the memory",
+	}
+
+	installCmd := &cobra.Command{
+		Use:   "install [hook...]",
+		Short: "Install the pre-commit and prepare-commit-msg hooks",
+		Long: `Install git hooks in the repository:
+
+  pre-commit          rejects commits whose staged .ohmymem/memory.md is malformed
+                      (broken anchors, duplicate IDs, invalid frontmatter or entries)
+  prepare-commit-msg  appends a "` + usecase.DecisionTrailer + `" trailer for each decision the
+                      commit adds to the memory, linking code changes to their rationale
+
+Both are installed unless hooks are named. An existing hook not written by ohmymem
+is kept unless --force is given; it is then moved aside and still runs first.`,
+		Example: `  ohmymem hook install
+  ohmymem hook install pre-commit`,
+		SilenceUsage: true,
+		RunE:         runInstall,
+	}
+	installCmd.Flags().BoolVar(&hookForce, "force", false, "Replace existing hooks, chaining them")
+
+	uninstallCmd := &cobra.Command{
+		Use:          "uninstall",
+		Short:        "Remove the ohmymem git hooks and restore the hooks they replaced",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runUninstall,
+	}
+
+	runCmd := &cobra.Command{
+		Use:          "run <hook> [args...]",
+		Short:        "Run a hook (called by the installed hook scripts)",
+		Hidden:       true,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runHook,
+	}
+
+	hookCmd.AddCommand(installCmd, uninstallCmd, runCmd)
+	cmd.RootCmd.AddCommand(hookCmd)
+}
+
+func runInstall(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	names := args
+	if len(names) == 0 {
+		names = usecase.Hooks
+	}
+	installed, err := usecase.NewHookUseCase(rootPath).Install(c.Context(), names, hookForce)
+	for _, hook := range installed {
+		note := ""
+		if hook.BackedUp {
+			note = " (existing hook kept and chained)"
+		}
+		fmt.Printf("✅ Installed %s%s\n", hook.Path, note)
+	}
+	return err
+}
+
+func runUninstall(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	removed, err := usecase.NewHookUseCase(rootPath).Uninstall(c.Context())
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		fmt.Println("No ohmymem hooks installed.")
+		return nil
+	}
+	for _, path := range removed {
+		fmt.Printf("✅ Removed %s\n", path)
+	}
+	return nil
+}
+
+func runHook(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	hooks := usecase.NewHookUseCase(rootPath)
+	switch args[0] {
+	case usecase.HookPreCommit:
+		problems, err := hooks.PreCommit(c.Context())
+		if err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "ohmymem: staged .ohmymem/memory.md is malformed:")
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, "  - "+problem)
+		}
+		return fmt.Errorf("%d problems in memory.md; fix them (see 'ohmymem doctor') or commit with --no-verify", len(problems))
+	case usecase.HookPrepareCommitMsg:
+		if len(args) < 2 {
+			return fmt.Errorf("%s needs the commit message file", usecase.HookPrepareCommitMsg)
+		}
+		source := ""
+		if len(args) > 2 {
+			source = args[2]
+		}
+		return hooks.PrepareCommitMsg(c.Context(), args[1], source)
+	default:
+		return fmt.Errorf("unknown hook %q (expected %s)", args[0], strings.Join(usecase.Hooks, " or "))
+	}
+}
@@ -0,0 +1,156 @@
+// Package importcmd implements "ohmymem import"; the directory name is a Go keyword
+package importcmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+var (
+	importFormat  string
+	importSection string
+	importTag     string
+	importDryRun  bool
+	importForce   bool
+)
+
+func init() {
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import entries from JSON, YAML, CSV or a markdown bullet list",
+		Long: `Import entries from a file ("-" reads stdin) in the export format (JSON, YAML,
+CSV) or as a plain markdown bullet list. "## Section" headers in markdown set the
+section of the bullets below them; "**[Tag]**" prefixes set their tag.
+
+Every record is validated and gets a new UUIDv7 ID. Entries are appended in a
+single write: if any record is invalid, nothing is imported. Records similar to
+existing entries are skipped unless --force is given.`,
+		Example: `  ohmymem import memory.json --dry-run
+  ohmymem import notes.md --section decisions --tag Architecture
+  ohmymem export | ssh other-host 'cd project && ohmymem import -'`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runImport,
+	}
+
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", "", "Input format: json, yaml, csv or markdown (default: from extension or content)")
+	importCmd.Flags().StringVarP(&importSection, "section", "s", "", "Section for records without one (default note)")
+	importCmd.Flags().StringVarP(&importTag, "tag", "t", "", "Tag for records without one (default: suggested from content)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Validate and preview without writing")
+	importCmd.Flags().BoolVar(&importForce, "force", false, "Import records that look like duplicates")
+
+	cmd.RegisterTagCompletion(importCmd)
+
+	cmd.RootCmd.AddCommand(importCmd)
+}
+
+func runImport(c *cobra.Command, args []string) error {
+	if importSection != "" && !domain.SectionType(importSection).IsValid() {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidCategory, importSection)
+	}
+
+	data, err := readInput(args[0])
+	if err != nil {
+		return err
+	}
+
+	format, err := resolveFormat(args[0], data)
+	if err != nil {
+		return err
+	}
+
+	records, err := usecase.ReadEntries(data, format)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Println("No entries found in input.")
+		return nil
+	}
+
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	result, err := uc.Import(c.Context(), records, usecase.ImportOptions{
+		DryRun:  importDryRun,
+		Force:   importForce,
+		Section: importSection,
+		Tag:     importTag,
+	})
+	if result != nil {
+		printIssues("Invalid", result.Invalid)
+		printIssues("Skipped", result.Skipped)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidImport) {
+			return fmt.Errorf("%w; fix the records above and retry", err)
+		}
+		return fmt.Errorf("import: %w", err)
+	}
+
+	if importDryRun {
+		if len(result.Entries) > 0 {
+			if err := cmd.PrintEntryTable(os.Stdout, result.Entries); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Dry run: %d entries would be imported, %d skipped.\n", len(result.Entries), len(result.Skipped))
+		return nil
+	}
+
+	fmt.Printf("✅ Imported %d entries (%d skipped)\n", len(result.Entries), len(result.Skipped))
+	return nil
+}
+
+// readInput reads the named file, or stdin for "-"
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// resolveFormat picks the format from --format, then the file extension, then the content
+func resolveFormat(path string, data []byte) (usecase.ExchangeFormat, error) {
+	if importFormat != "" {
+		return usecase.ParseExchangeFormat(importFormat)
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(path), "."); ext != "" {
+		if format, err := usecase.ParseExchangeFormat(ext); err == nil {
+			return format, nil
+		}
+	}
+	return usecase.DetectExchangeFormat(data), nil
+}
+
+// printIssues lists skipped or invalid records on stderr
+func printIssues(label string, issues []usecase.ImportIssue) {
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s %s: %s (%s)\n", label, issue.Source, cmd.Truncate(issue.Content, 50), issue.Reason)
+	}
+}
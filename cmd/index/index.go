@@ -0,0 +1,66 @@
+package index
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+var indexRebuild bool
+
+func init() {
+	indexCmd := &cobra.Command{
+		Use:   "index",
+		Short: "Compute entry embeddings for semantic search",
+		Long: `Compute the vectors of new and changed entries with the configured embedding
+provider and store them in .ohmymem/index/, so 'ohmymem search --semantic' and the
+ohmymem_search MCP tool can match entries by meaning. Searches refresh the index
+too; running this ahead saves the wait on the first search.
+
+Configure a provider first: a local model served by Ollama, or an OpenAI-compatible API.`,
+		Example: `  ohmymem config set embeddings.provider ollama
+  ohmymem index
+  ohmymem index --rebuild`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runIndex,
+	}
+
+	indexCmd.Flags().BoolVar(&indexRebuild, "rebuild", false, "Discard the index and embed every entry again")
+
+	cmd.RootCmd.AddCommand(indexCmd)
+}
+
+func runIndex(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+	semantic, err := uc.Semantic()
+	if err != nil {
+		return err
+	}
+
+	if indexRebuild {
+		if err := os.Remove(persistence.EmbeddingIndexPath(rootPath)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove index: %w", err)
+		}
+	}
+
+	stats, err := semantic.Refresh(c.Context())
+	if err != nil {
+		return fmt.Errorf("index: %w", err)
+	}
+	fmt.Printf("✅ Indexed %d entries (%d embedded, %d removed)\n", stats.Total, stats.Embedded, stats.Removed)
+	return nil
+}
@@ -1,51 +1,115 @@
 package init
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/herewei/ohmymem-core/cmd"
 	initApp "github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/detector"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/tui"
 )
 
 var (
-	initForce bool
-	initYes   bool
-	initRepo  string
+	initForce   bool
+	initYes     bool
+	initRepos   []string
+	initEditors []string
+	initPath    string
+	initDryRun  bool
+	initNoCache bool
+	initRefresh bool
+	initRepoRef string
+	initHooks   bool
+	initVerbose bool
+
+	initTemplate  string
+	initMirror    string
+	initLanguage  string
+	initFramework string
+	initDatabase  string
+	initType      string
+)
+
+// Template choices of --template besides a repository URL
+const (
+	templateDefault = "default"
+	templateEmpty   = "empty"
+)
+
+// Mirrors of the default template repository
+const (
+	mirrorGitHub = "github"
+	mirrorGitee  = "gitee"
 )
 
 func init() {
 	initCmd := &cobra.Command{
-		Use:   "init",
+		Use:   "init [path]",
 		Short: "Initialize OhMyMem in current project",
-		Long:  "Initialize OhMyMem with smart detection of your project stack.",
-		RunE:  runInit,
+		Long: `Initialize OhMyMem with smart detection of your project stack.
+
+The project is the current directory unless a path is given, as an argument or with --path.`,
+		Example: `  ohmymem init
+  ohmymem init ../api --yes
+  ohmymem init --path /work/web --editors claude`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runInit,
 	}
 
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Overwrite existing files")
 	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Skip confirmation prompts")
-	initCmd.Flags().StringVar(&initRepo, "repo", "", "Custom template repository URL")
+	initCmd.Flags().StringArrayVar(&initRepos, "repo", nil, "Custom template repository URL, or \"local\" for ~/.ohmymem/templates alone; repeat to layer repositories on top, in increasing priority")
+	initCmd.Flags().StringVar(&initRepoRef, "repo-ref", "", "Branch, tag or commit of the template repository (default: template.ref, else the default branch)")
+	initCmd.Flags().BoolVar(&initDryRun, "dry-run", false, "Detect and fetch templates, then show the files that would change without writing")
+	initCmd.Flags().StringVar(&initTemplate, "template", "", "Template: default, empty (no template) or a repository URL")
+	initCmd.Flags().BoolVar(&initNoCache, "no-cache", false, "Clone the template repository instead of using the cache")
+	initCmd.Flags().BoolVar(&initRefresh, "refresh", false, "Fetch the template repository again even if the cached copy is fresh")
+	initCmd.Flags().BoolVar(&initHooks, "hooks", false, "Run the post-init hooks of the templates without asking")
+	initCmd.Flags().StringVar(&initMirror, "mirror", "", "Mirror of the default template: github or gitee")
+	initCmd.Flags().StringVar(&initLanguage, "language", "", "Project language, overriding detection (e.g. go, typescript)")
+	initCmd.Flags().StringVar(&initFramework, "framework", "", "Project framework, overriding detection (e.g. echo, express)")
+	initCmd.Flags().StringVar(&initDatabase, "database", "", "Project database, overriding detection (e.g. postgresql, mysql)")
+	initCmd.Flags().StringVar(&initType, "type", "", "Project type, overriding detection: "+strings.Join(domain.ProjectTypes, ", "))
+	initCmd.Flags().BoolVarP(&initVerbose, "verbose", "v", false, "Explain which files and dependencies each detected value comes from")
+	initCmd.Flags().StringVar(&initPath, "path", "", "Project directory to initialize (default: current directory)")
+	initCmd.Flags().StringSliceVar(&initEditors, "editors", initApp.DefaultEditors,
+		"Agent integrations to link to AGENTS.md: "+strings.Join(initApp.EditorNames(), ", "))
 
 	cmd.RootCmd.AddCommand(initCmd)
 }
 
-func runInit(cmd *cobra.Command, args []string) error {
-	// 1. Get working directory
-	rootPath, err := os.Getwd()
+func runInit(c *cobra.Command, args []string) error {
+	// 1. Resolve the project directory
+	rootPath, err := resolveRootPath(args)
+	if err != nil {
+		return err
+	}
+
+	if _, err := initApp.ResolveEditors(initEditors); err != nil {
+		return err
+	}
+	overrides, err := detectionOverrides()
 	if err != nil {
-		return fmt.Errorf("get working directory: %w", err)
+		return err
+	}
+	fetch, err := cmd.TemplateFetch(initRepoRef, initNoCache, initRefresh)
+	if err != nil {
+		return err
 	}
 
 	// 1.1 Check if already initialized (interactive unless --yes or --force)
 	memoryPath := filepath.Join(rootPath, ".ohmymem", "memory.md")
-	if fileExists(memoryPath) && !initForce {
+	if fileExists(memoryPath) && !initForce && !initDryRun {
 		if initYes {
 			return fmt.Errorf("already initialized. Use '--force' to overwrite")
 		}
@@ -65,64 +129,18 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	// 2. Create dependencies
-	projectDetector := detector.NewCompositeDetector()
+	projectDetector := detector.NewCompositeDetector().WithOverrides(overrides).WithExplain(initVerbose)
 	iuc := initApp.NewInitUseCase(projectDetector)
 
 	// 3. Resolve init mode (interactive by default)
-	emptyProject := false
-	var repoURLs []string
-	if initRepo != "" {
-		repo := strings.TrimSpace(initRepo)
-		if repo != "" {
-			repoURLs = []string{repo}
-		}
-	} else if !initYes {
-		options := []string{
-			"Empty project (no template)",
-			"Use default template",
-			"Custom template repo",
-		}
-		_, choice, err := huh.SelectOne("How would you like to initialize?", options)
-		if err != nil {
-			if err == huh.ErrCancelled {
-				fmt.Println("Cancelled.")
-				return nil
-			}
-			return fmt.Errorf("prompt failed: %w", err)
-		}
-		switch choice {
-		case options[0]:
-			emptyProject = true
-		case options[1]:
-			_, repoChoice, err := huh.SelectOne("Choose default template repo", []string{"GitHub", "Gitee"})
-			if err != nil {
-				if err == huh.ErrCancelled {
-					fmt.Println("Cancelled.")
-					return nil
-				}
-				return fmt.Errorf("prompt failed: %w", err)
-			}
-			if repoChoice == "Gitee" {
-				repoURLs = []string{template.DefaultGiteeRepo}
-			} else {
-				repoURLs = []string{template.DefaultGitHubRepo}
-			}
-		case options[2]:
-			repo, err := huh.PromptInput("Custom template repo URL", "")
-			if err != nil {
-				if err == huh.ErrCancelled {
-					fmt.Println("Cancelled.")
-					return nil
-				}
-				return fmt.Errorf("prompt failed: %w", err)
-			}
-			repo = strings.TrimSpace(repo)
-			if repo == "" {
-				fmt.Println("Cancelled.")
-				return nil
-			}
-			repoURLs = []string{repo}
+	initRepo, layers := cmd.TemplateLayers(initRepos)
+	emptyProject, repoURLs, err := resolveTemplate(initRepo)
+	if err != nil {
+		if err == huh.ErrCancelled {
+			fmt.Println("Cancelled.")
+			return nil
 		}
+		return err
 	}
 
 	opts := initApp.InitOptions{
@@ -130,8 +148,22 @@ func runInit(cmd *cobra.Command, args []string) error {
 		Force:    initForce,
 		Yes:      initYes,
 		RepoURLs: repoURLs,
+		Layers:   layers,
+		Editors:  initEditors,
+		DryRun:   initDryRun,
+		Fetch:    fetch,
 	}
 
+	if emptyProject && initDryRun {
+		action := "create (empty)"
+		if fileExists(memoryPath) {
+			action = "overwrite (empty)"
+		}
+		fmt.Println("📋 Dry run, nothing written:")
+		fmt.Println()
+		fmt.Printf("   %s: %s\n", memoryPath, action)
+		return nil
+	}
 	if emptyProject {
 		ohmymemDir := filepath.Join(rootPath, ".ohmymem")
 		if err := os.MkdirAll(ohmymemDir, 0755); err != nil {
@@ -167,20 +199,29 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	info := preview.ProjectInfo
 	if info.IsDetected() {
-		fmt.Printf("   Language:   %s\n", info.Language)
-		if info.Framework != "" {
-			fmt.Printf("   Framework:  %s\n", info.Framework)
-		}
-		if info.Database != "" {
-			fmt.Printf("   Database:   %s\n", info.Database)
-		}
-		if info.ProjectType != "" {
-			fmt.Printf("   Type:       %s\n", info.ProjectType)
+		// Let the user pick among ranked candidates (unless --yes or --dry-run)
+		if !initYes && !initDryRun {
+			if err := chooseCandidates(info, overrides); err != nil {
+				if err == huh.ErrCancelled {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+				return err
+			}
 		}
+
+		cmd.PrintProjectInfo(os.Stdout, "   ", info)
 		fmt.Println()
+		if initVerbose && len(info.Evidence) > 0 {
+			fmt.Println("   Evidence:")
+			if err := cmd.PrintEvidence(os.Stdout, "   ", info.Evidence); err != nil {
+				return err
+			}
+			fmt.Println()
+		}
 
-		// 5. Confirm detection (unless --yes)
-		if !initYes {
+		// 5. Confirm detection (unless --yes or --dry-run)
+		if !initYes && !initDryRun {
 			confirmed, err := huh.Confirm("Is this correct?", true)
 			if err != nil {
 				if err == huh.ErrCancelled {
@@ -190,7 +231,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("confirmation failed: %w", err)
 			}
 			if !confirmed {
-				fmt.Println("Cancelled.")
+				fmt.Println("Cancelled. Re-run with --language, --framework, --database or --type to correct the detection.")
 				return nil
 			}
 		}
@@ -201,11 +242,34 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	opts.ProjectInfo = info
 
+	// 6. Choose the templates to compose (unless --yes or --dry-run)
+	if !initYes && !initDryRun {
+		selected, err := selectTemplates(iuc, opts, info)
+		if err != nil {
+			if err == huh.ErrCancelled {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return err
+		}
+		opts.Templates = selected
+		opts.Confirm = previewFiles
+	}
+
 	result, err := iuc.Execute(opts)
 	if err != nil {
+		if errors.Is(err, initApp.ErrInitCancelled) || errors.Is(err, huh.ErrCancelled) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
 		return err
 	}
 
+	if initDryRun {
+		printChanges(result)
+		return nil
+	}
+
 	// 7. Display results
 	fmt.Println("✨ Creating files...")
 	fmt.Println()
@@ -214,6 +278,14 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	if err := runHooks(c, rootPath, result.Hooks); err != nil {
+		if err != huh.ErrCancelled {
+			return err
+		}
+		fmt.Println("   Skipped the remaining post-init hooks.")
+		fmt.Println()
+	}
+
 	fmt.Println("✅ Initialization complete!")
 	fmt.Println()
 	fmt.Println("   Next steps:")
@@ -233,7 +305,284 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// chooseCandidates asks which framework and database the project uses when detection
+// found several, offering them ranked by confidence; values given by flags are kept
+func chooseCandidates(info *domain.ProjectInfo, overrides domain.ProjectOverrides) error {
+	choices := []struct {
+		kind  string
+		value *string
+		fixed bool
+	}{
+		{"framework", &info.Framework, overrides.Framework != ""},
+		{"database", &info.Database, overrides.Database != ""},
+	}
+	for _, choice := range choices {
+		candidates := info.CandidatesOf(choice.kind)
+		if choice.fixed || len(candidates) < 2 {
+			continue
+		}
+		options := make([]string, 0, len(candidates)+1)
+		for _, c := range candidates {
+			options = append(options, fmt.Sprintf("%s (%.0f%%)", c.Value, c.Confidence*100))
+		}
+		options = append(options, "none")
+
+		i, _, err := huh.SelectOne(fmt.Sprintf("Several %s candidates detected, which one does the project use?", choice.kind), options)
+		if err != nil {
+			return err
+		}
+		if i == len(candidates) {
+			*choice.value = ""
+		} else if i >= 0 {
+			*choice.value = candidates[i].Value
+		}
+	}
+	return nil
+}
+
+// selectTemplates offers the optional templates of the repository, pre-checked when
+// detection says they apply, and returns the IDs chosen. It returns nil, composing the
+// templates that apply, when the repository offers no choice or cannot be fetched;
+// Execute reports fetch errors.
+func selectTemplates(iuc *initApp.InitUseCase, opts initApp.InitOptions, info *domain.ProjectInfo) ([]string, error) {
+	choices, err := iuc.TemplateChoices(opts, info)
+	if err != nil || len(choices) == 0 {
+		return nil, nil
+	}
+
+	options := make([]huh.ContextOption, 0, len(choices))
+	for _, choice := range choices {
+		options = append(options, huh.ContextOption{
+			ID:          choice.ID,
+			Name:        fmt.Sprintf("%s (%s)", choice.Name, choice.Kind),
+			Description: choice.Description,
+			Selected:    choice.Selected,
+		})
+	}
+	selected, err := huh.SelectContexts("Templates to apply", options)
+	if err != nil {
+		return nil, err
+	}
+	if selected == nil {
+		selected = []string{}
+	}
+	return selected, nil
+}
+
+// previewFiles shows the generated memory.md and AGENTS.md block in a pager, then
+// asks whether to write them
+func previewFiles(memoryContent, agentsBlock string) (bool, error) {
+	markdown := "# .ohmymem/memory.md\n\n" + stripFrontmatter(memoryContent) + "\n\n---\n\n# AGENTS.md\n\n" + agentsBlock
+	if err := tui.Page("Preview of the files init writes", markdown); err != nil {
+		return false, err
+	}
+	return huh.Confirm("Write these files?", true)
+}
+
+// stripFrontmatter removes the YAML frontmatter of memory.md content, which
+// Markdown renders as a heading
+func stripFrontmatter(content string) string {
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		if _, body, found := strings.Cut(rest, "\n---\n"); found {
+			return strings.TrimLeft(body, "\n")
+		}
+	}
+	return content
+}
+
+// runHooks offers the post-init hooks of the templates one by one, running them
+// without asking with --hooks and skipping them with --yes. A failing hook is
+// reported without failing init.
+func runHooks(c *cobra.Command, rootPath string, hooks []domain.TemplateHook) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	if initYes && !initHooks {
+		fmt.Printf("   Skipped %d post-init hooks of the templates; re-run with --hooks to run them.\n", len(hooks))
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println("🪝 Post-init hooks...")
+	fmt.Println()
+	for _, hook := range hooks {
+		if !initHooks {
+			confirmed, err := huh.Confirm(fmt.Sprintf("Template %s: %s?", hook.Template, hookAction(hook)), false)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Printf("   Skipped: %s\n", hook.Describe())
+				continue
+			}
+		}
+		done, err := initApp.RunHook(c.Context(), rootPath, hook)
+		if err != nil {
+			fmt.Printf("   Warning: %s: %v\n", hook.Describe(), err)
+			continue
+		}
+		fmt.Printf("   %s: %s\n", hook.Describe(), done)
+	}
+	fmt.Println()
+	return nil
+}
+
+// hookAction describes exactly what a hook does, for confirmation
+func hookAction(hook domain.TemplateHook) string {
+	if len(hook.Run) > 0 {
+		return "run `" + strings.Join(hook.Run, " ") + "`"
+	}
+	return "create " + hook.Create
+}
+
+// printChanges shows the changes planned by a dry run
+func printChanges(result *initApp.InitResult) {
+	fmt.Println("📋 Dry run, nothing written:")
+	fmt.Println()
+	for _, change := range result.Changes {
+		fmt.Printf("   %s: %s\n", change.Path, change.Action)
+		if change.Diff != "" {
+			for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+				fmt.Println("      " + line)
+			}
+		}
+	}
+	for _, hook := range result.Hooks {
+		fmt.Printf("   post-init hook of %s: %s\n", hook.Template, hookAction(hook))
+	}
+	if len(result.Warnings) > 0 {
+		fmt.Println()
+		for _, w := range result.Warnings {
+			fmt.Println(w)
+		}
+	}
+}
+
+// resolveTemplate picks the template from --template, --repo and --mirror, prompting
+// for what they leave open unless --yes is set. It reports whether the project starts
+// empty and the repositories to fetch; no repositories means the default mirrors in order.
+func resolveTemplate(initRepo string) (bool, []string, error) {
+	mirror := strings.ToLower(strings.TrimSpace(initMirror))
+	if mirror != "" && mirror != mirrorGitHub && mirror != mirrorGitee {
+		return false, nil, fmt.Errorf("invalid --mirror %q (expected %s or %s)", initMirror, mirrorGitHub, mirrorGitee)
+	}
+
+	choice := strings.TrimSpace(initTemplate)
+	if repo := strings.TrimSpace(initRepo); repo != "" {
+		if choice != "" && choice != repo {
+			return false, nil, fmt.Errorf("--repo and --template select different templates")
+		}
+		choice = repo
+	}
+	if mirror != "" && choice != "" && choice != templateDefault {
+		return false, nil, fmt.Errorf("--mirror only applies to the default template")
+	}
+
+	if choice == "" && mirror != "" {
+		choice = templateDefault
+	}
+	if choice == "" && !initYes {
+		options := []string{
+			"Empty project (no template)",
+			"Use default template",
+			"Custom template repo",
+		}
+		index, _, err := huh.SelectOne("How would you like to initialize?", options)
+		if err != nil {
+			return false, nil, err
+		}
+		switch index {
+		case 0:
+			choice = templateEmpty
+		case 1:
+			choice = templateDefault
+		case 2:
+			repo, err := huh.PromptInput("Custom template repo URL", "")
+			if err != nil {
+				return false, nil, err
+			}
+			if choice = strings.TrimSpace(repo); choice == "" {
+				return false, nil, huh.ErrCancelled
+			}
+		}
+	}
+
+	switch choice {
+	case templateEmpty:
+		return true, nil, nil
+	case "", templateDefault:
+		// OHMYMEM_TEMPLATE_REPO or template.repo replaces the default repositories
+		if urls := template.ConfiguredRepoURLs(); mirror == "" && len(urls) > 0 {
+			return false, urls, nil
+		}
+		if mirror == "" && choice == templateDefault && !initYes {
+			_, repoChoice, err := huh.SelectOne("Choose default template repo", []string{"GitHub", "Gitee"})
+			if err != nil {
+				return false, nil, err
+			}
+			mirror = strings.ToLower(repoChoice)
+		}
+		switch mirror {
+		case mirrorGitHub:
+			return false, []string{template.DefaultGitHubRepo}, nil
+		case mirrorGitee:
+			return false, []string{template.DefaultGiteeRepo}, nil
+		}
+		return false, nil, nil
+	default:
+		return false, []string{choice}, nil
+	}
+}
+
+// resolveRootPath returns the absolute project directory from the path argument or
+// --path, defaulting to the working directory
+func resolveRootPath(args []string) (string, error) {
+	path := initPath
+	if len(args) == 1 {
+		if path != "" && filepath.Clean(path) != filepath.Clean(args[0]) {
+			return "", fmt.Errorf("conflicting project paths %q and --path %q", args[0], path)
+		}
+		path = args[0]
+	}
+	if path == "" {
+		rootPath, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("get working directory: %w", err)
+		}
+		return rootPath, nil
+	}
+
+	rootPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", path, err)
+	}
+	info, err := os.Stat(rootPath)
+	if err != nil {
+		return "", fmt.Errorf("project directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", rootPath)
+	}
+	return rootPath, nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
+
+// detectionOverrides collects the project info given with --language, --framework,
+// --database and --type, which replace what detection finds
+func detectionOverrides() (domain.ProjectOverrides, error) {
+	normalize := func(value string) string { return strings.ToLower(strings.TrimSpace(value)) }
+	overrides := domain.ProjectOverrides{
+		Language:    normalize(initLanguage),
+		Framework:   normalize(initFramework),
+		Database:    normalize(initDatabase),
+		ProjectType: normalize(initType),
+	}
+	if overrides.ProjectType != "" && !slices.Contains(domain.ProjectTypes, overrides.ProjectType) {
+		return overrides, fmt.Errorf("unknown project type %q (expected %s)", initType, strings.Join(domain.ProjectTypes, ", "))
+	}
+	return overrides, nil
+}
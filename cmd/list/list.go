@@ -0,0 +1,113 @@
+package list
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+var (
+	listSection        string
+	listTag            string
+	listSince          string
+	listIncludeExpired bool
+	listFiles          []string
+	listAuthor         string
+	listReferences     string
+	listJSON           bool
+)
+
+func init() {
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Aliases:      []string{"ls"},
+		Short:        "List memory entries",
+		Long:         "List entries in .ohmymem/memory.md as a table, optionally filtered by section, tag, age and the files they are scoped to.\nReferences to other entries (ref:<id>) are shown with the tag of the entry they point to.",
+		Example:      "  ohmymem list --section constraints --tag API --since 30d\n  ohmymem list --file internal/api/handler.go\n  ohmymem list --author agent\n  ohmymem list --references 01a14476-da35",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+
+	listCmd.Flags().StringVarP(&listSection, "section", "s", "", "Only these sections (comma-separated)")
+	listCmd.Flags().StringVarP(&listTag, "tag", "t", "", "Only entries with this tag")
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only entries created within a period (30d, 2w, 12h) or since a date (YYYY-MM-DD)")
+	listCmd.Flags().StringSliceVar(&listFiles, "file", nil, "Only entries that apply to these files, relative to the project root; unscoped entries always apply")
+	listCmd.Flags().StringVar(&listAuthor, "author", "", "Only entries captured by people (user), by agents (agent), or by authors whose name contains this text")
+	listCmd.Flags().StringVar(&listReferences, "references", "", "Only entries referencing the entry with this ID or ID prefix (ref:<id>)")
+	listCmd.Flags().BoolVar(&listIncludeExpired, "include-expired", false, "Include entries whose expiry has passed")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print entries as JSON")
+
+	cmd.RegisterTagCompletion(listCmd)
+
+	cmd.RootCmd.AddCommand(listCmd)
+}
+
+func runList(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	filter, err := buildFilter(uc.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := uc.Service().ListEntries(c.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+
+	if listJSON {
+		return cmd.PrintJSON(usecase.ToEntriesJSON(entries))
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No entries found.")
+		return nil
+	}
+
+	all, err := uc.Service().ListEntries(c.Context(), domain.EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+	index := domain.NewRefIndex(all)
+	for i := range entries {
+		entries[i].Content = index.Describe(entries[i].Content)
+	}
+	return cmd.PrintEntryTable(os.Stdout, entries)
+}
+
+// buildFilter converts the command flags into an entry filter
+func buildFilter(now time.Time) (domain.EntryFilter, error) {
+	sections, err := domain.ParseSections(listSection)
+	if err != nil {
+		return domain.EntryFilter{}, err
+	}
+	since, err := domain.ParseSince(listSince, now)
+	if err != nil {
+		return domain.EntryFilter{}, err
+	}
+	return domain.EntryFilter{
+		Sections:       sections,
+		Tag:            listTag,
+		Since:          since,
+		Files:          listFiles,
+		Author:         listAuthor,
+		References:     listReferences,
+		IncludeExpired: listIncludeExpired,
+		Now:            now,
+	}, nil
+}
@@ -3,18 +3,44 @@ package mcp
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	"log/slog"
 
 	"github.com/herewei/ohmymem-core/cmd"
 	mcpapp "github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/adapters"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/transport"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 )
 
+var (
+	mcpProjects     []string
+	mcpProjectPaths bool
+	mcpCaptureRate  int
+	mcpCaptureBurst int
+	mcpNoAudit      bool
+	mcpTransport    string
+	mcpAddr         string
+
+	mcpShutdownTimeout time.Duration
+)
+
+// Supported transports
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+)
+
 func init() {
 	mcpCmd := &cobra.Command{
 		Use:   "mcp",
@@ -25,48 +51,145 @@ This server provides tools for reading and appending to the working memory file.
 			runServer()
 		},
 	}
+	mcpCmd.Flags().StringArrayVar(&mcpProjects, "project", nil, "Additional project to serve as name=path (repeatable); tools select it via the 'project' parameter")
+	mcpCmd.Flags().BoolVar(&mcpProjectPaths, "allow-project-paths", false, "Also let tools select any initialized project by absolute path")
+	mcpCmd.Flags().IntVar(&mcpCaptureRate, "capture-rate", mcpapp.DefaultCapturesPerMinute, "Maximum captures per minute per client (0 disables limiting)")
+	mcpCmd.Flags().IntVar(&mcpCaptureBurst, "capture-burst", mcpapp.DefaultCaptureBurst, "Captures allowed in a burst before rate limiting applies")
+	mcpCmd.Flags().BoolVar(&mcpNoAudit, "no-audit", false, "Do not record tool calls to .ohmymem/audit.log")
+	mcpCmd.Flags().StringVar(&mcpTransport, "transport", transportStdio, "Transport to serve: stdio or http")
+	mcpCmd.Flags().StringVar(&mcpAddr, "addr", transport.DefaultHTTPAddr, "Listen address for the http transport")
+	mcpCmd.Flags().DurationVar(&mcpShutdownTimeout, "shutdown-timeout", 10*time.Second, "How long to wait for in-flight tool calls on shutdown")
 	cmd.RootCmd.AddCommand(mcpCmd)
 }
 
 func runServer() {
 	basePath := "."
 
+	projects, err := parseProjects(mcpProjects)
+	if err != nil {
+		slog.Error("invalid --project flag", "error", err)
+		os.Exit(1)
+	}
+
 	// Create MCP server and file store
-	s, _, err := mcpapp.NewServer(basePath)
+	inFlight := mcpapp.NewInFlightTracker()
+	health := mcpapp.NewHealthChecker(basePath, adapters.NewSystemClock())
+	s, _, err := mcpapp.NewServer(basePath, mcpapp.ServerOptions{
+		Projects:          projects,
+		AllowProjectPaths: mcpProjectPaths,
+		CapturesPerMinute: mcpCaptureRate,
+		CaptureBurst:      mcpCaptureBurst,
+		DisableAudit:      mcpNoAudit,
+		InFlight:          inFlight,
+		Health:            health,
+	})
 	if err != nil {
 		slog.Error("failed to create server", "error", err)
 		os.Exit(1)
 	}
 
-	// Start stdio server with graceful shutdown support
+	serve, stop, err := newTransport(s, health)
+	if err != nil {
+		slog.Error("failed to configure transport", "error", err)
+		os.Exit(1)
+	}
+
+	// Start server with graceful shutdown support
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	errChan := make(chan error, 1)
+	doneChan := make(chan struct{})
 
 	go func() {
-		if err := server.ServeStdio(s); err != nil {
-			if !errors.Is(err, context.Canceled) {
-				errChan <- err
-			}
+		defer close(doneChan)
+		if err := serve(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errChan <- err
 		}
-		cancel()
 	}()
 
 	// Wait for interrupt signal or server error
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	select {
 	case err := <-errChan:
-		if err != nil {
-			slog.Error("server error", "error", err)
-			os.Exit(1)
-		}
+		slog.Error("server error", "error", err)
+		os.Exit(1)
 	case <-sigChan:
-		// Interrupt signal received
 		slog.Info("shutting down...")
-		os.Exit(0)
-	case <-ctx.Done():
-		// Server stopped normally
-		os.Exit(0)
+	case <-doneChan:
+		// Server stopped normally (e.g. stdin closed)
+	}
+
+	// Reject new tool calls and let in-flight writes release the memory lock
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), mcpShutdownTimeout)
+	defer drainCancel()
+	if err := inFlight.Drain(drainCtx); err != nil {
+		slog.Warn("shutdown timed out with tool calls still running", "timeout", mcpShutdownTimeout)
+	}
+	if err := stop(drainCtx); err != nil {
+		slog.Warn("failed to stop transport cleanly", "error", err)
+	}
+	cancel()
+	// Returning lets the root command flush logs
+}
+
+// newTransport returns the blocking serve loop and the stop function for the selected transport
+func newTransport(s *server.MCPServer, health *mcpapp.HealthChecker) (serve func(context.Context) error, stop func(context.Context) error, err error) {
+	switch mcpTransport {
+	case transportStdio:
+		stdio := server.NewStdioServer(s)
+		serve = func(ctx context.Context) error {
+			return stdio.Listen(ctx, os.Stdin, os.Stdout)
+		}
+		// Listen returns once the serve context is cancelled
+		stop = func(context.Context) error { return nil }
+		return serve, stop, nil
+	case transportHTTP:
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("load config: %w", err)
+		}
+		if cfg.MCP.AuthToken == "" && !transport.IsLoopbackAddr(mcpAddr) {
+			slog.Warn("serving without authentication on a non-loopback address; set "+config.EnvAuthToken+" or mcp.auth_token", "addr", mcpAddr)
+		}
+		httpServer := &http.Server{
+			Addr:    mcpAddr,
+			Handler: transport.NewHTTPHandler(s, cfg.MCP.AuthToken, mcpapp.NewHealthHandler(health)),
+		}
+		slog.Info("serving MCP over http", "addr", mcpAddr, "path", transport.MCPEndpointPath, "auth", cfg.MCP.AuthToken != "")
+		serve = func(context.Context) error {
+			if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		}
+		return serve, httpServer.Shutdown, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown transport %q (expected %s or %s)", mcpTransport, transportStdio, transportHTTP)
+	}
+}
+
+// parseProjects parses repeated name=path flags; a bare path is named after its directory
+func parseProjects(values []string) (map[string]string, error) {
+	projects := make(map[string]string, len(values))
+	for _, value := range values {
+		name, path, ok := strings.Cut(value, "=")
+		if !ok {
+			path = value
+			name = filepath.Base(filepath.Clean(value))
+		}
+		name = strings.TrimSpace(name)
+		path = strings.TrimSpace(path)
+		if name == "" || path == "" {
+			return nil, fmt.Errorf("expected name=path, got %q", value)
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("resolve project path %q: %w", path, err)
+		}
+		projects[name] = absPath
 	}
+	return projects, nil
 }
@@ -0,0 +1,59 @@
+package merge
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func init() {
+	mergeCmd := &cobra.Command{
+		Use:   "merge <ours> <theirs> <base>",
+		Short: "Merge two versions of memory.md entry by entry (git merge driver)",
+		Long: `Merge theirs into ours at entry granularity, using base as the common ancestor, and
+write the result to ours. Entries added on either side are kept, except additions from
+theirs that nearly duplicate an entry in the same section; edits and deletions from theirs
+are applied when ours left the entry untouched.
+
+Entries changed incompatibly on both sides are reported as conflicts: ours is kept (or
+theirs, when ours deleted an entry theirs edited) and the command exits with an error so
+git leaves the file marked as conflicted for review.
+
+To use it as a git merge driver:
+
+  git config merge.ohmymem.name "ohmymem entry-level merge"
+  git config merge.ohmymem.driver "ohmymem merge %A %B %O"
+  echo ".ohmymem/memory.md merge=ohmymem" >> .gitattributes`,
+		Args:         cobra.ExactArgs(3),
+		SilenceUsage: true,
+		RunE:         runMerge,
+	}
+
+	cmd.RootCmd.AddCommand(mergeCmd)
+}
+
+func runMerge(c *cobra.Command, args []string) error {
+	result, err := usecase.MergeFiles(args[0], args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range result.Duplicates {
+		fmt.Printf("Skipped duplicate %s %s %s\n", entry.ID, entry.Tag, cmd.Truncate(entry.Content, 60))
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Printf("Conflict %s: %s\n", conflict.Ours.ID, conflict.Reason)
+		fmt.Printf("  ours:   %s %s\n", conflict.Ours.Tag, conflict.Ours.Content)
+		fmt.Printf("  theirs: %s %s\n", conflict.Theirs.Tag, conflict.Theirs.Content)
+	}
+	if result.HasConflicts() {
+		return fmt.Errorf("%d conflicting entries need review", len(result.Conflicts))
+	}
+
+	fmt.Printf("✅ Merged: %d added, %d updated, %d removed, %d duplicates skipped.\n",
+		len(result.Added), len(result.Updated), len(result.Removed), len(result.Duplicates))
+	return nil
+}
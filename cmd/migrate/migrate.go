@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+var migrateDryRun bool
+
+func init() {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Convert legacy inline entries to the anchored format",
+		Long: `Convert legacy "* **[Tag]** content" bullets in .ohmymem/memory.md to anchored
+entries with generated IDs and timestamps, and upgrade the frontmatter schema_version.
+The original file is copied to .ohmymem/backups/ before it is rewritten.`,
+		Example:      "  ohmymem migrate --dry-run",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runMigrate,
+	}
+
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show what would be migrated without writing")
+
+	cmd.RootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	result, err := uc.Migrate(c.Context(), migrateDryRun)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	if !result.Changed() {
+		fmt.Println("Memory file is already up to date.")
+		return nil
+	}
+
+	if len(result.Entries) > 0 {
+		if err := cmd.PrintEntryTable(os.Stdout, result.Entries); err != nil {
+			return err
+		}
+	}
+
+	if migrateDryRun {
+		fmt.Printf("Dry run: %d legacy entries would be converted", len(result.Entries))
+		if result.SchemaUpgraded {
+			fmt.Print(" and the frontmatter schema upgraded")
+		}
+		fmt.Println(".")
+		return nil
+	}
+
+	fmt.Printf("✅ Migrated %d legacy entries", len(result.Entries))
+	if result.SchemaUpgraded {
+		fmt.Print(" and upgraded the frontmatter schema")
+	}
+	fmt.Printf("\n   Backup: %s\n", result.BackupPath)
+	return nil
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// maxContentWidth is the number of content characters shown per table row
+const maxContentWidth = 60
+
+// maxAuthorWidth is the number of author characters shown per table row
+const maxAuthorWidth = 24
+
+// ANSI colors for added, removed and changed entries
+const (
+	ColorAdded   = "\033[32m"
+	ColorRemoved = "\033[31m"
+	ColorChanged = "\033[33m"
+	colorReset   = "\033[0m"
+)
+
+// PrintJSON writes v to stdout as indented JSON
+func PrintJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// PrintEntryTable writes entries as an aligned table
+func PrintEntryTable(w io.Writer, entries []domain.Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSECTION\tTAG\tCREATED\tAUTHOR\tCONTENT")
+	for _, entry := range entries {
+		created := "-"
+		if !entry.CreatedAt.IsZero() {
+			created = entry.CreatedAt.Local().Format(time.DateOnly)
+		}
+		author := "-"
+		if entry.Author != "" {
+			author = Truncate(entry.Author, maxAuthorWidth)
+		}
+		content := Truncate(entry.Content, maxContentWidth)
+		if !entry.IsActive() {
+			content = fmt.Sprintf("(%s) %s", entry.Status, content)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.Origin(), entry.Section, entry.Tag, created, author, content)
+	}
+	return tw.Flush()
+}
+
+// PrintProjectInfo writes the detected stack, one indented line per field, with the
+// other framework and database candidates and the secondary stacks of a monorepo
+func PrintProjectInfo(w io.Writer, indent string, info *domain.ProjectInfo) {
+	fmt.Fprintf(w, "%sLanguage:   %s\n", indent, info.Language)
+	if info.Framework != "" {
+		fmt.Fprintf(w, "%sFramework:  %s%s\n", indent, info.Framework, otherCandidates(info, "framework"))
+	}
+	if info.Database != "" {
+		fmt.Fprintf(w, "%sDatabase:   %s%s\n", indent, info.Database, otherCandidates(info, "database"))
+	}
+	if info.ProjectType != "" {
+		fmt.Fprintf(w, "%sType:       %s\n", indent, info.ProjectType)
+	}
+	if len(info.Modules) > 0 {
+		fmt.Fprintf(w, "%sModules:    %s\n", indent, strings.Join(info.Modules, ", "))
+	}
+	for _, stack := range info.Secondary {
+		fmt.Fprintf(w, "%sAlso:       %s\n", indent, describeStack(stack))
+	}
+	if len(info.Features) > 0 {
+		fmt.Fprintf(w, "%sFeatures:   %s\n", indent, strings.Join(info.Features, ", "))
+	}
+}
+
+// otherCandidates lists the candidates of kind other than the detected value
+func otherCandidates(info *domain.ProjectInfo, kind string) string {
+	var others []string
+	for _, c := range info.CandidatesOf(kind) {
+		if c.Value != info.Framework && c.Value != info.Database {
+			others = append(others, fmt.Sprintf("%s %.0f%%", c.Value, c.Confidence*100))
+		}
+	}
+	if len(others) == 0 {
+		return ""
+	}
+	return " (also: " + strings.Join(others, ", ") + ")"
+}
+
+// describeStack formats a secondary stack of a multi-language repository
+func describeStack(stack domain.ProjectStack) string {
+	parts := []string{stack.Language}
+	if stack.Framework != "" {
+		parts = append(parts, stack.Framework)
+	}
+	if stack.Database != "" {
+		parts = append(parts, stack.Database)
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(parts, ", "), stack.Path)
+}
+
+// PrintEvidence writes the detection evidence as an aligned table, each row indented,
+// one conclusion per row with the file or dependency it comes from
+func PrintEvidence(w io.Writer, indent string, evidence []domain.DetectionEvidence) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%sKIND\tVALUE\tCONFIDENCE\tSOURCE\n", indent)
+	for _, e := range evidence {
+		confidence := "-"
+		if e.Confidence > 0 {
+			confidence = fmt.Sprintf("%.0f%%", e.Confidence*100)
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\n", indent, e.Kind, e.Value, confidence, e.Source)
+	}
+	return tw.Flush()
+}
+
+// Truncate shortens s to at most width runes, marking the cut with an ellipsis
+func Truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:width-1])) + "…"
+}
+
+// UseColor reports whether stdout is a terminal that should receive ANSI colors
+func UseColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Paint wraps text in an ANSI color when color is enabled
+func Paint(color bool, code, text string) string {
+	if !color {
+		return text
+	}
+	return code + text + colorReset
+}
@@ -0,0 +1,153 @@
+package prune
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+var (
+	pruneOlderThan  string
+	pruneExpired    bool
+	pruneDeprecated bool
+	pruneSection    string
+	pruneTag        string
+	pruneArchive    bool
+	pruneDryRun     bool
+	pruneYes        bool
+)
+
+func init() {
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove or archive old, expired or deprecated entries",
+		Long: `Remove entries that are older than a given age, expired, or deprecated. An entry
+is pruned when it matches any of --older-than, --expired and --deprecated, within the
+--section and --tag scope. The selected entries are listed before confirmation.
+
+With --archive, entries are moved to .ohmymem/archive/pruned-YYYY-MM.md instead of
+being deleted.`,
+		Example: `  ohmymem prune --older-than 180d --section note --archive
+  ohmymem prune --expired --deprecated --yes`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runPrune,
+	}
+
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "", "Entries created before a period ago (180d, 4w) or a date (YYYY-MM-DD)")
+	pruneCmd.Flags().BoolVar(&pruneExpired, "expired", false, "Entries whose expiry has passed")
+	pruneCmd.Flags().BoolVar(&pruneDeprecated, "deprecated", false, "Entries marked deprecated or superseded")
+	pruneCmd.Flags().StringVarP(&pruneSection, "section", "s", "", "Only these sections (comma-separated)")
+	pruneCmd.Flags().StringVarP(&pruneTag, "tag", "t", "", "Only entries with this tag")
+	pruneCmd.Flags().BoolVar(&pruneArchive, "archive", false, "Move entries to the archive instead of deleting them")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List the entries that would be pruned without changing anything")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Skip confirmation")
+
+	cmd.RegisterTagCompletion(pruneCmd)
+
+	cmd.RootCmd.AddCommand(pruneCmd)
+}
+
+func runPrune(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+	svc := uc.Service()
+	ctx := c.Context()
+	now := uc.Now()
+
+	sections, err := domain.ParseSections(pruneSection)
+	if err != nil {
+		return err
+	}
+	olderThan, err := domain.ParseSince(pruneOlderThan, now)
+	if err != nil {
+		return err
+	}
+	criteria := domain.PruneCriteria{
+		OlderThan:  olderThan,
+		Expired:    pruneExpired,
+		Deprecated: pruneDeprecated,
+		Sections:   sections,
+		Tag:        pruneTag,
+		Now:        now,
+	}
+	if criteria.IsEmpty() {
+		return fmt.Errorf("specify at least one of --older-than, --expired or --deprecated")
+	}
+
+	selected, err := svc.SelectPrunable(ctx, criteria)
+	if err != nil {
+		return fmt.Errorf("select entries: %w", err)
+	}
+	if len(selected) == 0 {
+		fmt.Println("No entries to prune.")
+		return nil
+	}
+
+	if err := cmd.PrintEntryTable(os.Stdout, selected); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	action := "Delete"
+	if pruneArchive {
+		action = "Archive"
+	}
+	if pruneDryRun {
+		fmt.Printf("Dry run: %d entries would be pruned (%s).\n", len(selected), action)
+		return nil
+	}
+
+	if !pruneYes {
+		confirmed, err := huh.Confirm(fmt.Sprintf("%s %d entries?", action, len(selected)), false)
+		if err != nil {
+			if errors.Is(err, huh.ErrCancelled) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if pruneArchive {
+		archiveName := "pruned-" + now.Format("2006-01")
+		archived, err := svc.ArchiveEntries(ctx, selected, archiveName)
+		if err != nil {
+			return fmt.Errorf("archive entries: %w", err)
+		}
+		fmt.Printf("✅ Archived %d entries to %s\n", len(archived),
+			filepath.Join(persistence.DirName, persistence.ArchiveDirName, archiveName+".md"))
+		return nil
+	}
+
+	ids := make([]string, 0, len(selected))
+	for _, entry := range selected {
+		ids = append(ids, entry.ID)
+	}
+	deleted, err := svc.DeleteEntries(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("delete entries: %w", err)
+	}
+	fmt.Printf("✅ Deleted %d entries.\n", len(deleted))
+	return nil
+}
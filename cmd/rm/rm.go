@@ -0,0 +1,145 @@
+package rm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+)
+
+var (
+	rmSection string
+	rmTag     string
+	rmSoft    bool
+	rmYes     bool
+)
+
+func init() {
+	rmCmd := &cobra.Command{
+		Use:   "rm [entry-id...]",
+		Short: "Remove memory entries",
+		Long: `Remove entries from .ohmymem/memory.md by ID (a unique prefix is enough), or
+select entries in bulk with --tag and --section. Bulk removal asks for confirmation.
+With --soft, entries are marked deprecated instead of being removed.`,
+		Example: `  ohmymem rm 01a14476-da35
+  ohmymem rm --tag Legacy --section patterns --soft`,
+		SilenceUsage: true,
+		RunE:         runRm,
+	}
+
+	rmCmd.Flags().StringVarP(&rmSection, "section", "s", "", "Select entries in these sections (comma-separated)")
+	rmCmd.Flags().StringVarP(&rmTag, "tag", "t", "", "Select entries with this tag")
+	rmCmd.Flags().BoolVar(&rmSoft, "soft", false, "Mark entries deprecated instead of removing them")
+	rmCmd.Flags().BoolVarP(&rmYes, "yes", "y", false, "Skip confirmation for bulk selection")
+
+	cmd.RegisterTagCompletion(rmCmd)
+
+	cmd.RootCmd.AddCommand(rmCmd)
+}
+
+func runRm(c *cobra.Command, args []string) error {
+	bulk := rmSection != "" || rmTag != ""
+	if len(args) == 0 && !bulk {
+		return fmt.Errorf("specify entry IDs or select entries with --tag/--section")
+	}
+	if len(args) > 0 && bulk {
+		return fmt.Errorf("entry IDs cannot be combined with --tag/--section")
+	}
+
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+	svc := uc.Service()
+	ctx := c.Context()
+
+	var selected []domain.Entry
+	if bulk {
+		sections, err := domain.ParseSections(rmSection)
+		if err != nil {
+			return err
+		}
+		entries, err := svc.ListEntries(ctx, domain.EntryFilter{Sections: sections, Tag: rmTag, IncludeExpired: true})
+		if err != nil {
+			return fmt.Errorf("list entries: %w", err)
+		}
+		// Template and legacy entries have no ID to remove them by
+		for _, entry := range entries {
+			if entry.ID != "" {
+				selected = append(selected, entry)
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Println("No entries match the selection.")
+			return nil
+		}
+	} else {
+		for _, id := range args {
+			entry, err := svc.FindEntry(ctx, id)
+			if err != nil {
+				return err
+			}
+			selected = append(selected, *entry)
+		}
+	}
+
+	if bulk && !rmYes {
+		if err := cmd.PrintEntryTable(os.Stdout, selected); err != nil {
+			return err
+		}
+		fmt.Println()
+		action := "Remove"
+		if rmSoft {
+			action = "Deprecate"
+		}
+		confirmed, err := huh.Confirm(fmt.Sprintf("%s %d entries?", action, len(selected)), false)
+		if err != nil {
+			if errors.Is(err, huh.ErrCancelled) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if rmSoft {
+		deprecated, err := svc.DeprecateEntries(ctx, selected)
+		if err != nil {
+			return fmt.Errorf("deprecate entries: %w", err)
+		}
+		for _, entry := range deprecated {
+			fmt.Printf("   Deprecated: %s %s %s\n", entry.ID, entry.Tag, cmd.Truncate(entry.Content, 60))
+		}
+		fmt.Printf("✅ Deprecated %d entries.\n", len(deprecated))
+		return nil
+	}
+
+	ids := make([]string, 0, len(selected))
+	for _, entry := range selected {
+		ids = append(ids, entry.ID)
+	}
+	deleted, err := svc.DeleteEntries(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("remove entries: %w", err)
+	}
+	for _, entry := range deleted {
+		fmt.Printf("   Removed: %s %s %s\n", entry.ID, entry.Tag, cmd.Truncate(entry.Content, 60))
+	}
+	fmt.Printf("✅ Removed %d entries.\n", len(deleted))
+	return nil
+}
@@ -0,0 +1,161 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ANSI sequences used to highlight matches on a terminal
+const (
+	highlightStart = "\033[1;33m"
+	highlightEnd   = "\033[0m"
+)
+
+var (
+	searchSection        string
+	searchTag            string
+	searchContext        int
+	searchIncludeExpired bool
+	searchSemantic       bool
+	searchJSON           bool
+	searchNoColor        bool
+)
+
+func init() {
+	searchCmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search memory entries",
+		Long: `Search entries in .ohmymem/memory.md. An entry matches when every word of the
+query appears (case-insensitive) in its content, rationale or tag. Matches are ranked
+like the ohmymem_search MCP tool: by relevance (BM25 over content, tag and rationale),
+with a boost for recently captured entries.
+
+With --semantic, entries are ranked by similarity of meaning to the query instead,
+using the embedding provider configured with 'ohmymem config set embeddings.provider'.`,
+		Example:      "  ohmymem search postgres --section decisions\n  ohmymem search --semantic anything about caching",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runSearch,
+	}
+
+	searchCmd.Flags().StringVarP(&searchSection, "section", "s", "", "Only these sections (comma-separated)")
+	searchCmd.Flags().StringVarP(&searchTag, "tag", "t", "", "Only entries with this tag")
+	searchCmd.Flags().IntVarP(&searchContext, "context", "C", 40, "Characters of context shown around matches (0 shows the whole entry)")
+	searchCmd.Flags().BoolVar(&searchIncludeExpired, "include-expired", false, "Include entries whose expiry has passed")
+	searchCmd.Flags().BoolVar(&searchSemantic, "semantic", false, "Match entries by meaning with the configured embedding provider")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Print matches as JSON")
+	searchCmd.Flags().BoolVar(&searchNoColor, "no-color", false, "Disable match highlighting")
+
+	cmd.RegisterTagCompletion(searchCmd)
+
+	cmd.RootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	sections, err := domain.ParseSections(searchSection)
+	if err != nil {
+		return err
+	}
+	filter := domain.EntryFilter{
+		Sections:       sections,
+		Tag:            searchTag,
+		IncludeExpired: searchIncludeExpired,
+		Now:            uc.Now(),
+	}
+
+	query := strings.Join(args, " ")
+	var hits []domain.SearchHit
+	if searchSemantic {
+		semantic, err := uc.Semantic()
+		if err != nil {
+			return err
+		}
+		hits, err = semantic.Search(c.Context(), query, filter)
+		if err != nil {
+			return fmt.Errorf("search: %w", err)
+		}
+	} else if hits, err = uc.Service().Search(c.Context(), query, filter); err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+
+	if searchJSON {
+		return cmd.PrintJSON(usecase.ToSearchHitsJSON(hits))
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("No entries match %q.\n", query)
+		return nil
+	}
+
+	color := !searchNoColor && cmd.UseColor()
+	for _, hit := range hits {
+		entry := hit.Entry
+		created := ""
+		if !entry.CreatedAt.IsZero() {
+			created = "  " + entry.CreatedAt.Local().Format(time.DateOnly)
+		}
+		fmt.Printf("%s  %s  %s%s\n", entry.ID, entry.Section, entry.Tag, created)
+		fmt.Printf("   %s\n", snippet(entry.Content, hit.ContentMatches, searchContext, color))
+		if len(hit.RationaleMatches) > 0 {
+			fmt.Printf("   Rationale: %s\n", snippet(entry.Rationale, hit.RationaleMatches, searchContext, color))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d matching entries.\n", len(hits))
+	return nil
+}
+
+// snippet returns the text around the matches with the matches highlighted.
+// Text further than context bytes from any match is elided.
+func snippet(text string, spans []domain.Span, context int, color bool) string {
+	if len(spans) == 0 {
+		if context > 0 && len(text) > 2*context {
+			return text[:2*context] + "…"
+		}
+		return text
+	}
+
+	start, end := 0, len(text)
+	if context > 0 {
+		start = max(0, spans[0].Start-context)
+		end = min(len(text), spans[len(spans)-1].End+context)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, span := range spans {
+		sb.WriteString(text[pos:span.Start])
+		if color {
+			sb.WriteString(highlightStart + text[span.Start:span.End] + highlightEnd)
+		} else {
+			sb.WriteString("[" + text[span.Start:span.End] + "]")
+		}
+		pos = span.End
+	}
+	sb.WriteString(text[pos:end])
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
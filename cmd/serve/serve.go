@@ -0,0 +1,113 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/transport"
+)
+
+// defaultAddr is the default listen address (loopback only, next to the MCP http transport)
+const defaultAddr = "127.0.0.1:8766"
+
+var (
+	serveAddr            string
+	serveShutdownTimeout time.Duration
+)
+
+func init() {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the memory over a REST API",
+		Long: `Start an HTTP server exposing the memory as a JSON REST API, so dashboards and
+tools that don't speak MCP can read and write entries:
+
+  GET    /memory           all sections (?format=markdown for the raw file,
+                           ?include_expired=true to keep expired entries,
+                           ?file=<path> to drop entries scoped to other paths)
+  GET    /sections/{name}  one section (constraints, decisions, patterns, anti-patterns, note)
+  POST   /entries          add an entry: {"section", "tag", "content", "rationale",
+                           "expires_at", "ttl_days", "scope", "force", "supersedes",
+                           "author", "context", "options", "consequences"}
+  DELETE /entries/{id}     delete an entry by ID or unique prefix
+
+Entries are validated like 'ohmymem add' and must be posted as application/json;
+writes from another origin (e.g. a web page in a browser) are refused. When mcp.auth_token (or ` + config.EnvAuthToken + `)
+is set, every request must carry "Authorization: Bearer <token>".`,
+		Example: `  ohmymem serve
+  ohmymem serve --addr 0.0.0.0:8766
+  curl -X POST localhost:8766/entries -H 'Content-Type: application/json' -d '{"section":"decisions","content":"Use PostgreSQL","rationale":"Need JSONB"}'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runServe,
+	}
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", defaultAddr, "Listen address")
+	serveCmd.Flags().DurationVar(&serveShutdownTimeout, "shutdown-timeout", 10*time.Second, "How long to wait for in-flight requests on shutdown")
+
+	cmd.RootCmd.AddCommand(serveCmd)
+}
+
+func runServe(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	handler := usecase.NewRESTHandler(uc)
+	if cfg.MCP.AuthToken != "" {
+		handler = transport.BearerAuth(cfg.MCP.AuthToken, handler)
+	} else if !transport.IsLoopbackAddr(serveAddr) {
+		slog.Warn("serving without authentication on a non-loopback address; set "+config.EnvAuthToken+" or mcp.auth_token", "addr", serveAddr)
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", serveAddr, err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s (Ctrl+C to stop)\n", uc.Service().GetMemoryPath(), listener.Addr())
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("shut down: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package show
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// idColor dims entry IDs so they don't compete with the content
+const idColor = "243"
+
+// sectionColors gives each section header its own ANSI 256 color
+var sectionColors = map[domain.SectionType]string{
+	domain.SectionConstraints:  "203",
+	domain.SectionDecisions:    "75",
+	domain.SectionPatterns:     "114",
+	domain.SectionAntiPatterns: "214",
+	domain.SectionNote:         "250",
+}
+
+var (
+	showSection        string
+	showIncludeExpired bool
+	showWidth          int
+	showPlain          bool
+)
+
+func init() {
+	showCmd := &cobra.Command{
+		Use:          "show",
+		Short:        "Show the memory as rendered Markdown",
+		Long:         "Render .ohmymem/memory.md for the terminal, with colored sections and dimmed entry IDs.",
+		Example:      "  ohmymem show --section constraints,decisions",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runShow,
+	}
+
+	showCmd.Flags().StringVarP(&showSection, "section", "s", "", "Only these sections (comma-separated)")
+	showCmd.Flags().BoolVar(&showIncludeExpired, "include-expired", false, "Include entries whose expiry has passed")
+	showCmd.Flags().IntVarP(&showWidth, "width", "w", 100, "Wrap width")
+	showCmd.Flags().BoolVar(&showPlain, "plain", false, "Print plain Markdown without styling")
+
+	cmd.RootCmd.AddCommand(showCmd)
+}
+
+func runShow(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	sections, err := domain.ParseSections(showSection)
+	if err != nil {
+		return err
+	}
+	if len(sections) == 0 {
+		sections = domain.ValidSections()
+	}
+
+	entries, err := uc.Service().ListEntries(c.Context(), domain.EntryFilter{
+		Sections:       sections,
+		IncludeExpired: showIncludeExpired,
+		Now:            uc.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("Memory is empty.")
+		return nil
+	}
+	all, err := uc.Service().ListEntries(c.Context(), domain.EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+	index := domain.NewRefIndex(all)
+
+	styled := !showPlain && isTerminal()
+	var renderer *glamour.TermRenderer
+	if styled {
+		renderer, err = glamour.NewTermRenderer(
+			glamour.WithStyles(entryStyle()),
+			glamour.WithWordWrap(showWidth),
+		)
+		if err != nil {
+			return fmt.Errorf("create renderer: %w", err)
+		}
+	}
+
+	for _, section := range sections {
+		body := sectionMarkdown(section, entries, index)
+		if body == "" {
+			continue
+		}
+
+		if !styled {
+			fmt.Printf("## %s\n\n%s\n", section.Title(), body)
+			continue
+		}
+
+		header := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(sectionColors[section])).
+			MarginLeft(2).
+			Render("▌ " + section.Title())
+		rendered, err := renderer.Render(body)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", section, err)
+		}
+		fmt.Println(header)
+		fmt.Print(rendered)
+	}
+	return nil
+}
+
+// sectionMarkdown renders the entries of one section as a Markdown list, IDs as inline code
+// followed by the author when recorded, then the decision record and the referenced
+// entries as a nested list
+func sectionMarkdown(section domain.SectionType, entries []domain.Entry, index domain.RefIndex) string {
+	var sb strings.Builder
+	for _, entry := range entries {
+		if entry.Section != section {
+			continue
+		}
+		if entry.IsSuperseded() {
+			// Collapsed to one line pointing at the replacement
+			fmt.Fprintf(&sb, "* ~~**%s** %s~~ *(%s)*  \n  `%s`\n", entry.Tag, domain.EscapeContent(entry.Content), entry.StatusLabel(), entry.ID)
+			continue
+		}
+		status := ""
+		if !entry.IsActive() {
+			status = fmt.Sprintf("*(%s)* ", entry.Status)
+		}
+		fmt.Fprintf(&sb, "* **%s** %s%s  \n", entry.Tag, status, domain.EscapeContent(entry.Content))
+		if entry.Rationale != "" {
+			fmt.Fprintf(&sb, "  *Rationale: %s*  \n", domain.EscapeContent(entry.Rationale))
+		}
+		fmt.Fprintf(&sb, "  `%s`", entry.ID)
+		if entry.Author != "" {
+			fmt.Fprintf(&sb, " · *by %s*", domain.EscapeContent(entry.Author))
+		}
+		sb.WriteString("\n")
+		sb.WriteString(domain.RenderDecisionRecord(entry.ADR))
+
+		referenced, missing := index.Referenced(entry)
+		for _, target := range referenced {
+			fmt.Fprintf(&sb, "  * ↳ **%s** %s *(%s)* `%s`\n", target.Tag, domain.EscapeContent(cmd.Truncate(target.Content, 60)), target.Section, domain.ShortID(target.ID))
+		}
+		for _, ref := range missing {
+			fmt.Fprintf(&sb, "  * ↳ *missing entry* `%s`\n", ref)
+		}
+	}
+	return sb.String()
+}
+
+// entryStyle is the glamour theme matching the terminal background, with inline code dimmed for IDs
+func entryStyle() ansi.StyleConfig {
+	style := styles.LightStyleConfig
+	if lipgloss.HasDarkBackground() {
+		style = styles.DarkStyleConfig
+	}
+	color := idColor
+	style.Code.Color = &color
+	style.Code.BackgroundColor = nil
+	style.Code.Prefix = ""
+	style.Code.Suffix = ""
+	return style
+}
+
+// isTerminal reports whether stdout is a terminal
+func isTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
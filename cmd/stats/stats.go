@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+// barWidth is the width of the longest bar of the growth chart
+const barWidth = 40
+
+var (
+	statsByTag     bool
+	statsBySection bool
+	statsTop       int
+	statsJSON      bool
+)
+
+func init() {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show entry growth, breakdowns by tag and section, and the largest entries",
+		Long: `Analyse the memory to help decide what to prune: entries added per month (from the
+UUIDv7 entry IDs), entry counts and average content length per section and tag, and
+the largest entries. --by-tag and --by-section show only those breakdowns.`,
+		Example:      "  ohmymem stats\n  ohmymem stats --by-tag --top 20",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runStats,
+	}
+
+	statsCmd.Flags().BoolVar(&statsByTag, "by-tag", false, "Show only the breakdown by tag")
+	statsCmd.Flags().BoolVar(&statsBySection, "by-section", false, "Show only the breakdown by section")
+	statsCmd.Flags().IntVar(&statsTop, "top", usecase.DefaultStatsTop, "Number of tags and largest entries to show (0 for all)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the report as JSON")
+
+	cmd.RootCmd.AddCommand(statsCmd)
+}
+
+func runStats(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	report, err := uc.Stats(c.Context(), statsTop)
+	if err != nil {
+		return err
+	}
+
+	if statsJSON {
+		return cmd.PrintJSON(report)
+	}
+	if report.TotalEntries == 0 {
+		fmt.Println("No entries.")
+		return nil
+	}
+
+	all := !statsByTag && !statsBySection
+	if all {
+		fmt.Printf("Entries:    %d (average %.1f characters)\n", report.TotalEntries, report.AvgLength)
+		printGrowth(report)
+	}
+	if all || statsBySection {
+		fmt.Println("\nBy section:")
+		if err := printGroups(report.BySection, "%s"); err != nil {
+			return err
+		}
+	}
+	if all || statsByTag {
+		fmt.Println("\nBy tag:")
+		if err := printGroups(report.ByTag, "[%s]"); err != nil {
+			return err
+		}
+	}
+	if all {
+		fmt.Println("\nLargest entries:")
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, entry := range report.Largest {
+			id := entry.ID
+			if id == "" {
+				id = "-"
+			}
+			fmt.Fprintf(tw, "  %d\t%s\t[%s]\t%s\n", entry.Length, id, entry.Tag, cmd.Truncate(entry.Content, 60))
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+// printGrowth draws the entries added per month as a bar chart
+func printGrowth(report *usecase.StatsReport) {
+	if len(report.Growth) == 0 {
+		return
+	}
+	peak := 0
+	for _, point := range report.Growth {
+		peak = max(peak, point.Added)
+	}
+
+	fmt.Println("\nGrowth:")
+	for _, point := range report.Growth {
+		bar := strings.Repeat("█", max(point.Added*barWidth/peak, 1))
+		fmt.Printf("  %s  %-*s %d (total %d)\n", point.Month, barWidth, bar, point.Added, point.Total)
+	}
+	if report.Undated > 0 {
+		fmt.Printf("  %d entries without a timestamp\n", report.Undated)
+	}
+}
+
+// printGroups writes section or tag breakdowns as a table
+func printGroups(groups []usecase.GroupStats, nameFormat string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, group := range groups {
+		fmt.Fprintf(tw, "  "+nameFormat+"\t%d\tavg %.1f chars\n", group.Name, group.Entries, group.AvgLength)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,148 @@
+package status
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+var (
+	statusTop  int
+	statusJSON bool
+)
+
+func init() {
+	statusCmd := &cobra.Command{
+		Use:          "status",
+		Short:        "Show a summary of the project memory",
+		Long:         "Print the base path, schema version, entry counts and estimated tokens per section, top tags, file size, last modification time and the state of the AGENTS.md block. Warns when the memory costs more tokens than budget.max_tokens.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runStatus,
+	}
+
+	statusCmd.Flags().IntVar(&statusTop, "top", usecase.DefaultTopTags, "Number of top tags to show")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the status as JSON")
+
+	cmd.RootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	report, err := uc.Status(c.Context(), statusTop)
+	if err != nil {
+		return err
+	}
+
+	if statusJSON {
+		return cmd.PrintJSON(report)
+	}
+
+	schema := report.SchemaVersion
+	if schema == "" {
+		schema = "unknown (run 'ohmymem doctor')"
+	} else if report.EntryFormat != "" {
+		schema += " (" + report.EntryFormat + ")"
+	}
+
+	fmt.Printf("Base path:  %s\n", report.BasePath)
+	fmt.Printf("Memory:     %s\n", report.MemoryPath)
+	fmt.Printf("Schema:     %s\n", schema)
+	fmt.Printf("Size:       %s\n", formatSize(report.SizeBytes))
+	fmt.Printf("Tokens:     %s\n", tokensSummary(report))
+	fmt.Printf("Modified:   %s\n", report.ModifiedAt.Local().Format(time.DateTime))
+	fmt.Printf("AGENTS.md:  %s\n", agentsSummary(report.Agents))
+	if report.Stack != nil {
+		fmt.Printf("Stack:      %s\n", stackSummary(report.Stack))
+	}
+
+	fmt.Printf("\nEntries:    %d\n", report.TotalEntries)
+	for _, section := range report.Sections {
+		var notes []string
+		if section.Expired > 0 {
+			notes = append(notes, fmt.Sprintf("%d expired", section.Expired))
+		}
+		if section.Deprecated > 0 {
+			notes = append(notes, fmt.Sprintf("%d deprecated", section.Deprecated))
+		}
+		notes = append(notes, fmt.Sprintf("~%d tokens", section.Tokens))
+		fmt.Printf("  %-14s %d (%s)\n", section.Section, section.Entries, strings.Join(notes, ", "))
+	}
+
+	if len(report.TopTags) > 0 {
+		tags := make([]string, 0, len(report.TopTags))
+		for _, tag := range report.TopTags {
+			tags = append(tags, fmt.Sprintf("[%s] %d", tag.Name, tag.Count))
+		}
+		fmt.Printf("\nTop tags:   %s\n", strings.Join(tags, ", "))
+	}
+
+	if report.OverBudget {
+		fmt.Printf("\n⚠️  The memory costs ~%d tokens, over the budget of %d: prune, archive or dedupe entries, or have agents use ohmymem_summarize\n", report.Tokens, report.TokenBudget)
+	}
+	return nil
+}
+
+// tokensSummary describes the estimated tokens of the memory and the budget, if any
+func tokensSummary(report *usecase.StatusReport) string {
+	summary := fmt.Sprintf("~%d", report.Tokens)
+	if report.TokenBudget > 0 {
+		summary += fmt.Sprintf(" of a %d token budget (%d%%)", report.TokenBudget, report.Tokens*100/report.TokenBudget)
+	}
+	return summary
+}
+
+// agentsSummary describes the AGENTS.md block in one line
+func agentsSummary(agents usecase.AgentsBlockStatus) string {
+	if !agents.Present {
+		return "no ohmymem block (run 'ohmymem doctor --fix')"
+	}
+	summary := "block present"
+	if agents.UpToDate {
+		summary += ", up to date"
+	} else {
+		summary += ", boot protocol outdated"
+	}
+	if agents.LastUpdated != "" {
+		summary += ", last updated " + agents.LastUpdated
+	}
+	return summary
+}
+
+// stackSummary describes the saved project stack in one line
+func stackSummary(stack *usecase.StackStatus) string {
+	parts := []string{strings.Join(stack.Languages, ", ")}
+	for _, value := range []string{stack.Framework, stack.Database, stack.ProjectType} {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " · ") + ", detected " + stack.DetectedAt.Local().Format(time.DateTime)
+}
+
+// formatSize renders a byte count in B, KB or MB
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
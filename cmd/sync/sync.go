@@ -0,0 +1,142 @@
+package synccmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/remote"
+)
+
+// Sides a conflict can be resolved in favour of
+const (
+	preferLocal  = "local"
+	preferRemote = "remote"
+)
+
+var (
+	syncRemote string
+	syncBranch string
+	syncPrefer string
+	syncDryRun bool
+)
+
+func init() {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Pull and push the memory to a shared remote, merging entry by entry",
+		Long: `Share the memory across machines and teammates through a remote:
+
+  - a git remote name or URL: memory.md is stored alone on a dedicated branch
+    (sync.branch, default ` + config.DefaultSyncBranch + `), so project branches are untouched
+  - an http(s) URL: GET returns the memory file with an ETag, PUT replaces it and
+    must honour If-Match; sync.token (or ` + config.EnvSyncToken + `) is sent as a bearer token
+
+Each sync pulls the remote file, merges it with the local one at entry granularity,
+using the version of the last sync (.ohmymem/` + usecase.SyncBaseFileName + `) as the common ancestor,
+then writes the result locally and pushes it. Entries changed on both sides are
+reported as conflicts and keep the local version, or the remote one with --prefer remote.
+
+A git remote set in the project config alone is not used, as git remotes can run
+commands: set it in the global config too, or pass it with --remote.
+
+Only memory.md is synced; config, audit log and archives stay local.`,
+		Example: `  ohmymem config set sync.remote origin
+  ohmymem sync
+  ohmymem sync --remote https://memory.example.com/team/memory.md --dry-run`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runSync,
+	}
+
+	syncCmd.Flags().StringVar(&syncRemote, "remote", "", "Git remote or http(s) URL (default: sync.remote)")
+	syncCmd.Flags().StringVar(&syncBranch, "branch", "", "Branch of a git remote (default: sync.branch)")
+	syncCmd.Flags().StringVar(&syncPrefer, "prefer", preferLocal, "Side kept for entries changed on both: local or remote")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would be pulled and pushed without changing anything")
+
+	cmd.RootCmd.AddCommand(syncCmd)
+}
+
+func runSync(c *cobra.Command, args []string) error {
+	if syncPrefer != preferLocal && syncPrefer != preferRemote {
+		return fmt.Errorf("invalid --prefer %q (expected %s or %s)", syncPrefer, preferLocal, preferRemote)
+	}
+
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	location, branch := cfg.Sync.Remote, cfg.Sync.Branch
+	if syncRemote != "" {
+		location = syncRemote
+	} else if untrusted := cfg.UntrustedSyncRemote(); untrusted != "" {
+		// Git remotes can run commands, so the project config cannot pick one alone
+		return fmt.Errorf("git sync.remote %q is only set by the project config; pass --remote %q or set it in %s to trust it", untrusted, untrusted, config.GetConfigPath())
+	}
+	if syncBranch != "" {
+		branch = syncBranch
+	}
+	r, err := remote.New(rootPath, location, branch, cfg.Sync.Token)
+	if err != nil {
+		return err
+	}
+
+	result, err := uc.Sync(c.Context(), r, usecase.SyncOptions{
+		PreferRemote: syncPrefer == preferRemote,
+		DryRun:       syncDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	printChanges("Pull", result.Pulled)
+	printChanges("Push", result.Pushed)
+	kept := "local"
+	if syncPrefer == preferRemote {
+		kept = "remote"
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Printf("Conflict %s: %s, kept %s %s\n", conflict.Ours.ID, conflict.Reason, kept, cmd.Truncate(conflict.Ours.Content, 60))
+	}
+
+	summary := fmt.Sprintf("%d pulled, %d pushed, %d conflicts", changeCount(result.Pulled), changeCount(result.Pushed), len(result.Conflicts))
+	if syncDryRun {
+		fmt.Printf("Dry run against %s: %s.\n", r, summary)
+		return nil
+	}
+	fmt.Printf("✅ Synced with %s: %s.\n", r, summary)
+	return nil
+}
+
+// printChanges lists the entries a direction of the sync adds, removes or changes
+func printChanges(direction string, diff domain.EntryDiff) {
+	for _, entry := range diff.Added {
+		fmt.Printf("%s + %s %s\n", direction, entry.Tag, cmd.Truncate(entry.Content, 60))
+	}
+	for _, entry := range diff.Removed {
+		fmt.Printf("%s - %s %s\n", direction, entry.Tag, cmd.Truncate(entry.Content, 60))
+	}
+	for _, change := range diff.Changed {
+		fmt.Printf("%s ~ %s %s\n", direction, change.New.Tag, cmd.Truncate(change.New.Content, 60))
+	}
+}
+
+// changeCount counts the entries of a diff
+func changeCount(diff domain.EntryDiff) int {
+	return len(diff.Added) + len(diff.Removed) + len(diff.Changed)
+}
@@ -0,0 +1,187 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+var tagsJSON bool
+
+// tagJSON is the JSON view of a tag
+type tagJSON struct {
+	Name     string   `json:"name"`
+	Count    int      `json:"count"`
+	Sections []string `json:"sections"`
+}
+
+func init() {
+	tagsCmd := &cobra.Command{
+		Use:          "tags",
+		Short:        "List tags with their entry counts",
+		Long:         "List the tags in use, most used first, with the number of entries and the sections each appears in.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runTags,
+	}
+	tagsCmd.Flags().BoolVar(&tagsJSON, "json", false, "Print tags as JSON")
+
+	renameCmd := &cobra.Command{
+		Use:   "rename <old-tag> <new-tag>",
+		Short: "Rename a tag on every entry using it",
+		Long: `Rewrite every entry tagged <old-tag> (case-insensitive) with <new-tag> in a single
+atomic write. Legacy entries without an anchor are listed but left unchanged; run
+'ohmymem migrate' first to convert them.`,
+		Example:           "  ohmymem tags rename api API",
+		Args:              cobra.ExactArgs(2),
+		SilenceUsage:      true,
+		RunE:              runRename,
+		ValidArgsFunction: completeRename,
+	}
+	tagsCmd.AddCommand(renameCmd)
+
+	taxonomyCmd := &cobra.Command{
+		Use:   "taxonomy",
+		Short: "List the canonical tags and their aliases",
+		Long: `List the canonical tags that captured tags are normalized to, with their aliases:
+the built-in ones (e.g. db → Database), extended by .ohmymem/tags.yaml:
+
+  tags:
+    - name: Frontend
+      aliases: [fe, ui]
+    - name: Database
+      aliases: [pg]`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runTaxonomy,
+	}
+	taxonomyCmd.Flags().BoolVar(&tagsJSON, "json", false, "Print the taxonomy as JSON")
+	tagsCmd.AddCommand(taxonomyCmd)
+
+	cmd.RootCmd.AddCommand(tagsCmd)
+}
+
+func runTags(c *cobra.Command, args []string) error {
+	uc, err := openMemory()
+	if err != nil {
+		return err
+	}
+
+	tags, err := uc.Service().ListTags(c.Context())
+	if err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+
+	if tagsJSON {
+		views := make([]tagJSON, 0, len(tags))
+		for _, tag := range tags {
+			view := tagJSON{Name: tag.Name, Count: tag.Count, Sections: []string{}}
+			for _, section := range tag.Sections {
+				view.Sections = append(view.Sections, string(section))
+			}
+			views = append(views, view)
+		}
+		return cmd.PrintJSON(views)
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("No tags found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tCOUNT\tSECTIONS")
+	for _, tag := range tags {
+		sections := make([]string, 0, len(tag.Sections))
+		for _, section := range tag.Sections {
+			sections = append(sections, string(section))
+		}
+		fmt.Fprintf(tw, "[%s]\t%d\t%s\n", tag.Name, tag.Count, strings.Join(sections, ", "))
+	}
+	return tw.Flush()
+}
+
+func runRename(c *cobra.Command, args []string) error {
+	uc, err := openMemory()
+	if err != nil {
+		return err
+	}
+
+	result, err := uc.Service().RenameTag(c.Context(), args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range result.Legacy {
+		fmt.Fprintf(os.Stderr, "Skipped legacy entry: %s %s\n", entry.Tag, cmd.Truncate(entry.Content, 60))
+	}
+	if len(result.Legacy) > 0 {
+		fmt.Fprintln(os.Stderr, "Run 'ohmymem migrate' to convert legacy entries, then rename again.")
+	}
+
+	if len(result.Renamed) == 0 {
+		fmt.Printf("No entries to rename from [%s].\n", strings.Trim(args[0], "[]"))
+		return nil
+	}
+	fmt.Printf("✅ Renamed %d entries to %s\n", len(result.Renamed), result.Renamed[0].Tag)
+	return nil
+}
+
+// completeRename completes the tag to rename
+func completeRename(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cmd.CompleteTags(c, args, toComplete)
+}
+
+func runTaxonomy(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	custom, err := persistence.LoadTagTaxonomy(rootPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	taxonomy := domain.NewTagService(custom).Taxonomy()
+
+	if tagsJSON {
+		return cmd.PrintJSON(taxonomy)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tALIASES")
+	for _, definition := range taxonomy {
+		aliases := strings.Join(definition.Aliases, ", ")
+		if aliases == "" {
+			aliases = "-"
+		}
+		fmt.Fprintf(tw, "[%s]\t%s\n", definition.Name, aliases)
+	}
+	return tw.Flush()
+}
+
+// openMemory opens the memory of the project in the working directory
+func openMemory() (*usecase.MemoryUseCase, error) {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return nil, err
+	}
+	return uc, nil
+}
@@ -0,0 +1,253 @@
+package templatecmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/detector"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
+)
+
+var (
+	templateRepos   []string
+	templateJSON    bool
+	templateDryRun  bool
+	templateNoCache bool
+	templateRefresh bool
+	templateDetect  bool
+	templateRepoRef string
+	templateLang    string
+)
+
+func init() {
+	templateCmd := &cobra.Command{
+		Use:   "template",
+		Short: "Browse the template repository and refresh template entries",
+	}
+	templateCmd.PersistentFlags().StringArrayVar(&templateRepos, "repo", nil, "Template repository URL, or \"local\" for ~/.ohmymem/templates (default: the GitHub repository, then the Gitee mirror); repeat to layer repositories on top")
+	templateCmd.PersistentFlags().StringVar(&templateRepoRef, "repo-ref", "", "Branch, tag or commit of the template repository (default: template.ref, else the default branch)")
+	templateCmd.PersistentFlags().BoolVar(&templateNoCache, "no-cache", false, "Clone the template repository instead of using the cache")
+	templateCmd.PersistentFlags().BoolVar(&templateRefresh, "refresh", false, "Fetch the template repository again even if the cached copy is fresh")
+
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the bases, languages, frameworks and contexts of the template repository",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+	listCmd.Flags().BoolVar(&templateJSON, "json", false, "Print templates as JSON")
+
+	previewCmd := &cobra.Command{
+		Use:   "preview <name>",
+		Short: "Show the entries a template injects into the memory",
+		Long: `Show the entries a template injects into the memory, by section. The template is
+named by its ID (e.g. go) or its path in the repository (e.g. languages/go).`,
+		Example:      "  ohmymem template preview go\n  ohmymem template preview bases/common",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runPreview,
+	}
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Refresh the template entries of the memory from the repository",
+		Long: `Replace the entries injected from templates with those 'ohmymem init' would inject
+today. Entries captured with 'ohmymem add' or the MCP tools are never touched.
+The project stack saved by init in .ohmymem/project.yaml is reused unless --redetect is given.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runUpdate,
+	}
+	updateCmd.Flags().BoolVar(&templateDryRun, "dry-run", false, "Show what would change without writing")
+	updateCmd.Flags().BoolVar(&templateDetect, "redetect", false, "Detect the project again instead of using .ohmymem/project.yaml, and save the result")
+
+	newCmd := &cobra.Command{
+		Use:   "new <dir>",
+		Short: "Scaffold a template repository to author your own templates",
+		Long: `Create a template repository in dir: agents.md, the common base and a language
+template, each with a meta.yaml and a memory.md to edit. Use it with
+'ohmymem init --repo <dir>', or keep it in ~/.ohmymem/templates.`,
+		Example:      "  ohmymem template new ./my-templates\n  ohmymem template new ~/.ohmymem/templates --language python",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runNew,
+	}
+	newCmd.Flags().StringVar(&templateLang, "language", "go", "Language of the scaffolded language template")
+
+	templateCmd.AddCommand(listCmd, previewCmd, updateCmd, newCmd)
+	cmd.RootCmd.AddCommand(templateCmd)
+
+	// update-templates is 'template update' at the top level, for discoverability
+	updateTemplatesCmd := &cobra.Command{
+		Use:          "update-templates",
+		Short:        updateCmd.Short,
+		Long:         updateCmd.Long,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runUpdate,
+	}
+	updateTemplatesCmd.Flags().AddFlagSet(templateCmd.PersistentFlags())
+	updateTemplatesCmd.Flags().AddFlagSet(updateCmd.Flags())
+	cmd.RootCmd.AddCommand(updateTemplatesCmd)
+}
+
+func runList(c *cobra.Command, args []string) error {
+	uc, err := newTemplateUseCase()
+	if err != nil {
+		return err
+	}
+	infos, err := uc.List(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if templateJSON {
+		return cmd.PrintJSON(infos)
+	}
+	if len(infos) == 0 {
+		fmt.Println("No templates found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tKIND\tVERSION\tDESCRIPTION")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.ID, info.Kind, info.Version, cmd.Truncate(info.Description, 60))
+	}
+	return tw.Flush()
+}
+
+func runPreview(c *cobra.Command, args []string) error {
+	uc, err := newTemplateUseCase()
+	if err != nil {
+		return err
+	}
+	info, blocks, err := uc.Preview(c.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s (%s", info.Path(), info.Kind)
+	if info.Version != "" {
+		fmt.Printf(" %s", info.Version)
+	}
+	fmt.Println(")")
+	if info.Description != "" {
+		fmt.Println(info.Description)
+	}
+	if len(blocks) == 0 {
+		fmt.Println("\nNo entries.")
+		return nil
+	}
+
+	for _, section := range domain.ValidSections() {
+		header := false
+		for _, block := range blocks {
+			if block.Section != section {
+				continue
+			}
+			if !header {
+				fmt.Printf("\n## %s\n\n", section.Title())
+				header = true
+			}
+			for _, line := range strings.Split(block.Text, "\n") {
+				if !strings.HasPrefix(strings.TrimSpace(line), "<!--") {
+					fmt.Println(line)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func runUpdate(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	memory := usecase.NewMemoryUseCase(rootPath)
+	if err := memory.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	uc, err := newTemplateUseCase()
+	if err != nil {
+		return err
+	}
+	update, err := uc.Update(c.Context(), memory, templateDryRun)
+	if err != nil {
+		return err
+	}
+	if !update.Changed() {
+		fmt.Printf("Template entries are up to date (%d).\n", update.Kept)
+		return nil
+	}
+	for _, block := range update.AddedBlocks {
+		fmt.Printf("+ [%s] %s\n", block.Section.Title(), blockSummary(block))
+	}
+	for _, block := range update.RemovedBlocks {
+		fmt.Printf("- [%s] %s\n", block.Section.Title(), blockSummary(block))
+	}
+	fmt.Println()
+	if templateDryRun {
+		fmt.Printf("Would add %d and remove %d template entries, keeping %d.\n", update.Added, update.Removed, update.Kept)
+		return nil
+	}
+	fmt.Printf("✅ Added %d and removed %d template entries, kept %d\n", update.Added, update.Removed, update.Kept)
+	return nil
+}
+
+func runNew(c *cobra.Command, args []string) error {
+	files, err := template.Scaffold(args[0], templateLang)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Printf("   Created %s\n", file)
+	}
+	fmt.Printf("✅ Template repository scaffolded in %s\n", args[0])
+	fmt.Printf("   Try it with: ohmymem template list --repo %s\n", args[0])
+	return nil
+}
+
+// blockSummary returns the entry line of a template block, without its markers
+func blockSummary(block persistence.TemplateBlock) string {
+	for _, line := range strings.Split(block.Text, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "<!--") {
+			return cmd.Truncate(line, 100)
+		}
+	}
+	return ""
+}
+
+// newTemplateUseCase opens the repository of --repo, or the default ones, with the
+// layers of template.layers and further --repo flags
+func newTemplateUseCase() (*usecase.TemplateUseCase, error) {
+	repo, layers := cmd.TemplateLayers(templateRepos)
+	repoURLs := template.GetDefaultRepoURLs()
+	if repo != "" {
+		repoURLs = []string{repo}
+	}
+	repoURLs, err := template.ResolveRepoURLs(repoURLs)
+	if err != nil {
+		return nil, err
+	}
+	if layers, err = template.Layers(repoURLs, layers); err != nil {
+		return nil, err
+	}
+	fetch, err := cmd.TemplateFetch(templateRepoRef, templateNoCache, templateRefresh)
+	if err != nil {
+		return nil, err
+	}
+	projectDetector := detector.NewCachedDetector(detector.NewCompositeDetector()).WithRefresh(templateDetect).WithSave(!templateDryRun)
+	return usecase.NewTemplateUseCase(projectDetector, repoURLs, fetch).WithLayers(layers), nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
+)
+
+// TemplateFetch returns how to fetch template repositories from the --repo-ref,
+// --no-cache and --refresh flags and the template settings
+func TemplateFetch(ref string, noCache, refresh bool) (template.FetchOptions, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return template.FetchOptions{}, err
+	}
+	ttl, err := cfg.TemplateCacheTTL()
+	if err != nil {
+		return template.FetchOptions{}, err
+	}
+	if ref == "" {
+		ref = cfg.Template.Ref
+	}
+	if cfg.Template.Verify != "" && !slices.Contains(template.VerifyModes, cfg.Template.Verify) {
+		return template.FetchOptions{}, fmt.Errorf("invalid template.verify %q: expected %s", cfg.Template.Verify, strings.Join(template.VerifyModes, ", "))
+	}
+	return template.FetchOptions{
+		Ref: ref,
+		Cache: template.CacheOptions{
+			Disabled: noCache || ttl == 0,
+			Refresh:  refresh,
+			TTL:      ttl,
+		},
+		Verify: template.VerifyOptions{
+			Mode:      cfg.Template.Verify,
+			PublicKey: cfg.Template.PublicKey,
+		},
+	}, nil
+}
+
+// TemplateLayers splits repeated --repo flags into the template repository, the
+// first, and the repositories layered on top of it: those of template.layers, then
+// the other flags
+func TemplateLayers(repos []string) (string, []string) {
+	var repo string
+	var layers []string
+	for i, r := range repos {
+		if r = strings.TrimSpace(r); r == "" {
+			continue
+		}
+		if i == 0 {
+			repo = r
+		} else {
+			layers = append(layers, r)
+		}
+	}
+	return repo, append(template.ConfiguredLayers(), layers...)
+}
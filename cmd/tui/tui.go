@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/tui"
+)
+
+func init() {
+	tuiCmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and curate memory entries interactively",
+		Long: `Open a full-screen browser over the memory entries. Switch sections with tab,
+fuzzy-search tags, content and rationale with /, and inspect each entry's rationale,
+timestamps and status.
+
+Actions on the selected entry: e edits it in $EDITOR (like 'ohmymem edit'),
+d marks it deprecated and x deletes it, both after confirmation.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runTUI,
+	}
+
+	cmd.RootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate ohmymem executable: %w", err)
+	}
+	edit := func(id string) *exec.Cmd {
+		return exec.Command(self, "edit", id)
+	}
+
+	return tui.Run(c.Context(), uc.Service(), edit)
+}
@@ -0,0 +1,91 @@
+package uninit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/huh"
+)
+
+var (
+	uninitKeepMemory bool
+	uninitDryRun     bool
+	uninitYes        bool
+)
+
+func init() {
+	uninitCmd := &cobra.Command{
+		Use:     "uninit",
+		Aliases: []string{"clean"},
+		Short:   "Remove OhMyMem from the project",
+		Long: `Undo 'ohmymem init': remove the .cursorrules and CLAUDE.md symlinks pointing at
+AGENTS.md, strip the managed block from AGENTS.md (deleting the file when nothing else
+is left) and delete the .ohmymem directory. The changes are listed before confirmation.
+
+With --keep-memory, the .ohmymem directory (memory, archives, backups) is left in place.`,
+		Example: `  ohmymem uninit
+  ohmymem uninit --keep-memory --yes`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runUninit,
+	}
+
+	uninitCmd.Flags().BoolVar(&uninitKeepMemory, "keep-memory", false, "Keep the .ohmymem directory")
+	uninitCmd.Flags().BoolVar(&uninitDryRun, "dry-run", false, "List the changes without making them")
+	uninitCmd.Flags().BoolVarP(&uninitYes, "yes", "y", false, "Skip confirmation")
+
+	cmd.RootCmd.AddCommand(uninitCmd)
+}
+
+func runUninit(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewUninitUseCase(rootPath)
+	steps, err := uc.Plan(uninitKeepMemory)
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		fmt.Println("Nothing to remove.")
+		return nil
+	}
+
+	for _, step := range steps {
+		fmt.Printf("  %-14s %s\n", step.Path, step.Action)
+	}
+	fmt.Println()
+
+	if uninitDryRun {
+		fmt.Printf("Dry run: %d changes would be made.\n", len(steps))
+		return nil
+	}
+
+	if !uninitYes {
+		confirmed, err := huh.Confirm("Remove OhMyMem from this project?", false)
+		if err != nil {
+			if errors.Is(err, huh.ErrCancelled) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := uc.Apply(steps); err != nil {
+		return err
+	}
+	fmt.Println("✅ OhMyMem removed from this project.")
+	return nil
+}
@@ -0,0 +1,80 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+var (
+	validateStrict bool
+	validateJSON   bool
+)
+
+func init() {
+	validateCmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Lint memory.md and exit nonzero when it is malformed",
+		Long: `Check .ohmymem/memory.md, or the given file, for broken anchored blocks, entries
+violating the content rules, unknown sections, duplicated entry IDs and frontmatter
+issues. The command exits nonzero on errors, and on warnings too with --strict, so it
+can gate CI.`,
+		Example:      "  ohmymem validate\n  ohmymem validate --strict --json",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runValidate,
+	}
+
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Fail on warnings too")
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "Print the report as JSON")
+
+	cmd.RootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(c *cobra.Command, args []string) error {
+	var report usecase.ValidationReport
+	if len(args) == 1 {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read %s: %w", args[0], err)
+		}
+		report = usecase.ValidateMemory(string(data))
+	} else {
+		rootPath, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		uc := usecase.NewMemoryUseCase(rootPath)
+		if err := uc.EnsureInitialized(); err != nil {
+			return err
+		}
+		if report, err = uc.Validate(c.Context()); err != nil {
+			return err
+		}
+	}
+
+	if validateJSON {
+		if err := cmd.PrintJSON(report); err != nil {
+			return err
+		}
+	} else {
+		for _, problem := range report.Errors {
+			fmt.Println("❌ " + problem)
+		}
+		for _, problem := range report.Warnings {
+			fmt.Println("⚠️  " + problem)
+		}
+	}
+
+	if !report.Valid(validateStrict) {
+		return fmt.Errorf("%d errors, %d warnings", len(report.Errors), len(report.Warnings))
+	}
+	if !validateJSON {
+		fmt.Printf("✅ memory.md is valid (%d warnings)\n", len(report.Warnings))
+	}
+	return nil
+}
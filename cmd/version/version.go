@@ -0,0 +1,35 @@
+package versioncmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/version"
+)
+
+var versionJSON bool
+
+func init() {
+	versionCmd := &cobra.Command{
+		Use:          "version",
+		Short:        "Print the version, commit, build date, Go version and platform",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runVersion,
+	}
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print the build metadata as JSON")
+
+	cmd.RootCmd.SetVersionTemplate(version.Get().String())
+	cmd.RootCmd.AddCommand(versionCmd)
+}
+
+func runVersion(c *cobra.Command, args []string) error {
+	info := version.Get()
+	if versionJSON {
+		return cmd.PrintJSON(info)
+	}
+	fmt.Print(info.String())
+	return nil
+}
@@ -0,0 +1,106 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/herewei/ohmymem-core/cmd"
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+var (
+	watchJSON    bool
+	watchNoColor bool
+)
+
+// eventJSON is one line of --json output
+type eventJSON struct {
+	Time   time.Time         `json:"time"`
+	Type   string            `json:"type"` // added, removed or changed
+	Fields []string          `json:"fields,omitempty"`
+	Entry  usecase.EntryJSON `json:"entry"`
+}
+
+func init() {
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print entries as they are added, changed or removed",
+		Long: `Watch .ohmymem/memory.md and print every entry added, changed or removed, as it
+happens, until interrupted. Useful while pairing with an agent to see what it decides
+to remember in real time.
+
+With --json, each change is printed as one JSON object per line.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runWatch,
+	}
+
+	watchCmd.Flags().BoolVar(&watchJSON, "json", false, "Print one JSON object per change")
+	watchCmd.Flags().BoolVar(&watchNoColor, "no-color", false, "Disable colored output")
+
+	cmd.RootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(c *cobra.Command, args []string) error {
+	rootPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(rootPath)
+	if err := uc.EnsureInitialized(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !watchJSON {
+		fmt.Fprintf(os.Stderr, "Watching %s (Ctrl+C to stop)\n", uc.Service().GetMemoryPath())
+	}
+
+	color := !watchNoColor && cmd.UseColor()
+	encoder := json.NewEncoder(os.Stdout)
+	return uc.Watch(ctx, func(diff domain.EntryDiff) {
+		now := uc.Now()
+		if watchJSON {
+			for _, entry := range diff.Added {
+				encoder.Encode(eventJSON{Time: now, Type: "added", Entry: usecase.ToEntryJSON(entry)})
+			}
+			for _, change := range diff.Changed {
+				encoder.Encode(eventJSON{Time: now, Type: "changed", Fields: change.Fields, Entry: usecase.ToEntryJSON(change.New)})
+			}
+			for _, entry := range diff.Removed {
+				encoder.Encode(eventJSON{Time: now, Type: "removed", Entry: usecase.ToEntryJSON(entry)})
+			}
+			return
+		}
+
+		stamp := now.Local().Format(time.TimeOnly)
+		for _, entry := range diff.Added {
+			fmt.Println(cmd.Paint(color, cmd.ColorAdded, fmt.Sprintf("%s + %s", stamp, describe(entry))))
+			if entry.Rationale != "" {
+				fmt.Printf("           Rationale: %s\n", entry.Rationale)
+			}
+		}
+		for _, change := range diff.Changed {
+			fmt.Println(cmd.Paint(color, cmd.ColorChanged, fmt.Sprintf("%s ~ %s (%s)", stamp, describe(change.New), strings.Join(change.Fields, ", "))))
+		}
+		for _, entry := range diff.Removed {
+			fmt.Println(cmd.Paint(color, cmd.ColorRemoved, fmt.Sprintf("%s - %s", stamp, describe(entry))))
+		}
+	})
+}
+
+// describe renders an entry on one line
+func describe(entry domain.Entry) string {
+	return fmt.Sprintf("%s %s  (%s, %s)", entry.Tag, entry.Content, entry.Section, entry.ID)
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// ArchiveName returns the name of the dated archive file entries archived now go to
+func (u *MemoryUseCase) ArchiveName() string {
+	return u.Now().Format("2006-01-02")
+}
+
+// ListArchived returns the entries of the archive files under .ohmymem/archive
+func (u *MemoryUseCase) ListArchived(ctx context.Context) ([]persistence.ArchivedEntry, error) {
+	return u.repo.ListArchived(ctx)
+}
+
+// Restore moves archived entries back into the memory file. Each ID may be a unique
+// prefix of an archived entry ID.
+func (u *MemoryUseCase) Restore(ctx context.Context, idsOrPrefixes []string) ([]persistence.ArchivedEntry, error) {
+	archived, err := u.repo.ListArchived(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(idsOrPrefixes))
+	for _, idOrPrefix := range idsOrPrefixes {
+		id, err := findArchived(archived, strings.TrimSpace(idOrPrefix))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return u.repo.RestoreEntries(ctx, ids)
+}
+
+// findArchived resolves an ID or unique ID prefix among archived entries
+func findArchived(archived []persistence.ArchivedEntry, idOrPrefix string) (string, error) {
+	if idOrPrefix == "" {
+		return "", fmt.Errorf("%w: empty ID", domain.ErrEntryNotFound)
+	}
+
+	var matches []string
+	for _, entry := range archived {
+		if entry.ID == idOrPrefix {
+			return entry.ID, nil
+		}
+		if entry.ID != "" && strings.HasPrefix(entry.ID, idOrPrefix) {
+			matches = append(matches, entry.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w in the archive: %s", domain.ErrEntryNotFound, idOrPrefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s matches %d archived entries", domain.ErrAmbiguousID, idOrPrefix, len(matches))
+	}
+}
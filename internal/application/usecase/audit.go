@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// Audit statuses
+const (
+	auditStatusOK     = "ok"
+	auditStatusError  = "error"
+	auditStatusFailed = "failed"
+)
+
+// AuditMiddleware records every tool invocation through the given logger.
+// Arguments are stored as a hash so memory content never leaks into the audit log.
+func AuditMiddleware(logger domain.AuditLogger, timeProvider domain.TimeProvider) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			started := time.Now()
+			result, err := next(ctx, request)
+
+			record := domain.AuditRecord{
+				Time:       timeProvider.Now(),
+				Tool:       request.Params.Name,
+				ArgsHash:   hashArguments(request.GetArguments()),
+				Project:    request.GetString("project", ""),
+				Status:     auditStatusOK,
+				DurationMs: time.Since(started).Milliseconds(),
+			}
+			switch {
+			case err != nil:
+				record.Status = auditStatusFailed
+			case result != nil && result.IsError:
+				record.Status = auditStatusError
+			}
+
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				record.SessionID = session.SessionID()
+				if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+					info := withInfo.GetClientInfo()
+					record.ClientName = info.Name
+					record.ClientVersion = info.Version
+				}
+			}
+
+			if logErr := logger.Record(record); logErr != nil {
+				slog.Error("failed to write audit record", "error", logErr, "tool", record.Tool)
+			}
+
+			return result, err
+		}
+	}
+}
+
+// hashArguments returns a short, stable SHA-256 digest of the tool arguments
+func hashArguments(args map[string]any) string {
+	// encoding/json sorts map keys, so equal arguments hash equally
+	data, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// conflictMarkers open, separate and close the sides of a git merge conflict
+var conflictMarkers = []string{"<<<<<<< ", "=======", ">>>>>>> ", "|||||||"}
+
+// CheckOptions selects the checks of Check
+type CheckOptions struct {
+	SkipAgents bool // Do not require an up-to-date AGENTS.md block
+}
+
+// CheckReport is the outcome of the CI checks
+type CheckReport struct {
+	Validation      ValidationReport  `json:"validation"`
+	LegacyEntries   int               `json:"legacy_entries"`
+	ConflictMarkers []string          `json:"conflict_markers"` // file:line of each marker
+	Agents          AgentsBlockStatus `json:"agents"`
+	Problems        []string          `json:"problems"`
+}
+
+// Passed reports whether no check failed
+func (r *CheckReport) Passed() bool {
+	return len(r.Problems) == 0
+}
+
+// Check gates CI on the memory: it fails when memory.md is invalid, has legacy entries
+// to migrate or merge conflict markers, or when the AGENTS.md block is missing or stale
+func (u *MemoryUseCase) Check(ctx context.Context, opts CheckOptions) (*CheckReport, error) {
+	data, err := os.ReadFile(u.repo.FilePath())
+	if err != nil {
+		return nil, fmt.Errorf("read memory file: %w", err)
+	}
+	content := string(data)
+
+	report := &CheckReport{ConflictMarkers: []string{}, Problems: []string{}}
+
+	memoryPath := filepath.Join(persistence.DirName, persistence.FileName)
+	report.ConflictMarkers = append(report.ConflictMarkers, findConflictMarkers(memoryPath, content)...)
+	if agents, err := os.ReadFile(filepath.Join(u.basePath, agentsFileName)); err == nil {
+		report.ConflictMarkers = append(report.ConflictMarkers, findConflictMarkers(agentsFileName, string(agents))...)
+	}
+	for _, marker := range report.ConflictMarkers {
+		report.Problems = append(report.Problems, "merge conflict marker at "+marker)
+	}
+
+	report.Validation = ValidateMemory(content)
+	for _, problem := range report.Validation.Errors {
+		report.Problems = append(report.Problems, "memory.md: "+problem)
+	}
+
+	report.LegacyEntries = persistence.InspectContent(content).LegacyEntries
+	if report.LegacyEntries > 0 {
+		report.Problems = append(report.Problems, fmt.Sprintf("%d legacy entries not migrated, run 'ohmymem migrate'", report.LegacyEntries))
+	}
+
+	report.Agents = agentsBlockStatus(filepath.Join(u.basePath, agentsFileName))
+	if !opts.SkipAgents {
+		switch {
+		case !report.Agents.Present:
+			report.Problems = append(report.Problems, "AGENTS.md has no ohmymem block, run 'ohmymem doctor --fix'")
+		case !report.Agents.UpToDate:
+			report.Problems = append(report.Problems, "AGENTS.md block is stale: its boot protocol differs from this version of ohmymem")
+		}
+	}
+	return report, nil
+}
+
+// findConflictMarkers returns "name:line" for each line of content starting with a
+// merge conflict marker
+func findConflictMarkers(name, content string) []string {
+	var markers []string
+	for i, line := range strings.Split(content, "\n") {
+		for _, marker := range conflictMarkers {
+			if line == strings.TrimSpace(marker) || strings.HasPrefix(line, marker) {
+				markers = append(markers, fmt.Sprintf("%s:%d", name, i+1))
+				break
+			}
+		}
+	}
+	return markers
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// Diff compares the entries of two versions of the memory file. Each source is a
+// path to a memory file or a git revision; an empty newSource means the current file.
+func (u *MemoryUseCase) Diff(ctx context.Context, oldSource, newSource string) (*domain.EntryDiff, error) {
+	oldEntries, err := u.readVersion(ctx, oldSource)
+	if err != nil {
+		return nil, err
+	}
+	newEntries, err := u.readVersion(ctx, newSource)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := domain.DiffEntries(oldEntries, newEntries)
+	return &diff, nil
+}
+
+// readVersion parses the entries of a memory file version
+func (u *MemoryUseCase) readVersion(ctx context.Context, source string) ([]domain.Entry, error) {
+	if source == "" {
+		source = u.repo.FilePath()
+	}
+
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", source, err)
+		}
+		return persistence.ParseMemory(string(data)), nil
+	}
+
+	content, err := persistence.ReadRevision(ctx, u.basePath, source)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a file nor a git revision: %w", source, err)
+	}
+	return persistence.ParseMemory(content), nil
+}
+
+// Watch calls onDiff with the entries added, removed or changed each time the memory
+// file changes, until ctx is cancelled
+func (u *MemoryUseCase) Watch(ctx context.Context, onDiff func(domain.EntryDiff)) error {
+	content, err := u.repo.ReadAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := persistence.ParseMemory(content)
+	return u.repo.Watch(ctx, func(entries []domain.Entry) {
+		diff := domain.DiffEntries(previous, entries)
+		previous = entries
+		if !diff.IsEmpty() {
+			onDiff(diff)
+		}
+	})
+}
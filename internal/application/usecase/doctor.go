@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// Doctor check statuses
+const (
+	CheckOK   = "ok"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// DoctorCheck is the outcome of a single diagnostic
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail"`
+	Fixable bool   `json:"fixable,omitempty"` // --fix can repair it
+	Fixed   bool   `json:"fixed,omitempty"`   // Repaired during this run
+
+	fix func(ctx context.Context) error
+}
+
+// DoctorReport lists all diagnostics in the order they ran
+type DoctorReport struct {
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// Healthy reports whether no check failed after fixes were applied
+func (r *DoctorReport) Healthy() bool {
+	for _, check := range r.Checks {
+		if check.Status == CheckFail && !check.Fixed {
+			return false
+		}
+	}
+	return true
+}
+
+// DoctorUseCase diagnoses the ohmymem layout of a project
+type DoctorUseCase struct {
+	rootPath string
+	repo     *persistence.MarkdownMemoryRepository
+}
+
+// NewDoctorUseCase creates a doctor for the project at rootPath
+func NewDoctorUseCase(rootPath string) *DoctorUseCase {
+	return &DoctorUseCase{
+		rootPath: rootPath,
+		repo:     NewMemoryUseCase(rootPath).repo,
+	}
+}
+
+// Run executes all checks; with fix set, safely repairable problems are fixed
+func (d *DoctorUseCase) Run(ctx context.Context, fix bool) (*DoctorReport, error) {
+	inspection, err := d.repo.Inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checks run in order and each fix is applied before the next check, so
+	// symlinks are checked after AGENTS.md may have been created
+	checks := []func() DoctorCheck{func() DoctorCheck { return d.checkLayout(inspection) }}
+	if inspection.Exists {
+		checks = append(checks,
+			func() DoctorCheck { return d.checkFrontmatter(inspection) },
+			func() DoctorCheck { return d.checkSections(inspection) },
+			func() DoctorCheck { return d.checkEntries(inspection) })
+	}
+	checks = append(checks, func() DoctorCheck { return d.checkLocks(inspection) }, d.checkAgentsBlock)
+	for _, link := range d.editorLinks() {
+		checks = append(checks, func() DoctorCheck { return d.checkSymlink(link) })
+	}
+
+	report := &DoctorReport{}
+	for _, run := range checks {
+		check := run()
+		if fix && check.Fixable && check.fix != nil {
+			if err := check.fix(ctx); err != nil {
+				check.Detail += fmt.Sprintf(" (fix failed: %v)", err)
+			} else {
+				check.Fixed = true
+			}
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	return report, nil
+}
+
+// checkLayout verifies .ohmymem/ and memory.md exist
+func (d *DoctorUseCase) checkLayout(inspection *persistence.Inspection) DoctorCheck {
+	check := DoctorCheck{Name: "Directory layout"}
+	dirInfo, err := os.Stat(d.repo.DirPath())
+	switch {
+	case err == nil && !dirInfo.IsDir():
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("%s is not a directory", persistence.DirName)
+	case err != nil:
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("%s missing, run 'ohmymem init'", persistence.DirName)
+	case !inspection.Exists:
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("%s missing, run 'ohmymem init'", filepath.Join(persistence.DirName, persistence.FileName))
+	default:
+		check.Status, check.Detail = CheckOK, filepath.Join(persistence.DirName, persistence.FileName)+" present"
+	}
+	return check
+}
+
+// checkFrontmatter verifies the frontmatter parses and declares the schema
+func (d *DoctorUseCase) checkFrontmatter(inspection *persistence.Inspection) DoctorCheck {
+	check := DoctorCheck{Name: "Frontmatter", fix: d.repairStructure}
+	switch {
+	case !inspection.HasFrontmatter:
+		check.Status, check.Detail, check.Fixable = CheckWarn, "missing", true
+	case inspection.FrontmatterErr != nil:
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("invalid YAML: %v", inspection.FrontmatterErr)
+	default:
+		var missing []string
+		for _, key := range []string{persistence.SchemaVersionKey, persistence.EntryFormatKey} {
+			if _, ok := inspection.Frontmatter[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			check.Status, check.Detail, check.Fixable = CheckWarn, "missing "+strings.Join(missing, ", "), true
+		} else {
+			check.Status = CheckOK
+			check.Detail = fmt.Sprintf("schema_version %v, entry_format %v",
+				inspection.Frontmatter[persistence.SchemaVersionKey], inspection.Frontmatter[persistence.EntryFormatKey])
+		}
+	}
+	return check
+}
+
+// checkSections verifies the required section headers exist
+func (d *DoctorUseCase) checkSections(inspection *persistence.Inspection) DoctorCheck {
+	check := DoctorCheck{Name: "Sections", fix: d.repairStructure}
+	if len(inspection.MissingSections) == 0 {
+		check.Status, check.Detail = CheckOK, "all section headers present"
+		return check
+	}
+	titles := make([]string, 0, len(inspection.MissingSections))
+	for _, section := range inspection.MissingSections {
+		titles = append(titles, "## "+section.Title())
+	}
+	check.Status, check.Detail, check.Fixable = CheckWarn, "missing "+strings.Join(titles, ", "), true
+	return check
+}
+
+// checkEntries reports how many bullets parse as anchored entries
+func (d *DoctorUseCase) checkEntries(inspection *persistence.Inspection) DoctorCheck {
+	check := DoctorCheck{Name: "Entry format", Status: CheckOK}
+	detail := fmt.Sprintf("%d anchored entries", inspection.AnchoredEntries)
+
+	if inspection.LegacyEntries > 0 {
+		check.Status = CheckWarn
+		detail += fmt.Sprintf(", %d legacy entries (run 'ohmymem migrate')", inspection.LegacyEntries)
+	}
+	if inspection.UnparsedBullets > 0 {
+		check.Status = CheckWarn
+		detail += fmt.Sprintf(", %d bullets in no entry format", inspection.UnparsedBullets)
+	}
+	if inspection.BrokenAnchors > 0 {
+		check.Status = CheckFail
+		detail += fmt.Sprintf(", %d broken entry anchors (edit the file by hand)", inspection.BrokenAnchors)
+	}
+	if len(inspection.DuplicateIDs) > 0 {
+		check.Status = CheckFail
+		detail += fmt.Sprintf(", %d duplicate entry IDs (edit the file by hand)", len(inspection.DuplicateIDs))
+	}
+	check.Detail = detail
+	return check
+}
+
+// checkLocks reports a held write lock and leftover temp files from interrupted writes
+func (d *DoctorUseCase) checkLocks(inspection *persistence.Inspection) DoctorCheck {
+	check := DoctorCheck{Name: "Locks", Status: CheckOK, Detail: "no stale lock or temp files"}
+	switch {
+	case inspection.LockHeld && inspection.TempFileExists:
+		check.Status, check.Detail = CheckWarn, "write lock held and temp file present, a write may be in progress"
+	case inspection.LockHeld:
+		check.Status, check.Detail = CheckWarn, "write lock held by another process"
+	case inspection.TempFileExists:
+		tmpPath := d.repo.TempFilePath()
+		check.Status, check.Fixable = CheckWarn, true
+		check.Detail = fmt.Sprintf("leftover %s from an interrupted write", filepath.Base(tmpPath))
+		check.fix = func(context.Context) error {
+			// A repair earlier in this run may already have reused the temp file
+			if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		}
+	}
+	return check
+}
+
+// editorLinks returns the editor instruction files present in the project, or the
+// default ones when there are none
+func (d *DoctorUseCase) editorLinks() []string {
+	var links []string
+	for _, editor := range EditorIntegrations {
+		if _, err := os.Lstat(filepath.Join(d.rootPath, editor.Path)); err == nil {
+			links = append(links, editor.Path)
+		}
+	}
+	if len(links) > 0 {
+		return links
+	}
+	editors, _ := ResolveEditors(nil)
+	for _, editor := range editors {
+		links = append(links, editor.Path)
+	}
+	return links
+}
+
+// checkSymlink verifies an editor instruction file links to AGENTS.md
+func (d *DoctorUseCase) checkSymlink(link string) DoctorCheck {
+	linkPath := filepath.Join(d.rootPath, link)
+	agentsExists := fileExists(filepath.Join(d.rootPath, agentsFileName))
+	expected := agentsLinkTarget(link)
+	check := DoctorCheck{Name: link, fix: func(context.Context) error {
+		return (&InitUseCase{}).createSymlink(linkPath, expected)
+	}}
+
+	info, err := os.Lstat(linkPath)
+	switch {
+	case err != nil:
+		check.Status, check.Detail, check.Fixable = CheckWarn, "missing", agentsExists
+	case info.Mode()&os.ModeSymlink == 0:
+		check.Status, check.Detail = CheckWarn, "regular file, not linked to "+agentsFileName
+	default:
+		target, _ := os.Readlink(linkPath)
+		if _, err := os.Stat(linkPath); err != nil {
+			check.Status, check.Detail, check.Fixable = CheckFail, "broken symlink to "+target, agentsExists
+		} else if target != expected {
+			check.Status, check.Detail = CheckWarn, "links to "+target+" instead of "+expected
+		} else {
+			check.Status, check.Detail = CheckOK, "→ "+target
+		}
+	}
+	return check
+}
+
+// checkAgentsBlock verifies AGENTS.md holds exactly one well-formed ohmymem block
+func (d *DoctorUseCase) checkAgentsBlock() DoctorCheck {
+	agentsPath := filepath.Join(d.rootPath, agentsFileName)
+	check := DoctorCheck{Name: agentsFileName + " block", fix: func(context.Context) error {
+		return (&InitUseCase{}).updateAgentsFile(agentsPath, domain.DefaultAgentsContent())
+	}}
+
+	data, err := os.ReadFile(agentsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			check.Status, check.Detail, check.Fixable = CheckWarn, "AGENTS.md missing", true
+		} else {
+			check.Status, check.Detail = CheckFail, err.Error()
+		}
+		return check
+	}
+
+	content := string(data)
+	starts := strings.Count(content, agentsBlockStart)
+	ends := strings.Count(content, agentsBlockEnd)
+	switch {
+	case starts == 0 && ends == 0:
+		check.Status, check.Detail, check.Fixable = CheckWarn, "no ohmymem block", true
+	case starts != 1 || ends != 1:
+		check.Status, check.Detail = CheckFail, fmt.Sprintf("%d start and %d end markers, expected one of each", starts, ends)
+	case strings.Index(content, agentsBlockStart) > strings.Index(content, agentsBlockEnd):
+		check.Status, check.Detail = CheckFail, "end marker comes before start marker"
+	default:
+		check.Status, check.Detail = CheckOK, "ohmymem block intact"
+	}
+	return check
+}
+
+// repairStructure adds missing frontmatter and section headers
+func (d *DoctorUseCase) repairStructure(ctx context.Context) error {
+	_, err := d.repo.RepairStructure(ctx)
+	return err
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// EditorIntegration is an agent instruction file init links to AGENTS.md
+type EditorIntegration struct {
+	Name string // Value accepted by init --editors
+	Path string // Path of the symlink, relative to the project root
+}
+
+// EditorIntegrations lists the editors and agents init can integrate with
+var EditorIntegrations = []EditorIntegration{
+	{Name: "claude", Path: "CLAUDE.md"},
+	{Name: "cursor", Path: ".cursorrules"},
+	{Name: "copilot", Path: filepath.Join(".github", "copilot-instructions.md")},
+	{Name: "windsurf", Path: ".windsurfrules"},
+	{Name: "cline", Path: ".clinerules"},
+}
+
+// DefaultEditors are the integrations init creates when none are selected
+var DefaultEditors = []string{"claude", "cursor"}
+
+// EditorNames returns the names of all supported integrations
+func EditorNames() []string {
+	names := make([]string, 0, len(EditorIntegrations))
+	for _, editor := range EditorIntegrations {
+		names = append(names, editor.Name)
+	}
+	return names
+}
+
+// ResolveEditors returns the integrations for names (case-insensitive), or the
+// defaults when names is empty
+func ResolveEditors(names []string) ([]EditorIntegration, error) {
+	if len(names) == 0 {
+		names = DefaultEditors
+	}
+
+	var editors []EditorIntegration
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		editor, ok := findEditor(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown editor %q (expected %s)", name, strings.Join(EditorNames(), ", "))
+		}
+		seen[name] = true
+		editors = append(editors, editor)
+	}
+	return editors, nil
+}
+
+// findEditor looks up an integration by name
+func findEditor(name string) (EditorIntegration, bool) {
+	for _, editor := range EditorIntegrations {
+		if editor.Name == name {
+			return editor, true
+		}
+	}
+	return EditorIntegration{}, false
+}
+
+// agentsLinkTarget is the symlink target pointing from link to AGENTS.md, relative to
+// the directory of the link
+func agentsLinkTarget(link string) string {
+	target, err := filepath.Rel(filepath.Dir(link), agentsFileName)
+	if err != nil {
+		return agentsFileName
+	}
+	return target
+}
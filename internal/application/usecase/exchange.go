@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ExchangeFormat is a serialization format for exporting and importing entries
+type ExchangeFormat string
+
+const (
+	FormatJSON ExchangeFormat = "json"
+	FormatYAML ExchangeFormat = "yaml"
+	FormatCSV  ExchangeFormat = "csv"
+
+	// FormatMarkdown is a plain bullet list, accepted by import only
+	FormatMarkdown ExchangeFormat = "markdown"
+)
+
+// csvHeader is the column order of CSV exports
+var csvHeader = []string{"id", "section", "tag", "content", "rationale", "status", "created_at", "expires_at", "scope", "superseded_by", "author", "context", "options", "consequences"}
+
+// markdownBulletPattern matches "**[Tag]** content (*Rationale: r*)" after the bullet marker;
+// tag and rationale are optional
+var markdownBulletPattern = regexp.MustCompile(`^(?:\*\*\[([^\]]+)\]\*\*\s*|\[([^\]]+)\]\s+)?(.*?)(?:\s*\(\*Rationale:\s*(.*?)\*\))?$`)
+
+// ParseExchangeFormat validates a format name, accepting "yml" and "md" as aliases
+func ParseExchangeFormat(value string) (ExchangeFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "csv":
+		return FormatCSV, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown format %q (valid: json, yaml, csv, markdown)", value)
+	}
+}
+
+// DetectExchangeFormat guesses the format of data from its first non-blank line
+func DetectExchangeFormat(data []byte) ExchangeFormat {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "["), strings.HasPrefix(line, "{"):
+			return FormatJSON
+		case strings.HasPrefix(line, "id,"):
+			return FormatCSV
+		case strings.HasPrefix(line, "- id:"), strings.HasPrefix(line, "---"):
+			return FormatYAML
+		default:
+			return FormatMarkdown
+		}
+	}
+	return FormatMarkdown
+}
+
+// WriteEntries serializes entries to w in the given format
+func WriteEntries(w io.Writer, format ExchangeFormat, entries []domain.Entry) error {
+	views := ToEntriesJSON(entries)
+
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(views)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(views); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, view := range views {
+			record := []string{view.ID, view.Section, view.Tag, view.Content, view.Rationale, view.Status, view.CreatedAt, view.ExpiresAt, strings.Join(view.Scope, " "), view.SupersededBy, view.Author, view.Context, strings.Join(view.Options, "; "), view.Consequences}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("format %q is not supported for export", format)
+	}
+}
+
+// ImportRecord is an entry read from an import file with its location for error reporting
+type ImportRecord struct {
+	Source string // e.g. "item 3" or "line 12"
+	Entry  EntryJSON
+}
+
+// ReadEntries parses data in the given format into import records.
+// Markdown input takes entries from "-" or "*" bullets; "## Section" headers set
+// the section of the bullets below them, decision record bullets ("* *Context:* ...")
+// belong to the entry above them and other lines are ignored.
+func ReadEntries(data []byte, format ExchangeFormat) ([]ImportRecord, error) {
+	var views []EntryJSON
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &views); err != nil {
+			return nil, fmt.Errorf("parse JSON: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &views); err != nil {
+			return nil, fmt.Errorf("parse YAML: %w", err)
+		}
+	case FormatCSV:
+		return readCSV(data)
+	case FormatMarkdown:
+		return readMarkdown(data), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	records := make([]ImportRecord, 0, len(views))
+	for i, view := range views {
+		records = append(records, ImportRecord{Source: fmt.Sprintf("item %d", i+1), Entry: view})
+	}
+	return records, nil
+}
+
+// readCSV parses CSV with a header row; columns are matched by name so any subset
+// of the export columns may be present
+func readCSV(data []byte) ([]ImportRecord, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, fmt.Errorf("parse CSV: missing content column")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	records := make([]ImportRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		records = append(records, ImportRecord{
+			Source: fmt.Sprintf("line %d", i+2),
+			Entry: EntryJSON{
+				ID:           field(row, "id"),
+				Section:      field(row, "section"),
+				Tag:          field(row, "tag"),
+				Content:      field(row, "content"),
+				Rationale:    field(row, "rationale"),
+				Status:       field(row, "status"),
+				CreatedAt:    field(row, "created_at"),
+				ExpiresAt:    field(row, "expires_at"),
+				Scope:        scopeColumn(field(row, "scope")),
+				SupersededBy: field(row, "superseded_by"),
+				Author:       field(row, "author"),
+				DecisionRecord: domain.DecisionRecord{
+					Context:      field(row, "context"),
+					Options:      domain.ParseOptions(field(row, "options")),
+					Consequences: field(row, "consequences"),
+				},
+			},
+		})
+	}
+	return records, nil
+}
+
+// scopeColumn splits the space-separated scope column of a CSV row, nil when empty
+func scopeColumn(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return strings.Fields(value)
+}
+
+// readMarkdown parses a bullet list, optionally grouped under section headers
+func readMarkdown(data []byte) []ImportRecord {
+	var records []ImportRecord
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if title, ok := strings.CutPrefix(line, "## "); ok {
+			section = ""
+			if candidate := domain.SectionType(strings.ToLower(strings.TrimSpace(title))); candidate.IsValid() {
+				section = string(candidate)
+			}
+			continue
+		}
+
+		// Decision record lines nest under the decision they describe
+		if len(records) > 0 && domain.ParseDecisionLine(&records[len(records)-1].Entry.DecisionRecord, line) {
+			continue
+		}
+
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			item, ok = strings.CutPrefix(line, "* ")
+		}
+		if !ok || strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		match := markdownBulletPattern.FindStringSubmatch(strings.TrimSpace(item))
+		tag := match[1]
+		if tag == "" {
+			tag = match[2]
+		}
+		records = append(records, ImportRecord{
+			Source: fmt.Sprintf("line %d", lineNo),
+			Entry: EntryJSON{
+				Section:   section,
+				Tag:       tag,
+				Content:   domain.UnescapeContent(strings.TrimSpace(match[3])),
+				Rationale: domain.UnescapeContent(strings.TrimSpace(match[4])),
+			},
+		})
+	}
+	return records
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// Format normalizes the layout of the memory file and reports whether it was not
+// formatted. With check, the file is left unchanged.
+func (u *MemoryUseCase) Format(ctx context.Context, check bool) (bool, error) {
+	changed := false
+	err := u.repo.Rewrite(ctx, func(content string) (string, error) {
+		formatted := persistence.FormatMemory(content)
+		changed = formatted != content
+		if check {
+			return content, nil
+		}
+		return formatted, nil
+	})
+	return changed, err
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+	"github.com/herewei/ohmymem-core/internal/version"
+)
+
+// Health statuses
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+)
+
+// HealthReport describes the state of a running MCP server process
+type HealthReport struct {
+	Status        string `json:"status"`
+	Version       string `json:"version"`
+	Uptime        string `json:"uptime"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	BasePath      string `json:"base_path,omitempty"`
+	MemoryPath    string `json:"memory_path,omitempty"`
+	Readable      bool   `json:"readable"`
+	Writable      bool   `json:"writable"`
+	Locked        bool   `json:"locked"` // Another process holds the write lock, so captures would wait
+}
+
+// HealthChecker reports uptime and memory file accessibility
+type HealthChecker struct {
+	basePath     string
+	memoryPath   string
+	lockPath     string
+	startedAt    time.Time
+	timeProvider domain.TimeProvider
+}
+
+// NewHealthChecker creates a checker for the project at basePath; uptime counts from now
+func NewHealthChecker(basePath string, timeProvider domain.TimeProvider) *HealthChecker {
+	if absPath, err := filepath.Abs(basePath); err == nil {
+		basePath = absPath
+	}
+	return &HealthChecker{
+		basePath:     basePath,
+		memoryPath:   filepath.Join(basePath, persistence.DirName, persistence.FileName),
+		lockPath:     filepath.Join(basePath, persistence.DirName, persistence.LockFileName),
+		startedAt:    timeProvider.Now(),
+		timeProvider: timeProvider,
+	}
+}
+
+// Check probes the memory file and its write lock without modifying either
+func (c *HealthChecker) Check() HealthReport {
+	uptime := c.timeProvider.Now().Sub(c.startedAt).Truncate(time.Second)
+	report := HealthReport{
+		Status:        HealthStatusOK,
+		Version:       version.Version,
+		Uptime:        uptime.String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		BasePath:      c.basePath,
+		MemoryPath:    c.memoryPath,
+		Readable:      canOpen(c.memoryPath, os.O_RDONLY),
+		Writable:      canOpen(c.memoryPath, os.O_WRONLY|os.O_APPEND),
+	}
+	if locked, err := persistence.LockHeld(c.lockPath); err != nil || locked {
+		report.Locked = true
+	}
+	if !report.Readable || !report.Writable || report.Locked {
+		report.Status = HealthStatusDegraded
+	}
+	return report
+}
+
+// NewHealthHandler serves the health report without filesystem paths, since it is
+// unauthenticated; a degraded report is served with 503 Service Unavailable
+func NewHealthHandler(health *HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := health.Check()
+		report.BasePath = ""
+		report.MemoryPath = ""
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != HealthStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.Warn("failed to write health report", "error", err)
+		}
+	})
+}
+
+// canOpen reports whether path can be opened with flag
+func canOpen(path string, flag int) bool {
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+}
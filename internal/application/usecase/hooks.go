@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// Git hooks managed by ohmymem
+const (
+	HookPreCommit        = "pre-commit"
+	HookPrepareCommitMsg = "prepare-commit-msg"
+)
+
+// Hooks lists the git hooks 'ohmymem hook install' installs by default
+var Hooks = []string{HookPreCommit, HookPrepareCommitMsg}
+
+// hookMarker identifies hook scripts written by ohmymem
+const hookMarker = "# ohmymem-hook:"
+
+// hookBackupSuffix is appended to a foreign hook replaced with --force; the ohmymem
+// hook runs it first so existing checks keep working
+const hookBackupSuffix = ".pre-ohmymem"
+
+// DecisionTrailer is the commit message trailer naming a decision captured in the commit
+const DecisionTrailer = "Memory-Decision"
+
+// ErrForeignHook is returned when a hook not written by ohmymem is in the way
+var ErrForeignHook = errors.New("hook exists and was not installed by ohmymem")
+
+// HookInstall describes one installed hook
+type HookInstall struct {
+	Name     string
+	Path     string
+	BackedUp bool // A foreign hook was moved aside and is chained
+}
+
+// HookUseCase installs and runs the git hooks of a project
+type HookUseCase struct {
+	rootPath string
+}
+
+// NewHookUseCase creates a hook use case for the git repository at rootPath
+func NewHookUseCase(rootPath string) *HookUseCase {
+	return &HookUseCase{rootPath: rootPath}
+}
+
+// Install writes the named hooks. A hook not written by ohmymem is only replaced with
+// force, in which case it is kept next to it and still runs first.
+func (h *HookUseCase) Install(ctx context.Context, names []string, force bool) ([]HookInstall, error) {
+	dir, err := h.hooksDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create hooks directory: %w", err)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "ohmymem"
+	}
+
+	var installed []HookInstall
+	for _, name := range names {
+		if err := validateHook(name); err != nil {
+			return installed, err
+		}
+		install := HookInstall{Name: name, Path: filepath.Join(dir, name)}
+		if data, err := os.ReadFile(install.Path); err == nil && !strings.Contains(string(data), hookMarker) {
+			if !force {
+				return installed, fmt.Errorf("%w: %s (use --force to chain it)", ErrForeignHook, install.Path)
+			}
+			if err := os.Rename(install.Path, install.Path+hookBackupSuffix); err != nil {
+				return installed, fmt.Errorf("back up %s: %w", install.Path, err)
+			}
+			install.BackedUp = true
+		}
+		if err := os.WriteFile(install.Path, []byte(hookScript(name, executable)), 0755); err != nil {
+			return installed, fmt.Errorf("write %s: %w", install.Path, err)
+		}
+		installed = append(installed, install)
+	}
+	return installed, nil
+}
+
+// Uninstall removes the ohmymem hooks and restores hooks they replaced. It returns
+// the paths removed; hooks not written by ohmymem are left alone.
+func (h *HookUseCase) Uninstall(ctx context.Context) ([]string, error) {
+	dir, err := h.hooksDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, name := range Hooks {
+		hookPath := filepath.Join(dir, name)
+		data, err := os.ReadFile(hookPath)
+		if err != nil || !strings.Contains(string(data), hookMarker) {
+			continue
+		}
+		if err := os.Remove(hookPath); err != nil {
+			return removed, fmt.Errorf("remove %s: %w", hookPath, err)
+		}
+		if err := os.Rename(hookPath+hookBackupSuffix, hookPath); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("restore %s: %w", hookPath, err)
+		}
+		removed = append(removed, hookPath)
+	}
+	return removed, nil
+}
+
+// PreCommit checks the format of the staged memory file and returns the problems
+// found; nothing is checked when the memory file is not part of the commit
+func (h *HookUseCase) PreCommit(ctx context.Context) ([]string, error) {
+	staged, err := h.git(ctx, "diff", "--cached", "--name-only", "--", path.Join(persistence.DirName, persistence.FileName))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(staged) == "" {
+		return nil, nil
+	}
+
+	content, err := persistence.ReadRevision(ctx, h.rootPath, "")
+	if err != nil {
+		// Staged for deletion
+		return nil, nil
+	}
+	return formatProblems(content), nil
+}
+
+// PrepareCommitMsg appends a trailer to the commit message in msgFile for each
+// decision the commit adds to the memory file. Merge, squash and amended commit
+// messages are left as they are.
+func (h *HookUseCase) PrepareCommitMsg(ctx context.Context, msgFile, source string) error {
+	if source == "merge" || source == "squash" || source == "commit" {
+		return nil
+	}
+
+	staged, err := persistence.ReadRevision(ctx, h.rootPath, "")
+	if err != nil {
+		return nil
+	}
+	committed, err := persistence.ReadRevision(ctx, h.rootPath, "HEAD")
+	if err != nil {
+		// First commit, or the memory file is new
+		committed = ""
+	}
+
+	var trailers []string
+	for _, entry := range domain.DiffEntries(persistence.ParseMemory(committed), persistence.ParseMemory(staged)).Added {
+		if entry.Section != domain.SectionDecisions || entry.ID == "" {
+			continue
+		}
+		trailers = append(trailers, fmt.Sprintf("%s: %s %s (%s)", DecisionTrailer, entry.Tag, entry.Content, entry.ID))
+	}
+	if len(trailers) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(msgFile)
+	if err != nil {
+		return fmt.Errorf("read commit message: %w", err)
+	}
+	return os.WriteFile(msgFile, []byte(appendTrailers(string(data), trailers)), 0644)
+}
+
+// appendTrailers adds trailers missing from msg after its text and before the
+// comment lines git strips
+func appendTrailers(msg string, trailers []string) string {
+	lines := strings.Split(msg, "\n")
+	cut := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			cut = i
+			break
+		}
+	}
+	text := strings.TrimRight(strings.Join(lines[:cut], "\n"), "\n")
+	comments := strings.Join(lines[cut:], "\n")
+
+	var missing []string
+	for _, trailer := range trailers {
+		if !strings.Contains(text, trailer) {
+			missing = append(missing, trailer)
+		}
+	}
+	if len(missing) == 0 {
+		return msg
+	}
+
+	var sb strings.Builder
+	sb.WriteString(text)
+	sb.WriteString("\n\n")
+	sb.WriteString(strings.Join(missing, "\n"))
+	sb.WriteString("\n")
+	if comments != "" {
+		sb.WriteString("\n" + comments)
+	}
+	return sb.String()
+}
+
+// hooksDir returns the hooks directory git uses, honouring core.hooksPath and worktrees
+func (h *HookUseCase) hooksDir(ctx context.Context) (string, error) {
+	dir, err := h.git(ctx, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	dir = strings.TrimSpace(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(h.rootPath, dir)
+	}
+	return dir, nil
+}
+
+// git runs a git command in the project and returns its output
+func (h *HookUseCase) git(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = h.rootPath
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// validateHook rejects hooks ohmymem does not provide
+func validateHook(name string) error {
+	for _, hook := range Hooks {
+		if name == hook {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown hook %q (expected %s)", name, strings.Join(Hooks, " or "))
+}
+
+// hookScript renders the shell script of a hook. It prefers ohmymem on PATH, falls
+// back to the binary that installed it, and skips the hook when neither is found.
+func hookScript(name, executable string) string {
+	quoted := "'" + strings.ReplaceAll(executable, "'", `'\''`) + "'"
+	return fmt.Sprintf(`#!/bin/sh
+%s installed by 'ohmymem hook install', removed by 'ohmymem hook uninstall'
+if [ -x "$0%s" ]; then
+	"$0%s" "$@" || exit $?
+fi
+ohmymem=ohmymem
+command -v "$ohmymem" >/dev/null 2>&1 || ohmymem=%s
+if ! command -v "$ohmymem" >/dev/null 2>&1; then
+	echo "ohmymem not found, skipping %s hook" >&2
+	exit 0
+fi
+exec "$ohmymem" hook run %s "$@"
+`, hookMarker, hookBackupSuffix, hookBackupSuffix, quoted, name, name)
+}
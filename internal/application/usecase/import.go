@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ImportOptions controls how records are imported
+type ImportOptions struct {
+	DryRun  bool   // Validate and report without writing
+	Force   bool   // Import records that look like duplicates
+	Section string // Section for records without one; defaults to note
+	Tag     string // Tag for records without one; defaults to a suggested tag
+}
+
+// ImportIssue describes a record that was skipped or rejected
+type ImportIssue struct {
+	Source  string
+	Content string
+	Reason  string
+}
+
+// ImportResult describes the outcome of an import
+type ImportResult struct {
+	Entries []domain.Entry // Entries appended, or that would be appended on a dry run
+	Skipped []ImportIssue  // Duplicates and already expired records
+	Invalid []ImportIssue  // Records failing validation; nothing is written when any exist
+}
+
+// Import validates records and appends them with fresh UUIDv7 IDs in a single write.
+// Original creation times are kept when present. If any record is invalid nothing is
+// written and the result lists the problems alongside a domain.ErrInvalidImport error.
+func (u *MemoryUseCase) Import(ctx context.Context, records []ImportRecord, opts ImportOptions) (*ImportResult, error) {
+	now := u.timeProvider.Now()
+	result := &ImportResult{}
+
+	for _, record := range records {
+		entry, skip, err := u.prepareImport(ctx, record.Entry, opts, now)
+		if err != nil {
+			result.Invalid = append(result.Invalid, ImportIssue{Source: record.Source, Content: record.Entry.Content, Reason: err.Error()})
+			continue
+		}
+		if skip == "" && !opts.Force {
+			skip, err = u.duplicateReason(ctx, entry, result.Entries)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if skip != "" {
+			result.Skipped = append(result.Skipped, ImportIssue{Source: record.Source, Content: entry.Content, Reason: skip})
+			continue
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	if len(result.Invalid) > 0 {
+		return result, fmt.Errorf("%w: %d of %d records failed validation, nothing was imported",
+			domain.ErrInvalidImport, len(result.Invalid), len(records))
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if err := u.memoryService.AppendEntries(ctx, result.Entries); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// prepareImport turns a record into a validated entry. A non-empty skip reason
+// means the record is valid but should not be imported.
+func (u *MemoryUseCase) prepareImport(ctx context.Context, view EntryJSON, opts ImportOptions, now time.Time) (domain.Entry, string, error) {
+	input := domain.AppendInput{
+		Category:  strings.ToLower(strings.TrimSpace(firstNonEmpty(view.Section, opts.Section, string(domain.SectionNote)))),
+		Tag:       strings.Trim(strings.TrimSpace(firstNonEmpty(view.Tag, opts.Tag)), "[]"),
+		Content:   strings.TrimSpace(view.Content),
+		Rationale: strings.TrimSpace(view.Rationale),
+		Author:    view.Author,
+		ADR:       view.DecisionRecord,
+	}
+
+	if input.Tag == "" || strings.EqualFold(input.Tag, domain.AutoTag) {
+		suggested, err := u.memoryService.SuggestTag(ctx, input.Content)
+		if err != nil {
+			return domain.Entry{}, "", fmt.Errorf("suggest tag: %w", err)
+		}
+		input.Tag = suggested
+	}
+
+	scope, err := domain.NormalizeScope(view.Scope)
+	if err != nil {
+		return domain.Entry{}, "", err
+	}
+	input.Scope = scope
+
+	if err := u.memoryService.ValidateInput(input); err != nil {
+		return domain.Entry{}, "", err
+	}
+
+	status := domain.EntryStatus(strings.ToLower(strings.TrimSpace(view.Status)))
+	switch status {
+	case "", domain.StatusActive, domain.StatusDeprecated:
+	case domain.StatusSuperseded:
+		// Imported entries get new IDs, so the link to the replacement can't be kept
+		status = domain.StatusDeprecated
+	default:
+		return domain.Entry{}, "", fmt.Errorf("unknown status %q", view.Status)
+	}
+
+	createdAt := now
+	if view.CreatedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, view.CreatedAt)
+		if err != nil {
+			return domain.Entry{}, "", fmt.Errorf("created_at %q is not RFC3339", view.CreatedAt)
+		}
+		createdAt = parsed
+	}
+
+	if view.ExpiresAt != "" {
+		expiry, err := time.Parse(time.RFC3339, view.ExpiresAt)
+		if err != nil {
+			return domain.Entry{}, "", fmt.Errorf("%w: %q is not RFC3339", domain.ErrInvalidExpiry, view.ExpiresAt)
+		}
+		if !expiry.After(now) {
+			return domain.Entry{}, "expired", nil
+		}
+		input.ExpiresAt = expiry
+	}
+
+	id, err := u.uuidGen.NewV7()
+	if err != nil {
+		return domain.Entry{}, "", fmt.Errorf("generate ID: %w", err)
+	}
+
+	entry := u.memoryService.PrepareEntry(input, id, createdAt)
+	if status == domain.StatusDeprecated {
+		entry.Status = status
+	}
+	return entry, "", nil
+}
+
+// duplicateReason reports whether entry duplicates an existing entry or one
+// accepted earlier in the same import
+func (u *MemoryUseCase) duplicateReason(ctx context.Context, entry domain.Entry, accepted []domain.Entry) (string, error) {
+	input := domain.AppendInput{Category: string(entry.Section), Content: entry.Content}
+	duplicate, score, err := u.memoryService.FindDuplicate(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("check duplicates: %w", err)
+	}
+	if duplicate != nil {
+		return fmt.Sprintf("%.0f%% similar to existing entry %s", score*100, duplicate.ID), nil
+	}
+
+	detector := u.memoryService.Duplicates()
+	for _, other := range accepted {
+		if other.Section == entry.Section && detector.Score(entry.Content, other.Content) >= detector.Threshold() {
+			return "duplicate within the import", nil
+		}
+	}
+	return "", nil
+}
+
+// firstNonEmpty returns the first value that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
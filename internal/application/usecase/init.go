@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,9 +10,17 @@ import (
 	"time"
 
 	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
 )
 
+// AGENTS.md and the markers delimiting the block ohmymem manages inside it
+const (
+	agentsFileName   = "AGENTS.md"
+	agentsBlockStart = "<!-- ohmymem:start -->"
+	agentsBlockEnd   = "<!-- ohmymem:end -->"
+)
+
 type InitUseCase struct {
 	detector domain.ProjectDetector
 	template *domain.TemplateService
@@ -32,18 +41,39 @@ func NewInitUseCaseWithTemplate(detector domain.ProjectDetector, template *domai
 
 // InitOptions init command options
 type InitOptions struct {
-	RootPath    string              // Project root directory
-	ProjectInfo *domain.ProjectInfo // Optional: reuse detected project info
-	Force       bool                // Force overwrite
-	Yes         bool                // Skip confirmation
-	RepoURLs    []string            // Custom template repository URLs
+	RootPath    string                // Project root directory
+	ProjectInfo *domain.ProjectInfo   // Optional: reuse detected project info
+	Force       bool                  // Force overwrite
+	Yes         bool                  // Skip confirmation
+	RepoURLs    []string              // Custom template repository URLs
+	Layers      []string              // Template repositories composed on top, in increasing priority; the user templates are added
+	Editors     []string              // Editor integrations to link to AGENTS.md; empty uses DefaultEditors
+	DryRun      bool                  // Plan the changes without writing anything
+	Fetch       template.FetchOptions // Template ref and cache settings
+	Templates   []string              // IDs of the templates to compose besides the bases; nil uses those applying to the project
+
+	// Confirm is called with the generated memory.md and AGENTS.md block before
+	// anything is written; returning false cancels with ErrInitCancelled
+	Confirm func(memoryContent, agentsBlock string) (bool, error)
 }
 
+// ErrInitCancelled is returned by Execute when Confirm declines the generated files
+var ErrInitCancelled = errors.New("init cancelled")
+
 // InitResult init result
 type InitResult struct {
 	ProjectInfo  *domain.ProjectInfo
 	CreatedFiles []string
+	Changes      []InitChange // Planned changes of a dry run
 	Warnings     []string
+	Hooks        []domain.TemplateHook // Post-init hooks of the templates, for the caller to confirm and run with RunHook
+}
+
+// InitChange is a file change planned by a dry run
+type InitChange struct {
+	Path   string
+	Action string
+	Diff   string // Line diff of the AGENTS.md block: "+" added, "-" removed, indented unchanged
 }
 
 // Preview prepares init by detecting project.
@@ -67,10 +97,15 @@ func (uc *InitUseCase) Execute(opts InitOptions) (*InitResult, error) {
 
 	// 1. Check if already initialized
 	memoryPath := filepath.Join(opts.RootPath, ".ohmymem", "memory.md")
-	if fileExists(memoryPath) && !opts.Force {
+	if fileExists(memoryPath) && !opts.Force && !opts.DryRun {
 		return nil, fmt.Errorf("already initialized. Use '--force' to overwrite")
 	}
 
+	editors, err := ResolveEditors(opts.Editors)
+	if err != nil {
+		return nil, err
+	}
+
 	// 2. Resolve and detect project (or reuse provided info)
 	info, err := uc.resolveAndDetect(opts)
 	if err != nil {
@@ -80,12 +115,19 @@ func (uc *InitUseCase) Execute(opts InitOptions) (*InitResult, error) {
 
 	// 3. Generate memory content from templates
 	ctx := context.Background()
-	repoURLs := opts.RepoURLs
-	if len(repoURLs) == 0 {
-		repoURLs = template.GetDefaultRepoURLs()
+	repoURLs, err := resolveRepoURLs(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	memoryContent, agentsContent, err := uc.template.InitTemplate(ctx, info, repoURLs)
+	generated, err := uc.template.Init(ctx, info, repoURLs, opts.Templates)
+	if err != nil && usesDefaultRepos(repoURLs) {
+		// The default repositories are unreachable (offline, air-gapped): use the built-in templates
+		generated, err = uc.embeddedTemplate(info, opts.Templates)
+		if err == nil {
+			result.Warnings = append(result.Warnings, "Warning: could not fetch the template repository, used the built-in templates. Run 'ohmymem template update' once online.")
+		}
+	}
 	if err != nil {
 		// If user specifies a single custom repo, surface the raw git error directly.
 		if len(opts.RepoURLs) == 1 {
@@ -93,6 +135,27 @@ func (uc *InitUseCase) Execute(opts InitOptions) (*InitResult, error) {
 		}
 		return nil, fmt.Errorf("generate template: %w", err)
 	}
+	memoryContent, agentsContent := generated.Memory, generated.Agents
+	result.Hooks = generated.Hooks
+
+	if opts.Confirm != nil && !opts.DryRun {
+		confirmed, err := opts.Confirm(memoryContent, agentsBlock(agentsContent))
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed {
+			return nil, ErrInitCancelled
+		}
+	}
+
+	if opts.DryRun {
+		changes, err := uc.planChanges(opts.RootPath, info, memoryContent, agentsContent, editors)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = changes
+		return result, nil
+	}
 
 	// 4. Create .ohmymem directory
 	ohmymemDir := filepath.Join(opts.RootPath, ".ohmymem")
@@ -106,24 +169,28 @@ func (uc *InitUseCase) Execute(opts InitOptions) (*InitResult, error) {
 	}
 	result.CreatedFiles = append(result.CreatedFiles, memoryPath)
 
+	// Save the detected stack so later commands reuse it instead of detecting again
+	if info.IsDetected() {
+		if err := persistence.SaveProjectInfo(opts.RootPath, info); err != nil {
+			return nil, err
+		}
+		result.CreatedFiles = append(result.CreatedFiles, persistence.ProjectFilePath(opts.RootPath))
+	}
+
 	// 6. Write/Update AGENTS.md
-	agentsPath := filepath.Join(opts.RootPath, "AGENTS.md")
+	agentsPath := filepath.Join(opts.RootPath, agentsFileName)
 	if err := uc.updateAgentsFile(agentsPath, agentsContent); err != nil {
 		return nil, fmt.Errorf("update AGENTS.md: %w", err)
 	}
 	result.CreatedFiles = append(result.CreatedFiles, agentsPath)
 
 	// 7. Create symlinks
-	symlinks := map[string]string{
-		".cursorrules": "AGENTS.md",
-		"CLAUDE.md":    "AGENTS.md",
-	}
-
-	for link, target := range symlinks {
-		linkPath := filepath.Join(opts.RootPath, link)
+	for _, editor := range editors {
+		linkPath := filepath.Join(opts.RootPath, editor.Path)
+		target := agentsLinkTarget(editor.Path)
 		if err := uc.createSymlink(linkPath, target); err != nil {
 			// Symlink failure is not fatal, record warning for caller
-			result.Warnings = append(result.Warnings, fmt.Sprintf("Warning: failed to create symlink %s: %v", link, err))
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Warning: failed to create symlink %s: %v", editor.Path, err))
 		} else {
 			result.CreatedFiles = append(result.CreatedFiles, linkPath+" → "+target)
 		}
@@ -135,7 +202,13 @@ func (uc *InitUseCase) Execute(opts InitOptions) (*InitResult, error) {
 func (uc *InitUseCase) resolveAndDetect(opts InitOptions) (*domain.ProjectInfo, error) {
 	// Initialize template service if not provided
 	if uc.template == nil {
-		uc.initDefaultTemplateService()
+		repoURLs, err := resolveRepoURLs(opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.initDefaultTemplateService(opts, repoURLs); err != nil {
+			return nil, err
+		}
 	}
 
 	// Detect project (or reuse provided info)
@@ -151,16 +224,142 @@ func (uc *InitUseCase) resolveAndDetect(opts InitOptions) (*domain.ProjectInfo,
 	return info, nil
 }
 
+// embeddedTemplate generates memory and agents content from the built-in templates
+func (uc *InitUseCase) embeddedTemplate(info *domain.ProjectInfo, selected []string) (*domain.GeneratedTemplate, error) {
+	path, err := template.ExtractEmbedded()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(path)
+	return uc.template.GenerateLayered(context.Background(), path, info, selected)
+}
+
+// TemplateChoices returns the optional templates of the repository for the project,
+// pre-selected when they apply to it
+func (uc *InitUseCase) TemplateChoices(opts InitOptions, info *domain.ProjectInfo) ([]domain.TemplateChoice, error) {
+	repoURLs, err := resolveRepoURLs(opts)
+	if err != nil {
+		return nil, err
+	}
+	if uc.template == nil {
+		if err := uc.initDefaultTemplateService(opts, repoURLs); err != nil {
+			return nil, err
+		}
+	}
+	return uc.template.TemplateChoices(context.Background(), info, repoURLs)
+}
+
+// resolveRepoURLs returns the template repositories of opts, the default ones when
+// none is given, with "local" standing for the user templates directory
+func resolveRepoURLs(opts InitOptions) ([]string, error) {
+	repoURLs := opts.RepoURLs
+	if len(repoURLs) == 0 {
+		repoURLs = template.GetDefaultRepoURLs()
+	}
+	return template.ResolveRepoURLs(repoURLs)
+}
+
+// usesDefaultRepos reports whether repoURLs are the default repositories, which
+// the built-in templates stand in for; custom repositories fail loudly instead
+func usesDefaultRepos(repoURLs []string) bool {
+	for _, repoURL := range repoURLs {
+		if !template.IsDefaultRepo(repoURL) {
+			return false
+		}
+	}
+	return true
+}
+
 // SetTemplateService sets the template service (for testing)
 func (uc *InitUseCase) SetTemplateService(svc *domain.TemplateService) {
 	uc.template = svc
 }
 
-// initDefaultTemplateService initializes the default template service
-func (uc *InitUseCase) initDefaultTemplateService() {
-	repo := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout)
+// initDefaultTemplateService initializes the default template service, composing the
+// layers of opts and the user templates on top of repoURLs
+func (uc *InitUseCase) initDefaultTemplateService(opts InitOptions, repoURLs []string) error {
+	layers, err := template.Layers(repoURLs, opts.Layers)
+	if err != nil {
+		return err
+	}
+	repo := template.NewTemplateRepository(opts.Fetch)
 	loader := domain.NewLocalTemplateLoader()
-	uc.template = domain.NewTemplateService(repo, loader)
+	uc.template = domain.NewTemplateService(repo, loader).WithLayers(layers)
+	return nil
+}
+
+// planChanges lists what Execute would write, without writing it
+func (uc *InitUseCase) planChanges(rootPath string, info *domain.ProjectInfo, memoryContent, agentsContent string, editors []EditorIntegration) ([]InitChange, error) {
+	var changes []InitChange
+
+	memoryPath := filepath.Join(rootPath, ".ohmymem", "memory.md")
+	action := fmt.Sprintf("create (%d lines)", strings.Count(memoryContent, "\n"))
+	if fileExists(memoryPath) {
+		action = fmt.Sprintf("overwrite (%d lines)", strings.Count(memoryContent, "\n"))
+	}
+	changes = append(changes, InitChange{Path: memoryPath, Action: action})
+
+	if info.IsDetected() {
+		projectPath := persistence.ProjectFilePath(rootPath)
+		action = "create"
+		if fileExists(projectPath) {
+			action = "overwrite"
+		}
+		changes = append(changes, InitChange{Path: projectPath, Action: action})
+	}
+
+	agentsPath := filepath.Join(rootPath, agentsFileName)
+	existing := ""
+	if fileExists(agentsPath) {
+		data, err := os.ReadFile(agentsPath)
+		if err != nil {
+			return nil, err
+		}
+		existing = string(data)
+	}
+	oldBlock := ""
+	switch start, end := strings.Index(existing, agentsBlockStart), strings.Index(existing, agentsBlockEnd); {
+	case existing == "":
+		action = "create"
+	case start != -1 && end != -1:
+		action = "replace ohmymem block"
+		oldBlock = existing[start : end+len(agentsBlockEnd)]
+	default:
+		action = "append ohmymem block"
+	}
+	changes = append(changes, InitChange{Path: agentsPath, Action: action, Diff: lineDiff(oldBlock, agentsBlock(agentsContent))})
+
+	for _, editor := range editors {
+		linkPath := filepath.Join(rootPath, editor.Path)
+		target := agentsLinkTarget(editor.Path)
+		action := "symlink → " + target
+		if info, err := os.Lstat(linkPath); err == nil {
+			existingTarget, _ := os.Readlink(linkPath)
+			switch {
+			case info.Mode()&os.ModeSymlink == 0:
+				action = "skip (file exists and is not a symlink)"
+			case existingTarget == target:
+				action = "unchanged (already linked)"
+			default:
+				action = fmt.Sprintf("relink → %s (was %s)", target, existingTarget)
+			}
+		}
+		changes = append(changes, InitChange{Path: linkPath, Action: action})
+	}
+	return changes, nil
+}
+
+// agentsBlock renders the ohmymem block of AGENTS.md
+func agentsBlock(agentsContent string) string {
+	return fmt.Sprintf(`%s
+<!-- 
+  This section is managed by OhMyMem.
+  Manual edits within this block may be overwritten.
+  Last updated: %s
+-->
+
+%s
+%s`, agentsBlockStart, time.Now().Format(time.RFC3339), agentsContent, agentsBlockEnd)
 }
 
 // updateAgentsFile updates or creates AGENTS.md
@@ -176,23 +375,15 @@ func (uc *InitUseCase) updateAgentsFile(path, agentsContent string) error {
 	}
 
 	// Build ohmymem block
-	block := fmt.Sprintf(`<!-- ohmymem:start -->
-<!-- 
-  This section is managed by OhMyMem.
-  Manual edits within this block may be overwritten.
-  Last updated: %s
--->
-
-%s
-<!-- ohmymem:end -->`, time.Now().Format(time.RFC3339), agentsContent)
+	block := agentsBlock(agentsContent)
 
 	// Check if ohmymem block already exists
-	if strings.Contains(content, "<!-- ohmymem:start -->") {
+	if strings.Contains(content, agentsBlockStart) {
 		// Replace existing block
-		startIdx := strings.Index(content, "<!-- ohmymem:start -->")
-		endIdx := strings.Index(content, "<!-- ohmymem:end -->")
+		startIdx := strings.Index(content, agentsBlockStart)
+		endIdx := strings.Index(content, agentsBlockEnd)
 		if startIdx != -1 && endIdx != -1 {
-			endIdx += len("<!-- ohmymem:end -->")
+			endIdx += len(agentsBlockEnd)
 			content = content[:startIdx] + block + content[endIdx:]
 		}
 	} else {
@@ -228,6 +419,9 @@ func (uc *InitUseCase) createSymlink(linkPath, target string) error {
 		}
 	}
 
+	if err := os.MkdirAll(filepath.Dir(linkPath), 0755); err != nil {
+		return err
+	}
 	return os.Symlink(target, linkPath)
 }
 
@@ -235,3 +429,46 @@ func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
+
+// lineDiff renders the lines removed from old (prefixed "-") and added in new
+// (prefixed "+"), with unchanged lines indented, using a longest common subsequence
+func lineDiff(old, new string) string {
+	a := strings.Split(strings.TrimSuffix(old, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(new, "\n"), "\n")
+	if old == "" {
+		a = nil
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return sb.String()
+}
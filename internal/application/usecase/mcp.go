@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"log/slog"
 
@@ -11,6 +14,7 @@ import (
 
 	"github.com/herewei/ohmymem-core/internal/domain"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/adapters"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/audit"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
 	"github.com/herewei/ohmymem-core/internal/version"
 )
@@ -20,6 +24,9 @@ type McpUseCase struct {
 	memoryService *domain.MemoryService
 	uuidGen       domain.UUIDGenerator
 	timeProvider  domain.TimeProvider
+	projects      *ProjectRegistry // Optional: routes the "project" parameter
+	captureLimit  *RateLimiter     // Optional: limits captures per client
+	health        *HealthChecker   // Optional: enables the ohmymem_ping tool
 }
 
 // NewMcpUseCase creates a new MCP McpUseCase
@@ -35,95 +42,702 @@ func NewMcpUseCase(
 	}
 }
 
+// SetHealthChecker enables the ohmymem_ping tool
+func (h *McpUseCase) SetHealthChecker(health *HealthChecker) {
+	h.health = health
+}
+
+// SetProjectRegistry enables routing tool calls to other projects via the "project" parameter
+func (h *McpUseCase) SetProjectRegistry(projects *ProjectRegistry) {
+	h.projects = projects
+}
+
+// SetCaptureRateLimiter limits how fast each session may capture entries
+func (h *McpUseCase) SetCaptureRateLimiter(limiter *RateLimiter) {
+	h.captureLimit = limiter
+}
+
+// withProjectParam declares the shared optional "project" parameter
+func (h *McpUseCase) withProjectParam() mcp.ToolOption {
+	desc := "Optional project to operate on: a configured project name. Defaults to the server's working directory."
+	if h.projects != nil {
+		if h.projects.AllowsPaths() {
+			desc = "Optional project to operate on: a configured project name or the absolute path of an initialized project. Defaults to the server's working directory."
+		}
+		if names := h.projects.Names(); len(names) > 0 {
+			desc += fmt.Sprintf(" Configured projects: %s.", strings.Join(names, ", "))
+		}
+	}
+	return mcp.WithString("project", mcp.Description(desc))
+}
+
+// resolveService returns the memory service targeted by the request's "project" parameter
+func (h *McpUseCase) resolveService(request mcp.CallToolRequest) (*domain.MemoryService, error) {
+	return h.resolveProject(request.GetString("project", ""))
+}
+
+// resolveProject returns the memory service for a project name or path; empty selects the default
+func (h *McpUseCase) resolveProject(project string) (*domain.MemoryService, error) {
+	project = strings.TrimSpace(project)
+	if project == "" {
+		return h.memoryService, nil
+	}
+	if h.projects == nil {
+		return nil, fmt.Errorf("project routing is not enabled on this server")
+	}
+	return h.projects.Resolve(project)
+}
+
+// readOnlyAnnotations marks a tool that only reads memory, so clients can skip confirmation
+func readOnlyAnnotations(title string) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		t.Annotations = mcp.ToolAnnotation{
+			Title:           title,
+			ReadOnlyHint:    mcp.ToBoolPtr(true),
+			DestructiveHint: mcp.ToBoolPtr(false),
+			IdempotentHint:  mcp.ToBoolPtr(true),
+			OpenWorldHint:   mcp.ToBoolPtr(false),
+		}
+	}
+}
+
+// writeAnnotations marks a tool that modifies memory. Destructive tools (update,
+// delete) change or remove existing entries; additive tools only append.
+func writeAnnotations(title string, destructive bool) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		t.Annotations = mcp.ToolAnnotation{
+			Title:           title,
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(destructive),
+			IdempotentHint:  mcp.ToBoolPtr(false),
+			OpenWorldHint:   mcp.ToBoolPtr(false),
+		}
+	}
+}
+
 // RegisterTools registers the MCP tools with the server
 func (h *McpUseCase) RegisterTools(s *server.MCPServer) {
 	// Register ohmymem_read tool
 	readTool := mcp.NewTool("ohmymem_read",
+		readOnlyAnnotations("Read memory"),
 		mcp.WithDescription("Read the working memory file (.ohmymem/memory.md). Returns the raw Markdown content containing constraints, decisions, patterns, and anti-patterns."),
+		mcp.WithNumber("max_tokens",
+			mcp.Description("Optional token budget, estimated like tiktoken counts tokens. When the memory is larger, Constraints and Decisions are kept first and the most recent entries win."),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("max_chars",
+			mcp.Description("Optional character budget, same trimming as max_tokens. Both budgets apply when both are set."),
+			mcp.Min(1),
+		),
+		withFormatParam(),
+		withIncludeExpiredParam(),
+		withFilesParam(),
+		h.withProjectParam(),
 	)
 
 	s.AddTool(readTool, h.handleReadMemory)
 
+	// Register ohmymem_read_section tool
+	readSectionTool := mcp.NewTool("ohmymem_read_section",
+		readOnlyAnnotations("Read memory section"),
+		mcp.WithDescription("Read a single section of the working memory file. Use this instead of ohmymem_read when only one category is relevant, to save tokens."),
+		mcp.WithString("category",
+			mcp.Required(),
+			mcp.Description("Category: 'constraints', 'decisions', 'patterns', 'anti-patterns' or 'note'."),
+			mcp.Enum("constraints", "decisions", "patterns", "anti-patterns", "note"),
+		),
+		withFormatParam(),
+		withIncludeExpiredParam(),
+		withFilesParam(),
+		h.withProjectParam(),
+	)
+
+	s.AddTool(readSectionTool, h.handleReadSection)
+
+	// Register ohmymem_tags tool
+	tagsTool := mcp.NewTool("ohmymem_tags",
+		readOnlyAnnotations("List memory tags"),
+		mcp.WithDescription("List the tags already used in the working memory with counts and example entries, and the canonical tags with their aliases. Call this before capturing to reuse an existing tag instead of inventing a near-duplicate."),
+		h.withProjectParam(),
+	)
+
+	s.AddTool(tagsTool, h.handleListTags)
+
+	// Register ohmymem_summarize tool
+	summarizeTool := mcp.NewTool("ohmymem_summarize",
+		readOnlyAnnotations("Summarize memory"),
+		mcp.WithDescription("Return a condensed digest of the working memory grouped by section and tag, with near-duplicates removed and the most recent entries first. Use it when the full memory no longer fits your context."),
+		mcp.WithNumber("max_tokens",
+			mcp.Description("Optional token cap for the digest."),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("max_chars",
+			mcp.Description("Optional character cap for the digest. Both caps apply when both are set."),
+			mcp.Min(1),
+		),
+		h.withProjectParam(),
+	)
+
+	s.AddTool(summarizeTool, h.handleSummarize)
+
+	// Register ohmymem_search tool
+	searchTool := mcp.NewTool("ohmymem_search",
+		readOnlyAnnotations("Search memory"),
+		mcp.WithDescription("Search the working memory for entries containing every word of a query in their content, tag or rationale. Results are ranked by relevance with a boost for recent entries, the same ranking as 'ohmymem search'. Use it to find the few entries relevant to a task instead of reading the whole memory. With semantic: true, entries are matched by meaning instead (e.g. 'anything about caching?'), when an embedding provider is configured."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Words to search for, case-insensitive, e.g. 'postgres migrations'."),
+		),
+		mcp.WithString("category",
+			mcp.Description("Optional category to search: 'constraints', 'decisions', 'patterns', 'anti-patterns' or 'note'."),
+			mcp.Enum("constraints", "decisions", "patterns", "anti-patterns", "note"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Optional tag the entries must have, e.g. 'Database'."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of entries returned, best first. Defaults to %d.", defaultSearchLimit)),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("semantic",
+			mcp.Description("Rank entries by similarity of meaning to the query rather than by matching words. Requires embeddings.provider in the ohmymem config. Defaults to false."),
+		),
+		withIncludeExpiredParam(),
+		withFilesParam(),
+		h.withProjectParam(),
+	)
+
+	s.AddTool(searchTool, h.handleSearch)
+
 	// Register ohmymem_capture tool
 	captureTool := mcp.NewTool("ohmymem_capture",
+		writeAnnotations("Capture memory entry", false),
 		mcp.WithDescription("When you find some valueable to memory.use this tool to capture a new entry to the working memory file under a specific category."),
 		mcp.WithString("category",
 			mcp.Description("Category: 'constraints', 'decisions', 'patterns', 'anti-patterns' or 'note'. Defaults to 'note' if not specified."),
 			mcp.Enum("constraints", "decisions", "patterns", "anti-patterns", "note"),
 		),
 		mcp.WithString("tag",
-			mcp.Required(),
-			mcp.Description("Tag for the entry (max 50 chars, auto-wrapped in brackets)"),
+			mcp.Description("Tag for the entry (max 50 chars, auto-wrapped in brackets). Aliases such as 'db' are normalized to their canonical tag (see ohmymem_tags). Omit or pass 'auto' to have a tag suggested from the content and existing tags."),
 		),
 		mcp.WithString("content",
 			mcp.Required(),
-			mcp.Description("Content to remember (max 2000 chars, no newlines or markdown headers)"),
+			mcp.Description("Content to remember (max 2000 chars, a single line; inline `code` and angle brackets are fine). Reference related entries with ref:<id> (a unique ID prefix of 8+ chars is enough); references are checked and expanded to full IDs."),
 		),
 		mcp.WithString("rationale",
 			mcp.Description("Optional reason/justification (max 500 chars)"),
 		),
+		mcp.WithString("expires_at",
+			mcp.Description("Optional expiry for ephemeral facts, RFC3339 or YYYY-MM-DD. Expired entries are hidden from reads and later archived."),
+		),
+		mcp.WithNumber("ttl_days",
+			mcp.Description("Optional time-to-live in days, alternative to expires_at."),
+			mcp.Min(1),
+		),
+		mcp.WithArray("scope",
+			mcp.Description("Optional path patterns relative to the project root that the entry applies to, e.g. 'internal/api/**'. Omit for entries that apply to the whole project."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("supersedes",
+			mcp.Description("Optional ID of an active entry the new one replaces; it is kept, marked superseded and collapsed in reads. Required to capture a decision that contradicts an active constraint."),
+		),
+		mcp.WithString("context",
+			mcp.Description("Decisions only, optional: the context and forces that led to the decision (max 500 chars). With options and consequences, the decision doubles as a lightweight ADR."),
+		),
+		mcp.WithArray("options",
+			mcp.Description("Decisions only, optional: the alternatives considered, including the chosen one (at most 10, max 200 chars each)."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("consequences",
+			mcp.Description("Decisions only, optional: what the decision makes easier or harder (max 500 chars)."),
+		),
+		mcp.WithString("author",
+			mcp.Description("Optional name and model of the agent capturing the entry, e.g. 'Claude Sonnet 4'. Recorded with the MCP client name so teams can audit machine-written memory."),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Capture even if a similar entry already exists in the category. Defaults to false."),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate and check for duplicates without writing, returning the rendered entry block. Defaults to false."),
+		),
+		h.withProjectParam(),
 	)
 
 	s.AddTool(captureTool, h.handleCaptureMemory)
+
+	// Register ohmymem_ping tool
+	if h.health != nil {
+		pingTool := mcp.NewTool("ohmymem_ping",
+			readOnlyAnnotations("Ping server"),
+			mcp.WithDescription("Report server health: version, uptime, base path, and whether memory.md is readable and writable."),
+		)
+
+		s.AddTool(pingTool, h.handlePing)
+	}
+}
+
+// handlePing handles the ohmymem_ping tool request
+func (h *McpUseCase) handlePing(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return jsonResult(h.health.Check())
+}
+
+// Output formats supported by the read tools
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+// withFormatParam declares the shared "format" parameter of the read tools
+func withFormatParam() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("Output format: 'markdown' (default) returns the Markdown text, 'json' returns parsed entries with IDs and timestamps. Token budgets only apply to markdown."),
+		mcp.Enum(formatMarkdown, formatJSON),
+	)
+}
+
+// withIncludeExpiredParam declares the shared "include_expired" parameter of the read tools
+func withIncludeExpiredParam() mcp.ToolOption {
+	return mcp.WithBoolean("include_expired",
+		mcp.Description("Include entries whose expiry has passed. Defaults to false."),
+	)
+}
+
+// withFilesParam declares the shared "files" parameter of the read tools
+func withFilesParam() mcp.ToolOption {
+	return mcp.WithArray("files",
+		mcp.Description("Optional paths, relative to the project root, of the files you are working on. Entries scoped to other paths are left out; entries without a scope are always included."),
+		mcp.WithStringItems(),
+	)
+}
+
+// sectionJSON is the JSON representation of a section returned by the read tools
+type sectionJSON struct {
+	Category string      `json:"category"`
+	Entries  []EntryJSON `json:"entries"`
+}
+
+func toSectionJSON(section *domain.Section) sectionJSON {
+	out := sectionJSON{
+		Category: string(section.Type),
+		Entries:  make([]EntryJSON, 0, len(section.Entries)),
+	}
+	for _, entry := range section.Entries {
+		view := ToEntryJSON(entry)
+		view.Section = string(section.Type)
+		out.Entries = append(out.Entries, view)
+	}
+	return out
 }
 
 // handleReadMemory handles the ohmymem_read tool request
 func (h *McpUseCase) handleReadMemory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	content, err := h.memoryService.ReadMemory(ctx)
+	svc, err := h.resolveService(request)
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	includeExpired := request.GetBool("include_expired", false)
+	files := request.GetStringSlice("files", nil)
+	now := h.timeProvider.Now()
+
+	if request.GetString("format", formatMarkdown) == formatJSON {
+		sections, err := svc.ReadSections(ctx)
+		if err != nil {
+			slog.Error("failed to read memory", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read memory: %v", err)), nil
+		}
+		views := make([]sectionJSON, 0, len(sections))
+		for _, section := range sections {
+			if !includeExpired {
+				section = section.WithoutExpired(now)
+			}
+			views = append(views, toSectionJSON(section.ForFiles(files)))
+		}
+		return jsonResult(map[string]any{"sections": views})
+	}
+
+	maxChars, maxTokens := budgetsFromRequest(request)
+
+	content, err := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{
+		MaxChars:           maxChars,
+		MaxTokens:          maxTokens,
+		ExcludeExpired:     !includeExpired,
+		Now:                now,
+		Files:              files,
+		CollapseSuperseded: true,
+	})
 	if err != nil {
 		slog.Error("failed to read memory", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to read memory: %v", err)), nil
 	}
 
+	if budget := svc.TokenBudget(); budget > 0 && maxChars <= 0 && maxTokens <= 0 {
+		if tokens := svc.Tokens().Count(content); tokens > budget {
+			content += fmt.Sprintf("\n<!-- memory is ~%d tokens, over the %d token budget: pass max_tokens or use ohmymem_summarize -->\n", tokens, budget)
+		}
+	}
+
 	return mcp.NewToolResultText(content), nil
 }
 
+// handleReadSection handles the ohmymem_read_section tool request
+func (h *McpUseCase) handleReadSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	svc, err := h.resolveService(request)
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	category := request.GetString("category", "")
+
+	sectionType := domain.SectionType(category)
+	if !sectionType.IsValid() {
+		slog.Warn("invalid category", "category", category)
+		return mcp.NewToolResultError(fmt.Sprintf("Validation failed: %v: %s", domain.ErrInvalidCategory, category)), nil
+	}
+
+	section, err := svc.ReadSection(ctx, sectionType)
+	if err != nil {
+		slog.Error("failed to read section", "error", err, "category", category)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read section: %v", err)), nil
+	}
+
+	if !request.GetBool("include_expired", false) {
+		section = section.WithoutExpired(h.timeProvider.Now())
+	}
+	section = section.ForFiles(request.GetStringSlice("files", nil))
+
+	if request.GetString("format", formatMarkdown) == formatJSON {
+		return jsonResult(toSectionJSON(section))
+	}
+
+	content, err := svc.RenderSection(section, true)
+	if err != nil {
+		slog.Error("failed to render section", "error", err, "category", category)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to render section: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// budgetsFromRequest returns the max_chars and max_tokens parameters, zero when unset
+func budgetsFromRequest(request mcp.CallToolRequest) (maxChars, maxTokens int) {
+	return max(request.GetInt("max_chars", 0), 0), max(request.GetInt("max_tokens", 0), 0)
+}
+
+// handleSummarize handles the ohmymem_summarize tool request
+func (h *McpUseCase) handleSummarize(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	svc, err := h.resolveService(request)
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	maxChars, maxTokens := budgetsFromRequest(request)
+	summary, err := svc.Summarize(ctx, domain.SummaryOptions{
+		MaxChars:  maxChars,
+		MaxTokens: maxTokens,
+		Now:       h.timeProvider.Now(),
+	})
+	if err != nil {
+		slog.Error("failed to summarize memory", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to summarize memory: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(summary), nil
+}
+
+// defaultSearchLimit is the number of entries ohmymem_search returns by default
+const defaultSearchLimit = 10
+
+// handleSearch handles the ohmymem_search tool request
+func (h *McpUseCase) handleSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	svc, err := h.resolveService(request)
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	query := strings.TrimSpace(request.GetString("query", ""))
+	if query == "" {
+		return mcp.NewToolResultError("Validation failed: query cannot be empty"), nil
+	}
+	filter := domain.EntryFilter{
+		Tag:            request.GetString("tag", ""),
+		Files:          request.GetStringSlice("files", nil),
+		IncludeExpired: request.GetBool("include_expired", false),
+		Now:            h.timeProvider.Now(),
+	}
+	if category := request.GetString("category", ""); category != "" {
+		sectionType := domain.SectionType(category)
+		if !sectionType.IsValid() {
+			slog.Warn("invalid category", "category", category)
+			return mcp.NewToolResultError(fmt.Sprintf("Validation failed: %v: %s", domain.ErrInvalidCategory, category)), nil
+		}
+		filter.Sections = []domain.SectionType{sectionType}
+	}
+
+	var hits []domain.SearchHit
+	if request.GetBool("semantic", false) {
+		semantic, semanticErr := newSemanticService(svc)
+		if semanticErr != nil {
+			slog.Warn("semantic search unavailable", "error", semanticErr)
+			return mcp.NewToolResultError(semanticErr.Error()), nil
+		}
+		hits, err = semantic.Search(ctx, query, filter)
+	} else {
+		hits, err = domain.NewQueryService(svc).Search(ctx, query, filter)
+	}
+	if err != nil {
+		slog.Error("failed to search memory", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to search memory: %v", err)), nil
+	}
+	total := len(hits)
+	if limit := request.GetInt("limit", defaultSearchLimit); limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	var sb strings.Builder
+	if total == 0 {
+		sb.WriteString(fmt.Sprintf("No entries match %q.\n", query))
+	} else {
+		sb.WriteString(fmt.Sprintf("%d of %d matching entries, best first:\n", len(hits), total))
+	}
+	for _, hit := range hits {
+		entry := hit.Entry
+		sb.WriteString(fmt.Sprintf("- %s %s %s (%s, score %.2f)", entry.ID, entry.Tag, entry.Content, entry.Section, hit.Score))
+		if entry.Rationale != "" {
+			sb.WriteString(fmt.Sprintf(" Rationale: %s", entry.Rationale))
+		}
+		sb.WriteString("\n")
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"hits": ToSearchHitsJSON(hits), "total": total}, sb.String()), nil
+}
+
+// tagJSON is the JSON representation of a tag summary
+type tagJSON struct {
+	Tag      string      `json:"tag"`
+	Count    int         `json:"count"`
+	Sections []string    `json:"sections"`
+	Examples []EntryJSON `json:"examples"`
+}
+
+// handleListTags handles the ohmymem_tags tool request
+func (h *McpUseCase) handleListTags(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	svc, err := h.resolveService(request)
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tags, err := svc.ListTags(ctx)
+	if err != nil {
+		slog.Error("failed to list tags", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list tags: %v", err)), nil
+	}
+
+	taxonomy := svc.Tags().Taxonomy()
+	var sb strings.Builder
+	if len(tags) == 0 {
+		sb.WriteString("No tags in use yet.\n")
+	}
+
+	views := make([]tagJSON, 0, len(tags))
+	for _, tag := range tags {
+		sections := make([]string, 0, len(tag.Sections))
+		for _, section := range tag.Sections {
+			sections = append(sections, string(section))
+		}
+		view := tagJSON{Tag: tag.Name, Count: tag.Count, Sections: sections}
+		sb.WriteString(fmt.Sprintf("- [%s] x%d (%s)\n", tag.Name, tag.Count, strings.Join(sections, ", ")))
+		for _, example := range tag.Examples {
+			view.Examples = append(view.Examples, EntryJSON{
+				ID:      example.ID,
+				Tag:     example.TagName,
+				Content: example.Content,
+			})
+			sb.WriteString(fmt.Sprintf("    e.g. %s\n", example.Content))
+		}
+		views = append(views, view)
+	}
+
+	sb.WriteString("\nCanonical tags (aliases are normalized on capture):\n")
+	for _, definition := range taxonomy {
+		sb.WriteString(fmt.Sprintf("- [%s]", definition.Name))
+		if len(definition.Aliases) > 0 {
+			sb.WriteString(" ← " + strings.Join(definition.Aliases, ", "))
+		}
+		sb.WriteString("\n")
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"tags": views, "taxonomy": taxonomy}, sb.String()), nil
+}
+
+// jsonResult wraps a JSON-serializable value as a tool result
+func jsonResult(data any) (*mcp.CallToolResult, error) {
+	result, err := mcp.NewToolResultJSON(data)
+	if err != nil {
+		slog.Error("failed to encode JSON result", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode result: %v", err)), nil
+	}
+	return result, nil
+}
+
+// agentAuthor returns the author of an entry captured over MCP: the agent name given
+// by the caller followed by the client name and version from the session, e.g.
+// "agent:Claude Sonnet 4 via claude-code 1.0.3"
+func agentAuthor(ctx context.Context, name string) string {
+	client := ""
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo); ok {
+		info := session.GetClientInfo()
+		client = strings.TrimSpace(info.Name + " " + info.Version)
+	}
+	name = strings.TrimSpace(name)
+	switch {
+	case name != "" && client != "":
+		return domain.NewAuthor(domain.AuthorAgent, name+" via "+client)
+	case name != "":
+		return domain.NewAuthor(domain.AuthorAgent, name)
+	default:
+		return domain.NewAuthor(domain.AuthorAgent, client)
+	}
+}
+
+// trimOptions trims the options considered of a decision, dropping empty ones
+func trimOptions(options []string) []string {
+	var trimmed []string
+	for _, option := range options {
+		if option = strings.TrimSpace(option); option != "" {
+			trimmed = append(trimmed, option)
+		}
+	}
+	return trimmed
+}
+
 // handleCaptureMemory handles the ohmymem_capture tool request
 func (h *McpUseCase) handleCaptureMemory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Extract parameters
-	category := request.GetString("category", "")
-	tag := request.GetString("tag", "")
-	content := request.GetString("content", "")
-	rationale := request.GetString("rationale", "")
+	// Dry runs never write, so they don't count against the rate limit
+	dryRun := request.GetBool("dry_run", false)
+	if !dryRun {
+		if allowed, retryAfter := h.captureLimit.Allow(rateLimitKey(ctx)); !allowed {
+			slog.Warn("capture rate limit exceeded", "client", rateLimitKey(ctx), "retry_after", retryAfter)
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"Rate limit exceeded: too many captures in a short time. Retry in %s, and only capture information worth remembering.",
+				retryAfter.Round(time.Second))), nil
+		}
+	}
 
-	// Validate input
-	input := domain.AppendInput{
-		Category:  category,
-		Tag:       tag,
-		Content:   content,
-		Rationale: rationale,
+	svc, err := h.resolveService(request)
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	if err := h.memoryService.ValidateInput(input); err != nil {
-		slog.Warn("validation failed", "error", err, "category", category, "tag", tag)
+	expiresAt, err := domain.ParseExpiry(request.GetString("expires_at", ""), request.GetInt("ttl_days", 0), h.timeProvider.Now())
+	if err != nil {
+		slog.Warn("validation failed", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Validation failed: %v", err)), nil
 	}
-
-	// Generate ID and timestamp
-	id, err := h.uuidGen.NewV7()
+	scope, err := domain.NormalizeScope(request.GetStringSlice("scope", nil))
 	if err != nil {
-		slog.Error("failed to generate UUID", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate ID: %v", err)), nil
+		slog.Warn("validation failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Validation failed: %v", err)), nil
 	}
-	now := h.timeProvider.Now()
 
-	// Append to memory
-	if err := h.memoryService.AppendMemory(ctx, input, id, now); err != nil {
+	input := domain.AppendInput{
+		Category:   request.GetString("category", ""),
+		Tag:        request.GetString("tag", ""),
+		Content:    request.GetString("content", ""),
+		Rationale:  request.GetString("rationale", ""),
+		ExpiresAt:  expiresAt,
+		Scope:      scope,
+		Supersedes: request.GetString("supersedes", ""),
+		Author:     agentAuthor(ctx, request.GetString("author", "")),
+		ADR: domain.DecisionRecord{
+			Context:      strings.TrimSpace(request.GetString("context", "")),
+			Options:      trimOptions(request.GetStringSlice("options", nil)),
+			Consequences: strings.TrimSpace(request.GetString("consequences", "")),
+		},
+	}
+	result, err := newServiceUseCase(svc, h.uuidGen, h.timeProvider).Add(ctx, input, AddOptions{
+		Force:  request.GetBool("force", false),
+		DryRun: dryRun,
+	})
+	switch {
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		slog.Debug("possible duplicate rejected", "category", result.Section, "duplicate_id", result.Duplicates[0].Entry.ID, "score", result.Duplicates[0].Score)
+		return mcp.NewToolResultText(formatDuplicateCandidates(result.Duplicates) + "\nNothing was captured. Pass force: true to capture anyway."), nil
+	case errors.Is(err, domain.ErrContradiction):
+		slog.Debug("contradicting decision rejected", "constraint_id", result.Contradicted.ID)
+		return mcp.NewToolResultText(formatContradiction(*result.Contradicted) + "\nNothing was captured. Pass supersedes: " + result.Contradicted.ID + " to replace the constraint, or drop the decision."), nil
+	case errors.Is(err, domain.ErrMemoryBusy):
+		slog.Error("failed to capture memory", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to capture to memory: %v. Another client is writing; retry shortly.", err)), nil
+	case isValidationError(err), errors.Is(err, domain.ErrEntryNotFound), errors.Is(err, domain.ErrAmbiguousID), errors.Is(err, domain.ErrInactiveEntry):
+		slog.Warn("validation failed", "error", err, "category", input.Category, "tag", input.Tag)
+		return mcp.NewToolResultError(fmt.Sprintf("Validation failed: %v", err)), nil
+	case err != nil:
 		slog.Error("failed to capture memory", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to capture to memory: %v", err)), nil
 	}
 
+	notes := ""
+	if result.AutoTagged {
+		notes += fmt.Sprintf(" Tag %s was chosen automatically.", result.Entry.Tag)
+	}
+	if result.TagAlias != "" {
+		notes += fmt.Sprintf(" Tag %q was normalized to %s.", result.TagAlias, result.Entry.Tag)
+	}
+
+	// Preview without writing
+	if dryRun {
+		rendered, err := svc.RenderEntry(result.Entry)
+		if err != nil {
+			slog.Error("failed to render entry", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to render entry: %v", err)), nil
+		}
+		text := fmt.Sprintf("Dry run: validation passed, nothing was written. The entry would be added to '%s' as:\n\n%s", result.Section, rendered)
+		if notes != "" {
+			text += "\n\n" + strings.TrimSpace(notes)
+		}
+		if len(result.Duplicates) > 0 {
+			text += "\n\nWarning: " + formatDuplicateCandidates(result.Duplicates) + "\nA real capture would be rejected unless force: true is passed."
+		}
+		if result.Contradicted != nil {
+			text += "\n\nWarning: " + formatContradiction(*result.Contradicted) + "\nA real capture would be rejected unless supersedes: " + result.Contradicted.ID + " is passed."
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+
 	slog.Debug("memory entry added",
-		"category", category,
-		"tag", tag,
-		"id", id)
+		"category", result.Section,
+		"tag", result.Entry.Tag,
+		"id", result.Entry.ID)
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully captured entry to '%s' category.", category)), nil
+	message := fmt.Sprintf("Successfully captured entry to '%s' category.", result.Section) + notes
+	if result.Superseded != nil {
+		message += fmt.Sprintf(" Entry %s is now superseded by %s.", result.Superseded.ID, result.Entry.ID)
+	}
+	return mcp.NewToolResultText(message), nil
+}
+
+// ServerOptions configures optional MCP server behavior
+type ServerOptions struct {
+	Projects          map[string]string // Project name -> base path, for multi-project routing
+	AllowProjectPaths bool              // Also route the absolute path of any initialized project
+	CapturesPerMinute int               // Capture rate per client; zero or less disables limiting
+	CaptureBurst      int               // Captures allowed in a burst before limiting kicks in
+	DisableAudit      bool              // Skip recording tool calls to .ohmymem/audit.log
+	InFlight          *InFlightTracker  // Optional: tracks tool calls so shutdown can drain them
+	Health            *HealthChecker    // Optional: enables the ohmymem_ping tool
 }
 
 // NewServer creates and configures a new MCP server
 func NewServer(
 	basePath string,
+	opts ServerOptions,
 ) (*server.MCPServer, *persistence.MarkdownMemoryRepository, error) {
 	// Initialize infrastructure
 	uuidGen := adapters.NewGoogleUUIDGenerator()
@@ -131,18 +745,69 @@ func NewServer(
 	repo := persistence.NewMemoryRepository(basePath, uuidGen, timeProvider)
 
 	// Initialize domain service
-	memoryService := domain.NewMemoryService(repo)
+	memoryService := newMemoryService(basePath, repo)
+
+	// Archive entries that expired since the last run
+	if pruned, err := memoryService.PruneExpired(context.Background(), timeProvider.Now()); err != nil {
+		slog.Error("failed to prune expired entries", "error", err)
+	} else if len(pruned) > 0 {
+		slog.Info("archived expired entries", "count", len(pruned))
+	}
 
 	// Create MCP server
+	serverOpts := []server.ServerOption{
+		server.WithToolCapabilities(true),
+	}
+	if opts.InFlight != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(opts.InFlight.Middleware()))
+	}
+	if !opts.DisableAudit {
+		auditLogger := audit.NewJSONLAuditLogger(repo.DirPath())
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(AuditMiddleware(auditLogger, timeProvider)))
+	}
+
 	s := server.NewMCPServer(
 		"OhMyMem MCP Server",
 		version.Version,
-		server.WithToolCapabilities(true),
+		serverOpts...,
 	)
 
 	// Create McpUseCase and register tools
 	McpUseCase := NewMcpUseCase(memoryService, uuidGen, timeProvider)
+	if len(opts.Projects) > 0 || opts.AllowProjectPaths {
+		projects := NewProjectRegistry(opts.Projects, uuidGen, timeProvider)
+		projects.SetAllowPaths(opts.AllowProjectPaths)
+		McpUseCase.SetProjectRegistry(projects)
+	}
+	McpUseCase.SetCaptureRateLimiter(NewRateLimiter(opts.CapturesPerMinute, opts.CaptureBurst, timeProvider))
+	McpUseCase.SetHealthChecker(opts.Health)
 	McpUseCase.RegisterTools(s)
+	McpUseCase.RegisterPrompts(s)
 
 	return s, repo, nil
 }
+
+// maxDuplicateCandidates is the number of similar entries a capture rejection lists
+const maxDuplicateCandidates = 3
+
+// formatContradiction describes the active constraint a decision contradicts
+func formatContradiction(constraint domain.Entry) string {
+	return fmt.Sprintf("The decision contradicts active constraint %s: %s %s", constraint.ID, constraint.Tag, constraint.Content)
+}
+
+// formatDuplicateCandidates describes the entries a capture may duplicate, most
+// similar first
+func formatDuplicateCandidates(candidates []domain.DuplicateCandidate) string {
+	best := candidates[0]
+	notice := fmt.Sprintf("Possible duplicate of entry %s (%.0f%% similar): %s %s",
+		best.Entry.ID, best.Score*100, best.Entry.Tag, best.Entry.Content)
+	for i, candidate := range candidates[1:] {
+		if i+1 == maxDuplicateCandidates {
+			notice += fmt.Sprintf("\n... and %d more similar entries", len(candidates)-maxDuplicateCandidates)
+			break
+		}
+		notice += fmt.Sprintf("\nAlso similar: entry %s (%.0f%% similar): %s %s",
+			candidate.Entry.ID, candidate.Score*100, candidate.Entry.Tag, candidate.Entry.Content)
+	}
+	return notice
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/adapters"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// MemoryUseCase implements the memory operations behind the CLI commands
+type MemoryUseCase struct {
+	basePath      string
+	memoryService *domain.MemoryService
+	repo          *persistence.MarkdownMemoryRepository
+	uuidGen       domain.UUIDGenerator
+	timeProvider  domain.TimeProvider
+}
+
+// NewMemoryUseCase creates a use case for the project at basePath
+func NewMemoryUseCase(basePath string) *MemoryUseCase {
+	uuidGen := adapters.NewGoogleUUIDGenerator()
+	timeProvider := adapters.NewSystemClock()
+	repo := persistence.NewMemoryRepository(basePath, uuidGen, timeProvider)
+
+	return &MemoryUseCase{
+		basePath:      basePath,
+		memoryService: newMemoryService(basePath, repo),
+		repo:          repo,
+		uuidGen:       uuidGen,
+		timeProvider:  timeProvider,
+	}
+}
+
+// newServiceUseCase wraps the memory service of a project the MCP server routes to, so
+// its captures go through Add like those of the CLI and the REST API. It has no
+// repository: only Add and the service itself may be used.
+func newServiceUseCase(svc *domain.MemoryService, uuidGen domain.UUIDGenerator, timeProvider domain.TimeProvider) *MemoryUseCase {
+	return &MemoryUseCase{memoryService: svc, uuidGen: uuidGen, timeProvider: timeProvider}
+}
+
+// newMemoryService creates the memory service of the project at basePath, with the
+// validation policy of the configuration and the default tag taxonomy extended by
+// .ohmymem/tags.yaml when present
+func newMemoryService(basePath string, repo domain.MemoryRepository) *domain.MemoryService {
+	svc := domain.NewMemoryService(repo)
+	if cfg, err := config.LoadProject(basePath); err != nil {
+		slog.Warn("using the default validation policy", "error", fmt.Errorf("load config: %w", err))
+	} else {
+		if err := svc.SetValidationPolicy(cfg.Validation.Policy()); err != nil {
+			slog.Warn("using the default validation policy", "error", err)
+		}
+		svc.SetTokenBudget(cfg.Budget.MaxTokens)
+	}
+
+	custom, err := persistence.LoadTagTaxonomy(basePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("ignoring invalid tag taxonomy", "path", persistence.TagsFilePath(basePath), "error", err)
+		}
+		return svc
+	}
+	svc.SetTags(domain.NewTagService(custom))
+	return svc
+}
+
+// Service returns the underlying memory service
+func (u *MemoryUseCase) Service() *domain.MemoryService {
+	return u.memoryService
+}
+
+// Compaction returns a compaction service over the same memory file
+func (u *MemoryUseCase) Compaction() *domain.CompactionService {
+	return domain.NewCompactionService(u.repo)
+}
+
+// EnsureInitialized returns an error when the project has no memory file yet
+func (u *MemoryUseCase) EnsureInitialized() error {
+	if _, err := os.Stat(u.repo.FilePath()); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no memory file at %s, run 'ohmymem init' first", u.repo.FilePath())
+		}
+		return fmt.Errorf("stat memory file: %w", err)
+	}
+	return nil
+}
+
+// AddOptions controls how an entry is added
+type AddOptions struct {
+	Force  bool // Add even when a similar entry already exists
+	DryRun bool // Run every check and prepare the entry, but write nothing
+}
+
+// AddResult describes an added entry
+type AddResult struct {
+	Entry      domain.Entry
+	Section    domain.SectionType
+	AutoTagged bool
+	TagAlias   string        // Tag as given when it was normalized to a canonical tag
+	Superseded *domain.Entry // Entry the new one supersedes, if any
+
+	// Checks that reject an entry; a dry run reports them instead
+	Duplicates   []domain.DuplicateCandidate // Similar entries of the section, most similar first
+	Contradicted *domain.Entry               // Active constraint the decision contradicts
+}
+
+// Add validates and appends an entry, suggesting a tag when none is given and
+// normalizing aliases to their canonical tag and expanding references to full IDs.
+// A near-duplicate in the same section is rejected with domain.ErrDuplicateEntry unless
+// forced; a decision contradicting an active constraint it does not supersede is
+// rejected with domain.ErrContradiction. On those two errors the result is returned
+// too, with the entries at fault. A dry run reports them without error and writes nothing.
+func (u *MemoryUseCase) Add(ctx context.Context, input domain.AppendInput, opts AddOptions) (*AddResult, error) {
+	if input.Category == "" {
+		input.Category = string(domain.SectionNote)
+	}
+	result := &AddResult{Section: domain.SectionType(input.Category)}
+
+	if tag := strings.TrimSpace(input.Tag); tag == "" || strings.EqualFold(tag, domain.AutoTag) {
+		suggested, err := u.memoryService.SuggestTag(ctx, input.Content)
+		if err != nil {
+			return nil, fmt.Errorf("suggest tag: %w", err)
+		}
+		input.Tag = suggested
+		result.AutoTagged = true
+	}
+	if canonical, ok := u.memoryService.Tags().Canonical(input.Tag); ok {
+		if canonical != strings.Trim(strings.TrimSpace(input.Tag), "[]") {
+			result.TagAlias = input.Tag
+		}
+		input.Tag = canonical
+	}
+
+	if err := u.memoryService.ResolveRefs(ctx, &input); err != nil {
+		return nil, err
+	}
+	if err := u.memoryService.ValidateInput(input); err != nil {
+		return nil, err
+	}
+
+	if !opts.Force {
+		candidates, err := u.memoryService.FindDuplicates(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("check duplicates: %w", err)
+		}
+		result.Duplicates = candidates
+		if len(candidates) > 0 && !opts.DryRun {
+			best := candidates[0]
+			return result, fmt.Errorf("%w: %.0f%% similar to %s %s %s",
+				domain.ErrDuplicateEntry, best.Score*100, best.Entry.ID, best.Entry.Tag, best.Entry.Content)
+		}
+	}
+
+	now := u.timeProvider.Now()
+	superseded, err := u.memoryService.ResolveSupersedes(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	contradicted, err := u.memoryService.FindContradiction(ctx, input, now)
+	if err != nil {
+		return nil, fmt.Errorf("check contradictions: %w", err)
+	}
+	result.Contradicted = contradicted
+	if contradicted != nil && !opts.DryRun {
+		return result, fmt.Errorf("%w: %s %s %s", domain.ErrContradiction, contradicted.ID, contradicted.Tag, contradicted.Content)
+	}
+
+	id, err := u.uuidGen.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("generate ID: %w", err)
+	}
+	result.Entry = u.memoryService.PrepareEntry(input, id, now)
+	if superseded != nil {
+		superseded.Status = domain.StatusSuperseded
+		superseded.SupersededBy = id
+		result.Superseded = superseded
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if err := u.memoryService.AppendMemory(ctx, input, id, now); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Migrate converts legacy inline entries to the anchored format and upgrades the
+// frontmatter schema, backing up the original file first. A dry run only reports.
+func (u *MemoryUseCase) Migrate(ctx context.Context, dryRun bool) (*persistence.MigrationResult, error) {
+	return u.repo.MigrateLegacy(ctx, dryRun)
+}
+
+// UserAuthor returns the author of entries captured through the CLI: the git
+// user.name of the project, falling back to the login name
+func (u *MemoryUseCase) UserAuthor(ctx context.Context) string {
+	if name, err := persistence.GitUserName(ctx, u.basePath); err == nil && name != "" {
+		return domain.NewAuthor(domain.AuthorUser, name)
+	}
+	if current, err := user.Current(); err == nil {
+		return domain.NewAuthor(domain.AuthorUser, current.Username)
+	}
+	return domain.NewAuthor(domain.AuthorUser, os.Getenv("USER"))
+}
+
+// Now returns the current time from the use case clock
+func (u *MemoryUseCase) Now() time.Time {
+	return u.timeProvider.Now()
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// MergeFiles merges theirs into ours at entry granularity, with base as the common
+// ancestor, and writes the result to oursPath as a git merge driver must. A missing
+// base is treated as empty.
+func MergeFiles(oursPath, theirsPath, basePath string) (*domain.MergeResult, error) {
+	ours, err := os.ReadFile(oursPath)
+	if err != nil {
+		return nil, fmt.Errorf("read ours: %w", err)
+	}
+	theirs, err := os.ReadFile(theirsPath)
+	if err != nil {
+		return nil, fmt.Errorf("read theirs: %w", err)
+	}
+	base, err := os.ReadFile(basePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read base: %w", err)
+	}
+
+	result := domain.MergeEntries(
+		persistence.ParseMemory(string(base)),
+		persistence.ParseMemory(string(ours)),
+		persistence.ParseMemory(string(theirs)),
+	)
+
+	merged := persistence.ApplyMerge(string(ours), result)
+	if merged != string(ours) {
+		if err := os.WriteFile(oursPath, []byte(merged), 0644); err != nil {
+			return nil, fmt.Errorf("write merged file: %w", err)
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// ProjectRegistry routes MCP tool calls to the memory of one of several projects
+// served by a single MCP process
+type ProjectRegistry struct {
+	mu         sync.Mutex
+	paths      map[string]string                // project name -> base path
+	allowPaths bool                             // Also route absolute paths of initialized projects
+	services   map[string]*domain.MemoryService // cleaned base path -> service
+	newService func(basePath string) *domain.MemoryService
+}
+
+// NewProjectRegistry creates a registry for the named project base paths
+func NewProjectRegistry(projects map[string]string, uuidGen domain.UUIDGenerator, timeProvider domain.TimeProvider) *ProjectRegistry {
+	paths := make(map[string]string, len(projects))
+	for name, path := range projects {
+		paths[name] = path
+	}
+	return &ProjectRegistry{
+		paths:    paths,
+		services: make(map[string]*domain.MemoryService),
+		newService: func(basePath string) *domain.MemoryService {
+			return newMemoryService(basePath, persistence.NewMemoryRepository(basePath, uuidGen, timeProvider))
+		},
+	}
+}
+
+// Names returns the configured project names in sorted order
+func (r *ProjectRegistry) Names() []string {
+	names := make([]string, 0, len(r.paths))
+	for name := range r.paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetAllowPaths lets Resolve route absolute paths of initialized projects besides the
+// configured names. Off by default: any client could otherwise read and write the
+// memory of every project on the machine.
+func (r *ProjectRegistry) SetAllowPaths(allow bool) {
+	r.allowPaths = allow
+}
+
+// AllowsPaths reports whether Resolve routes absolute project paths
+func (r *ProjectRegistry) AllowsPaths() bool {
+	return r.allowPaths
+}
+
+// Resolve returns the memory service for a configured project name or, when path
+// routing is allowed, for an absolute path to an already initialized project
+func (r *ProjectRegistry) Resolve(project string) (*domain.MemoryService, error) {
+	basePath, ok := r.paths[project]
+	if !ok {
+		if !r.allowPaths || !filepath.IsAbs(project) {
+			return nil, fmt.Errorf("unknown project %q (configured: %v)", project, r.Names())
+		}
+		if _, err := os.Stat(filepath.Join(project, persistence.DirName, persistence.FileName)); err != nil {
+			return nil, fmt.Errorf("project %q is not initialized, run 'ohmymem init' there first", project)
+		}
+		basePath = project
+	}
+	basePath = filepath.Clean(basePath)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	svc, ok := r.services[basePath]
+	if !ok {
+		svc = r.newService(basePath)
+		r.services[basePath] = svc
+	}
+	return svc, nil
+}
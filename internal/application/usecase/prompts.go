@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// BootPromptName is the MCP prompt that injects the boot protocol and current constraints
+const BootPromptName = "ohmymem_boot"
+
+// RegisterPrompts registers the MCP prompts with the server
+func (h *McpUseCase) RegisterPrompts(s *server.MCPServer) {
+	bootPrompt := mcp.NewPrompt(BootPromptName,
+		mcp.WithPromptDescription("Boot protocol plus the project's current constraints. Inject at the start of a conversation instead of relying on AGENTS.md instructions."),
+		mcp.WithArgument("project",
+			mcp.ArgumentDescription("Optional project name or absolute path when the server manages several projects."),
+		),
+	)
+
+	s.AddPrompt(bootPrompt, h.handleBootPrompt)
+}
+
+// handleBootPrompt renders the ohmymem_boot prompt
+func (h *McpUseCase) handleBootPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	svc, err := h.resolveProject(request.Params.Arguments["project"])
+	if err != nil {
+		slog.Warn("failed to resolve project", "error", err)
+		return nil, err
+	}
+
+	section, err := svc.ReadSection(ctx, domain.SectionConstraints)
+	if err != nil {
+		slog.Error("failed to read constraints", "error", err)
+		return nil, fmt.Errorf("read constraints: %w", err)
+	}
+	// Only the rules in force: superseded and deprecated constraints are left out
+	section = section.WithoutExpired(h.timeProvider.Now()).Active()
+
+	var sb strings.Builder
+	sb.WriteString(domain.BootProtocol)
+	sb.WriteString("\n")
+	if len(section.Entries) == 0 {
+		sb.WriteString("## Constraints\n\nNo constraints recorded yet.\n")
+	} else {
+		constraints, err := svc.RenderSection(section, false)
+		if err != nil {
+			slog.Error("failed to render constraints", "error", err)
+			return nil, fmt.Errorf("render constraints: %w", err)
+		}
+		sb.WriteString(constraints)
+	}
+
+	return mcp.NewGetPromptResult(
+		"OhMyMem boot protocol and current constraints",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(sb.String())),
+		},
+	), nil
+}
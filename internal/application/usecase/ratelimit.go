@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/transport"
+)
+
+// Default capture rate limits applied per MCP client
+const (
+	DefaultCapturesPerMinute = 30
+	DefaultCaptureBurst      = 10
+)
+
+// RateLimiter is a token bucket per client limiting how fast captures are accepted
+type RateLimiter struct {
+	mu           sync.Mutex
+	perMinute    int
+	burst        int
+	timeProvider domain.TimeProvider
+	buckets      map[string]*tokenBucket
+	lastSweep    time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perMinute captures with the given burst.
+// A perMinute of zero or less disables limiting.
+func NewRateLimiter(perMinute, burst int, timeProvider domain.TimeProvider) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		perMinute:    perMinute,
+		burst:        burst,
+		timeProvider: timeProvider,
+		buckets:      make(map[string]*tokenBucket),
+	}
+}
+
+// Allow consumes a token for the key, e.g. a client or session, and reports whether
+// the call may proceed. When rejected, it also returns how long until the next token
+// is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	if l == nil || l.perMinute <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.timeProvider.Now()
+	ratePerSecond := float64(l.perMinute) / 60
+	l.evictRefilled(now, ratePerSecond)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = bucket
+	}
+
+	// Refill based on elapsed time
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(float64(l.burst), bucket.tokens+elapsed*ratePerSecond)
+		bucket.last = now
+	}
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / ratePerSecond * float64(time.Second))
+		return false, wait
+	}
+
+	bucket.tokens--
+	return true, 0
+}
+
+// Tracked returns the number of keys the limiter holds a bucket for
+func (l *RateLimiter) Tracked() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// evictRefilled drops the buckets refilled to the burst since their last call: a new
+// bucket starts full, so forgetting them changes nothing. It sweeps at most once per
+// refill period.
+func (l *RateLimiter) evictRefilled(now time.Time, ratePerSecond float64) {
+	refill := time.Duration(float64(l.burst) / ratePerSecond * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*ratePerSecond >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitKey returns the key captures are limited by: the client host over HTTP,
+// where a client could open a session per call, else the MCP session
+func rateLimitKey(ctx context.Context) string {
+	if addr := transport.ClientAddr(ctx); addr != "" {
+		return "client:" + addr
+	}
+	return "session:" + sessionIDFromContext(ctx)
+}
+
+// sessionIDFromContext returns the MCP client session ID, or "default" for
+// transports without sessions
+func sessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return session.SessionID()
+	}
+	return "default"
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// maxRequestBody bounds the size of a POST /entries body
+const maxRequestBody = 64 << 10
+
+// EntryRequest is the body of POST /entries
+type EntryRequest struct {
+	Section    string   `json:"section"`
+	Tag        string   `json:"tag"` // Omit or "auto" to have one suggested
+	Content    string   `json:"content"`
+	Rationale  string   `json:"rationale"`
+	ExpiresAt  string   `json:"expires_at"` // RFC3339 or YYYY-MM-DD
+	TTLDays    int      `json:"ttl_days"`
+	Scope      []string `json:"scope"`      // Path patterns the entry applies to, e.g. "internal/api/**"
+	Force      bool     `json:"force"`      // Add even when a similar entry exists
+	Supersedes string   `json:"supersedes"` // ID or unique prefix of the entry the new one replaces
+	Author     string   `json:"author"`     // Agent capturing the entry; defaults to the User-Agent
+
+	// Decision record fields, for decisions only
+	Context      string   `json:"context"`
+	Options      []string `json:"options"`
+	Consequences string   `json:"consequences"`
+}
+
+// NewRESTHandler serves the memory of a project as a JSON REST API:
+//
+//	GET    /memory           all sections (Markdown text with ?format=markdown)
+//	GET    /sections/{name}  one section
+//
+// The GET endpoints take ?file= (repeatable) to keep only the entries whose scope
+// covers one of the files, along with the unscoped ones.
+//
+//	POST   /entries          add an entry, validated like the CLI and MCP tools
+//	DELETE /entries/{id}     delete an entry by ID or unique prefix
+//
+// POST bodies must be sent as application/json, and the write endpoints refuse
+// requests from another origin, so a web page cannot write to a local server.
+func NewRESTHandler(u *MemoryUseCase) http.Handler {
+	api := &restAPI{memory: u}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /memory", api.getMemory)
+	mux.HandleFunc("GET /sections/{name}", api.getSection)
+	mux.HandleFunc("POST /entries", sameOrigin(api.postEntry))
+	mux.HandleFunc("DELETE /entries/{id}", sameOrigin(api.deleteEntry))
+	return mux
+}
+
+// sameOrigin rejects requests whose Origin header names another host than the one
+// they were sent to. Browsers set it on cross-site requests, including the simple
+// ones they send without a CORS preflight.
+func sameOrigin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !strings.EqualFold(u.Host, r.Host) {
+				writeJSON(w, http.StatusForbidden, map[string]string{"error": "cross-origin request refused: " + origin})
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// restAPI implements the REST endpoints
+type restAPI struct {
+	memory *MemoryUseCase
+}
+
+func (a *restAPI) getMemory(w http.ResponseWriter, r *http.Request) {
+	svc := a.memory.Service()
+	includeExpired := r.URL.Query().Get("include_expired") == "true"
+	files := r.URL.Query()["file"]
+	now := a.memory.Now()
+
+	if r.URL.Query().Get("format") == formatMarkdown {
+		content, err := svc.ReadMemoryWithOptions(r.Context(), domain.ReadOptions{
+			ExcludeExpired:     !includeExpired,
+			Now:                now,
+			Files:              files,
+			CollapseSuperseded: true,
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		fmt.Fprint(w, content)
+		return
+	}
+
+	sections, err := svc.ReadSections(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	views := make([]sectionJSON, 0, len(sections))
+	for _, section := range sections {
+		if !includeExpired {
+			section = section.WithoutExpired(now)
+		}
+		views = append(views, toSectionJSON(section.ForFiles(files)))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"sections": views})
+}
+
+func (a *restAPI) getSection(w http.ResponseWriter, r *http.Request) {
+	sectionType := domain.SectionType(r.PathValue("name"))
+	if !sectionType.IsValid() {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("%v: %s", domain.ErrInvalidCategory, sectionType)})
+		return
+	}
+
+	section, err := a.memory.Service().ReadSection(r.Context(), sectionType)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if r.URL.Query().Get("include_expired") != "true" {
+		section = section.WithoutExpired(a.memory.Now())
+	}
+	writeJSON(w, http.StatusOK, toSectionJSON(section.ForFiles(r.URL.Query()["file"])))
+}
+
+func (a *restAPI) postEntry(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+		return
+	}
+
+	var req EntryRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body: " + err.Error()})
+		return
+	}
+
+	expiresAt, err := domain.ParseExpiry(req.ExpiresAt, req.TTLDays, a.memory.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	scope, err := domain.NormalizeScope(req.Scope)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result, err := a.memory.Add(r.Context(), domain.AppendInput{
+		Category:   req.Section,
+		Tag:        req.Tag,
+		Content:    strings.TrimSpace(req.Content),
+		Rationale:  strings.TrimSpace(req.Rationale),
+		ExpiresAt:  expiresAt,
+		Scope:      scope,
+		Supersedes: req.Supersedes,
+		Author:     domain.NewAuthor(domain.AuthorAgent, firstNonEmpty(req.Author, r.UserAgent())),
+		ADR: domain.DecisionRecord{
+			Context:      strings.TrimSpace(req.Context),
+			Options:      trimOptions(req.Options),
+			Consequences: strings.TrimSpace(req.Consequences),
+		},
+	}, AddOptions{Force: req.Force})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, ToEntryJSON(result.Entry))
+}
+
+func (a *restAPI) deleteEntry(w http.ResponseWriter, r *http.Request) {
+	svc := a.memory.Service()
+	entry, err := svc.FindEntry(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	deleted, err := svc.DeleteEntry(r.Context(), entry.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ToEntryJSON(*deleted))
+}
+
+// writeError maps domain errors to HTTP status codes
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case isValidationError(err):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrEntryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrDuplicateEntry), errors.Is(err, domain.ErrAmbiguousID),
+		errors.Is(err, domain.ErrContradiction), errors.Is(err, domain.ErrInactiveEntry):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrMemoryBusy):
+		status = http.StatusServiceUnavailable
+	default:
+		slog.Error("rest request failed", "error", err)
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// isValidationError reports whether err rejects the input of a request, as opposed to
+// a failure to serve it
+func isValidationError(err error) bool {
+	for _, target := range []error{
+		domain.ErrInvalidCategory, domain.ErrInvalidTag, domain.ErrInvalidContent,
+		domain.ErrInvalidRationale, domain.ErrForbiddenContent, domain.ErrListItem,
+		domain.ErrInvalidExpiry, domain.ErrInvalidScope, domain.ErrInvalidDecisionRecord,
+		domain.ErrInvalidReference,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("failed to write response", "error", err)
+	}
+}
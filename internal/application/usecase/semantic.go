@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/embeddings"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// newSemanticService returns the semantic search over memory, with the embedding
+// provider configured for its project and the index in .ohmymem/index. The error is
+// embeddings.ErrNotConfigured when no provider is set.
+func newSemanticService(memory *domain.MemoryService) (*domain.SemanticService, error) {
+	basePath := filepath.Dir(filepath.Dir(memory.GetMemoryPath()))
+	cfg, err := config.LoadProject(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	provider, err := embeddings.New(cfg.Embeddings.Provider, cfg.Embeddings.Model, cfg.Embeddings.URL, cfg.Embeddings.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewSemanticService(memory, provider, persistence.NewEmbeddingStore(basePath)), nil
+}
+
+// Semantic returns the semantic search of the project; the error is
+// embeddings.ErrNotConfigured when no embedding provider is configured
+func (u *MemoryUseCase) Semantic() (*domain.SemanticService, error) {
+	return newSemanticService(u.memoryService)
+}
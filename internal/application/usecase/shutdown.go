@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrShuttingDown is returned to tool calls arriving after shutdown began
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// InFlightTracker counts running tool calls so shutdown can wait for writes to finish
+type InFlightTracker struct {
+	mu      sync.Mutex
+	closing bool
+	wg      sync.WaitGroup
+}
+
+// NewInFlightTracker creates a tracker accepting tool calls
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{}
+}
+
+// Middleware rejects tool calls once the tracker is closed and tracks the rest
+func (t *InFlightTracker) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !t.acquire() {
+				return mcp.NewToolResultError(ErrShuttingDown.Error()), nil
+			}
+			defer t.wg.Done()
+			return next(ctx, request)
+		}
+	}
+}
+
+// acquire registers a call unless the tracker is closed
+func (t *InFlightTracker) acquire() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closing {
+		return false
+	}
+	t.wg.Add(1)
+	return true
+}
+
+// Drain stops accepting tool calls and waits for running ones until ctx is done
+func (t *InFlightTracker) Drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.closing = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
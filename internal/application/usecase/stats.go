@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// DefaultStatsTop is the number of tags and largest entries listed in a stats report
+const DefaultStatsTop = 10
+
+// GrowthPoint counts the entries added in a month and the running total
+type GrowthPoint struct {
+	Month string `json:"month"` // YYYY-MM
+	Added int    `json:"added"`
+	Total int    `json:"total"`
+}
+
+// GroupStats describes the entries of a section or tag
+type GroupStats struct {
+	Name      string  `json:"name"`
+	Entries   int     `json:"entries"`
+	AvgLength float64 `json:"avg_length"` // Average content length in characters
+}
+
+// SizedEntry is an entry with its content length in characters
+type SizedEntry struct {
+	EntryJSON
+	Length int `json:"length"`
+}
+
+// StatsReport analyses the entries of the memory to help decide what to prune
+type StatsReport struct {
+	TotalEntries int           `json:"total_entries"`
+	AvgLength    float64       `json:"avg_length"`
+	Undated      int           `json:"undated,omitempty"` // Entries without a timestamp, left out of Growth
+	Growth       []GrowthPoint `json:"growth"`
+	BySection    []GroupStats  `json:"by_section"`
+	ByTag        []GroupStats  `json:"by_tag"`
+	Largest      []SizedEntry  `json:"largest"`
+}
+
+// Stats computes entry growth per month, breakdowns by section and tag, and the
+// largest entries. top limits the tags and largest entries listed.
+func (u *MemoryUseCase) Stats(ctx context.Context, top int) (*StatsReport, error) {
+	entries, err := u.memoryService.ListEntries(ctx, domain.EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StatsReport{
+		TotalEntries: len(entries),
+		Growth:       []GrowthPoint{},
+		BySection:    []GroupStats{},
+		ByTag:        []GroupStats{},
+		Largest:      []SizedEntry{},
+	}
+	if len(entries) == 0 {
+		return report, nil
+	}
+
+	months := map[string]int{}
+	sections := map[domain.SectionType]*groupTotal{}
+	tags := map[string]*groupTotal{}
+	var tagOrder []string
+	totalLength := 0
+	sized := make([]SizedEntry, 0, len(entries))
+	for _, entry := range entries {
+		length := utf8.RuneCountInString(entry.Content)
+		totalLength += length
+		sized = append(sized, SizedEntry{EntryJSON: ToEntryJSON(entry), Length: length})
+
+		if created := entry.CapturedAt(); created.IsZero() {
+			report.Undated++
+		} else {
+			months[created.UTC().Format("2006-01")]++
+		}
+
+		if sections[entry.Section] == nil {
+			sections[entry.Section] = &groupTotal{}
+		}
+		sections[entry.Section].add(length)
+
+		key := strings.ToLower(entry.TagName)
+		if tags[key] == nil {
+			tags[key] = &groupTotal{name: entry.TagName}
+			tagOrder = append(tagOrder, key)
+		}
+		tags[key].add(length)
+	}
+	report.AvgLength = average(totalLength, len(entries))
+
+	monthKeys := make([]string, 0, len(months))
+	for month := range months {
+		monthKeys = append(monthKeys, month)
+	}
+	sort.Strings(monthKeys)
+	running := 0
+	for _, month := range monthKeys {
+		running += months[month]
+		report.Growth = append(report.Growth, GrowthPoint{Month: month, Added: months[month], Total: running})
+	}
+
+	for _, sectionType := range domain.ValidSections() {
+		if total, ok := sections[sectionType]; ok {
+			report.BySection = append(report.BySection, total.stats(string(sectionType)))
+		}
+	}
+
+	for _, key := range tagOrder {
+		report.ByTag = append(report.ByTag, tags[key].stats(tags[key].name))
+	}
+	sort.SliceStable(report.ByTag, func(i, j int) bool { return report.ByTag[i].Entries > report.ByTag[j].Entries })
+	if top > 0 && len(report.ByTag) > top {
+		report.ByTag = report.ByTag[:top]
+	}
+
+	sort.SliceStable(sized, func(i, j int) bool { return sized[i].Length > sized[j].Length })
+	if top > 0 && len(sized) > top {
+		sized = sized[:top]
+	}
+	report.Largest = sized
+
+	return report, nil
+}
+
+// groupTotal accumulates the entry count and content length of a group
+type groupTotal struct {
+	name    string
+	entries int
+	length  int
+}
+
+func (g *groupTotal) add(length int) {
+	g.entries++
+	g.length += length
+}
+
+func (g *groupTotal) stats(name string) GroupStats {
+	return GroupStats{Name: name, Entries: g.entries, AvgLength: average(g.length, g.entries)}
+}
+
+// average returns total/count rounded to one decimal
+func average(total, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Round(float64(total)/float64(count)*10) / 10
+}
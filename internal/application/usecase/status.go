@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// DefaultTopTags is the number of tags listed in a status report
+const DefaultTopTags = 5
+
+// agentsUpdatedRegex extracts the timestamp init writes into the AGENTS.md block
+var agentsUpdatedRegex = regexp.MustCompile(`Last updated: (\S+)`)
+
+// SectionStatus counts the entries of one section and the tokens they cost
+type SectionStatus struct {
+	Section    string `json:"section"`
+	Entries    int    `json:"entries"`
+	Expired    int    `json:"expired,omitempty"`
+	Deprecated int    `json:"deprecated,omitempty"`
+	Tokens     int    `json:"tokens"`
+}
+
+// TagCount is a tag and the number of entries using it
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// AgentsBlockStatus describes the ohmymem block in AGENTS.md
+type AgentsBlockStatus struct {
+	Present     bool   `json:"present"`
+	UpToDate    bool   `json:"up_to_date"`             // Block contains the current boot protocol
+	LastUpdated string `json:"last_updated,omitempty"` // Timestamp written by init
+}
+
+// StatusReport summarizes the memory of a project
+type StatusReport struct {
+	BasePath      string            `json:"base_path"`
+	MemoryPath    string            `json:"memory_path"`
+	SchemaVersion string            `json:"schema_version,omitempty"`
+	EntryFormat   string            `json:"entry_format,omitempty"`
+	Sections      []SectionStatus   `json:"sections"`
+	TotalEntries  int               `json:"total_entries"`
+	Tokens        int               `json:"tokens"`                 // Estimated tokens of the whole memory file
+	TokenBudget   int               `json:"token_budget,omitempty"` // Configured budget.max_tokens
+	OverBudget    bool              `json:"over_budget,omitempty"`
+	TopTags       []TagCount        `json:"top_tags"`
+	SizeBytes     int64             `json:"size_bytes"`
+	ModifiedAt    time.Time         `json:"modified_at"`
+	Agents        AgentsBlockStatus `json:"agents"`
+	Stack         *StackStatus      `json:"stack,omitempty"`
+}
+
+// StackStatus is the project stack saved by init in .ohmymem/project.yaml
+type StackStatus struct {
+	Languages   []string  `json:"languages"`
+	Framework   string    `json:"framework,omitempty"`
+	Database    string    `json:"database,omitempty"`
+	ProjectType string    `json:"project_type,omitempty"`
+	Features    []string  `json:"features,omitempty"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// Status collects a summary of the memory file and the AGENTS.md block
+func (u *MemoryUseCase) Status(ctx context.Context, topTags int) (*StatusReport, error) {
+	info, err := os.Stat(u.repo.FilePath())
+	if err != nil {
+		return nil, fmt.Errorf("stat memory file: %w", err)
+	}
+
+	basePath := u.basePath
+	if absPath, err := filepath.Abs(basePath); err == nil {
+		basePath = absPath
+	}
+	report := &StatusReport{
+		BasePath:   basePath,
+		MemoryPath: u.repo.FilePath(),
+		SizeBytes:  info.Size(),
+		ModifiedAt: info.ModTime(),
+		Agents:     agentsBlockStatus(filepath.Join(u.basePath, agentsFileName)),
+	}
+	if project, detectedAt, err := persistence.LoadProjectInfo(u.basePath); err == nil {
+		report.Stack = &StackStatus{
+			Languages:   project.Languages(),
+			Framework:   project.Framework,
+			Database:    project.Database,
+			ProjectType: project.ProjectType,
+			Features:    project.Features,
+			DetectedAt:  detectedAt,
+		}
+	}
+
+	inspection, err := u.repo.Inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.Frontmatter != nil {
+		report.SchemaVersion = frontmatterString(inspection.Frontmatter, persistence.SchemaVersionKey)
+		report.EntryFormat = frontmatterString(inspection.Frontmatter, persistence.EntryFormatKey)
+	}
+
+	sections, err := u.memoryService.ReadSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := u.memoryService.TokenUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Tokens = usage.Total
+	report.TokenBudget = usage.Budget
+	report.OverBudget = usage.OverBudget()
+	sectionTokens := make(map[domain.SectionType]int, len(usage.Sections))
+	for _, section := range usage.Sections {
+		sectionTokens[section.Section] = section.Tokens
+	}
+
+	now := u.timeProvider.Now()
+	for _, section := range sections {
+		status := SectionStatus{Section: string(section.Type), Entries: len(section.Entries), Tokens: sectionTokens[section.Type]}
+		for _, entry := range section.Entries {
+			if entry.IsExpired(now) {
+				status.Expired++
+			}
+			if !entry.IsActive() {
+				status.Deprecated++
+			}
+		}
+		report.Sections = append(report.Sections, status)
+		report.TotalEntries += status.Entries
+	}
+
+	tags, err := u.memoryService.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.TopTags = []TagCount{}
+	for _, tag := range tags {
+		if len(report.TopTags) == topTags {
+			break
+		}
+		report.TopTags = append(report.TopTags, TagCount{Name: tag.Name, Count: tag.Count})
+	}
+
+	return report, nil
+}
+
+// agentsBlockStatus reads the ohmymem block of AGENTS.md, if any
+func agentsBlockStatus(path string) AgentsBlockStatus {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AgentsBlockStatus{}
+	}
+
+	content := string(data)
+	start := strings.Index(content, agentsBlockStart)
+	end := strings.Index(content, agentsBlockEnd)
+	if start == -1 || end < start {
+		return AgentsBlockStatus{}
+	}
+
+	block := content[start:end]
+	status := AgentsBlockStatus{
+		Present:  true,
+		UpToDate: strings.Contains(block, domain.BootProtocol),
+	}
+	if match := agentsUpdatedRegex.FindStringSubmatch(block); match != nil {
+		status.LastUpdated = match[1]
+	}
+	return status
+}
+
+// frontmatterString renders a frontmatter value as a string
+func frontmatterString(frontmatter map[string]any, key string) string {
+	value, ok := frontmatter[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
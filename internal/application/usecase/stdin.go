@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StdinHeader is the optional front-matter-style header of content piped to add --stdin
+type StdinHeader struct {
+	Category   string `yaml:"category"`
+	Tag        string `yaml:"tag"`
+	Rationale  string `yaml:"rationale"`
+	Expires    string `yaml:"expires"`
+	Scope      string `yaml:"scope"`
+	Supersedes string `yaml:"supersedes"`
+
+	Context      string   `yaml:"context"`
+	Options      []string `yaml:"options"`
+	Consequences string   `yaml:"consequences"`
+}
+
+// ParseStdin splits content piped to add --stdin into its optional header and the
+// entry text. The header opens and closes with lines that are exactly "---"; Windows
+// line endings are accepted.
+func ParseStdin(text string) (StdinHeader, string, error) {
+	var header StdinHeader
+	text = strings.TrimLeft(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		return header, text, nil
+	}
+
+	lines := strings.Split(rest, "\n")
+	for i, line := range lines {
+		if line != "---" {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[:i], "\n")), &header); err != nil {
+			return header, "", fmt.Errorf("parse stdin header: %w", err)
+		}
+		return header, strings.Join(lines[i+1:], "\n"), nil
+	}
+	return header, "", fmt.Errorf("stdin header is not closed with ---")
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/remote"
+)
+
+// SyncBaseFileName stores the memory file as of the last sync, the common ancestor
+// of the next three-way merge
+const SyncBaseFileName = "sync-base.md"
+
+// syncAttempts bounds how often a sync is retried when the remote moves during it
+const syncAttempts = 3
+
+// SyncOptions controls a sync
+type SyncOptions struct {
+	PreferRemote bool // Resolve entries changed on both sides in favour of the remote
+	DryRun       bool // Report the changes without writing or pushing
+}
+
+// SyncResult describes the changes a sync exchanged with the remote
+type SyncResult struct {
+	Pulled    domain.EntryDiff // Changes taken from the remote into the local file
+	Pushed    domain.EntryDiff // Changes sent from the local file to the remote
+	Conflicts []domain.MergeConflict
+}
+
+// Sync merges the remote memory file and the local one at entry granularity, using
+// the version of the last sync as base, then writes the result to both sides.
+// Conflicting entries keep the local version unless opts.PreferRemote is set.
+func (u *MemoryUseCase) Sync(ctx context.Context, r remote.Remote, opts SyncOptions) (*SyncResult, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := u.syncOnce(ctx, r, opts)
+		if errors.Is(err, remote.ErrRemoteChanged) && attempt < syncAttempts {
+			continue
+		}
+		return result, err
+	}
+}
+
+// syncOnce pulls, merges under the write lock, then pushes
+func (u *MemoryUseCase) syncOnce(ctx context.Context, r remote.Remote, opts SyncOptions) (*SyncResult, error) {
+	snapshot, err := r.Pull(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pull from %s: %w", r, err)
+	}
+	base, err := os.ReadFile(u.syncBasePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read sync base: %w", err)
+	}
+
+	var result SyncResult
+	var merged string
+	err = u.repo.Rewrite(ctx, func(local string) (string, error) {
+		merged, result = mergeForSync(string(base), local, snapshot.Content, opts.PreferRemote)
+		if opts.DryRun {
+			return local, nil
+		}
+		return merged, nil
+	})
+	if err != nil || opts.DryRun {
+		return &result, err
+	}
+
+	if merged != snapshot.Content {
+		if err := r.Push(ctx, merged, snapshot.Version); err != nil {
+			return &result, fmt.Errorf("push to %s: %w", r, err)
+		}
+	}
+	if err := os.WriteFile(u.syncBasePath(), []byte(merged), 0644); err != nil {
+		return &result, fmt.Errorf("write sync base: %w", err)
+	}
+	return &result, nil
+}
+
+// mergeForSync three-way merges the local and remote content; the preferred side is
+// "ours", whose text the other side's changes are applied to
+func mergeForSync(base, local, remoteContent string, preferRemote bool) (string, SyncResult) {
+	ours, theirs := local, remoteContent
+	if preferRemote && remoteContent != "" {
+		ours, theirs = remoteContent, local
+	}
+
+	merge := domain.MergeEntries(persistence.ParseMemory(base), persistence.ParseMemory(ours), persistence.ParseMemory(theirs))
+	merged := persistence.ApplyMerge(ours, merge)
+
+	mergedEntries := persistence.ParseMemory(merged)
+	return merged, SyncResult{
+		Pulled:    domain.DiffEntries(persistence.ParseMemory(local), mergedEntries),
+		Pushed:    domain.DiffEntries(persistence.ParseMemory(remoteContent), mergedEntries),
+		Conflicts: merge.Conflicts,
+	}
+}
+
+// syncBasePath returns the path of the last synced version
+func (u *MemoryUseCase) syncBasePath() string {
+	return filepath.Join(u.repo.DirPath(), SyncBaseFileName)
+}
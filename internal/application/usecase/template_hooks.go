@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// templateHookTimeout bounds a post-init command of a template
+const templateHookTimeout = 2 * time.Minute
+
+// hookEnv lists the environment variables passed to post-init commands; the rest of
+// the environment, credentials included, is withheld
+var hookEnv = []string{"PATH", "HOME", "USERPROFILE", "SYSTEMROOT", "TMPDIR", "TEMP", "LANG"}
+
+// RunHook runs a post-init hook of a template in the project at rootPath. A file hook
+// creates its file inside the project, never overwriting one; a command hook runs
+// without a shell in the project directory, with a minimal environment and a time
+// limit. It returns what the hook did.
+func RunHook(ctx context.Context, rootPath string, hook domain.TemplateHook) (string, error) {
+	if len(hook.Run) > 0 {
+		return runHookCommand(ctx, rootPath, hook.Run)
+	}
+
+	target := filepath.Join(rootPath, filepath.FromSlash(hook.Create))
+	if !withinDir(rootPath, target) {
+		return "", fmt.Errorf("%s is outside the project", hook.Create)
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Sprintf("skipped, %s exists", hook.Create), nil
+	}
+
+	// A symlinked directory on the way would send the file out of the project: the
+	// directory is checked with its symlinks resolved before and after creating it
+	dir := filepath.Dir(target)
+	if err := checkHookDir(rootPath, dir, hook.Create); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create %s: %w", filepath.Dir(hook.Create), err)
+	}
+	if err := checkHookDir(rootPath, dir, hook.Create); err != nil {
+		return "", err
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", fmt.Errorf("write %s: %w", hook.Create, err)
+	}
+	if _, err := file.WriteString(hook.Content); err != nil {
+		file.Close()
+		return "", fmt.Errorf("write %s: %w", hook.Create, err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("write %s: %w", hook.Create, err)
+	}
+	return "created " + hook.Create, nil
+}
+
+// checkHookDir fails unless dir, with its symlinks resolved, is inside the project at
+// rootPath. A directory still missing is judged by its nearest existing ancestor,
+// which MkdirAll would follow.
+func checkHookDir(rootPath, dir, name string) error {
+	root, err := filepath.EvalSymlinks(rootPath)
+	if err != nil {
+		return fmt.Errorf("resolve project directory: %w", err)
+	}
+	existing := dir
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", name, err)
+	}
+	if !withinDir(root, resolved) {
+		return fmt.Errorf("%s is outside the project", name)
+	}
+	return nil
+}
+
+// withinDir reports whether path is dir or inside it, without resolving symlinks
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// runHookCommand runs a post-init command in rootPath
+func runHookCommand(ctx context.Context, rootPath string, argv []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, templateHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = rootPath
+	cmd.Env = []string{"OHMYMEM_PROJECT_ROOT=" + rootPath}
+	for _, key := range hookEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("%s timed out after %v", argv[0], templateHookTimeout)
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return "", fmt.Errorf("%s failed: %s: %w", argv[0], out, err)
+		}
+		return "", fmt.Errorf("%s failed: %w", argv[0], err)
+	}
+	return "done", nil
+}
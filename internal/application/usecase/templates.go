@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
+)
+
+// TemplateUseCase browses a template repository and regenerates template blocks
+type TemplateUseCase struct {
+	detector domain.ProjectDetector
+	service  *domain.TemplateService
+	repoURLs []string
+}
+
+// NewTemplateUseCase creates a template use case over repoURLs, tried in order;
+// empty uses the default repositories
+func NewTemplateUseCase(detector domain.ProjectDetector, repoURLs []string, fetch template.FetchOptions) *TemplateUseCase {
+	if len(repoURLs) == 0 {
+		repoURLs = template.GetDefaultRepoURLs()
+	}
+	repo := template.NewTemplateRepository(fetch)
+	return &TemplateUseCase{
+		detector: detector,
+		service:  domain.NewTemplateService(repo, domain.NewLocalTemplateLoader()),
+		repoURLs: repoURLs,
+	}
+}
+
+// WithLayers composes layers on top of the repository when regenerating template
+// entries, as init does
+func (uc *TemplateUseCase) WithLayers(layers []string) *TemplateUseCase {
+	uc.service.WithLayers(layers)
+	return uc
+}
+
+// TemplateUpdate describes a refresh of the template blocks of the memory file
+type TemplateUpdate struct {
+	Added   int // Blocks not in the memory file before
+	Removed int // Blocks no longer provided by the templates
+	Kept    int
+
+	AddedBlocks   []persistence.TemplateBlock
+	RemovedBlocks []persistence.TemplateBlock
+}
+
+// Changed reports whether the refresh modifies the memory file
+func (t TemplateUpdate) Changed() bool {
+	return t.Added > 0 || t.Removed > 0
+}
+
+// List returns the templates of the repository
+func (uc *TemplateUseCase) List(ctx context.Context) ([]template.Info, error) {
+	var infos []template.Info
+	err := uc.service.WithRepository(ctx, uc.repoURLs, func(path string) error {
+		var err error
+		infos, err = template.List(path)
+		return err
+	})
+	return infos, err
+}
+
+// Preview returns the template named by its ID or path and the blocks it injects
+func (uc *TemplateUseCase) Preview(ctx context.Context, name string) (template.Info, []persistence.TemplateBlock, error) {
+	var (
+		info   template.Info
+		blocks []persistence.TemplateBlock
+	)
+	err := uc.service.WithRepository(ctx, uc.repoURLs, func(path string) error {
+		var err error
+		if info, err = template.Find(path, name); err != nil {
+			return err
+		}
+		content, err := template.ReadMemory(info)
+		if err != nil {
+			return err
+		}
+		blocks = persistence.TemplateBlocks(content)
+		return nil
+	})
+	return info, blocks, err
+}
+
+// Update replaces the template blocks of the memory file with those init would
+// inject today. Entries captured by users are left untouched.
+func (uc *TemplateUseCase) Update(ctx context.Context, memory *MemoryUseCase, dryRun bool) (TemplateUpdate, error) {
+	info, err := uc.detector.Detect(memory.basePath)
+	if err != nil {
+		return TemplateUpdate{}, fmt.Errorf("detect project: %w", err)
+	}
+	generated, _, err := uc.service.InitTemplate(ctx, info, uc.repoURLs)
+	if err != nil {
+		return TemplateUpdate{}, err
+	}
+	return memory.UpdateTemplateBlocks(ctx, persistence.TemplateBlocks(generated), dryRun)
+}
+
+// UpdateTemplateBlocks replaces the template blocks of the memory file with blocks
+func (u *MemoryUseCase) UpdateTemplateBlocks(ctx context.Context, blocks []persistence.TemplateBlock, dryRun bool) (TemplateUpdate, error) {
+	var update TemplateUpdate
+	err := u.repo.Rewrite(ctx, func(content string) (string, error) {
+		existing := persistence.TemplateBlocks(content)
+		current := map[persistence.TemplateBlock]bool{}
+		for _, block := range existing {
+			current[block] = true
+		}
+		for _, block := range blocks {
+			if current[block] {
+				update.Kept++
+				delete(current, block)
+			} else {
+				update.Added++
+				update.AddedBlocks = append(update.AddedBlocks, block)
+			}
+		}
+		for _, block := range existing {
+			if current[block] {
+				update.RemovedBlocks = append(update.RemovedBlocks, block)
+				delete(current, block)
+			}
+		}
+		update.Removed = len(update.RemovedBlocks)
+
+		if dryRun || !update.Changed() {
+			return content, nil
+		}
+		return persistence.ReplaceTemplateBlocks(content, blocks), nil
+	})
+	return update, err
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// UninitStep is one change made (or planned) when removing ohmymem from a project
+type UninitStep struct {
+	Path   string
+	Action string
+
+	apply func() error
+}
+
+// UninitUseCase removes what init created in a project
+type UninitUseCase struct {
+	rootPath string
+}
+
+// NewUninitUseCase creates an uninit for the project at rootPath
+func NewUninitUseCase(rootPath string) *UninitUseCase {
+	return &UninitUseCase{rootPath: rootPath}
+}
+
+// Plan lists the changes uninit would make without applying them: the editor symlinks
+// pointing at AGENTS.md, the managed block in AGENTS.md (the whole file when nothing
+// else is left) and, unless keepMemory is set, the .ohmymem directory
+func (u *UninitUseCase) Plan(keepMemory bool) ([]UninitStep, error) {
+	var steps []UninitStep
+
+	for _, editor := range EditorIntegrations {
+		linkPath := filepath.Join(u.rootPath, editor.Path)
+		info, err := os.Lstat(linkPath)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if target, _ := os.Readlink(linkPath); target != agentsLinkTarget(editor.Path) {
+			continue
+		}
+		steps = append(steps, UninitStep{Path: editor.Path, Action: "remove symlink", apply: func() error {
+			return os.Remove(linkPath)
+		}})
+	}
+
+	agentsPath := filepath.Join(u.rootPath, agentsFileName)
+	if data, err := os.ReadFile(agentsPath); err == nil {
+		if stripped, ok := stripAgentsBlock(string(data)); ok {
+			if strings.TrimSpace(stripped) == "" {
+				steps = append(steps, UninitStep{Path: agentsFileName, Action: "delete (only contains the ohmymem block)", apply: func() error {
+					return os.Remove(agentsPath)
+				}})
+			} else {
+				steps = append(steps, UninitStep{Path: agentsFileName, Action: "remove ohmymem block", apply: func() error {
+					return os.WriteFile(agentsPath, []byte(stripped), 0644)
+				}})
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read %s: %w", agentsFileName, err)
+	}
+
+	dirPath := filepath.Join(u.rootPath, persistence.DirName)
+	if info, err := os.Stat(dirPath); err == nil && info.IsDir() && !keepMemory {
+		steps = append(steps, UninitStep{Path: persistence.DirName, Action: "delete directory", apply: func() error {
+			return os.RemoveAll(dirPath)
+		}})
+	}
+
+	return steps, nil
+}
+
+// Apply makes the planned changes in order, stopping at the first failure
+func (u *UninitUseCase) Apply(steps []UninitStep) error {
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			return fmt.Errorf("%s %s: %w", step.Action, step.Path, err)
+		}
+	}
+	return nil
+}
+
+// stripAgentsBlock removes the ohmymem block and the blank lines init put before it
+func stripAgentsBlock(content string) (string, bool) {
+	start := strings.Index(content, agentsBlockStart)
+	end := strings.Index(content, agentsBlockEnd)
+	if start == -1 || end < start {
+		return content, false
+	}
+	end += len(agentsBlockEnd)
+
+	before := strings.TrimRight(content[:start], "\n")
+	after := strings.TrimLeft(content[end:], "\n")
+	switch {
+	case before == "":
+		return after, true
+	case after == "":
+		return before + "\n", true
+	default:
+		return before + "\n\n" + after, true
+	}
+}
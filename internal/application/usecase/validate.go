@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// ValidationReport lists what is wrong with a memory file. Errors make agents lose or
+// misread entries; warnings are tolerated but worth fixing.
+type ValidationReport struct {
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// Valid reports whether the memory file has no errors, and no warnings when strict
+func (r ValidationReport) Valid(strict bool) bool {
+	return len(r.Errors) == 0 && (!strict || len(r.Warnings) == 0)
+}
+
+// Validate lints the memory file of the project
+func (u *MemoryUseCase) Validate(ctx context.Context) (ValidationReport, error) {
+	data, err := os.ReadFile(u.repo.FilePath())
+	if err != nil {
+		return ValidationReport{}, fmt.Errorf("read memory file: %w", err)
+	}
+	return ValidateMemory(string(data)), nil
+}
+
+// ValidateMemory lints memory file content
+func ValidateMemory(content string) ValidationReport {
+	report := ValidationReport{Errors: formatProblems(content), Warnings: []string{}}
+	if report.Errors == nil {
+		report.Errors = []string{}
+	}
+
+	inspection := persistence.InspectContent(content)
+	if !inspection.HasFrontmatter {
+		report.Warnings = append(report.Warnings, "missing frontmatter")
+	}
+	for _, key := range []string{persistence.SchemaVersionKey, persistence.EntryFormatKey} {
+		if _, ok := inspection.Frontmatter[key]; inspection.Frontmatter != nil && !ok {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("frontmatter missing %s", key))
+		}
+	}
+	if version, ok := inspection.Frontmatter[persistence.SchemaVersionKey]; ok && fmt.Sprint(version) != persistence.CurrentSchemaVersion {
+		report.Errors = append(report.Errors, fmt.Sprintf("unsupported schema_version %v (expected %s)", version, persistence.CurrentSchemaVersion))
+	}
+	for _, title := range inspection.UnknownSections {
+		report.Errors = append(report.Errors, fmt.Sprintf("unknown section ## %s (expected %s)", title, sectionTitles()))
+	}
+	if inspection.LegacyEntries > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d legacy entries without anchors, run 'ohmymem migrate'", inspection.LegacyEntries))
+	}
+	if inspection.UnparsedBullets > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d bullets match no entry format and are ignored", inspection.UnparsedBullets))
+	}
+	return report
+}
+
+// sectionTitles lists the valid section headers
+func sectionTitles() string {
+	titles := make([]string, 0, len(domain.ValidSections()))
+	for _, sectionType := range domain.ValidSections() {
+		titles = append(titles, sectionType.Title())
+	}
+	return strings.Join(titles, ", ")
+}
+
+// formatProblems lists what makes memory file content unsafe to commit
+func formatProblems(content string) []string {
+	inspection := persistence.InspectContent(content)
+
+	var problems []string
+	if inspection.FrontmatterErr != nil {
+		problems = append(problems, fmt.Sprintf("invalid frontmatter: %v", inspection.FrontmatterErr))
+	}
+	for _, section := range inspection.MissingSections {
+		problems = append(problems, fmt.Sprintf("missing section header ## %s", section.Title()))
+	}
+	if inspection.BrokenAnchors > 0 {
+		problems = append(problems, fmt.Sprintf("%d broken entry anchors", inspection.BrokenAnchors))
+	}
+	for _, id := range inspection.DuplicateIDs {
+		problems = append(problems, fmt.Sprintf("duplicate entry ID %s", id))
+	}
+
+	for _, entry := range persistence.ParseMemory(content) {
+		label := entry.ID
+		if label == "" {
+			label = entry.Tag
+		}
+		switch {
+		case strings.TrimSpace(entry.TagName) == "":
+			problems = append(problems, fmt.Sprintf("entry %s: empty tag", label))
+		case utf8.RuneCountInString(entry.TagName) > domain.MaxTagLength:
+			problems = append(problems, fmt.Sprintf("entry %s: tag longer than %d characters", label, domain.MaxTagLength))
+		}
+		if strings.TrimSpace(entry.Content) == "" {
+			problems = append(problems, fmt.Sprintf("entry %s: empty content", label))
+		} else if err := domain.ValidateContent(entry.Content); err != nil {
+			problems = append(problems, fmt.Sprintf("entry %s: %v", label, err))
+		}
+	}
+	return problems
+}
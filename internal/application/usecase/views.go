@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// EntryJSON is the JSON representation of an entry shared by MCP tools and CLI output
+type EntryJSON struct {
+	ID           string   `json:"id" yaml:"id"`
+	Section      string   `json:"section,omitempty" yaml:"section,omitempty"`
+	Tag          string   `json:"tag" yaml:"tag"`
+	Content      string   `json:"content" yaml:"content"`
+	Rationale    string   `json:"rationale,omitempty" yaml:"rationale,omitempty"`
+	Status       string   `json:"status,omitempty" yaml:"status,omitempty"`
+	SupersededBy string   `json:"superseded_by,omitempty" yaml:"superseded_by,omitempty"`
+	CreatedAt    string   `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	ExpiresAt    string   `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+	Scope        []string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Author       string   `json:"author,omitempty" yaml:"author,omitempty"`
+	Refs         []string `json:"refs,omitempty" yaml:"refs,omitempty"`         // IDs referenced with ref:<id>
+	Template     string   `json:"template,omitempty" yaml:"template,omitempty"` // Source of an entry injected from a template
+	// Decision record fields, inlined: context, options and consequences
+	domain.DecisionRecord `yaml:",inline"`
+}
+
+// ToEntryJSON converts an entry to its JSON representation
+func ToEntryJSON(entry domain.Entry) EntryJSON {
+	view := EntryJSON{
+		ID:             entry.ID,
+		Section:        string(entry.Section),
+		Tag:            entry.TagName,
+		Content:        entry.Content,
+		Rationale:      entry.Rationale,
+		Scope:          entry.Scope,
+		Author:         entry.Author,
+		Refs:           entry.Refs(),
+		Template:       entry.Template,
+		DecisionRecord: entry.ADR,
+	}
+	if !entry.IsActive() {
+		view.Status = string(entry.Status)
+		view.SupersededBy = entry.SupersededBy
+	}
+	if !entry.CreatedAt.IsZero() {
+		view.CreatedAt = entry.CreatedAt.Format(time.RFC3339)
+	}
+	if !entry.ExpiresAt.IsZero() {
+		view.ExpiresAt = entry.ExpiresAt.Format(time.RFC3339)
+	}
+	return view
+}
+
+// ToEntriesJSON converts entries to their JSON representation
+func ToEntriesJSON(entries []domain.Entry) []EntryJSON {
+	views := make([]EntryJSON, 0, len(entries))
+	for _, entry := range entries {
+		views = append(views, ToEntryJSON(entry))
+	}
+	return views
+}
+
+// SpanJSON is the JSON representation of a match position within a field
+type SpanJSON struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchHitJSON is the JSON representation of a search hit shared by ohmymem_search
+// and `ohmymem search --json`
+type SearchHitJSON struct {
+	EntryJSON
+	Score            float64    `json:"score"`
+	TagMatch         bool       `json:"tag_match,omitempty"`
+	ContentMatches   []SpanJSON `json:"content_matches,omitempty"`
+	RationaleMatches []SpanJSON `json:"rationale_matches,omitempty"`
+}
+
+// ToSearchHitsJSON converts search hits to their JSON representation, with scores
+// rounded to three decimals
+func ToSearchHitsJSON(hits []domain.SearchHit) []SearchHitJSON {
+	views := make([]SearchHitJSON, 0, len(hits))
+	for _, hit := range hits {
+		views = append(views, SearchHitJSON{
+			EntryJSON:        ToEntryJSON(hit.Entry),
+			Score:            math.Round(hit.Score*1000) / 1000,
+			TagMatch:         hit.TagMatch,
+			ContentMatches:   toSpansJSON(hit.ContentMatches),
+			RationaleMatches: toSpansJSON(hit.RationaleMatches),
+		})
+	}
+	return views
+}
+
+// toSpansJSON converts match positions to their JSON representation
+func toSpansJSON(spans []domain.Span) []SpanJSON {
+	out := make([]SpanJSON, 0, len(spans))
+	for _, span := range spans {
+		out = append(out, SpanJSON{Start: span.Start, End: span.End})
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "strings"
+
+// Author kinds, recorded as the prefix of Entry.Author to tell human-written
+// entries from machine-written ones
+const (
+	AuthorUser  = "user"  // Captured by a person through the CLI: "user:Jane Doe"
+	AuthorAgent = "agent" // Captured by an agent over MCP or REST: "agent:claude-code 1.0.3"
+)
+
+// MaxAuthorLength is the number of characters kept of an author
+const MaxAuthorLength = 100
+
+// NewAuthor formats an author of the given kind, e.g. NewAuthor(AuthorUser, "Jane Doe")
+// returns "user:Jane Doe". A blank name gives an empty author.
+func NewAuthor(kind, name string) string {
+	name = NormalizeAuthor(name)
+	if name == "" {
+		return ""
+	}
+	return kind + ":" + name
+}
+
+// NormalizeAuthor makes an author safe to store in anchor metadata: commas and HTML
+// comment markers are dropped, whitespace is collapsed and the result is truncated
+// to MaxAuthorLength characters
+func NormalizeAuthor(author string) string {
+	author = strings.NewReplacer(",", " ", "<!--", "", "-->", "").Replace(author)
+	author = strings.Join(strings.Fields(author), " ")
+	if runes := []rune(author); len(runes) > MaxAuthorLength {
+		author = strings.TrimSpace(string(runes[:MaxAuthorLength]))
+	}
+	return author
+}
+
+// AuthorKind returns the kind of the entry author (AuthorUser or AuthorAgent), or ""
+// for entries without one or recorded before authors were
+func (e Entry) AuthorKind() string {
+	kind, _, ok := strings.Cut(e.Author, ":")
+	if !ok || (kind != AuthorUser && kind != AuthorAgent) {
+		return ""
+	}
+	return kind
+}
+
+// AuthorName returns the entry author without its kind, e.g. "Jane Doe"
+func (e Entry) AuthorName() string {
+	if kind := e.AuthorKind(); kind != "" {
+		return strings.TrimPrefix(e.Author, kind+":")
+	}
+	return e.Author
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DuplicateGroup is a cluster of near-duplicate entries. The first entry is the
+// suggested keeper: the one in the highest-priority section, with a rationale, oldest.
+type DuplicateGroup struct {
+	Entries []Entry
+	Score   float64 // Highest pairwise similarity within the group
+}
+
+// Keeper returns the entry suggested to keep
+func (g DuplicateGroup) Keeper() Entry {
+	return g.Entries[0]
+}
+
+// Duplicates returns the entries suggested to remove
+func (g DuplicateGroup) Duplicates() []Entry {
+	return g.Entries[1:]
+}
+
+// CompactionService finds and resolves near-duplicate entries across sections
+type CompactionService struct {
+	memory *MemoryService
+	repo   MemoryRepository
+}
+
+// NewCompactionService creates a compaction service backed by repo
+func NewCompactionService(repo MemoryRepository) *CompactionService {
+	return &CompactionService{memory: NewMemoryService(repo), repo: repo}
+}
+
+// FindDuplicateGroups clusters active entries the detector finds similar, transitively,
+// across all sections. Groups are ordered by score, highest first.
+func (c *CompactionService) FindDuplicateGroups(ctx context.Context, detector *DuplicateDetector) ([]DuplicateGroup, error) {
+	all, err := c.memory.ListEntries(ctx, EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, entry := range all {
+		if entry.ID != "" && entry.IsActive() {
+			entries = append(entries, entry)
+		}
+	}
+
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	scores := make(map[int]float64)
+	for _, pair := range detector.Pairs(entries) {
+		ri, rj := find(pair.I), find(pair.J)
+		if ri != rj {
+			parent[rj] = ri
+			scores[ri] = max(scores[ri], scores[rj])
+		}
+		scores[ri] = max(scores[ri], pair.Score)
+	}
+
+	members := make(map[int][]Entry)
+	var roots []int
+	for i, entry := range entries {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], entry)
+	}
+
+	var groups []DuplicateGroup
+	for _, root := range roots {
+		if len(members[root]) < 2 {
+			continue
+		}
+		group := members[root]
+		sort.SliceStable(group, func(i, j int) bool { return keepBefore(group[i], group[j]) })
+		groups = append(groups, DuplicateGroup{Entries: group, Score: scores[root]})
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Score > groups[j].Score })
+	return groups, nil
+}
+
+// MergeGroup keeps the group's keeper, folds the other rationales into it when they
+// fit the rationale limit, and removes the other entries. It returns the updated keeper.
+func (c *CompactionService) MergeGroup(ctx context.Context, group DuplicateGroup) (*Entry, error) {
+	keeper := group.Keeper()
+
+	rationales := []string{}
+	if keeper.Rationale != "" {
+		rationales = append(rationales, keeper.Rationale)
+	}
+	for _, entry := range group.Duplicates() {
+		if entry.Rationale != "" && !containsFold(rationales, entry.Rationale) {
+			rationales = append(rationales, entry.Rationale)
+		}
+	}
+	if merged := strings.Join(rationales, "; "); merged != keeper.Rationale && utf8.RuneCountInString(merged) <= MaxRationaleLength {
+		keeper.Rationale = merged
+		if err := c.repo.UpdateEntry(ctx, &keeper); err != nil {
+			return nil, fmt.Errorf("update %s: %w", keeper.ID, err)
+		}
+	}
+
+	if _, err := c.DeleteDuplicates(ctx, group); err != nil {
+		return nil, err
+	}
+	return &keeper, nil
+}
+
+// DeleteDuplicates removes every entry of the group except the keeper
+func (c *CompactionService) DeleteDuplicates(ctx context.Context, group DuplicateGroup) ([]Entry, error) {
+	ids := make([]string, 0, len(group.Entries)-1)
+	for _, entry := range group.Duplicates() {
+		ids = append(ids, entry.ID)
+	}
+	return c.repo.DeleteEntries(ctx, ids)
+}
+
+// keepBefore orders entries by how suitable they are to keep
+func keepBefore(a, b Entry) bool {
+	if pa, pb := sectionPriority(a.Section), sectionPriority(b.Section); pa != pb {
+		return pa < pb
+	}
+	if (a.Rationale != "") != (b.Rationale != "") {
+		return a.Rationale != ""
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// sectionPriority ranks sections in their canonical order, constraints first
+func sectionPriority(section SectionType) int {
+	for i, candidate := range ValidSections() {
+		if candidate == section {
+			return i
+		}
+	}
+	return len(ValidSections())
+}
+
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package domain
+
+import "strings"
+
+// contentEscaper escapes angle brackets, and the ampersands that would otherwise read
+// as one of the escapes, so entry text can't open HTML tags or comments in the file
+var contentEscaper = strings.NewReplacer(
+	"&lt;", "&amp;lt;",
+	"&gt;", "&amp;gt;",
+	"&amp;", "&amp;amp;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// contentUnescaper reverses contentEscaper
+var contentUnescaper = strings.NewReplacer(
+	"&lt;", "<",
+	"&gt;", ">",
+	"&amp;", "&",
+)
+
+// EscapeContent prepares entry text for the Markdown memory file: angle brackets
+// outside inline code spans are written as &lt; and &gt;, while code spans are kept
+// verbatim, so "use `Map<K, V>`" and "Map<K, V>" both render as written
+func EscapeContent(text string) string {
+	return outsideCodeSpans(text, contentEscaper.Replace)
+}
+
+// UnescapeContent restores entry text read from the memory file, reversing EscapeContent
+func UnescapeContent(text string) string {
+	return outsideCodeSpans(text, contentUnescaper.Replace)
+}
+
+// outsideCodeSpans applies f to the parts of text outside inline code spans
+func outsideCodeSpans(text string, f func(string) string) string {
+	spans := codeSpans(text)
+	if len(spans) == 0 {
+		return f(text)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, span := range spans {
+		sb.WriteString(f(text[last:span[0]]))
+		sb.WriteString(text[span[0]:span[1]])
+		last = span[1]
+	}
+	sb.WriteString(f(text[last:]))
+	return sb.String()
+}
+
+// codeSpans returns the byte ranges of the inline code spans of text, delimiters
+// included. As in CommonMark, a run of backticks opens a span closed by the next run
+// of the same length; a run without one is literal text.
+func codeSpans(text string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRun(text, i)
+		end := -1
+		for j := i + run; j < len(text); {
+			if text[j] != '`' {
+				j++
+				continue
+			}
+			n := backtickRun(text, j)
+			if n == run {
+				end = j + n
+				break
+			}
+			j += n
+		}
+		if end == -1 {
+			i += run
+			continue
+		}
+		spans = append(spans, [2]int{i, end})
+		i = end
+	}
+	return spans
+}
+
+// backtickRun returns the length of the run of backticks starting at i
+func backtickRun(text string, i int) int {
+	n := 0
+	for i+n < len(text) && text[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// hasUnclosedCodeSpan reports whether text has backticks outside closed code spans
+func hasUnclosedCodeSpan(text string) bool {
+	last := 0
+	for _, span := range codeSpans(text) {
+		if strings.Contains(text[last:span[0]], "`") {
+			return true
+		}
+		last = span[1]
+	}
+	return strings.Contains(text[last:], "`")
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits of the decision record fields; context and consequences share the
+// rationale limit of the validation policy
+const (
+	MaxDecisionOptions = 10
+	MaxOptionLength    = 200
+)
+
+// Labels of the decision record lines in the memory file
+const (
+	decisionContextLabel      = "Context"
+	decisionOptionsLabel      = "Options"
+	decisionConsequencesLabel = "Consequences"
+)
+
+// decisionOptionSeparator joins the options considered on one line
+const decisionOptionSeparator = "; "
+
+// DecisionRecord holds the optional ADR fields of a decision; the decision itself
+// is the entry content
+type DecisionRecord struct {
+	Context      string   `json:"context,omitempty" yaml:"context,omitempty"`           // Forces at play when deciding
+	Options      []string `json:"options,omitempty" yaml:"options,omitempty"`           // Alternatives considered
+	Consequences string   `json:"consequences,omitempty" yaml:"consequences,omitempty"` // What becomes easier or harder
+}
+
+// IsZero reports whether no field of the record is set
+func (d DecisionRecord) IsZero() bool {
+	return d.Context == "" && len(d.Options) == 0 && d.Consequences == ""
+}
+
+// ParseOptions splits a semicolon-separated list of options considered
+// (e.g. "PostgreSQL; MySQL; MongoDB"), dropping empty ones
+func ParseOptions(value string) []string {
+	var options []string
+	for _, option := range strings.Split(value, ";") {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// ValidateDecisionRecord checks the record belongs to a decision and its fields are
+// single lines within their default length limits
+func ValidateDecisionRecord(section SectionType, record DecisionRecord) error {
+	return validateDecisionRecord(section, record, MaxRationaleLength)
+}
+
+// validateDecisionRecord is ValidateDecisionRecord with context and consequences
+// limited to maxLength characters
+func validateDecisionRecord(section SectionType, record DecisionRecord, maxLength int) error {
+	if record.IsZero() {
+		return nil
+	}
+	if section != SectionDecisions {
+		return fmt.Errorf("%w: context, options and consequences are only recorded for decisions", ErrInvalidDecisionRecord)
+	}
+
+	fields := []struct {
+		name, value string
+	}{
+		{"context", record.Context},
+		{"consequences", record.Consequences},
+	}
+	for _, field := range fields {
+		if n := utf8.RuneCountInString(field.value); n > maxLength {
+			return fmt.Errorf("%w: %s must be %d characters or less (got %d)", ErrInvalidDecisionRecord, field.name, maxLength, n)
+		}
+		if err := validateDecisionText(field.name, field.value); err != nil {
+			return err
+		}
+	}
+
+	if len(record.Options) > MaxDecisionOptions {
+		return fmt.Errorf("%w: at most %d options (got %d)", ErrInvalidDecisionRecord, MaxDecisionOptions, len(record.Options))
+	}
+	for _, option := range record.Options {
+		if strings.TrimSpace(option) == "" {
+			return fmt.Errorf("%w: options cannot be empty", ErrInvalidDecisionRecord)
+		}
+		if strings.Contains(option, ";") {
+			return fmt.Errorf("%w: option %q cannot contain semicolons", ErrInvalidDecisionRecord, option)
+		}
+		if n := utf8.RuneCountInString(option); n > MaxOptionLength {
+			return fmt.Errorf("%w: options must be %d characters or less (got %d)", ErrInvalidDecisionRecord, MaxOptionLength, n)
+		}
+		if err := validateDecisionText("option", option); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDecisionText applies the content rules that keep a field on its own line
+func validateDecisionText(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	if err := ValidateContent(value); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidDecisionRecord, name, err)
+	}
+	return nil
+}
+
+// RenderDecisionRecord renders the set fields of the record as the nested bullets
+// following the entry bullet ("  * *Context:* ...", then Options and Consequences),
+// each ending with a newline
+func RenderDecisionRecord(record DecisionRecord) string {
+	var sb strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&sb, "  * *%s:* %s\n", label, EscapeContent(value))
+		}
+	}
+	line(decisionContextLabel, record.Context)
+	line(decisionOptionsLabel, strings.Join(record.Options, decisionOptionSeparator))
+	line(decisionConsequencesLabel, record.Consequences)
+	return sb.String()
+}
+
+// ParseDecisionLine reads one line rendered by RenderDecisionRecord into the record,
+// reporting whether it was a decision record line
+func ParseDecisionLine(record *DecisionRecord, line string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "* *")
+	if !ok {
+		return false
+	}
+	label, value, ok := strings.Cut(rest, ":* ")
+	if !ok {
+		return false
+	}
+	value = UnescapeContent(strings.TrimSpace(value))
+	switch label {
+	case decisionContextLabel:
+		record.Context = value
+	case decisionOptionsLabel:
+		record.Options = ParseOptions(value)
+	case decisionConsequencesLabel:
+		record.Consequences = value
+	default:
+		return false
+	}
+	return true
+}
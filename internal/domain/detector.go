@@ -1,13 +1,79 @@
 package domain
 
+import "slices"
+
 // ProjectInfo 检测到的项目信息
 type ProjectInfo struct {
-	Language    string   // go, typescript, python, rust, unknown
-	Framework   string   // echo, gin, express, fastapi, etc. 空字符串表示未检测到
-	ProjectType string   // backend, frontend, cli, library
-	Database    string   // postgresql, mysql, mongodb, etc.
-	Features    []string // 检测到的特性
-	RootPath    string   // 项目根目录
+	Language    string   `json:"language"`               // go, typescript, python, rust, unknown
+	Framework   string   `json:"framework,omitempty"`    // echo, gin, express, fastapi, etc. 空字符串表示未检测到
+	ProjectType string   `json:"project_type,omitempty"` // backend, frontend, fullstack, cli, library
+	Database    string   `json:"database,omitempty"`     // postgresql, mysql, mongodb, etc.
+	Features    []string `json:"features"`               // 检测到的特性
+	RootPath    string   `json:"root_path"`              // 项目根目录
+	Modules     []string `json:"modules,omitempty"`      // Go 工作区（go.work）的成员模块，相对项目根目录
+
+	// Secondary 同一仓库中检测到的其他技术栈（monorepo），按检测器优先级排序
+	Secondary []ProjectStack `json:"secondary,omitempty"`
+
+	// Candidates 主技术栈的框架和数据库候选项，同类按置信度从高到低排序
+	// （Node.js 项目的前端框架排在后端框架之前）；Framework 和 Database 取各自的第一个候选项
+	Candidates []DetectionCandidate `json:"candidates,omitempty"`
+
+	// Evidence explain 模式下每个结论的依据（触发它的文件或依赖）
+	Evidence []DetectionEvidence `json:"evidence,omitempty"`
+}
+
+// DetectionEvidence 一个检测结论及触发它的文件或依赖
+type DetectionEvidence struct {
+	Kind       string  `json:"kind"`                 // language, framework, database, project_type, feature
+	Value      string  `json:"value"`                // go, gin, docker, etc.
+	Source     string  `json:"source"`               // 相对项目根目录的文件，或 "文件: 依赖"
+	Confidence float64 `json:"confidence,omitempty"` // 框架和数据库候选项的置信度，其他结论为 0
+}
+
+// DetectionCandidate 检测候选项及其置信度
+type DetectionCandidate struct {
+	Kind       string  `json:"kind"`             // framework, database
+	Value      string  `json:"value"`            // gin, postgresql, etc.
+	Confidence float64 `json:"confidence"`       // 0~1，直接依赖高于间接依赖
+	Source     string  `json:"source,omitempty"` // 触发候选项的依赖，如 "go.mod: github.com/gin-gonic/gin"
+}
+
+// CandidatesOf 返回某类候选项，按置信度从高到低排序
+func (p *ProjectInfo) CandidatesOf(kind string) []DetectionCandidate {
+	var candidates []DetectionCandidate
+	for _, c := range p.Candidates {
+		if c.Kind == kind {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// ProjectStack 项目中的一个语言技术栈
+type ProjectStack struct {
+	Language    string `json:"language"`
+	Framework   string `json:"framework,omitempty"`
+	Database    string `json:"database,omitempty"`
+	ProjectType string `json:"project_type,omitempty"`
+	Path        string `json:"path"` // 相对项目根目录的位置，"." 表示根目录
+}
+
+// Stacks 返回主技术栈及所有次要技术栈
+func (p *ProjectInfo) Stacks() []ProjectStack {
+	primary := ProjectStack{Language: p.Language, Framework: p.Framework, Database: p.Database, ProjectType: p.ProjectType, Path: "."}
+	return append([]ProjectStack{primary}, p.Secondary...)
+}
+
+// Languages 返回检测到的所有语言，主语言在前
+func (p *ProjectInfo) Languages() []string {
+	var languages []string
+	for _, stack := range p.Stacks() {
+		if stack.Language != "" && stack.Language != "unknown" {
+			languages = append(languages, stack.Language)
+		}
+	}
+	return languages
 }
 
 // IsDetected 是否成功检测到语言
@@ -20,3 +86,97 @@ type ProjectDetector interface {
 	// 检测项目信息
 	Detect(rootPath string) (*ProjectInfo, error)
 }
+
+// ProjectTypes 支持的项目类型
+var ProjectTypes = []string{"backend", "frontend", "fullstack", "cli", "library"}
+
+// ProjectOverrides 用户指定的项目信息，覆盖检测结果；空字段表示使用检测结果
+type ProjectOverrides struct {
+	Language    string
+	Framework   string
+	Database    string
+	ProjectType string
+}
+
+// IsEmpty 是否没有任何覆盖
+func (o ProjectOverrides) IsEmpty() bool {
+	return o.Language == "" && o.Framework == "" && o.Database == "" && o.ProjectType == ""
+}
+
+// Apply 将覆盖写入项目信息
+func (o ProjectOverrides) Apply(info *ProjectInfo) {
+	if o.Language != "" {
+		info.Language = o.Language
+	}
+	if o.Framework != "" {
+		info.Framework = o.Framework
+	}
+	if o.Database != "" {
+		info.Database = o.Database
+	}
+	if o.ProjectType != "" {
+		info.ProjectType = o.ProjectType
+	}
+}
+
+// StackDeclaration 用户在 .ohmymem/stack.yaml 中声明的技术栈。
+// 声明的字段覆盖检测结果，特性和次要技术栈与检测结果合并
+type StackDeclaration struct {
+	ProjectOverrides
+	Features       []string       // 追加的特性
+	IgnoreFeatures []string       // 移除的误检特性
+	Secondary      []ProjectStack // 次要技术栈，替换检测到的同路径技术栈
+}
+
+// Apply 将声明合并到检测结果；声明了框架或数据库时丢弃对应的候选项
+func (s StackDeclaration) Apply(info *ProjectInfo) {
+	s.ProjectOverrides.Apply(info)
+	for _, kind := range []struct {
+		name     string
+		declared bool
+	}{{"framework", s.Framework != ""}, {"database", s.Database != ""}} {
+		if !kind.declared {
+			continue
+		}
+		var kept []DetectionCandidate
+		for _, c := range info.Candidates {
+			if c.Kind != kind.name {
+				kept = append(kept, c)
+			}
+		}
+		info.Candidates = kept
+	}
+
+	for _, feature := range s.Features {
+		if !slices.Contains(info.Features, feature) {
+			info.Features = append(info.Features, feature)
+		}
+	}
+	if len(s.IgnoreFeatures) > 0 {
+		var kept []string
+		for _, feature := range info.Features {
+			if !slices.Contains(s.IgnoreFeatures, feature) {
+				kept = append(kept, feature)
+			}
+		}
+		info.Features = kept
+	}
+
+	for _, declared := range s.Secondary {
+		if declared.Path == "" {
+			declared.Path = "."
+		}
+		replaced := false
+		for i, stack := range info.Secondary {
+			// 根目录可有多种语言，按语言区分；子目录按路径区分
+			if stack.Path == declared.Path && (declared.Path != "." || stack.Language == declared.Language) {
+				info.Secondary[i] = declared
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			info.Secondary = append(info.Secondary, declared)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// EntryChange pairs two versions of the same entry and names the fields that differ
+type EntryChange struct {
+	Old    Entry
+	New    Entry
+	Fields []string // section, tag, content, rationale, status, expires, scope
+}
+
+// EntryDiff is the entry-level difference between two versions of a memory file
+type EntryDiff struct {
+	Added   []Entry
+	Removed []Entry
+	Changed []EntryChange
+}
+
+// IsEmpty reports whether both versions hold the same entries
+func (d EntryDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffEntries compares two entry lists. Entries are matched by ID; legacy entries
+// without an ID are matched by section and content, so they are only ever added or removed.
+func DiffEntries(oldEntries, newEntries []Entry) EntryDiff {
+	oldByKey := make(map[string]Entry, len(oldEntries))
+	for _, entry := range oldEntries {
+		oldByKey[diffKey(entry)] = entry
+	}
+
+	var diff EntryDiff
+	seen := make(map[string]bool, len(newEntries))
+	for _, entry := range newEntries {
+		key := diffKey(entry)
+		seen[key] = true
+		previous, ok := oldByKey[key]
+		if !ok {
+			diff.Added = append(diff.Added, entry)
+			continue
+		}
+		if fields := changedFields(previous, entry); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, EntryChange{Old: previous, New: entry, Fields: fields})
+		}
+	}
+	for _, entry := range oldEntries {
+		if !seen[diffKey(entry)] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+	return diff
+}
+
+// diffKey identifies an entry across versions
+func diffKey(entry Entry) string {
+	if entry.ID != "" {
+		return entry.ID
+	}
+	return string(entry.Section) + "\x00" + strings.TrimSpace(entry.Content)
+}
+
+// changedFields lists the user-visible fields that differ between two versions of an entry
+func changedFields(a, b Entry) []string {
+	var fields []string
+	if a.Section != b.Section {
+		fields = append(fields, "section")
+	}
+	if a.Tag != b.Tag {
+		fields = append(fields, "tag")
+	}
+	if a.Content != b.Content {
+		fields = append(fields, "content")
+	}
+	if a.Rationale != b.Rationale {
+		fields = append(fields, "rationale")
+	}
+	if a.StatusLabel() != b.StatusLabel() {
+		fields = append(fields, "status")
+	}
+	if !sameTime(a.ExpiresAt, b.ExpiresAt) {
+		fields = append(fields, "expires")
+	}
+	if !slices.Equal(a.Scope, b.Scope) {
+		fields = append(fields, "scope")
+	}
+	return fields
+}
+
+// sameTime compares two optional timestamps
+func sameTime(a, b time.Time) bool {
+	return a.IsZero() == b.IsZero() && a.Equal(b)
+}
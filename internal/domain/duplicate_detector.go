@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// DuplicateMethod is how a DuplicateDetector compares content
+type DuplicateMethod string
+
+const (
+	// DuplicateOverlap compares the normalized word sets exactly (Jaccard similarity)
+	DuplicateOverlap DuplicateMethod = "overlap"
+	// DuplicateMinHash estimates the word set overlap from fixed-size signatures,
+	// trading some precision for speed on large memories: Pairs only compares the
+	// entries whose signatures agree on a band of rows, instead of every pair
+	DuplicateMinHash DuplicateMethod = "minhash"
+)
+
+// minHashSize is the number of hash functions of a MinHash signature; the estimate
+// is within about 0.1 of the exact overlap
+const minHashSize = 128
+
+// minHashRecall is the least probability that two entries scoring exactly the
+// threshold agree on a band, and so are compared by Pairs
+const minHashRecall = 0.99
+
+// minHashSeeds salt the word hash once per signature position
+var minHashSeeds = func() []uint64 {
+	seeds := make([]uint64, minHashSize)
+	state := uint64(0x6f686d796d656d)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(state)
+	}
+	return seeds
+}()
+
+// DuplicateCandidate is an entry similar to some content, with its similarity score
+type DuplicateCandidate struct {
+	Entry Entry
+	Score float64
+}
+
+// DuplicatePair is two entries, by index, whose similarity reaches the threshold
+type DuplicatePair struct {
+	I, J  int
+	Score float64
+}
+
+// DuplicateDetector finds near-duplicate entries by normalized word overlap, either
+// exactly or estimated with MinHash. It needs no index or external service, so it
+// works offline and is shared by capture, import and dedupe.
+type DuplicateDetector struct {
+	method    DuplicateMethod
+	threshold float64
+}
+
+// NewDuplicateDetector creates a detector comparing content with method and
+// reporting matches scoring at least threshold (0..1)
+func NewDuplicateDetector(method DuplicateMethod, threshold float64) (*DuplicateDetector, error) {
+	switch method {
+	case DuplicateOverlap, DuplicateMinHash:
+	case "":
+		method = DuplicateOverlap
+	default:
+		return nil, fmt.Errorf("unknown duplicate detection method %q (expected overlap or minhash)", method)
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("duplicate threshold must be between 0 and 1 (got %g)", threshold)
+	}
+	return &DuplicateDetector{method: method, threshold: threshold}, nil
+}
+
+// DefaultDuplicateDetector compares exact word overlap at DuplicateThreshold
+func DefaultDuplicateDetector() *DuplicateDetector {
+	return &DuplicateDetector{method: DuplicateOverlap, threshold: DuplicateThreshold}
+}
+
+// Method returns how the detector compares content
+func (d *DuplicateDetector) Method() DuplicateMethod {
+	return d.method
+}
+
+// Threshold returns the score at or above which content counts as a duplicate
+func (d *DuplicateDetector) Threshold() float64 {
+	return d.threshold
+}
+
+// Score returns the similarity (0..1) of a and b
+func (d *DuplicateDetector) Score(a, b string) float64 {
+	return d.compare(d.fingerprint(a), d.fingerprint(b))
+}
+
+// Candidates returns the entries whose content is similar to content, most similar first
+func (d *DuplicateDetector) Candidates(content string, entries []Entry) []DuplicateCandidate {
+	target := d.fingerprint(content)
+	var candidates []DuplicateCandidate
+	for _, entry := range entries {
+		if score := d.compare(target, d.fingerprint(entry.Content)); score >= d.threshold {
+			candidates = append(candidates, DuplicateCandidate{Entry: entry, Score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
+}
+
+// Pairs returns every pair of entries whose content is similar, in index order
+func (d *DuplicateDetector) Pairs(entries []Entry) []DuplicatePair {
+	fingerprints := make([]fingerprint, len(entries))
+	for i, entry := range entries {
+		fingerprints[i] = d.fingerprint(entry.Content)
+	}
+	if d.method == DuplicateMinHash {
+		return d.bandedPairs(fingerprints)
+	}
+	var pairs []DuplicatePair
+	for i := range fingerprints {
+		for j := i + 1; j < len(fingerprints); j++ {
+			if score := d.compare(fingerprints[i], fingerprints[j]); score >= d.threshold {
+				pairs = append(pairs, DuplicatePair{I: i, J: j, Score: score})
+			}
+		}
+	}
+	return pairs
+}
+
+// bandedPairs finds the similar pairs among MinHash fingerprints by locality-sensitive
+// hashing: signatures are cut into bands, and only entries with an identical band are
+// compared. Entries without words only match each other.
+func (d *DuplicateDetector) bandedPairs(fingerprints []fingerprint) []DuplicatePair {
+	candidates := make(map[[2]int]struct{})
+	addBucket := func(bucket []int) {
+		for x := range bucket {
+			for y := x + 1; y < len(bucket); y++ {
+				candidates[[2]int{bucket[x], bucket[y]}] = struct{}{}
+			}
+		}
+	}
+
+	var empty []int
+	for i, fp := range fingerprints {
+		if fp.signature == nil {
+			empty = append(empty, i)
+		}
+	}
+	addBucket(empty)
+
+	rows := minHashRows(d.threshold)
+	buckets := make(map[uint64][]int)
+	for start := 0; start < minHashSize; start += rows {
+		clear(buckets)
+		for i, fp := range fingerprints {
+			if fp.signature == nil {
+				continue
+			}
+			key := uint64(start)
+			for _, v := range fp.signature[start : start+rows] {
+				key = mix64(key ^ v)
+			}
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, bucket := range buckets {
+			addBucket(bucket)
+		}
+	}
+
+	var pairs []DuplicatePair
+	for pair := range candidates {
+		if score := d.compare(fingerprints[pair[0]], fingerprints[pair[1]]); score >= d.threshold {
+			pairs = append(pairs, DuplicatePair{I: pair[0], J: pair[1], Score: score})
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a].I != pairs[b].I {
+			return pairs[a].I < pairs[b].I
+		}
+		return pairs[a].J < pairs[b].J
+	})
+	return pairs
+}
+
+// minHashRows returns how many signature rows make a band for threshold: the most,
+// so the fewest dissimilar entries are compared, that still keep minHashRecall
+func minHashRows(threshold float64) int {
+	rows := 1
+	for r := 2; r <= minHashSize; r *= 2 {
+		bands := float64(minHashSize / r)
+		if 1-math.Pow(1-math.Pow(threshold, float64(r)), bands) < minHashRecall {
+			break
+		}
+		rows = r
+	}
+	return rows
+}
+
+// fingerprint is the comparable form of some content: its normalized word set, or
+// the MinHash signature of it
+type fingerprint struct {
+	words     map[string]struct{}
+	signature []uint64 // nil when the word set is empty
+}
+
+// fingerprint computes the form of content the detector compares
+func (d *DuplicateDetector) fingerprint(content string) fingerprint {
+	words := wordSet(NormalizeContent(content))
+	if d.method != DuplicateMinHash {
+		return fingerprint{words: words}
+	}
+	return fingerprint{signature: minHashSignature(words)}
+}
+
+// compare scores two fingerprints like Similarity: two empty ones are identical and
+// an empty one matches nothing else
+func (d *DuplicateDetector) compare(a, b fingerprint) float64 {
+	if d.method != DuplicateMinHash {
+		return jaccard(a.words, b.words)
+	}
+	if a.signature == nil || b.signature == nil {
+		if a.signature == nil && b.signature == nil {
+			return 1
+		}
+		return 0
+	}
+	equal := 0
+	for i := range a.signature {
+		if a.signature[i] == b.signature[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a.signature))
+}
+
+// minHashSignature keeps, for each seed, the smallest salted hash of the words
+func minHashSignature(words map[string]struct{}) []uint64 {
+	if len(words) == 0 {
+		return nil
+	}
+	signature := make([]uint64, minHashSize)
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+	for word := range words {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		for i, seed := range minHashSeeds {
+			if v := mix64(sum ^ seed); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+	return signature
+}
+
+// mix64 is the splitmix64 finalizer, spreading the bits of x
+func mix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
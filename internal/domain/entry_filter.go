@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EntryFilter selects entries across sections; zero values match everything
+type EntryFilter struct {
+	Sections       []SectionType // Only these sections
+	Tag            string        // Tag name, with or without brackets, case-insensitive
+	Since          time.Time     // Only entries created at or after this time
+	Files          []string      // Only entries whose scope covers one of these files (unscoped entries always match)
+	Author         string        // Author kind ("user" or "agent") or part of the author name, case-insensitive
+	References     string        // Only entries referencing the entry with this ID or ID prefix
+	IncludeExpired bool          // Keep entries whose expiry has passed
+	Now            time.Time     // Reference time for expiry; zero uses time.Now
+}
+
+// Matches reports whether an entry passes the filter
+func (f EntryFilter) Matches(entry Entry) bool {
+	if len(f.Sections) > 0 && !containsSection(f.Sections, entry.Section) {
+		return false
+	}
+	if f.Tag != "" && !strings.EqualFold(strings.Trim(f.Tag, "[]"), entry.TagName) {
+		return false
+	}
+	if !f.Since.IsZero() && entry.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !entry.AppliesTo(f.Files) {
+		return false
+	}
+	if f.Author != "" && !matchAuthor(f.Author, entry) {
+		return false
+	}
+	if f.References != "" && !references(entry, f.References) {
+		return false
+	}
+	if !f.IncludeExpired {
+		now := f.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		if entry.IsExpired(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListEntries returns the entries matching the filter, in section order then file order
+func (s *MemoryService) ListEntries(ctx context.Context, filter EntryFilter) ([]Entry, error) {
+	sections, err := s.ReadSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, section := range sections {
+		for _, entry := range section.Entries {
+			if filter.Matches(entry) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ParseSections parses a comma-separated list of section names
+func ParseSections(value string) ([]SectionType, error) {
+	var sections []SectionType
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		section := SectionType(strings.ToLower(name))
+		if !section.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCategory, name)
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// ParseSince parses a relative age ("30d", "2w", "12h") or a date (RFC3339 or YYYY-MM-DD)
+// into the earliest creation time to include
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	units := map[byte]time.Duration{'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	unit, ok := units[value[len(value)-1]]
+	if ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q: use a relative age like 30d, 2w, 12h or a date like 2026-01-31", value)
+}
+
+// matchAuthor reports whether the entry author is of the given kind or contains the
+// given name, ignoring case
+func matchAuthor(author string, entry Entry) bool {
+	author = strings.ToLower(strings.TrimSpace(author))
+	if author == AuthorUser || author == AuthorAgent {
+		return entry.AuthorKind() == author
+	}
+	return strings.Contains(strings.ToLower(entry.Author), author)
+}
+
+// references reports whether the entry references an ID starting with idOrPrefix
+func references(entry Entry, idOrPrefix string) bool {
+	idOrPrefix = strings.ToLower(strings.TrimSpace(idOrPrefix))
+	for _, ref := range entry.Refs() {
+		if strings.HasPrefix(ref, idOrPrefix) || strings.HasPrefix(idOrPrefix, ref) {
+			return true
+		}
+	}
+	return false
+}
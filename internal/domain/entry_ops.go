@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FindEntry returns the entry whose ID equals or uniquely starts with idOrPrefix
+func (s *MemoryService) FindEntry(ctx context.Context, idOrPrefix string) (*Entry, error) {
+	idOrPrefix = strings.TrimSpace(idOrPrefix)
+	if idOrPrefix == "" {
+		return nil, fmt.Errorf("%w: empty ID", ErrEntryNotFound)
+	}
+
+	entries, err := s.ListEntries(ctx, EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+	return findByID(entries, idOrPrefix)
+}
+
+// findByID returns the entry with the given ID or unique ID prefix
+func findByID(entries []Entry, idOrPrefix string) (*Entry, error) {
+	var matches []Entry
+	for _, entry := range entries {
+		if entry.ID == idOrPrefix {
+			return &entry, nil
+		}
+		if strings.HasPrefix(entry.ID, idOrPrefix) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, idOrPrefix)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %s matches %d entries", ErrAmbiguousID, idOrPrefix, len(matches))
+	}
+}
+
+// DeleteEntry removes a single entry by ID
+func (s *MemoryService) DeleteEntry(ctx context.Context, id string) (*Entry, error) {
+	deleted, err := s.repo.DeleteEntries(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, id)
+	}
+	return &deleted[0], nil
+}
+
+// DeleteEntries removes the entries with the given IDs
+func (s *MemoryService) DeleteEntries(ctx context.Context, ids []string) ([]Entry, error) {
+	return s.repo.DeleteEntries(ctx, ids)
+}
+
+// DeprecateEntries marks active entries as deprecated, keeping them in the memory
+// file. All entries are marked in one write, so either all of them are or none.
+func (s *MemoryService) DeprecateEntries(ctx context.Context, entries []Entry) ([]Entry, error) {
+	deprecated := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsActive() {
+			continue
+		}
+		entry.Status = StatusDeprecated
+		deprecated = append(deprecated, entry)
+	}
+	if err := s.repo.UpdateEntries(ctx, deprecated); err != nil {
+		return nil, err
+	}
+	return deprecated, nil
+}
+
+// EditEntry applies edited fields to an existing entry after validating them,
+// keeping its ID, section, creation time, status and author
+func (s *MemoryService) EditEntry(ctx context.Context, entry Entry, input AppendInput) (*Entry, error) {
+	input.Category = string(entry.Section)
+	if err := s.ResolveRefs(ctx, &input); err != nil {
+		return nil, err
+	}
+	if err := s.ValidateInput(input); err != nil {
+		return nil, err
+	}
+
+	edited := s.PrepareEntry(input, entry.ID, entry.CreatedAt)
+	edited.Status = entry.Status
+	edited.SupersededBy = entry.SupersededBy
+	edited.Author = entry.Author
+	if err := s.repo.UpdateEntry(ctx, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
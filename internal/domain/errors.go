@@ -9,4 +9,17 @@ var (
 	ErrInvalidRationale = errors.New("invalid rationale")
 	ErrForbiddenContent = errors.New("forbidden content")
 	ErrListItem         = errors.New("list item not allowed")
+	ErrInvalidExpiry    = errors.New("invalid expiry")
+	ErrInvalidScope     = errors.New("invalid scope")
+	ErrMemoryBusy       = errors.New("memory busy")
+	ErrDuplicateEntry   = errors.New("possible duplicate entry")
+	ErrEntryNotFound    = errors.New("entry not found")
+	ErrAmbiguousID      = errors.New("ambiguous entry ID")
+	ErrInactiveEntry    = errors.New("entry is not active")
+	ErrContradiction    = errors.New("contradicts an active constraint")
+	ErrInvalidImport    = errors.New("invalid import")
+
+	ErrInvalidDecisionRecord = errors.New("invalid decision record")
+	ErrInvalidReference      = errors.New("invalid entry reference")
+	ErrInvalidPolicy         = errors.New("invalid validation policy")
 )
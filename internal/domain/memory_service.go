@@ -4,24 +4,82 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"html/template"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"log/slog"
 )
 
+// Default field length limits enforced by ValidateInput, in characters (runes) rather
+// than bytes, so CJK text and emoji get the same room as ASCII
+const (
+	MaxTagLength       = 50
+	MaxContentLength   = 2000
+	MaxRationaleLength = 500
+)
+
 // MemoryService handles business logic and template rendering
 type MemoryService struct {
-	repo MemoryRepository
+	repo        MemoryRepository
+	tags        *TagService
+	rules       *ValidationRules
+	duplicates  *DuplicateDetector
+	tokens      *TokenEstimator
+	tokenBudget int
 }
 
-// NewMemoryService creates a new memory service
+// NewMemoryService creates a new memory service with the default tag taxonomy,
+// validation policy and duplicate detector, and no token budget
 func NewMemoryService(repo MemoryRepository) *MemoryService {
-	return &MemoryService{repo: repo}
+	return &MemoryService{
+		repo:       repo,
+		tags:       NewTagService(nil),
+		rules:      defaultValidationRules(),
+		duplicates: DefaultDuplicateDetector(),
+		tokens:     NewTokenEstimator(),
+	}
+}
+
+// Tags returns the tag taxonomy used to normalize captured tags
+func (s *MemoryService) Tags() *TagService {
+	return s.tags
+}
+
+// SetTags replaces the tag taxonomy, e.g. with one extended by project definitions
+func (s *MemoryService) SetTags(tags *TagService) {
+	s.tags = tags
+}
+
+// Duplicates returns the detector FindDuplicates compares entries with
+func (s *MemoryService) Duplicates() *DuplicateDetector {
+	return s.duplicates
 }
 
-// ValidateInput validates append input against schema constraints
+// SetDuplicateDetector replaces the detector, e.g. with one using MinHash
+func (s *MemoryService) SetDuplicateDetector(detector *DuplicateDetector) {
+	s.duplicates = detector
+}
+
+// Rules returns the validation rules ValidateInput runs
+func (s *MemoryService) Rules() *ValidationRules {
+	return s.rules
+}
+
+// SetValidationPolicy replaces the validation rules with ones compiled from policy,
+// e.g. one loaded from configuration
+func (s *MemoryService) SetValidationPolicy(policy ValidationPolicy) error {
+	rules, err := NewValidationRules(policy)
+	if err != nil {
+		return err
+	}
+	s.rules = rules
+	return nil
+}
+
+// ValidateInput validates append input against schema constraints and the rules of
+// the validation policy
 func (s *MemoryService) ValidateInput(input AppendInput) error {
 	// Default to "note" if category is empty
 	if input.Category == "" {
@@ -34,41 +92,41 @@ func (s *MemoryService) ValidateInput(input AppendInput) error {
 		return fmt.Errorf("%w: %s (must be constraints, decisions, patterns, anti-patterns or note)", ErrInvalidCategory, input.Category)
 	}
 
-	// Validate Tag
-	if len(input.Tag) == 0 {
-		return fmt.Errorf("%w: tag cannot be empty", ErrInvalidTag)
-	}
-	if len(input.Tag) > 50 {
-		return fmt.Errorf("%w: tag must be 50 characters or less (got %d)", ErrInvalidTag, len(input.Tag))
-	}
-
-	// Validate Content
-	if len(input.Content) == 0 {
-		return fmt.Errorf("%w: content cannot be empty", ErrInvalidContent)
-	}
-	if len(input.Content) > 2000 {
-		return fmt.Errorf("%w: content must be 2000 characters or less (got %d)", ErrInvalidContent, len(input.Content))
-	}
-	if err := ValidateContent(input.Content); err != nil {
+	if err := s.rules.Validate(input); err != nil {
 		return err
 	}
+	_, err := NormalizeScope(input.Scope)
+	return err
+}
 
-	// Validate Rationale
-	if len(input.Rationale) > 500 {
-		return fmt.Errorf("%w: rationale must be 500 characters or less (got %d)", ErrInvalidRationale, len(input.Rationale))
-	}
+// ValidateTag checks a tag is present and within the default length limit
+func ValidateTag(tag string) error {
+	return defaultValidationRules().ValidateTag(tag)
+}
 
-	return nil
+// ValidateEntryContent checks content is present, within the default length limit and
+// free of forbidden patterns
+func ValidateEntryContent(content string) error {
+	return defaultValidationRules().ValidateEntryContent(content)
+}
+
+// ValidateRationale checks an optional rationale is within the default length limit
+func ValidateRationale(rationale string) error {
+	return defaultValidationRules().ValidateRationale(rationale)
 }
 
-// ValidateContent checks for forbidden content patterns
+// ValidateContent checks for forbidden content patterns. Inline code and angle
+// brackets are allowed: EscapeContent keeps them from breaking the memory file.
 func ValidateContent(content string) error {
-	forbidden := []string{"\n", "\r", "###", "```", "<", ">"}
+	forbidden := []string{"\n", "\r", "<!--", "-->", "(*Rationale:", "(*理由:"}
 	for _, char := range forbidden {
 		if strings.Contains(content, char) {
 			return fmt.Errorf("%w: contains %q", ErrForbiddenContent, char)
 		}
 	}
+	if hasUnclosedCodeSpan(content) {
+		return fmt.Errorf("%w: unclosed inline code span", ErrForbiddenContent)
+	}
 
 	trimmed := strings.TrimSpace(content)
 	if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
@@ -78,6 +136,86 @@ func ValidateContent(content string) error {
 	return nil
 }
 
+// ParseExpiry resolves an expires_at value (RFC3339 or YYYY-MM-DD) or a TTL in days
+// into an expiry time. Both empty returns the zero time (no expiry).
+func ParseExpiry(expiresAt string, ttlDays int, now time.Time) (time.Time, error) {
+	expiresAt = strings.TrimSpace(expiresAt)
+	if expiresAt != "" && ttlDays != 0 {
+		return time.Time{}, fmt.Errorf("%w: use either expires_at or ttl_days, not both", ErrInvalidExpiry)
+	}
+	if ttlDays < 0 {
+		return time.Time{}, fmt.Errorf("%w: ttl_days must be positive (got %d)", ErrInvalidExpiry, ttlDays)
+	}
+
+	var expiry time.Time
+	switch {
+	case ttlDays > 0:
+		expiry = now.AddDate(0, 0, ttlDays)
+	case expiresAt != "":
+		parsed, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			parsed, err = time.ParseInLocation("2006-01-02", expiresAt, now.Location())
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%w: %q is not RFC3339 or YYYY-MM-DD", ErrInvalidExpiry, expiresAt)
+			}
+		}
+		expiry = parsed
+	default:
+		return time.Time{}, nil
+	}
+
+	if !expiry.After(now) {
+		return time.Time{}, fmt.Errorf("%w: %s is in the past", ErrInvalidExpiry, expiry.Format(time.RFC3339))
+	}
+	return expiry, nil
+}
+
+// AnchorExtras renders the optional anchor metadata fields of an entry
+// (e.g. ", expires: 2026-01-01T00:00:00Z"), shared by all entry renderers
+func AnchorExtras(entry Entry) string {
+	var sb strings.Builder
+	if !entry.ExpiresAt.IsZero() {
+		sb.WriteString(", expires: ")
+		sb.WriteString(entry.ExpiresAt.Format(time.RFC3339))
+	}
+	if !entry.IsActive() {
+		sb.WriteString(", status: ")
+		sb.WriteString(string(entry.Status))
+	}
+	if entry.SupersededBy != "" {
+		sb.WriteString(", superseded_by: ")
+		sb.WriteString(entry.SupersededBy)
+	}
+	if len(entry.Scope) > 0 {
+		sb.WriteString(", scope: ")
+		sb.WriteString(strings.Join(entry.Scope, " "))
+	}
+	if author := NormalizeAuthor(entry.Author); author != "" {
+		sb.WriteString(", author: ")
+		sb.WriteString(author)
+	}
+	return sb.String()
+}
+
+// FindEntryBlock locates the anchored block of the entry with the given ID,
+// returning the byte range including its trailing newline, or -1, -1
+func FindEntryBlock(content, id string) (int, int) {
+	start := strings.Index(content, "<!-- entry-id: "+id+",")
+	if start == -1 {
+		return -1, -1
+	}
+	endMarker := "<!-- entry-end -->"
+	rel := strings.Index(content[start:], endMarker)
+	if rel == -1 {
+		return -1, -1
+	}
+	end := start + rel + len(endMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end
+}
+
 // EntryView is the data structure for template rendering
 type EntryView struct {
 	ID        string
@@ -86,24 +224,44 @@ type EntryView struct {
 	Content   string
 	Rationale string
 	Time      string
+	Extras    string
+	Record    string // Decision record lines, each ending with a newline
+	Source    string // Template the entry comes from, for template entries
 }
 
-const entryTemplate = `<!-- entry-id: {{.ID}}, tag: {{.Tag}}, time: {{.Time}} -->
+const entryTemplate = `<!-- entry-id: {{.ID}}, tag: {{.Tag}}, time: {{.Time}}{{.Extras}} -->
 * **[{{.TagName}}]** {{.Content}}{{if .Rationale}} (*Rationale: {{.Rationale}}*){{end}}
+{{.Record}}<!-- entry-end -->`
+
+const templateEntryTemplate = `<!-- template-entry, tag: {{.Tag}}, source: {{.Source}} -->
+* **[{{.TagName}}]** {{.Content}}{{if .Rationale}} (*理由: {{.Rationale}}*){{end}}
 <!-- entry-end -->`
 
-// RenderEntry renders an entry to the 4-line anchored format
+const legacyEntryTemplate = `* **[{{.TagName}}]** {{.Content}}{{if .Rationale}} (*Rationale: {{.Rationale}}*){{end}}`
+
+// RenderEntry renders an entry the way it is stored: captured entries in the 4-line
+// anchored format, template entries as a template block and legacy entries as a bullet
 func (s *MemoryService) RenderEntry(entry Entry) (string, error) {
 	view := EntryView{
 		ID:        entry.ID,
 		Tag:       entry.Tag,
 		TagName:   entry.TagName,
-		Content:   entry.Content,
-		Rationale: entry.Rationale,
+		Content:   EscapeContent(entry.Content),
+		Rationale: EscapeContent(entry.Rationale),
 		Time:      entry.CreatedAt.Format(time.RFC3339),
+		Extras:    AnchorExtras(entry),
+		Record:    RenderDecisionRecord(entry.ADR),
+		Source:    entry.Template,
 	}
 
-	tmpl, err := template.New("entry").Parse(entryTemplate)
+	text := entryTemplate
+	switch {
+	case entry.Template != "":
+		text = templateEntryTemplate
+	case entry.ID == "":
+		text = legacyEntryTemplate
+	}
+	tmpl, err := template.New("entry").Parse(text)
 	if err != nil {
 		slog.Error("failed to parse entry template", "error", err)
 		return "", fmt.Errorf("failed to parse template: %w", err)
@@ -118,6 +276,28 @@ func (s *MemoryService) RenderEntry(entry Entry) (string, error) {
 	return buf.String(), nil
 }
 
+// RenderSection renders a section header followed by its entries, each as RenderEntry
+// does. With collapseSuperseded, superseded entries are rendered as one-line pointers
+// to their successor, as ReadMemoryWithOptions does.
+func (s *MemoryService) RenderSection(section *Section, collapseSuperseded bool) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## %s\n\n", section.Type.Title()))
+
+	for _, entry := range section.Entries {
+		rendered := CollapseSuperseded(entry)
+		if !collapseSuperseded || !entry.IsSuperseded() {
+			var err error
+			if rendered, err = s.RenderEntry(entry); err != nil {
+				return "", err
+			}
+		}
+		sb.WriteString(rendered)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
 // PrepareEntry creates a new Entry from AppendInput
 func (s *MemoryService) PrepareEntry(input AppendInput, id string, now time.Time) Entry {
 	// Auto-wrap tag with brackets
@@ -129,6 +309,11 @@ func (s *MemoryService) PrepareEntry(input AppendInput, id string, now time.Time
 	// Remove brackets for TagName
 	tagName := strings.Trim(tag, "[]")
 
+	section := SectionType(input.Category)
+	if section == "" {
+		section = SectionNote
+	}
+
 	return Entry{
 		ID:        id,
 		Tag:       tag,
@@ -136,6 +321,11 @@ func (s *MemoryService) PrepareEntry(input AppendInput, id string, now time.Time
 		Content:   input.Content,
 		Rationale: input.Rationale,
 		CreatedAt: now,
+		ExpiresAt: input.ExpiresAt,
+		Scope:     input.Scope,
+		Section:   section,
+		Author:    NormalizeAuthor(input.Author),
+		ADR:       input.ADR,
 	}
 }
 
@@ -144,6 +334,72 @@ func (s *MemoryService) ReadSection(ctx context.Context, sectionType SectionType
 	return s.repo.GetSection(ctx, sectionType)
 }
 
+// ReadSections retrieves all sections in ValidSections order
+func (s *MemoryService) ReadSections(ctx context.Context) ([]*Section, error) {
+	sections := make([]*Section, 0, len(ValidSections()))
+	for _, sectionType := range ValidSections() {
+		section, err := s.repo.GetSection(ctx, sectionType)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// MaxTagExamples is the number of example entries kept per tag summary
+const MaxTagExamples = 2
+
+// ListTags returns the distinct tags in use, most used first
+func (s *MemoryService) ListTags(ctx context.Context) ([]TagSummary, error) {
+	sections, err := s.ReadSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*TagSummary)
+	var order []string
+	for _, section := range sections {
+		for _, entry := range section.Entries {
+			summary, ok := byName[entry.TagName]
+			if !ok {
+				summary = &TagSummary{Name: entry.TagName}
+				byName[entry.TagName] = summary
+				order = append(order, entry.TagName)
+			}
+			summary.Count++
+			if !containsSection(summary.Sections, section.Type) {
+				summary.Sections = append(summary.Sections, section.Type)
+			}
+			if len(summary.Examples) < MaxTagExamples {
+				summary.Examples = append(summary.Examples, entry)
+			}
+		}
+	}
+
+	tags := make([]TagSummary, 0, len(order))
+	for _, name := range order {
+		tags = append(tags, *byName[name])
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name)
+	})
+
+	return tags, nil
+}
+
+func containsSection(sections []SectionType, target SectionType) bool {
+	for _, section := range sections {
+		if section == target {
+			return true
+		}
+	}
+	return false
+}
+
 // GetMemoryPath returns the memory file path
 func (s *MemoryService) GetMemoryPath() string {
 	return s.repo.FilePath()
@@ -154,8 +410,212 @@ func (s *MemoryService) ReadMemory(ctx context.Context) (string, error) {
 	return s.repo.ReadAll(ctx)
 }
 
-// AppendMemory appends an entry to the memory file
+// ReadOptions controls how ReadMemoryWithOptions shapes the memory content
+type ReadOptions struct {
+	MaxChars       int       // Character budget; zero or less disables trimming
+	MaxTokens      int       // Token budget, estimated by the TokenEstimator; zero or less disables trimming
+	ExcludeExpired bool      // Drop entries whose expiry is not after Now
+	Now            time.Time // Reference time for expiry checks
+	Files          []string  // Drop scoped entries that apply to none of these files
+	// CollapseSuperseded replaces superseded entries with a one-line pointer to their successor
+	CollapseSuperseded bool
+}
+
+// ReadMemoryWithOptions returns the memory content, optionally without expired
+// entries or entries scoped to other files, with superseded entries collapsed, and
+// trimmed to at most MaxChars and MaxTokens. When the content exceeds a budget, entries are kept
+// by priority: sections in ValidSections order (Constraints first), most recent
+// entries first, followed by a truncation notice that counts toward the budget.
+func (s *MemoryService) ReadMemoryWithOptions(ctx context.Context, opts ReadOptions) (string, error) {
+	content, err := s.repo.ReadAll(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if (opts.ExcludeExpired || len(opts.Files) > 0 || opts.CollapseSuperseded) && content != "" {
+		sections, err := s.ReadSections(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, section := range sections {
+			for _, entry := range section.Entries {
+				if entry.ID == "" {
+					continue
+				}
+				replacement := ""
+				if !(opts.ExcludeExpired && entry.IsExpired(opts.Now)) && entry.AppliesTo(opts.Files) {
+					if !opts.CollapseSuperseded || !entry.IsSuperseded() {
+						continue
+					}
+					replacement = CollapseSuperseded(entry) + "\n"
+				}
+				if start, end := FindEntryBlock(content, entry.ID); start != -1 {
+					content = content[:start] + replacement + content[end:]
+				}
+			}
+		}
+	}
+
+	maxChars, maxTokens := opts.MaxChars, opts.MaxTokens
+	overChars := maxChars > 0 && len(content) > maxChars
+	overTokens := maxTokens > 0 && s.tokens.Count(content) > maxTokens
+	if !overChars && !overTokens {
+		return content, nil
+	}
+
+	sections, err := s.ReadSections(ctx)
+	if err != nil {
+		return "", err
+	}
+	total := 0
+	for i, section := range sections {
+		if opts.ExcludeExpired {
+			section = section.WithoutExpired(opts.Now)
+		}
+		sections[i] = section.ForFiles(opts.Files)
+		total += len(sections[i].Entries)
+	}
+
+	// The notice is written last but its room is kept from the start; it is sized
+	// for every entry being shown, its longest form
+	budgets := make([]string, 0, 2)
+	if maxChars > 0 {
+		budgets = append(budgets, fmt.Sprintf("%d character", maxChars))
+	}
+	if maxTokens > 0 {
+		budgets = append(budgets, fmt.Sprintf("%d token", maxTokens))
+	}
+	notice := func(included int) string {
+		return fmt.Sprintf("<!-- truncated: showing %d of %d entries to fit a %s budget -->\n", included, total, strings.Join(budgets, " and "))
+	}
+	size, tokens := len(notice(total)), s.tokens.Count(notice(total))
+
+	var (
+		sb       strings.Builder
+		included int
+	)
+	for _, section := range sections {
+		entries := make([]Entry, len(section.Entries))
+		copy(entries, section.Entries)
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		})
+
+		// The first entry kept also pays for the section header and the blank line
+		// closing the section
+		header := fmt.Sprintf("## %s\n\n", section.Type.Title())
+		headerWritten := false
+		for _, entry := range entries {
+			rendered := CollapseSuperseded(entry)
+			if !opts.CollapseSuperseded || !entry.IsSuperseded() {
+				if rendered, err = s.RenderEntry(entry); err != nil {
+					return "", err
+				}
+			}
+			block := rendered + "\n"
+			cost := block
+			if !headerWritten {
+				block = header + block
+				cost = block + "\n"
+			}
+			if (maxChars > 0 && size+len(cost) > maxChars) || (maxTokens > 0 && tokens+s.tokens.Count(cost) > maxTokens) {
+				continue
+			}
+			size += len(cost)
+			tokens += s.tokens.Count(cost)
+			headerWritten = true
+			sb.WriteString(block)
+			included++
+		}
+		if headerWritten {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(notice(included))
+
+	return sb.String(), nil
+}
+
+// FindDuplicate returns the most similar existing entry in the target section
+// when its similarity reaches the detector threshold, or nil if none does. The entry
+// the input supersedes is not a duplicate.
+func (s *MemoryService) FindDuplicate(ctx context.Context, input AppendInput) (*Entry, float64, error) {
+	candidates, err := s.FindDuplicates(ctx, input)
+	if err != nil || len(candidates) == 0 {
+		return nil, 0, err
+	}
+	return &candidates[0].Entry, candidates[0].Score, nil
+}
+
+// FindDuplicates returns the existing entries of the target section similar to the
+// input, most similar first, leaving out the entry the input supersedes
+func (s *MemoryService) FindDuplicates(ctx context.Context, input AppendInput) ([]DuplicateCandidate, error) {
+	category := input.Category
+	if category == "" {
+		category = string(SectionNote)
+	}
+
+	section, err := s.repo.GetSection(ctx, SectionType(category))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := section.Entries
+	if supersedes := strings.TrimSpace(input.Supersedes); supersedes != "" {
+		entries = make([]Entry, 0, len(section.Entries))
+		for _, entry := range section.Entries {
+			if !strings.HasPrefix(entry.ID, supersedes) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return s.duplicates.Candidates(input.Content, entries), nil
+}
+
+// PruneExpired moves entries expired at now into a monthly archive file and returns them
+func (s *MemoryService) PruneExpired(ctx context.Context, now time.Time) ([]Entry, error) {
+	sections, err := s.ReadSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, section := range sections {
+		for _, entry := range section.Entries {
+			if entry.ID != "" && entry.IsExpired(now) {
+				ids = append(ids, entry.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return s.repo.ArchiveEntries(ctx, ids, "expired-"+now.Format("2006-01"))
+}
+
+// AppendMemory appends an entry to the memory file and, in the same write, marks the
+// entry it supersedes, if any, as superseded by it
 func (s *MemoryService) AppendMemory(ctx context.Context, input AppendInput, id string, now time.Time) error {
+	superseded, err := s.ResolveSupersedes(ctx, input)
+	if err != nil {
+		return err
+	}
+
 	entry := s.PrepareEntry(input, id, now)
-	return s.repo.AppendEntry(ctx, SectionType(input.Category), &entry)
+	if superseded == nil {
+		return s.repo.AppendEntry(ctx, SectionType(input.Category), &entry)
+	}
+
+	// The entry and the mark on the one it supersedes land together, so a failed
+	// write never leaves both rules active
+	entry.Section = SectionType(input.Category)
+	superseded.Status = StatusSuperseded
+	superseded.SupersededBy = id
+	return s.repo.ApplyEntries(ctx, []Entry{entry}, []Entry{*superseded})
+}
+
+// AppendEntries appends prepared entries to their sections in one write
+func (s *MemoryService) AppendEntries(ctx context.Context, entries []Entry) error {
+	return s.repo.AppendEntries(ctx, entries)
 }
@@ -0,0 +1,106 @@
+package domain
+
+// MergeConflict is an entry both sides changed in incompatible ways. The merge keeps
+// the Ours version, or Theirs when ours deleted an entry theirs edited.
+type MergeConflict struct {
+	Ours   Entry
+	Theirs Entry
+	Reason string
+}
+
+// MergeResult lists what a three-way merge takes from theirs into ours
+type MergeResult struct {
+	Added      []Entry // New in theirs
+	Updated    []Entry // Changed in theirs only, in their new version
+	Removed    []Entry // Deleted in theirs, unchanged in ours
+	Duplicates []Entry // New in theirs but a near-duplicate of an entry already kept
+	Conflicts  []MergeConflict
+}
+
+// HasConflicts reports whether the merge needs manual review
+func (r MergeResult) HasConflicts() bool {
+	return len(r.Conflicts) > 0
+}
+
+// MergeEntries merges theirs into ours at entry granularity, using base as the common
+// ancestor. Entries are matched by ID. New entries from both sides are kept (union),
+// except additions from theirs that nearly duplicate an entry in the same section.
+// Legacy entries without an ID are never removed.
+func MergeEntries(base, ours, theirs []Entry) MergeResult {
+	baseByKey := entriesByKey(base)
+	oursByKey := entriesByKey(ours)
+	theirsByKey := entriesByKey(theirs)
+
+	var result MergeResult
+	kept := append([]Entry(nil), ours...)
+	for _, entry := range theirs {
+		key := diffKey(entry)
+		previous, inBase := baseByKey[key]
+		current, inOurs := oursByKey[key]
+
+		switch {
+		case !inOurs && inBase:
+			// Deleted in ours: keep it deleted unless theirs edited it
+			if len(changedFields(previous, entry)) > 0 {
+				result.Added = append(result.Added, entry)
+				result.Conflicts = append(result.Conflicts, MergeConflict{Ours: previous, Theirs: entry, Reason: "deleted in ours, changed in theirs"})
+			}
+		case !inOurs:
+			if duplicatesAny(entry, kept) {
+				result.Duplicates = append(result.Duplicates, entry)
+				continue
+			}
+			result.Added = append(result.Added, entry)
+			kept = append(kept, entry)
+		default:
+			if !inBase {
+				previous = current
+			}
+			theirsChanged := len(changedFields(previous, entry)) > 0
+			oursChanged := len(changedFields(previous, current)) > 0
+			switch {
+			case !theirsChanged, len(changedFields(current, entry)) == 0:
+				// Nothing to take from theirs
+			case !oursChanged:
+				result.Updated = append(result.Updated, entry)
+			default:
+				result.Conflicts = append(result.Conflicts, MergeConflict{Ours: current, Theirs: entry, Reason: "changed on both sides"})
+			}
+		}
+	}
+
+	for _, entry := range ours {
+		key := diffKey(entry)
+		previous, inBase := baseByKey[key]
+		if _, inTheirs := theirsByKey[key]; inTheirs || !inBase || entry.ID == "" {
+			continue
+		}
+		// Deleted in theirs: drop it unless ours edited it
+		if len(changedFields(previous, entry)) > 0 {
+			result.Conflicts = append(result.Conflicts, MergeConflict{Ours: entry, Theirs: previous, Reason: "changed in ours, deleted in theirs"})
+			continue
+		}
+		result.Removed = append(result.Removed, entry)
+	}
+	return result
+}
+
+// entriesByKey indexes entries by their diff key
+func entriesByKey(entries []Entry) map[string]Entry {
+	byKey := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		byKey[diffKey(entry)] = entry
+	}
+	return byKey
+}
+
+// duplicatesAny reports whether entry nearly duplicates an entry of the same section
+func duplicatesAny(entry Entry, entries []Entry) bool {
+	detector := DefaultDuplicateDetector()
+	for _, other := range entries {
+		if other.Section == entry.Section && detector.Score(other.Content, entry.Content) >= detector.Threshold() {
+			return true
+		}
+	}
+	return false
+}
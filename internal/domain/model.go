@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // SectionType defines valid memory categories
 type SectionType string
@@ -28,6 +31,24 @@ func (s SectionType) IsValid() bool {
 	}
 }
 
+// Title returns the Markdown section header name (e.g. "Anti-Patterns")
+func (s SectionType) Title() string {
+	switch s {
+	case SectionConstraints:
+		return "Constraints"
+	case SectionDecisions:
+		return "Decisions"
+	case SectionPatterns:
+		return "Patterns"
+	case SectionAntiPatterns:
+		return "Anti-Patterns"
+	case SectionNote:
+		return "Note"
+	default:
+		return string(s)
+	}
+}
+
 // FileFormat represents the memory file format version
 type FileFormat string
 
@@ -42,14 +63,71 @@ type ParseMetadata struct {
 	Warning string // Optional warning message
 }
 
+// EntryStatus is the lifecycle state of an entry
+type EntryStatus string
+
+const (
+	StatusActive     EntryStatus = "active"
+	StatusDeprecated EntryStatus = "deprecated"
+	StatusSuperseded EntryStatus = "superseded"
+)
+
 // Entry represents a single memory entry
 type Entry struct {
-	ID        string    // UUID v7
-	Tag       string    // With brackets: "[Architecture]"
-	TagName   string    // Without brackets: "Architecture"
-	Content   string    // Cleaned single-line content
-	Rationale string    // Optional
-	CreatedAt time.Time // RFC3339 format
+	ID           string         // UUID v7
+	Tag          string         // With brackets: "[Architecture]"
+	TagName      string         // Without brackets: "Architecture"
+	Content      string         // Cleaned single-line content
+	Rationale    string         // Optional
+	CreatedAt    time.Time      // RFC3339 format
+	ExpiresAt    time.Time      // Optional: zero means the entry never expires
+	Section      SectionType    // Section the entry was read from
+	Status       EntryStatus    // Optional: empty means active
+	SupersededBy string         // Optional: ID of the entry replacing a superseded one
+	Scope        []string       // Optional: path patterns the entry applies to; empty means the whole project
+	Author       string         // Optional: who captured the entry, e.g. "user:Jane Doe" or "agent:cursor 1.2"
+	ADR          DecisionRecord // Optional, decisions only: context, options considered and consequences
+	Template     string         // Set on entries injected from a template: the template source, e.g. "go"
+}
+
+// Origin describes where an entry without an ID comes from, "template:<source>" or
+// "legacy", and returns the ID of captured entries
+func (e Entry) Origin() string {
+	switch {
+	case e.ID != "":
+		return e.ID
+	case e.Template != "":
+		return "template:" + e.Template
+	default:
+		return "legacy"
+	}
+}
+
+// IsActive reports whether the entry has not been deprecated or superseded
+func (e Entry) IsActive() bool {
+	return e.Status == "" || e.Status == StatusActive
+}
+
+// IsSuperseded reports whether another entry replaces this one
+func (e Entry) IsSuperseded() bool {
+	return e.Status == StatusSuperseded
+}
+
+// StatusLabel describes the status of the entry, e.g. "active" or "superseded by <id>"
+func (e Entry) StatusLabel() string {
+	switch {
+	case e.IsActive():
+		return string(StatusActive)
+	case e.IsSuperseded() && e.SupersededBy != "":
+		return fmt.Sprintf("%s by %s", e.Status, e.SupersededBy)
+	default:
+		return string(e.Status)
+	}
+}
+
+// IsExpired reports whether the entry has an expiry that is not after now
+func (e Entry) IsExpired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !e.ExpiresAt.After(now)
 }
 
 // Section represents a category of entries
@@ -58,10 +136,49 @@ type Section struct {
 	Entries []Entry
 }
 
+// WithoutExpired returns a copy of the section without entries expired at now
+func (s *Section) WithoutExpired(now time.Time) *Section {
+	active := &Section{Type: s.Type, Entries: make([]Entry, 0, len(s.Entries))}
+	for _, entry := range s.Entries {
+		if !entry.IsExpired(now) {
+			active.Entries = append(active.Entries, entry)
+		}
+	}
+	return active
+}
+
+// Active returns a copy of the section without deprecated or superseded entries
+func (s *Section) Active() *Section {
+	active := &Section{Type: s.Type, Entries: make([]Entry, 0, len(s.Entries))}
+	for _, entry := range s.Entries {
+		if entry.IsActive() {
+			active.Entries = append(active.Entries, entry)
+		}
+	}
+	return active
+}
+
+// TagSummary describes how a tag is used across the memory
+type TagSummary struct {
+	Name     string        // Without brackets: "Architecture"
+	Count    int           // Number of entries using the tag
+	Sections []SectionType // Sections the tag appears in
+	Examples []Entry       // A few entries using the tag
+}
+
 // AppendInput represents validated input for appending memory
 type AppendInput struct {
-	Category  string `json:"category" validate:"omitempty,oneof=constraints decisions patterns anti-patterns note"`
-	Tag       string `json:"tag" validate:"required,max=50"`
-	Content   string `json:"content" validate:"required,max=2000,ascii"`
-	Rationale string `json:"rationale,omitempty" validate:"max=500"`
+	Category  string    `json:"category" validate:"omitempty,oneof=constraints decisions patterns anti-patterns note"`
+	Tag       string    `json:"tag" validate:"required,max=50"`
+	Content   string    `json:"content" validate:"required,max=2000"`
+	Rationale string    `json:"rationale,omitempty" validate:"max=500"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Scope     []string  `json:"scope,omitempty"`
+	// Supersedes is the ID, or a unique ID prefix, of an active entry the new entry
+	// replaces; it is marked superseded once the new entry is appended
+	Supersedes string `json:"supersedes,omitempty"`
+	// Author records who captures the entry, formatted with NewAuthor
+	Author string `json:"author,omitempty"`
+	// ADR holds the optional architecture decision record fields of a decision
+	ADR DecisionRecord `json:"adr,omitempty"`
 }
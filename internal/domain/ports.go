@@ -13,6 +13,26 @@ type MemoryRepository interface {
 	// AppendEntry adds a new entry to the specified section
 	AppendEntry(ctx context.Context, sectionType SectionType, entry *Entry) error
 
+	// AppendEntries adds entries to their sections in a single locked write
+	AppendEntries(ctx context.Context, entries []Entry) error
+
+	// UpdateEntry rewrites the anchored block of an existing entry in place
+	UpdateEntry(ctx context.Context, entry *Entry) error
+
+	// UpdateEntries rewrites the anchored blocks of several entries in a single locked write
+	UpdateEntries(ctx context.Context, entries []Entry) error
+
+	// ApplyEntries adds the appended entries to their sections and rewrites the anchored
+	// blocks of the updated ones in a single locked write
+	ApplyEntries(ctx context.Context, appended, updated []Entry) error
+
+	// DeleteEntries removes the entries with the given IDs and returns the removed entries
+	DeleteEntries(ctx context.Context, ids []string) ([]Entry, error)
+
+	// ArchiveEntries moves the entries with the given IDs out of the memory file
+	// into the named archive file and returns the moved entries
+	ArchiveEntries(ctx context.Context, ids []string, archiveName string) ([]Entry, error)
+
 	// ReadAll returns the raw content of the entire memory file
 	ReadAll(ctx context.Context) (string, error)
 
@@ -29,3 +49,39 @@ type UUIDGenerator interface {
 type TimeProvider interface {
 	Now() time.Time
 }
+
+// AuditRecord describes a single MCP tool invocation
+type AuditRecord struct {
+	Time          time.Time `json:"time"`
+	Tool          string    `json:"tool"`
+	ArgsHash      string    `json:"args_hash"`
+	Project       string    `json:"project,omitempty"`
+	Status        string    `json:"status"` // ok, error (tool error result) or failed (handler error)
+	DurationMs    int64     `json:"duration_ms"`
+	SessionID     string    `json:"session_id,omitempty"`
+	ClientName    string    `json:"client_name,omitempty"`
+	ClientVersion string    `json:"client_version,omitempty"`
+}
+
+// AuditLogger records tool invocations for later review
+type AuditLogger interface {
+	Record(record AuditRecord) error
+}
+
+// EmbeddingProvider computes vectors for texts, with a local model or an API
+type EmbeddingProvider interface {
+	// Model identifies the vector space; vectors of different models are not comparable
+	Model() string
+
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbeddingStore persists the vectors computed for entries
+type EmbeddingStore interface {
+	// Load returns the stored index, or an empty one when none was saved yet
+	Load(ctx context.Context) (*EmbeddingIndex, error)
+
+	// Save replaces the stored index
+	Save(ctx context.Context, index *EmbeddingIndex) error
+}
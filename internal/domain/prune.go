@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PruneCriteria selects entries for pruning. An entry qualifies when it matches at
+// least one enabled criterion and lies within the section and tag scope.
+type PruneCriteria struct {
+	OlderThan  time.Time     // Created before this time; zero disables the criterion
+	Expired    bool          // Expiry has passed at Now
+	Deprecated bool          // Status is deprecated
+	Sections   []SectionType // Scope: only these sections
+	Tag        string        // Scope: only entries with this tag
+	Now        time.Time     // Reference time for expiry; zero uses time.Now
+}
+
+// IsEmpty reports whether no criterion is enabled
+func (c PruneCriteria) IsEmpty() bool {
+	return c.OlderThan.IsZero() && !c.Expired && !c.Deprecated
+}
+
+// Matches reports whether an entry should be pruned
+func (c PruneCriteria) Matches(entry Entry) bool {
+	scope := EntryFilter{Sections: c.Sections, Tag: c.Tag, IncludeExpired: true}
+	if !scope.Matches(entry) {
+		return false
+	}
+
+	now := c.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	switch {
+	case !c.OlderThan.IsZero() && !entry.CreatedAt.IsZero() && entry.CreatedAt.Before(c.OlderThan):
+		return true
+	case c.Expired && entry.IsExpired(now):
+		return true
+	case c.Deprecated && !entry.IsActive():
+		return true
+	default:
+		return false
+	}
+}
+
+// SelectPrunable returns the entries matching the criteria, in section order then file order
+func (s *MemoryService) SelectPrunable(ctx context.Context, criteria PruneCriteria) ([]Entry, error) {
+	if criteria.IsEmpty() {
+		return nil, nil
+	}
+
+	entries, err := s.ListEntries(ctx, EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []Entry
+	for _, entry := range entries {
+		if entry.ID != "" && criteria.Matches(entry) {
+			selected = append(selected, entry)
+		}
+	}
+	return selected, nil
+}
+
+// ArchiveEntries moves entries into the named archive file and returns the moved entries
+func (s *MemoryService) ArchiveEntries(ctx context.Context, entries []Entry, archiveName string) ([]Entry, error) {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	return s.repo.ArchiveEntries(ctx, ids, archiveName)
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"context"
+	"encoding/hex"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BM25 parameters: k1 caps how much repeated terms count, b how much long entries
+// are penalized
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field weights of a term occurrence: a tag match says more about an entry than a
+// mention in its rationale
+const (
+	tagWeight       = 2.0
+	contentWeight   = 1.0
+	rationaleWeight = 0.5
+)
+
+// Recency boost: an entry captured now scores up to RecencyBoost more than an old
+// one, half of that after RecencyHalfLife
+const (
+	RecencyBoost    = 0.25
+	RecencyHalfLife = 90 * 24 * time.Hour
+)
+
+// QueryService ranks entries against free-text queries, so the CLI and MCP searches
+// order results the same way
+type QueryService struct {
+	memory *MemoryService
+}
+
+// NewQueryService creates a query service over the entries of memory
+func NewQueryService(memory *MemoryService) *QueryService {
+	return &QueryService{memory: memory}
+}
+
+// Search returns entries containing every query term (case-insensitive) in their
+// content, rationale or tag, ranked by BM25 over the three fields with a boost for
+// recently captured entries; ties go to the newest entry
+func (q *QueryService) Search(ctx context.Context, query string, filter EntryFilter) ([]SearchHit, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	entries, err := q.memory.ListEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	// Document frequency of each term and average entry length, over the entries searched
+	docFreq := make(map[string]int, len(terms))
+	totalLength := 0
+	for _, entry := range entries {
+		totalLength += entryLength(entry)
+		for _, term := range terms {
+			if termFrequency(entry, term) > 0 {
+				docFreq[term]++
+			}
+		}
+	}
+	avgLength := float64(totalLength) / float64(len(entries))
+
+	now := filter.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var hits []SearchHit
+	for _, entry := range entries {
+		hit, ok := matchEntry(entry, terms)
+		if !ok {
+			continue
+		}
+		norm := 1 - bm25B
+		if avgLength > 0 {
+			norm += bm25B * float64(entryLength(entry)) / avgLength
+		}
+		score := 0.0
+		for _, term := range terms {
+			tf := termFrequency(entry, term)
+			score += idf(len(entries), docFreq[term]) * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		hit.Score = score * (1 + recencyBoost(entry.CapturedAt(), now))
+		hits = append(hits, hit)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Entry.CapturedAt().After(hits[j].Entry.CapturedAt())
+	})
+	return hits, nil
+}
+
+// Search ranks entries against a free-text query with a QueryService
+func (s *MemoryService) Search(ctx context.Context, query string, filter EntryFilter) ([]SearchHit, error) {
+	return NewQueryService(s).Search(ctx, query, filter)
+}
+
+// termFrequency counts the occurrences of a lowercase term in the entry, weighted by field
+func termFrequency(entry Entry, term string) float64 {
+	tf := contentWeight*float64(len(findSpans(entry.Content, term))) +
+		rationaleWeight*float64(len(findSpans(entry.Rationale, term)))
+	if strings.Contains(strings.ToLower(entry.TagName), term) {
+		tf += tagWeight
+	}
+	return tf
+}
+
+// entryLength is the number of words of the searched fields of the entry
+func entryLength(entry Entry) int {
+	return len(strings.Fields(entry.TagName)) + len(strings.Fields(entry.Content)) + len(strings.Fields(entry.Rationale))
+}
+
+// idf is the BM25 inverse document frequency of a term found in n of total entries,
+// always positive so a common term still counts
+func idf(total, n int) float64 {
+	return math.Log(1 + (float64(total)-float64(n)+0.5)/(float64(n)+0.5))
+}
+
+// recencyBoost decays from RecencyBoost for an entry captured at now, halving every
+// RecencyHalfLife; entries without a capture time get none
+func recencyBoost(captured, now time.Time) float64 {
+	if captured.IsZero() {
+		return 0
+	}
+	age := max(now.Sub(captured), 0)
+	return RecencyBoost * math.Exp2(-float64(age)/float64(RecencyHalfLife))
+}
+
+// CapturedAt returns when the entry was captured: the timestamp embedded in its
+// UUIDv7 ID, or the anchor time for entries with other IDs
+func (e Entry) CapturedAt() time.Time {
+	if t, ok := uuidV7Time(e.ID); ok {
+		return t
+	}
+	return e.CreatedAt
+}
+
+// uuidV7Time decodes the millisecond Unix timestamp in the first 48 bits of a UUIDv7
+func uuidV7Time(id string) (time.Time, bool) {
+	raw := strings.ReplaceAll(id, "-", "")
+	if len(raw) != 32 || len(id) != 36 || raw[12] != '7' {
+		return time.Time{}, false
+	}
+	b, err := hex.DecodeString(raw[:12])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var ms int64
+	for _, v := range b {
+		ms = ms<<8 | int64(v)
+	}
+	return time.UnixMilli(ms), true
+}
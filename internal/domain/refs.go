@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RefPrefix introduces a reference to another entry in content or rationale,
+// e.g. "Follows ref:01a14476-da35"
+const RefPrefix = "ref:"
+
+// MinRefLength is the shortest ID prefix a reference may use
+const MinRefLength = 8
+
+// shortIDLength is the length of the ID prefixes shown for references
+const shortIDLength = 13
+
+// refRegex matches a reference and captures the ID or ID prefix it points to
+var refRegex = regexp.MustCompile(`\bref:([0-9a-fA-F][0-9a-fA-F-]*[0-9a-fA-F])`)
+
+// ExtractRefs returns the IDs or ID prefixes referenced in text, lowercased, in order
+// of first appearance
+func ExtractRefs(text string) []string {
+	var refs []string
+	for _, match := range refRegex.FindAllStringSubmatch(text, -1) {
+		if ref := strings.ToLower(match[1]); !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Refs returns the IDs referenced in the content and rationale of the entry
+func (e Entry) Refs() []string {
+	refs := ExtractRefs(e.Content)
+	for _, ref := range ExtractRefs(e.Rationale) {
+		if !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ShortID returns the first two groups of an entry ID (e.g. "01a14476-da35"), enough
+// to tell entries apart when showing references
+func ShortID(id string) string {
+	if len(id) > shortIDLength {
+		return id[:shortIDLength]
+	}
+	return id
+}
+
+// RefIndex resolves references against a set of entries
+type RefIndex struct {
+	entries []Entry
+}
+
+// NewRefIndex creates an index over entries, usually all entries of the memory
+func NewRefIndex(entries []Entry) RefIndex {
+	return RefIndex{entries: entries}
+}
+
+// Resolve returns the entry an ID or unique ID prefix refers to
+func (x RefIndex) Resolve(idOrPrefix string) (*Entry, error) {
+	return findByID(x.entries, idOrPrefix)
+}
+
+// Describe rewrites the references in text to their short ID and tag, e.g.
+// "ref:01a14476-da35 [API]", so they read without looking the entries up
+func (x RefIndex) Describe(text string) string {
+	return refRegex.ReplaceAllStringFunc(text, func(match string) string {
+		entry, err := x.Resolve(strings.TrimPrefix(match, RefPrefix))
+		if err != nil {
+			return match + " (missing)"
+		}
+		return RefPrefix + ShortID(entry.ID) + " " + entry.Tag
+	})
+}
+
+// Referenced returns the entries referenced by the entry, and the references that
+// no longer resolve
+func (x RefIndex) Referenced(entry Entry) ([]Entry, []string) {
+	var found []Entry
+	var missing []string
+	for _, ref := range entry.Refs() {
+		if target, err := x.Resolve(ref); err == nil {
+			found = append(found, *target)
+		} else {
+			missing = append(missing, ref)
+		}
+	}
+	return found, missing
+}
+
+// ResolveRefs checks that every reference in the content and rationale of the input
+// points to exactly one entry and expands ID prefixes to full IDs, so references
+// stay unambiguous as the memory grows
+func (s *MemoryService) ResolveRefs(ctx context.Context, input *AppendInput) error {
+	if len(ExtractRefs(input.Content)) == 0 && len(ExtractRefs(input.Rationale)) == 0 {
+		return nil
+	}
+	entries, err := s.ListEntries(ctx, EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return err
+	}
+	index := NewRefIndex(entries)
+
+	var resolveErr error
+	expand := func(text string) string {
+		return refRegex.ReplaceAllStringFunc(text, func(match string) string {
+			ref := strings.ToLower(strings.TrimPrefix(match, RefPrefix))
+			if len(ref) < MinRefLength {
+				resolveErr = fmt.Errorf("%w: %s is shorter than %d characters", ErrInvalidReference, match, MinRefLength)
+				return match
+			}
+			entry, err := index.Resolve(ref)
+			if err != nil {
+				resolveErr = fmt.Errorf("%w: %s: %v", ErrInvalidReference, match, err)
+				return match
+			}
+			return RefPrefix + entry.ID
+		})
+	}
+	content, rationale := expand(input.Content), expand(input.Rationale)
+	if resolveErr != nil {
+		return resolveErr
+	}
+	input.Content, input.Rationale = content, rationale
+	return nil
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// MaxScopePatterns is the number of path patterns an entry scope may hold
+const MaxScopePatterns = 10
+
+// ParseScope splits a comma- or space-separated list of path patterns
+// (e.g. "internal/api/**, cmd/*.go") and validates them
+func ParseScope(value string) ([]string, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	return NormalizeScope(fields)
+}
+
+// NormalizeScope cleans path patterns into slash-separated paths relative to the
+// project root, dropping empty and repeated ones, and validates them
+func NormalizeScope(patterns []string) ([]string, error) {
+	var scope []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.ContainsAny(pattern, ", \t\n") || strings.Contains(pattern, "-->") {
+			return nil, fmt.Errorf("%w: %q cannot contain commas, spaces or \"-->\"", ErrInvalidScope, pattern)
+		}
+		pattern = strings.TrimPrefix(path.Clean(strings.ReplaceAll(pattern, `\`, "/")), "./")
+		if strings.HasPrefix(pattern, "/") || pattern == ".." || strings.HasPrefix(pattern, "../") {
+			return nil, fmt.Errorf("%w: %q must be relative to the project root", ErrInvalidScope, pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: %q is not a valid pattern", ErrInvalidScope, pattern)
+		}
+		if !slices.Contains(scope, pattern) {
+			scope = append(scope, pattern)
+		}
+	}
+	if len(scope) > MaxScopePatterns {
+		return nil, fmt.Errorf("%w: at most %d patterns (got %d)", ErrInvalidScope, MaxScopePatterns, len(scope))
+	}
+	return scope, nil
+}
+
+// MatchScope reports whether a file path, relative to the project root, falls under
+// a scope pattern. "**" matches any number of directories, other segments follow
+// path.Match; a pattern without wildcards also matches the files beneath it.
+func MatchScope(pattern, file string) bool {
+	file = strings.TrimPrefix(path.Clean(strings.ReplaceAll(file, `\`, "/")), "./")
+	if !strings.ContainsAny(pattern, "*?[") {
+		return file == pattern || pattern == "." || strings.HasPrefix(file, pattern+"/")
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, file []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(file); i++ {
+				if matchSegments(pattern[1:], file[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(file) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], file[0]); !ok {
+			return false
+		}
+		pattern, file = pattern[1:], file[1:]
+	}
+	return len(file) == 0
+}
+
+// AppliesTo reports whether the entry is relevant to any of the files: entries
+// without a scope apply everywhere, as do all entries when no files are given
+func (e Entry) AppliesTo(files []string) bool {
+	if len(e.Scope) == 0 || len(files) == 0 {
+		return true
+	}
+	for _, pattern := range e.Scope {
+		for _, file := range files {
+			if MatchScope(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ForFiles returns a copy of the section with only the entries that apply to files
+func (s *Section) ForFiles(files []string) *Section {
+	scoped := &Section{Type: s.Type, Entries: make([]Entry, 0, len(s.Entries))}
+	for _, entry := range s.Entries {
+		if entry.AppliesTo(files) {
+			scoped.Entries = append(scoped.Entries, entry)
+		}
+	}
+	return scoped
+}
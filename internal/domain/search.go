@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Span marks a match as byte offsets [Start, End) within a field
+type Span struct {
+	Start int
+	End   int
+}
+
+// SearchHit is an entry matching a search query with the match positions per field
+type SearchHit struct {
+	Entry            Entry
+	ContentMatches   []Span
+	RationaleMatches []Span
+	TagMatch         bool
+	Score            float64 // Relevance computed by QueryService; higher ranks first
+}
+
+// matchEntry reports whether every term occurs somewhere in the entry
+func matchEntry(entry Entry, terms []string) (SearchHit, bool) {
+	hit := SearchHit{Entry: entry}
+	tag := strings.ToLower(entry.TagName)
+
+	for _, term := range terms {
+		contentSpans := findSpans(entry.Content, term)
+		rationaleSpans := findSpans(entry.Rationale, term)
+		inTag := strings.Contains(tag, term)
+		if len(contentSpans) == 0 && len(rationaleSpans) == 0 && !inTag {
+			return SearchHit{}, false
+		}
+
+		hit.ContentMatches = append(hit.ContentMatches, contentSpans...)
+		hit.RationaleMatches = append(hit.RationaleMatches, rationaleSpans...)
+		hit.TagMatch = hit.TagMatch || inTag
+	}
+
+	hit.ContentMatches = mergeSpans(hit.ContentMatches)
+	hit.RationaleMatches = mergeSpans(hit.RationaleMatches)
+	return hit, true
+}
+
+// findSpans returns every case-insensitive occurrence of a lowercase term in text
+func findSpans(text, term string) []Span {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Case folding changed byte lengths; offsets would not map back to text
+		lower = text
+	}
+
+	var spans []Span
+	for offset := 0; ; {
+		i := strings.Index(lower[offset:], term)
+		if i < 0 {
+			return spans
+		}
+		start := offset + i
+		spans = append(spans, Span{Start: start, End: start + len(term)})
+		offset = start + len(term)
+	}
+}
+
+// mergeSpans sorts spans and joins overlapping ones
+func mergeSpans(spans []Span) []Span {
+	if len(spans) < 2 {
+		return spans
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	merged := spans[:1]
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.End {
+			last.End = max(last.End, span.End)
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
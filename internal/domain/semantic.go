@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// embedBatchSize is the number of entries sent to the provider per request
+const embedBatchSize = 64
+
+// EmbeddingIndex holds the vector of each entry, computed by one model
+type EmbeddingIndex struct {
+	Model   string                   `json:"model"`
+	Entries map[string]EmbeddedEntry `json:"entries"` // By indexKey
+}
+
+// EmbeddedEntry is the vector of an entry and the hash of the text it was computed
+// from, so edited entries are embedded again
+type EmbeddedEntry struct {
+	Hash   string    `json:"hash"`
+	Vector []float32 `json:"vector"`
+}
+
+// IndexStats describes what refreshing the embedding index did
+type IndexStats struct {
+	Embedded int // Entries new or changed since they were indexed
+	Removed  int // Entries no longer in the memory
+	Total    int // Entries in the index
+}
+
+// SemanticService searches entries by meaning rather than words, comparing the
+// vectors of the query and of each entry
+type SemanticService struct {
+	memory   *MemoryService
+	provider EmbeddingProvider
+	store    EmbeddingStore
+}
+
+// NewSemanticService creates a semantic search over the entries of memory, with
+// vectors computed by provider and kept in store
+func NewSemanticService(memory *MemoryService, provider EmbeddingProvider, store EmbeddingStore) *SemanticService {
+	return &SemanticService{memory: memory, provider: provider, store: store}
+}
+
+// Refresh embeds the entries that are new or changed since they were indexed and
+// drops removed ones. Changing the model reindexes every entry.
+func (s *SemanticService) Refresh(ctx context.Context) (IndexStats, error) {
+	_, stats, err := s.refresh(ctx)
+	return stats, err
+}
+
+// Search refreshes the index, then returns the entries passing filter ranked by the
+// cosine similarity of their vector to the query vector, most similar first
+func (s *SemanticService) Search(ctx context.Context, query string, filter EntryFilter) ([]SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	index, _, err := s.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := s.embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.memory.ListEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var hits []SearchHit
+	for _, entry := range entries {
+		embedded, ok := index.Entries[indexKey(entry)]
+		if !ok {
+			continue
+		}
+		if score := cosine(vectors[0], embedded.Vector); score > 0 {
+			hits = append(hits, SearchHit{Entry: entry, Score: score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits, nil
+}
+
+// refresh brings the stored index up to date with the memory and returns it
+func (s *SemanticService) refresh(ctx context.Context) (*EmbeddingIndex, IndexStats, error) {
+	var stats IndexStats
+	index, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, stats, fmt.Errorf("load embedding index: %w", err)
+	}
+	if index.Model != s.provider.Model() || index.Entries == nil {
+		index = &EmbeddingIndex{Model: s.provider.Model(), Entries: make(map[string]EmbeddedEntry)}
+	}
+
+	entries, err := s.memory.ListEntries(ctx, EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, stats, err
+	}
+	current := make(map[string]bool, len(entries))
+	var stale []Entry
+	for _, entry := range entries {
+		key := indexKey(entry)
+		current[key] = true
+		if indexed, ok := index.Entries[key]; !ok || indexed.Hash != embeddingHash(entry) {
+			stale = append(stale, entry)
+		}
+	}
+	for id := range index.Entries {
+		if !current[id] {
+			delete(index.Entries, id)
+			stats.Removed++
+		}
+	}
+
+	for start := 0; start < len(stale); start += embedBatchSize {
+		batch := stale[start:min(start+embedBatchSize, len(stale))]
+		texts := make([]string, len(batch))
+		for i, entry := range batch {
+			texts[i] = embeddingText(entry)
+		}
+		vectors, err := s.embed(ctx, texts)
+		if err != nil {
+			return nil, stats, err
+		}
+		for i, entry := range batch {
+			index.Entries[indexKey(entry)] = EmbeddedEntry{Hash: embeddingHash(entry), Vector: vectors[i]}
+		}
+		stats.Embedded += len(batch)
+	}
+
+	if stats.Embedded > 0 || stats.Removed > 0 {
+		if err := s.store.Save(ctx, index); err != nil {
+			return nil, stats, fmt.Errorf("save embedding index: %w", err)
+		}
+	}
+	stats.Total = len(index.Entries)
+	return index, stats, nil
+}
+
+// embed asks the provider for the vectors of texts, checking it returned one each
+func (s *SemanticService) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := s.provider.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed with %s: %w", s.provider.Model(), err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embed with %s: got %d vectors for %d texts", s.provider.Model(), len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// indexKey identifies an entry in the index: its ID, or for template and legacy
+// entries, which have none, their origin and text
+func indexKey(entry Entry) string {
+	if entry.ID != "" {
+		return entry.ID
+	}
+	return string(entry.Section) + "/" + entry.Origin() + "/" + embeddingHash(entry)
+}
+
+// embeddingText is the text an entry is embedded from: its tag, content and rationale
+func embeddingText(entry Entry) string {
+	text := "[" + entry.TagName + "] " + entry.Content
+	if entry.Rationale != "" {
+		text += " Rationale: " + entry.Rationale
+	}
+	return text
+}
+
+// embeddingHash identifies the text an entry was embedded from
+func embeddingHash(entry Entry) string {
+	sum := sha256.Sum256([]byte(embeddingText(entry)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cosine returns the cosine similarity of two vectors, or 0 when their dimensions
+// differ or either is zero
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DuplicateThreshold is the similarity score at or above which two entries are
+// considered possible duplicates
+const DuplicateThreshold = 0.8
+
+// NormalizeContent lowercases content and collapses punctuation and whitespace
+// so that cosmetic differences don't affect comparison
+func NormalizeContent(content string) string {
+	fields := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Similarity returns the Jaccard similarity (0..1) of the normalized word sets of a and b
+func Similarity(a, b string) float64 {
+	return jaccard(wordSet(NormalizeContent(a)), wordSet(NormalizeContent(b)))
+}
+
+// jaccard returns the size of the intersection of two word sets over their union;
+// two empty sets are identical
+func jaccard(setA, setB map[string]struct{}) float64 {
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for word := range setA {
+		if _, ok := setB[word]; ok {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+
+	return float64(intersection) / float64(union)
+}
+
+func wordSet(normalized string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range strings.Fields(normalized) {
+		set[word] = struct{}{}
+	}
+	return set
+}
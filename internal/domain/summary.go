@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SummaryOptions controls the digest produced by Summarize
+type SummaryOptions struct {
+	MaxChars  int       // Character cap; zero or less means no cap
+	MaxTokens int       // Token cap, estimated by the TokenEstimator; zero or less means no cap
+	Now       time.Time // Reference time; expired entries are left out
+}
+
+// tagGroup collects the deduplicated contents of one tag within a section
+type tagGroup struct {
+	tag      string
+	contents []string
+}
+
+// Summarize produces a condensed digest of the memory: entries grouped by section
+// and tag, near-duplicates dropped, most recent first, capped to MaxChars and MaxTokens
+func (s *MemoryService) Summarize(ctx context.Context, opts SummaryOptions) (string, error) {
+	sections, err := s.ReadSections(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	const (
+		omittedReserve       = 64 // room for the omission notice
+		omittedTokensReserve = 16
+	)
+	budget, tokenBudget := opts.MaxChars, opts.MaxTokens
+	if budget > 0 {
+		budget -= omittedReserve
+	}
+	if tokenBudget > 0 {
+		tokenBudget = max(tokenBudget-omittedTokensReserve, 1)
+	}
+
+	var (
+		sb       strings.Builder
+		size     int
+		tokens   int
+		omitted  int
+		included int
+	)
+	for _, section := range sections {
+		entries := section.WithoutExpired(opts.Now).Entries
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		})
+
+		header := fmt.Sprintf("## %s\n", section.Type.Title())
+		var (
+			groups []*tagGroup
+			kept   []string
+		)
+		byTag := make(map[string]*tagGroup)
+		headerCounted := false
+
+		for _, entry := range entries {
+			if isNearDuplicate(entry.Content, kept) {
+				continue
+			}
+
+			text := entry.Content
+			group, ok := byTag[entry.TagName]
+			if ok {
+				text += "; "
+			} else {
+				text += fmt.Sprintf("- [%s] \n", entry.TagName)
+			}
+			if !headerCounted {
+				text += header
+			}
+			cost, tokenCost := len(text), s.tokens.Count(text)
+			if (budget > 0 && size+cost > budget) || (tokenBudget > 0 && tokens+tokenCost > tokenBudget) {
+				omitted++
+				continue
+			}
+
+			if !ok {
+				group = &tagGroup{tag: entry.TagName}
+				byTag[entry.TagName] = group
+				groups = append(groups, group)
+			}
+			group.contents = append(group.contents, entry.Content)
+			kept = append(kept, entry.Content)
+			headerCounted = true
+			size += cost
+			tokens += tokenCost
+			included++
+		}
+
+		if len(groups) == 0 {
+			continue
+		}
+		sb.WriteString(header)
+		for _, group := range groups {
+			sb.WriteString(fmt.Sprintf("- [%s] %s\n", group.tag, strings.Join(group.contents, "; ")))
+		}
+	}
+
+	if included == 0 && omitted == 0 {
+		return "Memory is empty.\n", nil
+	}
+	if omitted > 0 {
+		var budgets []string
+		if opts.MaxChars > 0 {
+			budgets = append(budgets, fmt.Sprintf("%d characters", opts.MaxChars))
+		}
+		if opts.MaxTokens > 0 {
+			budgets = append(budgets, fmt.Sprintf("%d tokens", opts.MaxTokens))
+		}
+		sb.WriteString(fmt.Sprintf("… %d more entries omitted to fit %s\n", omitted, strings.Join(budgets, " and ")))
+	}
+
+	return sb.String(), nil
+}
+
+func isNearDuplicate(content string, kept []string) bool {
+	for _, other := range kept {
+		if Similarity(content, other) >= DuplicateThreshold {
+			return true
+		}
+	}
+	return false
+}
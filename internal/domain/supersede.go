@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContradictionThreshold is the similarity of two rules, negations aside, at or
+// above which rules of opposite polarity are considered contradictory. It is kept
+// high because contradictions reject captures: rules differing in their subject, such
+// as "Use PostgreSQL" and "Never use MySQL", usually agree.
+const ContradictionThreshold = 0.8
+
+// negations are the words that turn a rule around once content is normalized;
+// "t" is what remains of contractions like "don't"
+var negations = map[string]bool{
+	"no": true, "not": true, "never": true, "cannot": true, "t": true, "without": true,
+	"avoid": true, "forbid": true, "forbidden": true, "prohibit": true, "prohibited": true,
+	"disallow": true, "disallowed": true,
+}
+
+// auxiliaries are the verbs carrying a negation, dropped with it: "do not use" and
+// "use" have the same subject
+var auxiliaries = map[string]bool{
+	"do": true, "does": true, "did": true, "should": true, "must": true, "can": true,
+}
+
+// Contradicts reports whether a and b state the same rule with opposite polarity,
+// e.g. "Use an ORM for database access" and "Never use an ORM for database access"
+func Contradicts(a, b string) bool {
+	subjectA, negatedA := polarity(a)
+	subjectB, negatedB := polarity(b)
+	if negatedA == negatedB || subjectA == "" || subjectB == "" {
+		return false
+	}
+	return Similarity(subjectA, subjectB) >= ContradictionThreshold
+}
+
+// polarity strips the negations from content, returning the remaining words and
+// whether any negation was found
+func polarity(content string) (string, bool) {
+	words := strings.Fields(NormalizeContent(content))
+	subject := make([]string, 0, len(words))
+	negated := false
+	for i, word := range words {
+		switch {
+		case negations[word]:
+			negated = true
+		case auxiliaries[word] && i+1 < len(words) && negations[words[i+1]]:
+			// Auxiliary of a negation such as "do not"
+		case i+1 < len(words) && words[i+1] == "t":
+			// Contraction stem such as "don" or "shouldn"
+		default:
+			subject = append(subject, word)
+		}
+	}
+	return strings.Join(subject, " "), negated
+}
+
+// ResolveSupersedes returns the active entry input.Supersedes refers to, or nil when
+// the input supersedes nothing
+func (s *MemoryService) ResolveSupersedes(ctx context.Context, input AppendInput) (*Entry, error) {
+	if strings.TrimSpace(input.Supersedes) == "" {
+		return nil, nil
+	}
+	entry, err := s.FindEntry(ctx, input.Supersedes)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsActive() {
+		return nil, fmt.Errorf("%w: %s is already %s", ErrInactiveEntry, entry.ID, entry.Status)
+	}
+	return entry, nil
+}
+
+// Supersede marks an entry as superseded by the entry with ID successor
+func (s *MemoryService) Supersede(ctx context.Context, entry Entry, successor string) (*Entry, error) {
+	entry.Status = StatusSuperseded
+	entry.SupersededBy = successor
+	if err := s.repo.UpdateEntry(ctx, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindContradiction returns the active constraint a new decision contradicts, or nil
+// when the input is not a decision, contradicts nothing, or supersedes the constraint
+func (s *MemoryService) FindContradiction(ctx context.Context, input AppendInput, now time.Time) (*Entry, error) {
+	if SectionType(input.Category) != SectionDecisions {
+		return nil, nil
+	}
+	superseded, err := s.ResolveSupersedes(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := s.repo.GetSection(ctx, SectionConstraints)
+	if err != nil {
+		return nil, err
+	}
+	for i := range section.Entries {
+		constraint := section.Entries[i]
+		if constraint.ID == "" || !constraint.IsActive() || constraint.IsExpired(now) {
+			continue
+		}
+		if superseded != nil && superseded.ID == constraint.ID {
+			continue
+		}
+		if Contradicts(input.Content, constraint.Content) {
+			return &constraint, nil
+		}
+	}
+	return nil, nil
+}
+
+// CollapseSuperseded renders a superseded entry as a one-line pointer to its successor
+func CollapseSuperseded(entry Entry) string {
+	return fmt.Sprintf("<!-- superseded: %s %s by %s -->", entry.ID, entry.Tag, entry.SupersededBy)
+}
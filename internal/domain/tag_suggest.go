@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// AutoTag is the tag value asking the service to pick a tag from the content
+const AutoTag = "auto"
+
+// DefaultSuggestedTag is used when no keyword or existing tag matches
+const DefaultSuggestedTag = "General"
+
+// tagKeywords maps well-known tags to content keywords that suggest them
+var tagKeywords = map[string][]string{
+	"Architecture": {"architecture", "layer", "layers", "hexagonal", "module", "modules", "package", "packages", "dependency", "dependencies", "interface", "interfaces", "domain", "service", "services"},
+	"Testing":      {"test", "tests", "testing", "mock", "mocks", "coverage", "e2e", "fixture", "fixtures", "assert", "benchmark"},
+	"API":          {"api", "endpoint", "endpoints", "rest", "http", "grpc", "graphql", "request", "response", "route", "routes", "handler", "handlers"},
+	"Database":     {"database", "db", "sql", "postgres", "postgresql", "mysql", "sqlite", "mongodb", "redis", "query", "queries", "migration", "migrations", "schema", "transaction"},
+	"Security":     {"security", "auth", "authentication", "authorization", "token", "tokens", "secret", "secrets", "password", "encrypt", "encryption", "jwt", "permission", "permissions"},
+	"Performance":  {"performance", "latency", "cache", "caching", "memory", "cpu", "allocation", "allocations", "throughput", "optimize", "slow", "fast"},
+	"Logging":      {"log", "logs", "logging", "slog", "logger", "trace", "tracing", "metrics", "observability"},
+	"Config":       {"config", "configuration", "env", "environment", "flag", "flags", "setting", "settings", "yaml"},
+	"Build":        {"build", "ci", "makefile", "release", "deploy", "deployment", "docker", "pipeline", "compile"},
+	"Style":        {"naming", "style", "format", "formatting", "lint", "linter", "convention", "conventions", "comment", "comments"},
+	"Errors":       {"error", "errors", "panic", "wrap", "wrapping", "retry", "retries", "fallback"},
+}
+
+// SuggestTag picks a tag for content: an existing tag mentioned in the content is
+// preferred, then the best keyword match (reusing existing spelling), then DefaultSuggestedTag
+func (s *MemoryService) SuggestTag(ctx context.Context, content string) (string, error) {
+	existing, err := s.ListTags(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	words := strings.Fields(NormalizeContent(content))
+	wordSet := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		wordSet[word] = struct{}{}
+	}
+
+	// 1. Existing tag named in the content (ListTags is sorted by usage)
+	for _, tag := range existing {
+		if _, ok := wordSet[NormalizeContent(tag.Name)]; ok {
+			return tag.Name, nil
+		}
+	}
+
+	// 2. Keyword scoring
+	scores := make(map[string]int)
+	for tag, keywords := range tagKeywords {
+		for _, keyword := range keywords {
+			if _, ok := wordSet[keyword]; ok {
+				scores[tag]++
+			}
+		}
+	}
+	if len(scores) == 0 {
+		return DefaultSuggestedTag, nil
+	}
+
+	candidates := make([]string, 0, len(scores))
+	for tag := range scores {
+		candidates = append(candidates, tag)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if scores[candidates[i]] != scores[candidates[j]] {
+			return scores[candidates[i]] > scores[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	best := candidates[0]
+
+	// Reuse the spelling already present in the taxonomy
+	for _, tag := range existing {
+		if strings.EqualFold(tag.Name, best) {
+			return tag.Name, nil
+		}
+	}
+
+	return best, nil
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagDefinition is a canonical tag of the taxonomy and the aliases normalized to it
+type TagDefinition struct {
+	Name    string   `json:"name" yaml:"name"`                           // Canonical spelling: "Architecture"
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"` // Other spellings: "arch"
+}
+
+// DefaultTagTaxonomy returns the built-in canonical tags, matching the tags SuggestTag picks
+func DefaultTagTaxonomy() []TagDefinition {
+	return []TagDefinition{
+		{Name: "Architecture", Aliases: []string{"arch"}},
+		{Name: "API", Aliases: []string{"apis", "rest"}},
+		{Name: "Database", Aliases: []string{"db", "dbs", "sql"}},
+		{Name: "Testing", Aliases: []string{"test", "tests"}},
+		{Name: "Security", Aliases: []string{"sec", "auth"}},
+		{Name: "Performance", Aliases: []string{"perf"}},
+		{Name: "Logging", Aliases: []string{"log", "logs"}},
+		{Name: "Config", Aliases: []string{"cfg", "conf", "configuration"}},
+		{Name: "Build", Aliases: []string{"ci", "cicd"}},
+		{Name: "Style", Aliases: []string{"lint", "linting"}},
+		{Name: "Errors", Aliases: []string{"error", "err"}},
+		{Name: DefaultSuggestedTag, Aliases: []string{"misc"}},
+	}
+}
+
+// TagService normalizes tags against a taxonomy of canonical tags and aliases
+type TagService struct {
+	definitions []TagDefinition
+	canonical   map[string]int // tagKey of a name or alias -> index in definitions
+}
+
+// NewTagService creates a tag service over the default taxonomy extended by custom
+// definitions: a custom tag matching a default one replaces its spelling and adds its
+// aliases, and a custom alias moves over from the tag it belonged to. Aliases never
+// shadow a canonical tag.
+func NewTagService(custom []TagDefinition) *TagService {
+	t := &TagService{canonical: make(map[string]int)}
+	for _, definition := range DefaultTagTaxonomy() {
+		t.add(definition)
+	}
+	for _, definition := range custom {
+		t.add(definition)
+	}
+	return t
+}
+
+// add merges a definition into the taxonomy
+func (t *TagService) add(definition TagDefinition) {
+	name := trimTag(definition.Name)
+	key := tagKey(name)
+	if key == "" {
+		return
+	}
+
+	i, ok := t.canonical[key]
+	switch {
+	case ok && tagKey(t.definitions[i].Name) == key:
+		t.definitions[i].Name = name
+	case ok:
+		// A former alias becomes a canonical tag of its own
+		t.definitions[i].Aliases = removeAlias(t.definitions[i].Aliases, key)
+		fallthrough
+	default:
+		i = len(t.definitions)
+		t.definitions = append(t.definitions, TagDefinition{Name: name})
+	}
+	t.canonical[key] = i
+
+	for _, alias := range definition.Aliases {
+		alias = trimTag(alias)
+		aliasKey := tagKey(alias)
+		if aliasKey == "" {
+			continue
+		}
+		if previous, ok := t.canonical[aliasKey]; ok {
+			if previous == i || tagKey(t.definitions[previous].Name) == aliasKey {
+				continue
+			}
+			t.definitions[previous].Aliases = removeAlias(t.definitions[previous].Aliases, aliasKey)
+		}
+		t.canonical[aliasKey] = i
+		t.definitions[i].Aliases = append(t.definitions[i].Aliases, alias)
+	}
+}
+
+// Normalize returns the canonical spelling of a tag, with or without brackets, or the
+// trimmed tag itself when the taxonomy does not know it. Matching ignores case,
+// spaces, hyphens and underscores, so "DB", "db" and "data-base" all match.
+func (t *TagService) Normalize(tag string) string {
+	if canonical, ok := t.Canonical(tag); ok {
+		return canonical
+	}
+	return trimTag(tag)
+}
+
+// Canonical returns the canonical tag a name or alias refers to
+func (t *TagService) Canonical(tag string) (string, bool) {
+	i, ok := t.canonical[tagKey(trimTag(tag))]
+	if !ok {
+		return "", false
+	}
+	return t.definitions[i].Name, true
+}
+
+// Taxonomy returns the canonical tags with their aliases, sorted by name
+func (t *TagService) Taxonomy() []TagDefinition {
+	taxonomy := make([]TagDefinition, 0, len(t.definitions))
+	for _, definition := range t.definitions {
+		taxonomy = append(taxonomy, TagDefinition{Name: definition.Name, Aliases: append([]string(nil), definition.Aliases...)})
+	}
+	sort.Slice(taxonomy, func(i, j int) bool {
+		return strings.ToLower(taxonomy[i].Name) < strings.ToLower(taxonomy[j].Name)
+	})
+	return taxonomy
+}
+
+// trimTag strips spaces and brackets from a tag
+func trimTag(tag string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(tag), "[]"))
+}
+
+// tagKey folds a tag for comparison: lowercase, without spaces, hyphens and underscores
+func tagKey(tag string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(tag))
+}
+
+// removeAlias returns aliases without the ones with the given key
+func removeAlias(aliases []string, key string) []string {
+	kept := aliases[:0]
+	for _, alias := range aliases {
+		if tagKey(alias) != key {
+			kept = append(kept, alias)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// TagRenameResult describes a tag rename
+type TagRenameResult struct {
+	Renamed []Entry // Entries rewritten with the new tag
+	Legacy  []Entry // Matching legacy entries without an anchor, left unchanged
+}
+
+// RenameTag retags every entry whose tag matches oldName (case-insensitive, with or
+// without brackets) as newName, in a single write. Legacy entries cannot be rewritten
+// in place and are reported instead.
+func (s *MemoryService) RenameTag(ctx context.Context, oldName, newName string) (*TagRenameResult, error) {
+	oldName = strings.TrimSpace(strings.Trim(strings.TrimSpace(oldName), "[]"))
+	newName = strings.TrimSpace(strings.Trim(strings.TrimSpace(newName), "[]"))
+	if oldName == "" {
+		return nil, fmt.Errorf("%w: tag to rename cannot be empty", ErrInvalidTag)
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("%w: new tag cannot be empty", ErrInvalidTag)
+	}
+	if n := utf8.RuneCountInString(newName); n > MaxTagLength {
+		return nil, fmt.Errorf("%w: tag must be %d characters or less (got %d)", ErrInvalidTag, MaxTagLength, n)
+	}
+	if strings.ContainsAny(newName, "[],") {
+		return nil, fmt.Errorf("%w: tag cannot contain brackets or commas", ErrInvalidTag)
+	}
+
+	entries, err := s.ListEntries(ctx, EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TagRenameResult{}
+	for _, entry := range entries {
+		if !strings.EqualFold(entry.TagName, oldName) || entry.TagName == newName {
+			continue
+		}
+		if entry.ID == "" {
+			result.Legacy = append(result.Legacy, entry)
+			continue
+		}
+		entry.Tag = "[" + newName + "]"
+		entry.TagName = newName
+		result.Renamed = append(result.Renamed, entry)
+	}
+
+	if err := s.repo.UpdateEntries(ctx, result.Renamed); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // Template represents a loaded template from repository
@@ -14,11 +15,84 @@ type Template struct {
 
 // MemoryTemplateFile represents a single memory template file
 type MemoryTemplateFile struct {
-	Path     string   // Relative path in template repo
-	Content  string   // File content
-	Tags     []string // e.g., ["go", "logging"]
-	Source   string   // e.g., "languages/go"
-	Category string   // e.g., "constraints", "patterns"
+	Path     string       // Relative path in template repo
+	Content  string       // File content
+	Tags     []string     // e.g., ["go", "logging"]
+	Source   string       // e.g., "languages/go"
+	Category string       // e.g., "constraints", "patterns"
+	Meta     TemplateMeta // meta.yaml of the template directory
+}
+
+// TemplateKinds maps the top-level directories of a template repository to template
+// kinds, in the order templates are applied
+var TemplateKinds = []struct {
+	Dir  string
+	Kind string
+}{
+	{"bases", "base"},
+	{"languages", "language"},
+	{"frameworks", "framework"},
+	{"contexts", "context"},
+}
+
+// TemplateMeta is the meta.yaml of a template directory
+type TemplateMeta struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"` // base, language, framework or context; defaults to the directory's kind
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	Parent      string `yaml:"parent"` // ID of the language template a framework extends
+
+	// Category is the section of memory.md content outside any section header,
+	// e.g. "patterns"; defaults to "constraints"
+	Category string `yaml:"category"`
+	// Categories maps custom section headers of memory.md to categories,
+	// e.g. {"Rules": "constraints"}
+	Categories map[string]string     `yaml:"categories"`
+	Tags       []string              `yaml:"tags"`
+	AppliesTo  TemplateApplicability `yaml:"applies_to"`
+	// PostInit lists the hooks init offers to run after writing the memory
+	PostInit []TemplateHook `yaml:"post_init"`
+}
+
+// TemplateHook is a post-init step of a template: creating a project file, or
+// running a command in the project directory
+type TemplateHook struct {
+	Name   string   `yaml:"name"`
+	Create string   `yaml:"create"` // Project-relative file to create when missing
+	From   string   `yaml:"from"`   // File of the template directory holding its content
+	Run    []string `yaml:"run"`    // Command and arguments, run without a shell
+
+	Template string `yaml:"-"` // ID of the template declaring the hook
+	Content  string `yaml:"-"` // Content of From, read when the template is loaded
+}
+
+// Describe returns the name of the hook, else what it does
+func (h TemplateHook) Describe() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	if h.Create != "" {
+		return "create " + h.Create
+	}
+	return "run " + strings.Join(h.Run, " ")
+}
+
+// TemplateApplicability lists the projects a template applies to; each non-empty
+// list must contain the detected value, and features must share one with the project
+type TemplateApplicability struct {
+	Languages    []string `yaml:"languages"`
+	Frameworks   []string `yaml:"frameworks"`
+	Databases    []string `yaml:"databases"`
+	ProjectTypes []string `yaml:"project_types"`
+	Features     []string `yaml:"features"`
+}
+
+// IsEmpty reports whether no condition is set
+func (a TemplateApplicability) IsEmpty() bool {
+	return len(a.Languages) == 0 && len(a.Frameworks) == 0 && len(a.Databases) == 0 && len(a.ProjectTypes) == 0 &&
+		len(a.Features) == 0
 }
 
 // TemplateRepository defines the port for fetching templates from remote repositories
@@ -28,11 +102,12 @@ type TemplateRepository interface {
 	// The caller is responsible for cleaning up the temporary directory
 	Fetch(ctx context.Context, repoURL string) (string, error)
 
-	// FetchWithFallback tries multiple repository URLs in order
+	// FetchWithFallback tries multiple repository URLs, preferring the fastest mirror
 	// Returns the local path from the first successful clone
 	FetchWithFallback(ctx context.Context, repoURLs []string) (string, error)
 
-	// IsGitAvailable checks if git command is available in the system
+	// IsGitAvailable checks if git command is available in the system; fetching
+	// falls back to it when the built-in git client fails
 	IsGitAvailable() bool
 
 	// Cleanup removes the temporary directory created by Fetch
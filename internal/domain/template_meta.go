@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseTemplateMeta parses the meta.yaml of the template directory dir of the given
+// kind, defaulting the ID and name to the directory name, the type to kind and the
+// category to constraints. Empty data gives the defaults.
+func ParseTemplateMeta(data []byte, dir, kind string) (TemplateMeta, error) {
+	var m TemplateMeta
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("parse meta.yaml of %s: %w", dir, err)
+	}
+
+	if m.ID == "" {
+		m.ID = filepath.Base(dir)
+	}
+	if m.Name == "" {
+		m.Name = m.ID
+	}
+	if m.Type == "" {
+		m.Type = kind
+	}
+	if m.Category == "" {
+		m.Category = string(SectionConstraints)
+	}
+	if !SectionType(m.Category).IsValid() {
+		return m, fmt.Errorf("meta.yaml of %s: unknown category %q", dir, m.Category)
+	}
+	for header, category := range m.Categories {
+		if !SectionType(category).IsValid() {
+			return m, fmt.Errorf("meta.yaml of %s: unknown category %q for %q", dir, category, header)
+		}
+	}
+	for i := range m.PostInit {
+		hook := &m.PostInit[i]
+		hook.Template = m.ID
+		if err := hook.validate(); err != nil {
+			return m, fmt.Errorf("meta.yaml of %s: post_init %d: %w", dir, i+1, err)
+		}
+	}
+	return m, nil
+}
+
+// validate checks that a hook either creates a file inside the project or runs a command
+func (h TemplateHook) validate() error {
+	switch {
+	case h.Create != "" && len(h.Run) > 0:
+		return fmt.Errorf("set create or run, not both")
+	case len(h.Run) > 0:
+		if h.Run[0] == "" {
+			return fmt.Errorf("empty command")
+		}
+		return nil
+	case h.Create == "":
+		return fmt.Errorf("set create or run")
+	}
+	clean := filepath.Clean(filepath.FromSlash(h.Create))
+	if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("create %q is outside the project", h.Create)
+	}
+	if h.From == "" {
+		return fmt.Errorf("create %q needs from", h.Create)
+	}
+	if from := filepath.Clean(filepath.FromSlash(h.From)); filepath.IsAbs(from) || from == ".." || strings.HasPrefix(from, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("from %q is outside the template", h.From)
+	}
+	return nil
+}
+
+// Applies reports whether the template applies to the project, to any of its stacks
+// in a multi-language repository. Explicit applies_to conditions decide when set;
+// otherwise bases always apply, a language template applies to its language, a
+// framework template to its framework (named "echo" or "go-echo") under its parent
+// language, and a context template named database-<db> to its database or named
+// after a detected feature (docker, kubernetes, terraform, ...) to projects with it.
+// Other contexts are opt-in.
+func (m TemplateMeta) Applies(info *ProjectInfo) bool {
+	if info == nil {
+		info = &ProjectInfo{}
+	}
+	if !m.AppliesTo.IsEmpty() && !sharesAny(m.AppliesTo.Features, info.Features) {
+		return false
+	}
+	if m.AppliesTo.IsEmpty() && m.Type == "context" && sharesAny([]string{m.ID}, info.Features) {
+		return true
+	}
+	for _, stack := range info.Stacks() {
+		if m.appliesToStack(stack, info.ProjectType) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesToStack reports whether the template applies to one stack of the project
+func (m TemplateMeta) appliesToStack(stack ProjectStack, projectType string) bool {
+	if !m.AppliesTo.IsEmpty() {
+		return matchesAny(m.AppliesTo.Languages, stack.Language) &&
+			matchesAny(m.AppliesTo.Frameworks, stack.Framework) &&
+			matchesAny(m.AppliesTo.Databases, stack.Database) &&
+			matchesAny(m.AppliesTo.ProjectTypes, projectType)
+	}
+
+	switch m.Type {
+	case "base":
+		return true
+	case "language":
+		return stack.Language != "" && strings.EqualFold(m.ID, stack.Language)
+	case "framework":
+		if stack.Framework == "" {
+			return false
+		}
+		if m.Parent != "" && !strings.EqualFold(m.Parent, stack.Language) {
+			return false
+		}
+		return strings.EqualFold(m.ID, stack.Framework) || strings.EqualFold(m.ID, stack.Language+"-"+stack.Framework)
+	case "context":
+		return stack.Database != "" && strings.EqualFold(m.ID, "database-"+stack.Database)
+	default:
+		return false
+	}
+}
+
+// SectionOf returns the section of a memory.md header title in this template: a
+// standard section, a custom header mapped by categories, else the default category
+func (m TemplateMeta) SectionOf(title string) SectionType {
+	for _, section := range ValidSections() {
+		if strings.EqualFold(title, section.Title()) {
+			return section
+		}
+	}
+	for header, category := range m.Categories {
+		if strings.EqualFold(title, header) {
+			return SectionType(category)
+		}
+	}
+	return SectionType(m.Category)
+}
+
+// SplitSections splits the memory.md content of a template by section header;
+// content before the first header goes to the default category
+func (m TemplateMeta) SplitSections(content string) map[SectionType]string {
+	parts := map[SectionType][]string{}
+	section := SectionType(m.Category)
+	var current []string
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(current, "\n")); text != "" {
+			parts[section] = append(parts[section], text)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if title, ok := strings.CutPrefix(line, "## "); ok {
+			flush()
+			section = m.SectionOf(strings.TrimSpace(title))
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	sections := map[SectionType]string{}
+	for section, texts := range parts {
+		sections[section] = strings.Join(texts, "\n\n")
+	}
+	return sections
+}
+
+// ReadTemplateContent returns the memory.md content of the template directory dir,
+// followed by its per-category files (constraints.md, decisions.md, patterns.md,
+// anti-patterns.md, note.md) under their section headers. found is false when the
+// directory has none of them.
+func ReadTemplateContent(dir string) (content string, found bool, err error) {
+	var parts []string
+	data, err := os.ReadFile(filepath.Join(dir, "memory.md"))
+	switch {
+	case err == nil:
+		parts = append(parts, strings.TrimSpace(string(data)))
+		found = true
+	case !os.IsNotExist(err):
+		return "", false, err
+	}
+
+	for _, section := range ValidSections() {
+		data, err := os.ReadFile(filepath.Join(dir, string(section)+".md"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", false, err
+		}
+		parts = append(parts, "## "+section.Title()+"\n\n"+strings.TrimSpace(string(data)))
+		found = true
+	}
+	if !found {
+		return "", false, nil
+	}
+	return strings.Join(parts, "\n\n") + "\n", true, nil
+}
+
+// sharesAny reports whether values is empty or shares a value with detected, ignoring case
+func sharesAny(values, detected []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range detected {
+		if matchesAny(values, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether values is empty or contains value, ignoring case
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
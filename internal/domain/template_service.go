@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -12,6 +13,7 @@ import (
 type TemplateService struct {
 	repo   TemplateRepository
 	loader TemplateLoader
+	layers []string // Repositories composed on top of the template repository, in increasing priority
 }
 
 // NewTemplateService creates a new TemplateService
@@ -22,50 +24,235 @@ func NewTemplateService(repo TemplateRepository, loader TemplateLoader) *Templat
 	}
 }
 
+// WithLayers composes the templates of layers on top of those of the template
+// repository when generating, in increasing priority: a template replaces the one of
+// the same kind and ID of a lower layer, others are added
+func (s *TemplateService) WithLayers(layers []string) *TemplateService {
+	s.layers = layers
+	return s
+}
+
 // InitTemplate generates a complete memory.md content based on project info
 func (s *TemplateService) InitTemplate(ctx context.Context, info *ProjectInfo, repoURLs []string) (string, string, error) {
-	// Fetch templates from repository
-	var (
-		tempPath string
-		err      error
-	)
-	switch len(repoURLs) {
-	case 0:
-		return "", "", fmt.Errorf("no template repository URL provided")
-	case 1:
-		// When the user specifies a single custom repo, do a direct fetch and surface the raw git error.
-		tempPath, err = s.repo.Fetch(ctx, repoURLs[0])
-		if err != nil {
-			return "", "", err
-		}
-	default:
-		tempPath, err = s.repo.FetchWithFallback(ctx, repoURLs)
-		if err != nil {
-			return "", "", fmt.Errorf("fetch templates: %w", err)
-		}
+	return s.InitSelectedTemplates(ctx, info, repoURLs, nil)
+}
+
+// InitSelectedTemplates generates memory.md content from the bases and the templates
+// whose IDs are selected; nil selects the templates applying to the project
+func (s *TemplateService) InitSelectedTemplates(ctx context.Context, info *ProjectInfo, repoURLs, selected []string) (string, string, error) {
+	generated, err := s.Init(ctx, info, repoURLs, selected)
+	if err != nil {
+		return "", "", err
+	}
+	return generated.Memory, generated.Agents, nil
+}
+
+// GeneratedTemplate is what init writes from a template repository
+type GeneratedTemplate struct {
+	Memory string         // memory.md content
+	Agents string         // Agent instructions of the AGENTS.md block
+	Hooks  []TemplateHook // Post-init hooks of the composed templates, in template order
+}
+
+// Init fetches the template repository and generates memory.md and agents content
+// and the post-init hooks from the bases and the selected templates, composing the
+// layers on top
+func (s *TemplateService) Init(ctx context.Context, info *ProjectInfo, repoURLs, selected []string) (*GeneratedTemplate, error) {
+	tempPath, err := s.fetch(ctx, repoURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer s.repo.Cleanup(tempPath)
+
+	return s.GenerateLayered(ctx, tempPath, info, selected)
+}
+
+// GenerateLayered is Generate over the repository checked out at path with the
+// layers fetched and composed on top
+func (s *TemplateService) GenerateLayered(ctx context.Context, path string, info *ProjectInfo, selected []string) (*GeneratedTemplate, error) {
+	paths, err := s.fetchLayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cleanupLayers(paths)
+
+	return s.generate(append([]string{path}, paths...), info, selected)
+}
+
+// TemplateChoice is an optional template of a repository offered at init
+type TemplateChoice struct {
+	ID          string
+	Name        string
+	Kind        string
+	Description string
+	Selected    bool // Applies to the detected project
+}
+
+// TemplateChoices returns the templates init may compose besides the bases: those
+// applying to the project, pre-selected, then the other contexts, which are opt-in
+func (s *TemplateService) TemplateChoices(ctx context.Context, info *ProjectInfo, repoURLs []string) ([]TemplateChoice, error) {
+	tempPath, err := s.fetch(ctx, repoURLs)
+	if err != nil {
+		return nil, err
 	}
 	defer s.repo.Cleanup(tempPath)
+	paths, err := s.fetchLayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cleanupLayers(paths)
+
+	template, err := s.loadLayers(append([]string{tempPath}, paths...))
+	if err != nil {
+		return nil, err
+	}
+
+	var choices []TemplateChoice
+	for _, file := range template.MemoryFiles {
+		meta := file.Meta
+		applies := meta.Applies(info)
+		if meta.Type == "base" || (!applies && meta.Type != "context") {
+			continue
+		}
+		choices = append(choices, TemplateChoice{
+			ID:          meta.ID,
+			Name:        meta.Name,
+			Kind:        meta.Type,
+			Description: meta.Description,
+			Selected:    applies,
+		})
+	}
+	return choices, nil
+}
+
+// GenerateFromPath generates memory.md and agents content from the template
+// repository checked out at path, composing the bases and the selected templates
+// (nil selects the templates applying to the project)
+func (s *TemplateService) GenerateFromPath(path string, info *ProjectInfo, selected []string) (string, string, error) {
+	generated, err := s.Generate(path, info, selected)
+	if err != nil {
+		return "", "", err
+	}
+	return generated.Memory, generated.Agents, nil
+}
+
+// Generate is GenerateFromPath, also returning the post-init hooks of the composed templates
+func (s *TemplateService) Generate(path string, info *ProjectInfo, selected []string) (*GeneratedTemplate, error) {
+	return s.generate([]string{path}, info, selected)
+}
 
+// generate composes the templates of the repositories checked out at paths, in
+// increasing priority
+func (s *TemplateService) generate(paths []string, info *ProjectInfo, selected []string) (*GeneratedTemplate, error) {
 	// Load template
-	template, err := s.loader.LoadTemplate(tempPath)
+	template, err := s.loadLayers(paths)
 	if err != nil {
-		return "", "", fmt.Errorf("load template: %w", err)
+		return nil, err
 	}
 
-	// Load agents content
-	agentsContent, err := s.loader.LoadAgents(tempPath)
+	// Load agents content: from the highest layer having an agents.md
+	agentsPath := paths[0]
+	for _, path := range paths[1:] {
+		if _, err := os.Stat(filepath.Join(path, "agents.md")); err == nil {
+			agentsPath = path
+		}
+	}
+	agentsContent, err := s.loader.LoadAgents(agentsPath)
 	if err != nil {
-		return "", "", fmt.Errorf("load agents: %w", err)
+		return nil, fmt.Errorf("load agents: %w", err)
+	}
+
+	generated := &GeneratedTemplate{
+		// Generate memory content with project-specific sections
+		Memory: s.generateMemoryContent(template, info, selected),
+		Agents: agentsContent,
+	}
+	for _, file := range template.MemoryFiles {
+		if isSelected(file.Meta, info, selected) {
+			generated.Hooks = append(generated.Hooks, file.Meta.PostInit...)
+		}
+	}
+	return generated, nil
+}
+
+// loadLayers loads the templates of the repositories checked out at paths, a template
+// of a later path replacing the one of the same kind and ID
+func (s *TemplateService) loadLayers(paths []string) (*Template, error) {
+	var template *Template
+	for _, path := range paths {
+		layer, err := s.loader.LoadTemplate(path)
+		if err != nil {
+			return nil, fmt.Errorf("load template: %w", err)
+		}
+		if template == nil {
+			template = layer
+			continue
+		}
+		for _, file := range layer.MemoryFiles {
+			index := slices.IndexFunc(template.MemoryFiles, func(f MemoryTemplateFile) bool {
+				return f.Meta.Type == file.Meta.Type && f.Meta.ID == file.Meta.ID
+			})
+			if index == -1 {
+				template.MemoryFiles = append(template.MemoryFiles, file)
+			} else {
+				template.MemoryFiles[index] = file
+			}
+		}
 	}
+	return template, nil
+}
 
-	// Generate memory content with project-specific sections
-	memoryContent := s.generateMemoryContent(template, info)
+// fetchLayers checks out the layers, in order
+func (s *TemplateService) fetchLayers(ctx context.Context) ([]string, error) {
+	var paths []string
+	for _, layer := range s.layers {
+		path, err := s.repo.Fetch(ctx, layer)
+		if err != nil {
+			s.cleanupLayers(paths)
+			return nil, fmt.Errorf("fetch template layer %s: %w", layer, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// cleanupLayers removes the checkouts of the layers
+func (s *TemplateService) cleanupLayers(paths []string) {
+	for _, path := range paths {
+		s.repo.Cleanup(path)
+	}
+}
+
+// WithRepository fetches the template repository (the first reachable of repoURLs)
+// and calls fn with its local path, removing the checkout afterwards
+func (s *TemplateService) WithRepository(ctx context.Context, repoURLs []string, fn func(path string) error) error {
+	tempPath, err := s.fetch(ctx, repoURLs)
+	if err != nil {
+		return err
+	}
+	defer s.repo.Cleanup(tempPath)
+	return fn(tempPath)
+}
 
-	return memoryContent, agentsContent, nil
+// fetch checks out the template repository to a temporary directory
+func (s *TemplateService) fetch(ctx context.Context, repoURLs []string) (string, error) {
+	switch len(repoURLs) {
+	case 0:
+		return "", fmt.Errorf("no template repository URL provided")
+	case 1:
+		// When the user specifies a single custom repo, do a direct fetch and surface the raw git error.
+		return s.repo.Fetch(ctx, repoURLs[0])
+	default:
+		tempPath, err := s.repo.FetchWithFallback(ctx, repoURLs)
+		if err != nil {
+			return "", fmt.Errorf("fetch templates: %w", err)
+		}
+		return tempPath, nil
+	}
 }
 
 // generateMemoryContent generates the final memory.md content
-func (s *TemplateService) generateMemoryContent(template *Template, info *ProjectInfo) string {
+func (s *TemplateService) generateMemoryContent(template *Template, info *ProjectInfo, selected []string) string {
 	var sb strings.Builder
 
 	// Frontmatter
@@ -83,54 +270,56 @@ func (s *TemplateService) generateMemoryContent(template *Template, info *Projec
 		if info.Database != "" {
 			sb.WriteString(fmt.Sprintf("  database: \"%s\"\n", info.Database))
 		}
+		if len(info.Secondary) > 0 {
+			sb.WriteString("  secondary:\n")
+			for _, stack := range info.Secondary {
+				sb.WriteString(fmt.Sprintf("    - language: \"%s\"\n", stack.Language))
+				if stack.Framework != "" {
+					sb.WriteString(fmt.Sprintf("      framework: \"%s\"\n", stack.Framework))
+				}
+				if stack.Database != "" {
+					sb.WriteString(fmt.Sprintf("      database: \"%s\"\n", stack.Database))
+				}
+				sb.WriteString(fmt.Sprintf("      path: \"%s\"\n", stack.Path))
+			}
+		}
 	}
 
 	sb.WriteString("---\n\n")
 
-	// Merge all template content
-	sections := map[string][]string{
-		"Constraints":   {},
-		"Decisions":     {},
-		"Patterns":      {},
-		"Anti-Patterns": {},
-	}
-
-	// Collect content from all template files
+	// Merge the content of the templates applying to the project, by section
+	sections := map[SectionType][]string{}
 	for _, file := range template.MemoryFiles {
-		section := s.mapCategoryToSection(file.Category)
-		if section != "" {
-			sections[section] = append(sections[section], file.Content)
+		if !isSelected(file.Meta, info, selected) {
+			continue
+		}
+		for section, content := range file.Meta.SplitSections(file.Content) {
+			sections[section] = append(sections[section], content)
 		}
 	}
 
-	// Write sections in order
-	for _, sectionName := range []string{"Constraints", "Decisions", "Patterns", "Anti-Patterns"} {
-		sb.WriteString(fmt.Sprintf("## %s\n\n", sectionName))
-		for _, content := range sections[sectionName] {
+	// Write sections in order; Note only when a template fills it
+	for _, section := range ValidSections() {
+		if section == SectionNote && len(sections[section]) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", section.Title()))
+		for _, content := range sections[section] {
 			sb.WriteString(content)
-			sb.WriteString("\n")
+			sb.WriteString("\n\n")
 		}
 	}
 
-	return sb.String()
+	return strings.TrimRight(sb.String(), "\n") + "\n"
 }
 
-// mapCategoryToSection maps template categories to memory sections
-func (s *TemplateService) mapCategoryToSection(category string) string {
-	// For now, all content goes into appropriate sections based on content type
-	// This can be extended based on meta.yaml parsing
-	switch category {
-	case "constraints":
-		return "Constraints"
-	case "decisions":
-		return "Decisions"
-	case "patterns":
-		return "Patterns"
-	case "anti-patterns":
-		return "Anti-Patterns"
-	default:
-		return "Constraints" // Default section
+// isSelected reports whether a template is composed: bases always, others when
+// selected, or when they apply to the project if nothing was selected
+func isSelected(meta TemplateMeta, info *ProjectInfo, selected []string) bool {
+	if selected == nil || meta.Type == "base" {
+		return meta.Applies(info)
 	}
+	return slices.Contains(selected, meta.ID)
 }
 
 // LocalTemplateLoader implements TemplateLoader for local filesystem
@@ -141,22 +330,41 @@ func NewLocalTemplateLoader() *LocalTemplateLoader {
 	return &LocalTemplateLoader{}
 }
 
-// LoadTemplate loads template files from a local repository path
+// LoadTemplate loads the templates of a local repository path, in the order of
+// TemplateKinds then directory name
 func (l *LocalTemplateLoader) LoadTemplate(basePath string) (*Template, error) {
 	template := &Template{
 		BasePath:    basePath,
 		MemoryFiles: []MemoryTemplateFile{},
 	}
-
-	// Load base/common template
-	baseDir := filepath.Join(basePath, "bases", "common")
-	if err := l.loadMemoryFilesFromDir(baseDir, "common", &template.MemoryFiles); err != nil {
-		// Base template is optional
+	if err := l.loadTemplates(basePath, &template.MemoryFiles); err != nil {
+		return nil, err
 	}
-
 	return template, nil
 }
 
+// loadTemplates appends the templates of the repository at basePath to files
+func (l *LocalTemplateLoader) loadTemplates(basePath string, files *[]MemoryTemplateFile) error {
+	for _, kind := range TemplateKinds {
+		dirs, err := os.ReadDir(filepath.Join(basePath, kind.Dir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("read %s: %w", kind.Dir, err)
+		}
+		for _, dir := range dirs {
+			if !dir.IsDir() {
+				continue
+			}
+			if err := l.loadMemoryFilesFromDir(filepath.Join(basePath, kind.Dir, dir.Name()), kind.Kind, files); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // LoadAgents loads the agents.md content from repository
 func (l *LocalTemplateLoader) LoadAgents(basePath string) (string, error) {
 	agentsPath := filepath.Join(basePath, "agents.md")
@@ -171,36 +379,65 @@ func (l *LocalTemplateLoader) LoadAgents(basePath string) (string, error) {
 	return string(content), nil
 }
 
-// loadMemoryFilesFromDir loads all memory.md files from a directory
-func (l *LocalTemplateLoader) loadMemoryFilesFromDir(dir, source string, files *[]MemoryTemplateFile) error {
-	memoryPath := filepath.Join(dir, "memory.md")
-	content, err := os.ReadFile(memoryPath)
+// loadMemoryFilesFromDir loads the memory.md, per-category files and meta.yaml of a
+// template directory
+func (l *LocalTemplateLoader) loadMemoryFilesFromDir(dir, kind string, files *[]MemoryTemplateFile) error {
+	content, found, err := ReadTemplateContent(dir)
+	if err != nil {
+		return err
+	}
+
+	metaData, err := os.ReadFile(filepath.Join(dir, "meta.yaml"))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read meta.yaml of %s: %w", dir, err)
+	}
+	meta, err := ParseTemplateMeta(metaData, dir, kind)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
 		return err
 	}
+	if !found && len(meta.PostInit) == 0 {
+		return nil
+	}
+	for i, hook := range meta.PostInit {
+		if hook.From == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(hook.From)))
+		if err != nil {
+			return fmt.Errorf("read post_init file of %s: %w", dir, err)
+		}
+		meta.PostInit[i].Content = string(data)
+	}
 
 	*files = append(*files, MemoryTemplateFile{
-		Path:     memoryPath,
-		Content:  string(content),
-		Source:   source,
-		Category: "constraints", // Default category
+		Path:     filepath.Join(dir, "memory.md"),
+		Content:  content,
+		Tags:     meta.Tags,
+		Source:   meta.ID,
+		Category: meta.Category,
+		Meta:     meta,
 	})
 
 	return nil
 }
 
-// getDefaultAgentsContent returns default agents content
-func (l *LocalTemplateLoader) getDefaultAgentsContent() string {
-	return `### Boot Protocol
+// BootProtocol instructs agents how to load memory at the start of a conversation
+const BootProtocol = `### Boot Protocol
 
 At the **START** of every conversation:
 1. Call ` + "`ohmymem_read`" + ` tool to load project constraints
 2. Review all Constraints before writing any code
 3. Apply Patterns to maintain consistency
+`
+
+// getDefaultAgentsContent returns default agents content
+func (l *LocalTemplateLoader) getDefaultAgentsContent() string {
+	return DefaultAgentsContent()
+}
 
+// DefaultAgentsContent returns the agent instructions used when a template repository has no agents.md
+func DefaultAgentsContent() string {
+	return BootProtocol + `
 ### Memory Protocol
 
 When you identify important information:
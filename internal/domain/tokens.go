@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordCharsPerToken is how many letters of a long word fit in one token: common
+// words are a single token, rarer ones split into pieces of several letters
+const wordCharsPerToken = 6
+
+// punctCharsPerToken is how many punctuation characters of a run fit in one token;
+// Markdown markers such as "**" or "-->" are usually merged
+const punctCharsPerToken = 3
+
+// pretokenRegex splits text the way tiktoken's cl100k pre-tokenizer does: contractions,
+// words with their leading space or symbol, numbers of up to three digits, punctuation
+// runs and whitespace
+var pretokenRegex = regexp.MustCompile(`'(?i:[sdmt]|ll|ve|re)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+// TokenEstimator approximates how many tokens a text costs an LLM, without the
+// vocabulary of a real tokenizer: text is split like tiktoken splits it, then each
+// piece is priced by its length and script
+type TokenEstimator struct{}
+
+// NewTokenEstimator creates a token estimator
+func NewTokenEstimator() *TokenEstimator {
+	return &TokenEstimator{}
+}
+
+// Count returns the estimated number of tokens of text
+func (e *TokenEstimator) Count(text string) int {
+	tokens := 0
+	for _, piece := range pretokenRegex.FindAllString(text, -1) {
+		tokens += pieceTokens(piece)
+	}
+	return tokens
+}
+
+// pieceTokens prices one pre-token: CJK characters are about a token each, words one
+// token per wordCharsPerToken letters, punctuation runs one per punctCharsPerToken
+// characters, and numbers and whitespace one token
+func pieceTokens(piece string) int {
+	var letters, ideographs, punct int
+	for _, r := range piece {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			ideographs++
+		case unicode.IsLetter(r):
+			letters++
+		case !unicode.IsSpace(r) && !unicode.IsNumber(r):
+			punct++
+		}
+	}
+	switch {
+	case ideographs > 0:
+		return ideographs + ceilDiv(letters, wordCharsPerToken)
+	case letters > 0:
+		return ceilDiv(letters, wordCharsPerToken)
+	case punct > 0:
+		return ceilDiv(punct, punctCharsPerToken)
+	case utf8.RuneCountInString(piece) > 0:
+		return 1
+	}
+	return 0
+}
+
+// ceilDiv divides n by d, rounding up
+func ceilDiv(n, d int) int {
+	return (n + d - 1) / d
+}
+
+// SectionTokens is the estimated token cost of one section of the memory file
+type SectionTokens struct {
+	Section SectionType
+	Tokens  int
+}
+
+// TokenUsage is the estimated token cost of the memory, whole and per section
+type TokenUsage struct {
+	Total    int // The whole memory file, frontmatter included
+	Sections []SectionTokens
+	Budget   int // Configured budget; zero when none is set
+}
+
+// OverBudget reports whether the memory costs more tokens than the configured budget
+func (u TokenUsage) OverBudget() bool {
+	return u.Budget > 0 && u.Total > u.Budget
+}
+
+// Tokens returns the estimator the token budgets and usage are measured with
+func (s *MemoryService) Tokens() *TokenEstimator {
+	return s.tokens
+}
+
+// TokenBudget returns the number of tokens the memory should fit in, or zero when
+// no budget is set
+func (s *MemoryService) TokenBudget() int {
+	return s.tokenBudget
+}
+
+// SetTokenBudget sets the number of tokens the memory should fit in, e.g. one loaded
+// from configuration; zero or less removes the budget
+func (s *MemoryService) SetTokenBudget(tokens int) {
+	s.tokenBudget = max(tokens, 0)
+}
+
+// TokenUsage estimates how many tokens reading the memory costs, in total and per
+// section in ValidSections order. Each section is measured on its part of the file,
+// header included, so the sections add up to the total less the frontmatter.
+func (s *MemoryService) TokenUsage(ctx context.Context) (TokenUsage, error) {
+	usage := TokenUsage{Budget: s.tokenBudget}
+	content, err := s.repo.ReadAll(ctx)
+	if err != nil {
+		return usage, err
+	}
+	usage.Total = s.tokens.Count(content)
+
+	slices := sectionSlices(content)
+	for _, section := range ValidSections() {
+		usage.Sections = append(usage.Sections, SectionTokens{Section: section, Tokens: s.tokens.Count(slices[section])})
+	}
+	return usage, nil
+}
+
+// sectionSlices cuts memory file content at its "## " headers and returns the text of
+// each section, from its header to the next one. Text before the first header and
+// sections with other titles are left out.
+func sectionSlices(content string) map[SectionType]string {
+	titles := make(map[string]SectionType, len(ValidSections()))
+	for _, section := range ValidSections() {
+		titles[section.Title()] = section
+	}
+
+	slices := map[SectionType]string{}
+	var (
+		current SectionType
+		start   = -1
+		offset  int
+	)
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if title, ok := strings.CutPrefix(line, "## "); ok {
+			if start != -1 && current != "" {
+				slices[current] += content[start:offset]
+			}
+			current, start = titles[strings.TrimSpace(title)], offset
+		}
+		offset += len(line)
+	}
+	if start != -1 && current != "" {
+		slices[current] += content[start:]
+	}
+	return slices
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationPolicy holds the limits and forbidden patterns applied to captured
+// entries, so organizations can tighten or relax them through configuration
+type ValidationPolicy struct {
+	MaxTagLength       int // Characters; zero keeps MaxTagLength
+	MaxContentLength   int // Characters; zero keeps MaxContentLength
+	MaxRationaleLength int // Characters, also limiting decision record fields; zero keeps MaxRationaleLength
+	// AllowedCharacters is the body of a regexp character class every character of a
+	// captured field must match, e.g. `\p{Latin}\p{N}\p{P}\p{S} `; empty allows any
+	AllowedCharacters string
+	// BannedWords are rejected as whole words in any captured field, ignoring case
+	BannedWords []string
+}
+
+// DefaultValidationPolicy returns the built-in limits with no character or word restrictions
+func DefaultValidationPolicy() ValidationPolicy {
+	return ValidationPolicy{
+		MaxTagLength:       MaxTagLength,
+		MaxContentLength:   MaxContentLength,
+		MaxRationaleLength: MaxRationaleLength,
+	}
+}
+
+// ValidationRule checks one aspect of an entry about to be captured
+type ValidationRule func(input AppendInput) error
+
+// ValidationRules is the rules engine ValidateInput runs, compiled from a policy
+type ValidationRules struct {
+	policy  ValidationPolicy
+	allowed *regexp.Regexp // Matches one allowed character; nil allows any
+	banned  *regexp.Regexp // Matches a banned word; nil bans none
+	rules   []ValidationRule
+}
+
+// NewValidationRules compiles a policy into rules, filling unset limits with the defaults
+func NewValidationRules(policy ValidationPolicy) (*ValidationRules, error) {
+	defaults := DefaultValidationPolicy()
+	limits := []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"max_tag_length", &policy.MaxTagLength, defaults.MaxTagLength},
+		{"max_content_length", &policy.MaxContentLength, defaults.MaxContentLength},
+		{"max_rationale_length", &policy.MaxRationaleLength, defaults.MaxRationaleLength},
+	}
+	for _, limit := range limits {
+		if *limit.value < 0 {
+			return nil, fmt.Errorf("%w: %s must be positive (got %d)", ErrInvalidPolicy, limit.name, *limit.value)
+		}
+		if *limit.value == 0 {
+			*limit.value = limit.def
+		}
+	}
+
+	r := &ValidationRules{policy: policy}
+	if policy.AllowedCharacters != "" {
+		allowed, err := regexp.Compile(`^[` + policy.AllowedCharacters + `]$`)
+		if err != nil {
+			return nil, fmt.Errorf("%w: allowed_characters is not a character class: %v", ErrInvalidPolicy, err)
+		}
+		r.allowed = allowed
+	}
+	var words []string
+	for _, word := range policy.BannedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, regexp.QuoteMeta(word))
+		}
+	}
+	if len(words) > 0 {
+		// Whole words: not preceded or followed by a letter, digit or underscore
+		r.banned = regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}_])(` + strings.Join(words, "|") + `)(?:[^\p{L}\p{N}_]|$)`)
+	}
+
+	r.rules = []ValidationRule{
+		func(input AppendInput) error { return r.ValidateTag(input.Tag) },
+		func(input AppendInput) error { return r.ValidateEntryContent(input.Content) },
+		func(input AppendInput) error { return r.ValidateRationale(input.Rationale) },
+		func(input AppendInput) error { return r.validateDecisionRecord(SectionType(input.Category), input.ADR) },
+	}
+	return r, nil
+}
+
+// defaultValidationRules are the rules of a service whose policy was never set
+func defaultValidationRules() *ValidationRules {
+	rules, err := NewValidationRules(DefaultValidationPolicy())
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Policy returns the policy the rules were compiled from, with unset limits filled in
+func (r *ValidationRules) Policy() ValidationPolicy {
+	return r.policy
+}
+
+// Validate runs every rule in order, returning the first violation
+func (r *ValidationRules) Validate(input AppendInput) error {
+	for _, rule := range r.rules {
+		if err := rule(input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTag checks a tag is present, within the tag limit and allowed by the policy
+func (r *ValidationRules) ValidateTag(tag string) error {
+	if len(tag) == 0 {
+		return fmt.Errorf("%w: tag cannot be empty", ErrInvalidTag)
+	}
+	if n := utf8.RuneCountInString(tag); n > r.policy.MaxTagLength {
+		return fmt.Errorf("%w: tag must be %d characters or less (got %d)", ErrInvalidTag, r.policy.MaxTagLength, n)
+	}
+	return r.checkText("tag", tag)
+}
+
+// ValidateEntryContent checks content is present, within the content limit and free
+// of forbidden patterns
+func (r *ValidationRules) ValidateEntryContent(content string) error {
+	if len(content) == 0 {
+		return fmt.Errorf("%w: content cannot be empty", ErrInvalidContent)
+	}
+	if n := utf8.RuneCountInString(content); n > r.policy.MaxContentLength {
+		return fmt.Errorf("%w: content must be %d characters or less (got %d)", ErrInvalidContent, r.policy.MaxContentLength, n)
+	}
+	if err := ValidateContent(content); err != nil {
+		return err
+	}
+	return r.checkText("content", content)
+}
+
+// ValidateRationale checks an optional rationale is within the rationale limit and
+// allowed by the policy
+func (r *ValidationRules) ValidateRationale(rationale string) error {
+	if n := utf8.RuneCountInString(rationale); n > r.policy.MaxRationaleLength {
+		return fmt.Errorf("%w: rationale must be %d characters or less (got %d)", ErrInvalidRationale, r.policy.MaxRationaleLength, n)
+	}
+	return r.checkText("rationale", rationale)
+}
+
+// validateDecisionRecord applies the decision record rules with the rationale limit
+// of the policy, then the character and word restrictions to each field
+func (r *ValidationRules) validateDecisionRecord(section SectionType, record DecisionRecord) error {
+	if err := validateDecisionRecord(section, record, r.policy.MaxRationaleLength); err != nil {
+		return err
+	}
+	fields := append([]string{record.Context, record.Consequences}, record.Options...)
+	for _, field := range fields {
+		if err := r.checkText("decision record", field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkText rejects characters outside AllowedCharacters and banned words
+func (r *ValidationRules) checkText(field, text string) error {
+	if r.allowed != nil {
+		for _, char := range text {
+			if !r.allowed.MatchString(string(char)) {
+				return fmt.Errorf("%w: %s contains %q, which is not an allowed character", ErrForbiddenContent, field, char)
+			}
+		}
+	}
+	if r.banned != nil {
+		if match := r.banned.FindStringSubmatch(text); match != nil {
+			return fmt.Errorf("%w: %s contains the banned word %q", ErrForbiddenContent, field, match[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// FileName is the audit log file name inside the .ohmymem directory
+const FileName = "audit.log"
+
+// JSONLAuditLogger implements domain.AuditLogger by appending one JSON object per line
+type JSONLAuditLogger struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONLAuditLogger creates an audit logger writing to <dir>/audit.log
+func NewJSONLAuditLogger(dir string) *JSONLAuditLogger {
+	return &JSONLAuditLogger{
+		path: filepath.Join(dir, FileName),
+	}
+}
+
+// Path returns the audit log file path
+func (l *JSONLAuditLogger) Path() string {
+	return l.path
+}
+
+// Record appends the record to the audit log.
+// The file is opened in append mode per write so concurrent servers interleave whole lines.
+func (l *JSONLAuditLogger) Record(record domain.AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("create audit log dir: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure JSONLAuditLogger implements AuditLogger
+var _ domain.AuditLogger = (*JSONLAuditLogger)(nil)
@@ -1,21 +1,67 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
 )
 
 const (
 	// Config file name
 	ConfigFileName = "config.yaml"
 	ConfigDirName  = ".ohmymem"
+
+	// DefaultSyncBranch is the git branch 'ohmymem sync' uses when none is configured
+	DefaultSyncBranch = "ohmymem-memory"
+
+	// DefaultTemplateCacheTTL is how long fetched template repositories are reused
+	DefaultTemplateCacheTTL = "24h"
+
+	// DefaultTemplateVerify refuses templates failing their integrity manifest
+	DefaultTemplateVerify = "strict"
+)
+
+// Environment variables overriding config file values
+const (
+	EnvAuthToken        = "OHMYMEM_AUTH_TOKEN"
+	EnvSyncToken        = "OHMYMEM_SYNC_TOKEN"
+	EnvTemplateRepo     = "OHMYMEM_TEMPLATE_REPO"
+	EnvEmbeddingsAPIKey = "OHMYMEM_EMBEDDINGS_API_KEY"
 )
 
+// EnvKeys maps config keys to the environment variables overriding them
+var EnvKeys = map[string]string{
+	"mcp.auth_token":     EnvAuthToken,
+	"sync.token":         EnvSyncToken,
+	"template.repo":      EnvTemplateRepo,
+	"embeddings.api_key": EnvEmbeddingsAPIKey,
+}
+
+// endpointSecrets maps the config keys of remote endpoints to the credential sent to them
+var endpointSecrets = map[string]string{
+	"embeddings.url": "embeddings.api_key",
+	"sync.remote":    "sync.token",
+}
+
 // Config represents user configuration
 type Config struct {
-	Init InitConfig `yaml:"init"`
+	Init       InitConfig       `yaml:"init"`
+	MCP        MCPConfig        `yaml:"mcp"`
+	Sync       SyncConfig       `yaml:"sync"`
+	Template   TemplateConfig   `yaml:"template"`
+	Validation ValidationConfig `yaml:"validation"`
+	Embeddings EmbeddingsConfig `yaml:"embeddings"`
+	Budget     BudgetConfig     `yaml:"budget"`
+
+	// untrustedSyncRemote is a git sync.remote set only by the project config
+	untrustedSyncRemote string
 }
 
 // InitConfig holds init command defaults
@@ -23,51 +69,264 @@ type InitConfig struct {
 	Yes bool `yaml:"yes"`
 }
 
-// Load loads configuration from config file
+// MCPConfig holds MCP server settings
+type MCPConfig struct {
+	// AuthToken is the bearer token required by non-stdio transports; empty disables auth
+	AuthToken string `yaml:"auth_token"`
+}
+
+// SyncConfig holds the remote shared by 'ohmymem sync'
+type SyncConfig struct {
+	// Remote is a git remote name or URL, or an http(s) URL of a memory file endpoint
+	Remote string `yaml:"remote"`
+	// Branch is the git branch holding the shared memory
+	Branch string `yaml:"branch"`
+	// Token is the bearer token sent to an HTTP remote
+	Token string `yaml:"token"`
+}
+
+// TemplateConfig holds template repository settings
+type TemplateConfig struct {
+	// Repo replaces the default template repositories: URLs or local paths separated
+	// by commas, tried in order
+	Repo string `yaml:"repo"`
+	// Layers are template repositories composed on top of Repo or the defaults, e.g. an
+	// organization pack: URLs or local paths separated by commas, in increasing priority
+	Layers string `yaml:"layers"`
+	// Ref is the branch, tag or commit of the template repositories to check out;
+	// empty is their default branch
+	Ref string `yaml:"ref"`
+	// Verify is how templates failing their SHA256SUMS manifest are handled: strict
+	// (refuse), warn or off
+	Verify string `yaml:"verify"`
+	// PublicKey is a base64 ed25519 key the SHA256SUMS.sig signature of template
+	// repositories must verify against; empty skips signature checks
+	PublicKey string `yaml:"public_key"`
+	// CacheTTL is how long a fetched template repository is reused, e.g. "24h"; "0" disables the cache
+	CacheTTL string `yaml:"cache_ttl"`
+}
+
+// ValidationConfig holds the policy captured entries are validated against
+type ValidationConfig struct {
+	// MaxTagLength, MaxContentLength and MaxRationaleLength are field limits in characters
+	MaxTagLength       int `yaml:"max_tag_length"`
+	MaxContentLength   int `yaml:"max_content_length"`
+	MaxRationaleLength int `yaml:"max_rationale_length"`
+	// AllowedCharacters is the body of a regexp character class every character must
+	// match, e.g. "\p{Latin}\p{N}\p{P}\p{S} "; empty allows any character
+	AllowedCharacters string `yaml:"allowed_characters"`
+	// BannedWords are words rejected in captured entries, separated by commas
+	BannedWords string `yaml:"banned_words"`
+}
+
+// EmbeddingsConfig holds the optional provider computing entry vectors for semantic search
+type EmbeddingsConfig struct {
+	// Provider is "ollama" for a local model or "openai" for an OpenAI-compatible API;
+	// empty disables semantic search
+	Provider string `yaml:"provider"`
+	// Model is the embedding model; empty uses the provider default
+	Model string `yaml:"model"`
+	// URL is the base URL of the provider; empty uses the provider default
+	URL string `yaml:"url"`
+	// APIKey is the bearer token sent to an API provider
+	APIKey string `yaml:"api_key"`
+}
+
+// BudgetConfig holds the context budget the memory should fit in
+type BudgetConfig struct {
+	// MaxTokens is the estimated number of tokens reading the memory may cost before
+	// 'ohmymem status' and ohmymem_read warn; zero disables the warning
+	MaxTokens int `yaml:"max_tokens"`
+}
+
+// Policy returns the validation policy of the configuration
+func (v ValidationConfig) Policy() domain.ValidationPolicy {
+	return domain.ValidationPolicy{
+		MaxTagLength:       v.MaxTagLength,
+		MaxContentLength:   v.MaxContentLength,
+		MaxRationaleLength: v.MaxRationaleLength,
+		AllowedCharacters:  v.AllowedCharacters,
+		BannedWords:        splitList(v.BannedWords),
+	}
+}
+
+// RepoURLs returns the template repositories of Repo, or nil when none is set
+func (t TemplateConfig) RepoURLs() []string {
+	return splitList(t.Repo)
+}
+
+// LayerURLs returns the template repositories of Layers, or nil when none is set
+func (t TemplateConfig) LayerURLs() []string {
+	return splitList(t.Layers)
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// TemplateCacheTTL parses template.cache_ttl; zero means the cache is disabled
+func (c *Config) TemplateCacheTTL() (time.Duration, error) {
+	value := c.Template.CacheTTL
+	if value == "" {
+		value = DefaultTemplateCacheTTL
+	}
+	if value == "0" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid template.cache_ttl %q: expected a duration such as 24h", value)
+	}
+	return ttl, nil
+}
+
+// Load loads configuration from the global config file, then the config file of the
+// project in the working directory, then environment variables
 func Load() (*Config, error) {
+	return LoadProject(".")
+}
+
+// LoadProject loads configuration like Load, reading the config file of the project
+// at projectDir
+func LoadProject(projectDir string) (*Config, error) {
 	cfg := &Config{
 		Init: InitConfig{
 			Yes: false,
 		},
+		Sync: SyncConfig{
+			Branch: DefaultSyncBranch,
+		},
+		Template: TemplateConfig{
+			CacheTTL: DefaultTemplateCacheTTL,
+			Verify:   DefaultTemplateVerify,
+		},
+		Validation: ValidationConfig{
+			MaxTagLength:       domain.MaxTagLength,
+			MaxContentLength:   domain.MaxContentLength,
+			MaxRationaleLength: domain.MaxRationaleLength,
+		},
 	}
 
-	// Load from config file (if exists)
-	if err := cfg.loadFromFile(); err != nil {
-		// Ignore file not found, but log other errors
-		if !os.IsNotExist(err) {
-			return cfg, err
-		}
+	// Load from config files (if they exist)
+	if err := cfg.loadFromFiles(GetConfigPath(), ProjectConfigPath(projectDir)); err != nil {
+		return cfg, err
 	}
 
+	// Environment variables take precedence over the files
+	cfg.loadFromEnv()
+
+	global, err := ReadValues(GetConfigPath())
+	if err != nil {
+		return cfg, err
+	}
+	project, err := ReadValues(ProjectConfigPath(projectDir))
+	if err != nil {
+		return cfg, err
+	}
+	if err := cfg.scopeSecrets(global, project); err != nil {
+		return cfg, err
+	}
+	cfg.scopeSyncRemote(global, project)
+
 	return cfg, nil
 }
 
-// GetConfigPath returns the config file path
+// GetConfigPath returns the global config file path
 func GetConfigPath() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ConfigDirName, ConfigFileName)
 }
 
-// loadFromFile loads config from YAML file
-func (c *Config) loadFromFile() error {
-	path := GetConfigPath()
+// ProjectConfigPath returns the config file path of the project at projectDir
+func ProjectConfigPath(projectDir string) string {
+	return filepath.Join(projectDir, ConfigDirName, ConfigFileName)
+}
 
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return err
+// loadFromFiles merges the YAML files in order, later files overriding only the keys
+// they set. Missing files are skipped.
+func (c *Config) loadFromFiles(paths ...string) error {
+	merged := map[string]any{}
+	for _, path := range paths {
+		values, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		mergeMaps(merged, values)
 	}
 
-	var fileConfig Config
-	if err := yaml.Unmarshal(data, &fileConfig); err != nil {
+	data, err := yaml.Marshal(merged)
+	if err != nil {
 		return err
 	}
+	return yaml.Unmarshal(data, c)
+}
 
-	// Merge file config
-	c.Init.Yes = fileConfig.Init.Yes
+// loadFromEnv applies environment variable overrides
+func (c *Config) loadFromEnv() {
+	if token := os.Getenv(EnvAuthToken); token != "" {
+		c.MCP.AuthToken = token
+	}
+	if token := os.Getenv(EnvSyncToken); token != "" {
+		c.Sync.Token = token
+	}
+	if repo := os.Getenv(EnvTemplateRepo); repo != "" {
+		c.Template.Repo = repo
+	}
+	if key := os.Getenv(EnvEmbeddingsAPIKey); key != "" {
+		c.Embeddings.APIKey = key
+	}
+}
 
+// scopeSecrets keeps a project config, which comes with the repository, from sending
+// the user's credentials to an endpoint of its choice: when it points an endpoint
+// elsewhere than the global config, only a credential set in the project config itself
+// is sent there, never one from the global config or the environment
+func (c *Config) scopeSecrets(global, project map[string]string) error {
+	for endpoint, secret := range endpointSecrets {
+		if location, ok := project[endpoint]; !ok || location == global[endpoint] {
+			continue
+		}
+		field, err := fieldByKey(reflect.ValueOf(c).Elem(), secret)
+		if err != nil {
+			return err
+		}
+		field.SetString(project[secret])
+	}
 	return nil
 }
 
+// scopeSyncRemote keeps a project config from choosing the git remote sync runs git
+// against, since git remote names and URLs can run commands. A git remote set by the
+// project config alone is replaced by the global one; HTTP remotes are kept, their
+// token being scoped by scopeSecrets.
+func (c *Config) scopeSyncRemote(global, project map[string]string) {
+	location, ok := project["sync.remote"]
+	if !ok || location == global["sync.remote"] || isHTTPLocation(location) {
+		return
+	}
+	c.untrustedSyncRemote = location
+	c.Sync.Remote = global["sync.remote"]
+}
+
+// UntrustedSyncRemote returns the git sync.remote set by the project config alone and
+// ignored for it, or "" when there is none
+func (c *Config) UntrustedSyncRemote() string {
+	return c.untrustedSyncRemote
+}
+
+// isHTTPLocation reports whether a sync remote is an HTTP endpoint rather than git
+func isHTTPLocation(location string) bool {
+	location = strings.TrimSpace(location)
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if len(path) > 0 && path[0] == '~' {
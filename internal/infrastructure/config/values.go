@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys returns the dotted names of every configuration key, e.g. "init.yes"
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of a dotted key as a string
+func (c *Config) Get(key string) (string, error) {
+	field, err := fieldByKey(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(field.Interface()), nil
+}
+
+// ReadValues returns the keys set in one config file with their values; a missing
+// file has no values
+func ReadValues(path string) (map[string]string, error) {
+	values, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	flat := map[string]string{}
+	flattenMap(values, "", flat)
+	return flat, nil
+}
+
+// SetValue validates value against the type of key and writes it to the config file
+// at path, keeping the other keys of the file
+func SetValue(path, key, value string) error {
+	field, err := fieldByKey(reflect.ValueOf(&Config{}).Elem(), key)
+	if err != nil {
+		return err
+	}
+	parsed, err := parseValue(field.Kind(), value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	values, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(key, ".")
+	node := values
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := node[part].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[part] = child
+		}
+		node = child
+	}
+	node[parts[len(parts)-1]] = parsed
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+	return nil
+}
+
+// readFile decodes a YAML config file into a map; a missing or empty file gives an empty map
+func readFile(path string) (map[string]any, error) {
+	values := map[string]any{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	return values, nil
+}
+
+// mergeMaps copies src into dst, merging nested maps key by key
+func mergeMaps(dst, src map[string]any) {
+	for key, value := range src {
+		srcChild, srcIsMap := value.(map[string]any)
+		dstChild, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstChild, srcChild)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// flattenMap turns nested maps into dotted keys
+func flattenMap(values map[string]any, prefix string, out map[string]string) {
+	for key, value := range values {
+		if child, ok := value.(map[string]any); ok {
+			flattenMap(child, prefix+key+".", out)
+			continue
+		}
+		out[prefix+key] = fmt.Sprint(value)
+	}
+}
+
+// collectKeys lists the dotted yaml names of the leaf fields of t
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			collectKeys(field.Type, prefix+name+".", keys)
+			continue
+		}
+		*keys = append(*keys, prefix+name)
+	}
+}
+
+// fieldByKey finds the leaf field named by a dotted key
+func fieldByKey(v reflect.Value, key string) (reflect.Value, error) {
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown config key %q", key)
+		}
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			if yamlName(v.Type().Field(i)) == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+	if v.Kind() == reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("unknown config key %q", key)
+	}
+	return v, nil
+}
+
+// yamlName returns the yaml name of a struct field, or "" when it is not serialized
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// parseValue converts a command-line value to the kind of a config field
+func parseValue(kind reflect.Kind, value string) (any, error) {
+	switch kind {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", value)
+		}
+		return parsed, nil
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		return parsed, nil
+	case reflect.String:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", kind)
+	}
+}
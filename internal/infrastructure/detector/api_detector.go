@@ -0,0 +1,50 @@
+package detector
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// detectAPISpecs 检测API契约定义，返回对应的特性：
+// openapi（OpenAPI/Swagger 文档）、protobuf（.proto 文件）、graphql（GraphQL schema）
+func detectAPISpecs(rootPath string) []domain.DetectionEvidence {
+	found := map[string]string{} // 特性 -> 首个匹配的文件
+	walkProject(rootPath, func(path, rel, name string) {
+		lower := strings.ToLower(name)
+		var feature string
+		switch {
+		case strings.HasSuffix(lower, ".proto"):
+			feature = "protobuf"
+		case strings.HasSuffix(lower, ".graphql") || strings.HasSuffix(lower, ".graphqls") || strings.HasSuffix(lower, ".gql") ||
+			lower == "gqlgen.yml" || lower == "gqlgen.yaml":
+			feature = "graphql"
+		case found["openapi"] == "" && (strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml") || strings.HasSuffix(lower, ".json")):
+			if isOpenAPISpec(path, lower) {
+				feature = "openapi"
+			}
+		}
+		if _, ok := found[feature]; feature != "" && !ok {
+			found[feature] = rel
+		}
+	})
+	return foundFeatures(found, "openapi", "protobuf", "graphql")
+}
+
+// isOpenAPISpec 判断YAML/JSON文件是否为OpenAPI或Swagger文档：
+// 顶层声明 openapi/swagger 版本字段
+func isOpenAPISpec(path, lower string) bool {
+	// 只检查文件名提示为 API 文档的 JSON，避免读取大量无关的 JSON 文件
+	if strings.HasSuffix(lower, ".json") && !strings.Contains(lower, "openapi") && !strings.Contains(lower, "swagger") {
+		return false
+	}
+	for _, line := range readManifest(path) {
+		trimmed := bytes.TrimSpace(line)
+		if bytes.HasPrefix(line, []byte("openapi:")) || bytes.HasPrefix(line, []byte("swagger:")) ||
+			bytes.HasPrefix(trimmed, []byte(`"openapi":`)) || bytes.HasPrefix(trimmed, []byte(`"swagger":`)) {
+			return true
+		}
+	}
+	return false
+}
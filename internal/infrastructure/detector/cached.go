@@ -0,0 +1,68 @@
+package detector
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// CachedDetector 复用 init 保存在 .ohmymem/project.yaml 中的检测结果，
+// 避免后续命令（template update、status）每次重新检测
+type CachedDetector struct {
+	detector domain.ProjectDetector
+	refresh  bool
+	save     bool
+}
+
+// NewCachedDetector 创建复用保存结果的检测器，没有保存结果时使用 detector 检测，
+// 并将结果保存到已初始化项目的 project.yaml
+func NewCachedDetector(detector domain.ProjectDetector) *CachedDetector {
+	return &CachedDetector{detector: detector, save: true}
+}
+
+// WithRefresh 为 true 时忽略保存的结果重新检测
+func (d *CachedDetector) WithRefresh(refresh bool) *CachedDetector {
+	d.refresh = refresh
+	return d
+}
+
+// WithSave 为 false 时不写入 project.yaml（如 --dry-run）
+func (d *CachedDetector) WithSave(save bool) *CachedDetector {
+	d.save = save
+	return d
+}
+
+// Detect 返回保存的检测结果，需要刷新或没有保存结果时重新检测
+func (d *CachedDetector) Detect(rootPath string) (*domain.ProjectInfo, error) {
+	if !d.refresh {
+		info, _, err := persistence.LoadProjectInfo(rootPath)
+		if err == nil {
+			// stack.yaml 可能在保存检测结果后被修改
+			if err := applyStackDeclaration(rootPath, info); err != nil {
+				return nil, err
+			}
+			info.Evidence = nil // 保存的结果不含检测依据
+			return info, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	info, err := d.detector.Detect(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	// 只为已初始化的项目保存结果，不在任意目录下创建 .ohmymem
+	if d.save && info.IsDetected() {
+		if _, err := os.Stat(filepath.Join(rootPath, ".ohmymem")); err == nil {
+			if err := persistence.SaveProjectInfo(rootPath, info); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return info, nil
+}
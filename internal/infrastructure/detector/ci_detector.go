@@ -0,0 +1,41 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// detectCI 检测仓库根目录的CI配置，返回对应的特性：
+// github-actions、gitlab-ci、circleci、jenkins
+func detectCI(rootPath string) []domain.DetectionEvidence {
+	var features []domain.DetectionEvidence
+	if hasWorkflows(filepath.Join(rootPath, ".github", "workflows")) {
+		features = append(features, feature("github-actions", ".github/workflows"))
+	}
+	features = append(features, configFeatures(rootPath, []dependency{
+		{".gitlab-ci.yml", "gitlab-ci"},
+		{".gitlab-ci.yaml", "gitlab-ci"},
+		{".circleci/config.yml", "circleci"},
+		{".circleci/config.yaml", "circleci"},
+		{"Jenkinsfile", "jenkins"},
+	})...)
+	return features
+}
+
+// hasWorkflows 判断目录中是否有 GitHub Actions 工作流文件
+func hasWorkflows(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if !entry.IsDir() && (strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,22 @@
 package detector
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
 	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
 )
 
 // CompositeDetector 组合检测器
 type CompositeDetector struct {
 	detectors []LanguageDetector
+	overrides domain.ProjectOverrides
+	explain   bool
 }
 
 // LanguageDetector 语言检测器接口
@@ -21,26 +31,263 @@ func NewCompositeDetector() *CompositeDetector {
 	return &CompositeDetector{
 		detectors: []LanguageDetector{
 			NewGoDetector(),
-			// 未来添加更多：NewTypeScriptDetector(), NewPythonDetector()
+			NewNodeDetector(),
+			NewPythonDetector(),
 		},
 	}
 }
 
-// Detect 检测项目
+// WithOverrides 使用用户指定的项目信息覆盖检测结果。
+// 指定语言时只保留该语言的检测结果，其他语言的技术栈被忽略。
+func (d *CompositeDetector) WithOverrides(overrides domain.ProjectOverrides) *CompositeDetector {
+	d.overrides = overrides
+	return d
+}
+
+// WithExplain 为 true 时在 ProjectInfo.Evidence 中记录每个结论的依据，用于排查误检
+func (d *CompositeDetector) WithExplain(explain bool) *CompositeDetector {
+	d.explain = explain
+	return d
+}
+
+// Detect 检测项目。.ohmymem/stack.yaml 中声明的技术栈覆盖并补充检测结果，
+// 用户通过 WithOverrides 指定的项目信息优先级最高
 func (d *CompositeDetector) Detect(rootPath string) (*domain.ProjectInfo, error) {
-	for _, detector := range d.detectors {
-		info, err := detector.Detect(rootPath)
-		if err != nil {
+	info := d.detect(rootPath)
+	if err := applyStackDeclaration(rootPath, info); err != nil {
+		return nil, err
+	}
+	d.overrides.Apply(info)
+	explainDeclared(info, d.overrides, nil, "command-line flag")
+	if !d.explain {
+		info.Evidence = nil
+	}
+	return info, nil
+}
+
+// applyStackDeclaration 将 .ohmymem/stack.yaml 中声明的技术栈合并到检测结果，没有声明时不变
+func applyStackDeclaration(rootPath string, info *domain.ProjectInfo) error {
+	declaration, err := persistence.LoadStackDeclaration(rootPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	declaration.Apply(info)
+	// 忽略的特性不再保留其依据
+	info.Evidence = slices.DeleteFunc(info.Evidence, func(e domain.DetectionEvidence) bool {
+		return e.Kind == "feature" && !slices.Contains(info.Features, e.Value)
+	})
+	explainDeclared(info, declaration.ProjectOverrides, declaration.Features, ".ohmymem/"+persistence.StackFileName)
+	return nil
+}
+
+// explainDeclared 记录用户声明（stack.yaml 或命令行参数）的结论
+func explainDeclared(info *domain.ProjectInfo, declared domain.ProjectOverrides, features []string, source string) {
+	for _, field := range []struct{ kind, value string }{
+		{"language", declared.Language},
+		{"framework", declared.Framework},
+		{"database", declared.Database},
+		{"project_type", declared.ProjectType},
+	} {
+		if field.value != "" {
+			explain(info, field.kind, field.value, source)
+		}
+	}
+	for _, feature := range features {
+		explain(info, "feature", feature, source)
+	}
+}
+
+// skippedDirs 扫描子项目时跳过的目录
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"testdata":     true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+}
+
+// detect 按优先级运行所有检测器：根目录第一个检测到的语言为主技术栈，
+// 根目录及一级子目录（monorepo 的子项目）中的其他语言为次要技术栈；
+// 基础设施特性（Docker、Kubernetes、Terraform）、CI提供商、API定义和代码检查工具
+// 合并到主技术栈的 Features
+func (d *CompositeDetector) detect(rootPath string) *domain.ProjectInfo {
+	detectors := append([]LanguageDetector(nil), d.detectors...)
+	sort.SliceStable(detectors, func(i, j int) bool {
+		return detectors[i].Priority() < detectors[j].Priority()
+	})
+
+	var primary *domain.ProjectInfo
+	add := func(info *domain.ProjectInfo, path string) {
+		// 子项目的依据以其目录为前缀
+		if path != "." {
+			for i := range info.Evidence {
+				info.Evidence[i].Source = evidenceSource(path, info.Evidence[i])
+			}
+		}
+		if primary == nil {
+			primary = info
+			primary.RootPath = rootPath
+			return
+		}
+		for _, stack := range primary.Stacks() {
+			if stack.Language == info.Language {
+				return // 同一语言只记录一次
+			}
+		}
+		primary.Secondary = append(primary.Secondary, domain.ProjectStack{
+			Language:    info.Language,
+			Framework:   info.Framework,
+			Database:    info.Database,
+			ProjectType: info.ProjectType,
+			Path:        path,
+		})
+		primary.Features = appendUnique(primary.Features, info.Features...)
+		primary.Evidence = append(primary.Evidence, info.Evidence...)
+	}
+
+	for _, dir := range append([]string{"."}, subprojectDirs(rootPath)...) {
+		for _, detector := range detectors {
+			info, err := detector.Detect(filepath.Join(rootPath, dir))
+			if err != nil || !info.IsDetected() {
+				continue
+			}
+			if d.overrides.Language != "" && info.Language != d.overrides.Language {
+				continue
+			}
+			add(info, filepath.ToSlash(dir))
+		}
+	}
+	if primary != nil && primary.ProjectType != "fullstack" && isFullstack(primary.Stacks()) {
+		primary.ProjectType = "fullstack"
+		explain(primary, "project_type", "fullstack", "backend and frontend stacks")
+	}
+	if primary == nil {
+		// 未检测到
+		primary = &domain.ProjectInfo{
+			Language: "unknown",
+			RootPath: rootPath,
+		}
+	}
+
+	// 基础设施、CI配置、API定义和代码检查工具与语言无关，检测整个仓库
+	addFeatures(primary, detectInfrastructure(rootPath))
+	addFeatures(primary, detectCI(rootPath))
+	addFeatures(primary, detectAPISpecs(rootPath))
+	addFeatures(primary, detectLinters(rootPath))
+	return primary
+}
+
+// isFullstack 判断技术栈是否同时包含前端和后端（如 Go 后端加 web/ 下的 React 前端）
+func isFullstack(stacks []domain.ProjectStack) bool {
+	var frontend, backend bool
+	for _, stack := range stacks {
+		frontend = frontend || stack.ProjectType == "frontend" || stack.ProjectType == "fullstack"
+		backend = backend || stack.ProjectType == "backend" || stack.ProjectType == "fullstack"
+	}
+	return frontend && backend
+}
+
+// subprojectDirs 返回根目录下可能是子项目的一级子目录，跳过隐藏目录和依赖目录
+func subprojectDirs(rootPath string) []string {
+	entries, err := os.ReadDir(rootPath)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || skippedDirs[name] {
+			continue
+		}
+		dirs = append(dirs, name)
+	}
+	return dirs
+}
+
+// rank 合并同名候选项（保留最高置信度），按置信度从高到低排序（同置信度保持检测顺序），
+// 记录到 info.Candidates 并返回置信度最高的值，没有候选项时返回空字符串
+func rank(info *domain.ProjectInfo, candidates []domain.DetectionCandidate) string {
+	var merged []domain.DetectionCandidate
+	index := map[string]int{}
+	for _, c := range candidates {
+		if i, ok := index[c.Value]; ok {
+			if c.Confidence > merged[i].Confidence {
+				merged[i].Confidence, merged[i].Source = c.Confidence, c.Source
+			}
+			continue
+		}
+		index[c.Value] = len(merged)
+		merged = append(merged, c)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Confidence > merged[j].Confidence
+	})
+	info.Candidates = append(info.Candidates, merged...)
+	for _, c := range merged {
+		info.Evidence = append(info.Evidence, domain.DetectionEvidence{Kind: c.Kind, Value: c.Value, Source: c.Source, Confidence: c.Confidence})
+	}
+	if len(merged) == 0 {
+		return ""
+	}
+	return merged[0].Value
+}
+
+// evidenceSource 返回子目录 dir 中检测结论的来源：文件以 dir 为前缀，项目类型的判断依据标注目录
+func evidenceSource(dir string, evidence domain.DetectionEvidence) string {
+	if evidence.Kind == "project_type" {
+		return dir + ": " + evidence.Source
+	}
+	return dir + "/" + evidence.Source
+}
+
+// explain 记录一个检测结论的依据
+func explain(info *domain.ProjectInfo, kind, value, source string) {
+	info.Evidence = append(info.Evidence, domain.DetectionEvidence{Kind: kind, Value: value, Source: source})
+}
+
+// addFeatures 追加检测到的特性及其依据，已有的特性不重复记录
+func addFeatures(info *domain.ProjectInfo, findings []domain.DetectionEvidence) {
+	for _, finding := range findings {
+		if slices.Contains(info.Features, finding.Value) {
 			continue
 		}
-		if info.IsDetected() {
-			return info, nil
+		info.Features = append(info.Features, finding.Value)
+		info.Evidence = append(info.Evidence, finding)
+	}
+}
+
+// feature 构造一个特性结论
+func feature(value, source string) domain.DetectionEvidence {
+	return domain.DetectionEvidence{Kind: "feature", Value: value, Source: source}
+}
+
+// appendFeature 追加尚未记录的特性
+func appendFeature(features []domain.DetectionEvidence, finding domain.DetectionEvidence) []domain.DetectionEvidence {
+	for _, f := range features {
+		if f.Value == finding.Value {
+			return features
 		}
 	}
+	return append(features, finding)
+}
 
-	// 未检测到
-	return &domain.ProjectInfo{
-		Language: "unknown",
-		RootPath: rootPath,
-	}, nil
+// appendUnique 追加不重复的值
+func appendUnique(values []string, more ...string) []string {
+	for _, value := range more {
+		found := false
+		for _, v := range values {
+			if v == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, value)
+		}
+	}
+	return values
 }
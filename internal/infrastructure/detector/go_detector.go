@@ -2,12 +2,20 @@ package detector
 
 import (
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	"golang.org/x/mod/modfile"
+
 	"github.com/herewei/ohmymem-core/internal/domain"
 )
 
+// majorVersionSuffix Go模块路径的主版本后缀，如 /v4
+var majorVersionSuffix = regexp.MustCompile(`/v[0-9]+$`)
+
 // GoDetector Go项目检测器
 type GoDetector struct{}
 
@@ -24,11 +32,13 @@ func (d *GoDetector) Priority() int {
 	return 10
 }
 
-// Detect 检测Go项目
+// Detect 检测Go项目：单模块项目（go.mod）或多模块工作区（go.work）。
+// go.mod 按 golang.org/x/mod/modfile 解析，区分直接依赖与间接依赖，注释和 replace 指令不参与检测。
 func (d *GoDetector) Detect(rootPath string) (*domain.ProjectInfo, error) {
 	goModPath := filepath.Join(rootPath, "go.mod")
+	goWorkPath := filepath.Join(rootPath, "go.work")
 
-	if !fileExists(goModPath) {
+	if !fileExists(goModPath) && !fileExists(goWorkPath) {
 		return &domain.ProjectInfo{Language: ""}, nil
 	}
 
@@ -38,109 +48,203 @@ func (d *GoDetector) Detect(rootPath string) (*domain.ProjectInfo, error) {
 		Features: []string{},
 	}
 
-	// 读取go.mod分析依赖
-	content, err := os.ReadFile(goModPath)
-	if err != nil {
-		return info, nil
+	// 模块目录：根目录的go.mod，以及工作区的成员模块
+	dirs := []string{}
+	if fileExists(goModPath) {
+		dirs = append(dirs, ".")
+		explain(info, "language", "go", "go.mod")
+	}
+	if fileExists(goWorkPath) {
+		info.Modules = d.workspaceModules(goWorkPath)
+		dirs = appendUnique(dirs, info.Modules...)
+		explain(info, "language", "go", "go.work")
 	}
 
-	goModContent := string(content)
-
-	// 检测框架
-	info.Framework = d.detectFramework(goModContent)
+	// 解析所有模块的go.mod分析依赖
+	deps := map[string]float64{}
+	projectTypes := map[string]string{} // 项目类型 -> 判断依据
+	var vendored string
+	parsed := false
+	for _, dir := range dirs {
+		moduleDir := filepath.Join(rootPath, filepath.FromSlash(dir))
+		moduleDeps, err := goModDependencies(filepath.Join(moduleDir, "go.mod"))
+		if err != nil {
+			continue
+		}
+		parsed = true
+		for module, confidence := range moduleDeps {
+			deps[module] = max(deps[module], confidence)
+		}
+		projectType, reason := d.detectProjectType(moduleDir, moduleDeps)
+		if _, ok := projectTypes[projectType]; !ok {
+			if dir != "." {
+				reason = evidenceSource(dir, domain.DetectionEvidence{Kind: "project_type", Source: reason})
+			}
+			projectTypes[projectType] = reason
+		}
+		if vendored == "" && fileExists(filepath.Join(moduleDir, "vendor", "modules.txt")) {
+			vendored = path.Join(dir, "vendor/modules.txt")
+		}
+	}
+	if !parsed {
+		return info, nil
+	}
 
-	// 检测数据库
-	info.Database = d.detectDatabase(goModContent)
+	// 检测框架和数据库，按置信度排序候选项
+	info.Framework = rank(info, d.detectFramework(deps))
+	info.Database = rank(info, d.detectDatabase(deps))
 
-	// 检测项目类型
-	info.ProjectType = d.detectProjectType(rootPath, goModContent)
+	// 检测项目类型：工作区取成员模块中最具体的类型
+	for _, projectType := range []string{"backend", "cli", "library"} {
+		if reason, ok := projectTypes[projectType]; ok {
+			info.ProjectType = projectType
+			explain(info, "project_type", projectType, reason)
+			break
+		}
+	}
 
 	// 检测特性
-	info.Features = d.detectFeatures(goModContent)
+	addFeatures(info, d.detectFeatures(deps))
+	if len(info.Modules) > 0 {
+		addFeatures(info, []domain.DetectionEvidence{feature("go-workspace", "go.work")})
+	}
+	if vendored != "" {
+		addFeatures(info, []domain.DetectionEvidence{feature("vendor", vendored)})
+	}
 
 	return info, nil
 }
 
-// detectFramework 检测Web框架
-func (d *GoDetector) detectFramework(goMod string) string {
-	frameworks := map[string]string{
-		"github.com/labstack/echo": "echo",
-		"github.com/gin-gonic/gin": "gin",
-		"github.com/gofiber/fiber": "fiber",
-		"github.com/gorilla/mux":   "gorilla",
-		"github.com/go-chi/chi":    "chi",
-	}
-
-	for pkg, name := range frameworks {
-		if strings.Contains(goMod, pkg) {
-			return name
-		}
+// workspaceModules 解析go.work的use指令，返回成员模块目录（相对项目根目录）
+func (d *GoDetector) workspaceModules(goWorkPath string) []string {
+	content, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil
+	}
+	work, err := modfile.ParseWork(goWorkPath, content, nil)
+	if err != nil {
+		return nil
+	}
+	var modules []string
+	for _, use := range work.Use {
+		modules = appendUnique(modules, path.Clean(filepath.ToSlash(use.Path)))
 	}
+	return modules
+}
 
-	return ""
+// detectFramework 检测Web框架
+func (d *GoDetector) detectFramework(deps map[string]float64) []domain.DetectionCandidate {
+	return scoreDependencies(deps, "framework", "go.mod", []dependency{
+		{"github.com/labstack/echo", "echo"},
+		{"github.com/gin-gonic/gin", "gin"},
+		{"github.com/gofiber/fiber", "fiber"},
+		{"github.com/gorilla/mux", "gorilla"},
+		{"github.com/go-chi/chi", "chi"},
+	})
 }
 
 // detectDatabase 检测数据库
-func (d *GoDetector) detectDatabase(goMod string) string {
-	databases := map[string]string{
-		"github.com/jackc/pgx":           "postgresql",
-		"github.com/lib/pq":              "postgresql",
-		"github.com/go-sql-driver/mysql": "mysql",
-		"go.mongodb.org/mongo-driver":    "mongodb",
-		"github.com/go-redis/redis":      "redis",
-		"github.com/redis/go-redis":      "redis",
-	}
-
-	for pkg, name := range databases {
-		if strings.Contains(goMod, pkg) {
-			return name
+func (d *GoDetector) detectDatabase(deps map[string]float64) []domain.DetectionCandidate {
+	return scoreDependencies(deps, "database", "go.mod", []dependency{
+		{"github.com/jackc/pgx", "postgresql"},
+		{"github.com/lib/pq", "postgresql"},
+		{"github.com/go-sql-driver/mysql", "mysql"},
+		{"go.mongodb.org/mongo-driver", "mongodb"},
+		{"github.com/go-redis/redis", "redis"},
+		{"github.com/redis/go-redis", "redis"},
+	})
+}
+
+// directDependency go.mod中直接依赖的置信度
+const directDependency = 0.9
+
+// goModDependencies 解析go.mod，返回依赖的模块路径（去掉 /vN 主版本后缀）及其置信度：
+// 直接依赖0.9，间接依赖（// indirect）0.2
+func goModDependencies(goModPath string) (map[string]float64, error) {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.Parse(goModPath, content, nil)
+	if err != nil {
+		return nil, err
+	}
+	deps := map[string]float64{}
+	for _, require := range file.Require {
+		confidence := directDependency
+		if require.Indirect {
+			confidence = 0.2
 		}
+		module := majorVersionSuffix.ReplaceAllString(require.Mod.Path, "")
+		deps[module] = max(deps[module], confidence)
 	}
-
-	return ""
+	return deps, nil
 }
 
-// detectProjectType 检测项目类型
-func (d *GoDetector) detectProjectType(rootPath, goMod string) string {
+// detectProjectType 检测项目类型，同时返回判断依据
+func (d *GoDetector) detectProjectType(rootPath string, deps map[string]float64) (string, string) {
 	// 有cmd目录通常是CLI或服务
 	if dirExists(filepath.Join(rootPath, "cmd")) {
-		// 检查是否有web相关包
-		if strings.Contains(goMod, "echo") ||
-			strings.Contains(goMod, "gin") ||
-			strings.Contains(goMod, "fiber") ||
-			strings.Contains(goMod, "net/http") {
-			return "backend"
+		// 直接依赖Web框架的是服务
+		for _, framework := range d.detectFramework(deps) {
+			if framework.Confidence >= directDependency {
+				return "backend", "cmd/ with framework " + framework.Value
+			}
 		}
-		return "cli"
+		return "cli", "cmd/ without a web framework"
 	}
 
 	// 有internal但没有cmd，可能是库
 	if dirExists(filepath.Join(rootPath, "internal")) {
-		return "backend"
+		return "backend", "internal/ without cmd/"
 	}
 
-	return "library"
+	return "library", "no cmd/ or internal/"
 }
 
-// detectFeatures 检测项目特性
-func (d *GoDetector) detectFeatures(goMod string) []string {
-	var features []string
+// detectFeatures 检测项目特性，只看直接依赖；模块路径等于或位于前缀之下即匹配
+func (d *GoDetector) detectFeatures(deps map[string]float64) []domain.DetectionEvidence {
+	featureModules := []dependency{
+		{"google.golang.org/grpc", "grpc"},
+		{"github.com/grpc-ecosystem", "grpc"},
+		{"github.com/swaggo/swag", "swagger"},
+		{"github.com/golang-jwt/jwt", "jwt"},
+		{"github.com/prometheus/client_golang", "prometheus"},
+		{"go.opentelemetry.io/otel", "opentelemetry"},
+		// 测试框架
+		{"github.com/stretchr/testify", "testify"},
+		{"github.com/onsi/ginkgo", "ginkgo"},
+		{"go.uber.org/mock", "gomock"},
+		{"github.com/golang/mock", "gomock"},
+		// 消息队列
+		{"github.com/segmentio/kafka-go", "kafka"},
+		{"github.com/confluentinc/confluent-kafka-go", "kafka"},
+		{"github.com/IBM/sarama", "kafka"},
+		{"github.com/Shopify/sarama", "kafka"},
+		{"github.com/twmb/franz-go", "kafka"},
+		{"github.com/nats-io/nats.go", "nats"},
+		{"github.com/rabbitmq/amqp091-go", "rabbitmq"},
+		{"github.com/streadway/amqp", "rabbitmq"},
+		{"github.com/aws/aws-sdk-go-v2/service/sqs", "aws-sqs"},
+		{"cloud.google.com/go/pubsub", "gcp-pubsub"},
+		{"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus", "azure-servicebus"},
+	}
 
-	featureMap := map[string]string{
-		"google.golang.org/grpc":       "grpc",
-		"github.com/grpc-ecosystem":    "grpc",
-		"github.com/swaggo/swag":       "swagger",
-		"github.com/golang-jwt/jwt":    "jwt",
-		"github.com/prometheus/client": "prometheus",
-		"go.opentelemetry.io/otel":     "opentelemetry",
+	modules := make([]string, 0, len(deps))
+	for module := range deps {
+		modules = append(modules, module)
 	}
+	slices.Sort(modules)
 
-	for pkg, feature := range featureMap {
-		if strings.Contains(goMod, pkg) {
-			features = append(features, feature)
+	var features []domain.DetectionEvidence
+	for _, c := range featureModules {
+		for _, module := range modules {
+			if deps[module] >= directDependency && (module == c.pkg || strings.HasPrefix(module, c.pkg+"/")) {
+				features = appendFeature(features, feature(c.name, "go.mod: "+module))
+				break
+			}
 		}
 	}
-
 	return features
 }
 
@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// scanDepth 扫描基础设施和 API 定义的最大目录深度（根目录为 0）
+const scanDepth = 3
+
+// maxManifestSize 检查内容的配置文件大小上限
+const maxManifestSize = 1 << 20
+
+// detectInfrastructure 检测容器、编排和基础设施即代码配置，返回对应的特性：
+// docker、docker-compose、kubernetes、helm、terraform
+func detectInfrastructure(rootPath string) []domain.DetectionEvidence {
+	found := map[string]string{} // 特性 -> 首个匹配的文件
+	mark := func(feature, rel string) {
+		if _, ok := found[feature]; !ok {
+			found[feature] = rel
+		}
+	}
+	walkProject(rootPath, func(path, rel, name string) {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == "dockerfile" || strings.HasPrefix(lower, "dockerfile.") || strings.HasSuffix(lower, ".dockerfile"):
+			mark("docker", rel)
+		case lower == "compose.yaml" || lower == "compose.yml" || lower == "docker-compose.yaml" || lower == "docker-compose.yml":
+			mark("docker-compose", rel)
+		case name == "Chart.yaml":
+			mark("helm", rel)
+			mark("kubernetes", rel)
+		case lower == "kustomization.yaml" || lower == "kustomization.yml":
+			mark("kubernetes", rel)
+		case strings.HasSuffix(lower, ".tf"):
+			mark("terraform", rel)
+		case (strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml")) && found["kubernetes"] == "":
+			if isKubernetesManifest(path) {
+				mark("kubernetes", rel)
+			}
+		}
+	})
+	return foundFeatures(found, "docker", "docker-compose", "kubernetes", "helm", "terraform")
+}
+
+// foundFeatures 按 order 的顺序返回已找到的特性及其来源文件
+func foundFeatures(found map[string]string, order ...string) []domain.DetectionEvidence {
+	var features []domain.DetectionEvidence
+	for _, name := range order {
+		if source, ok := found[name]; ok {
+			features = append(features, feature(name, source))
+		}
+	}
+	return features
+}
+
+// isKubernetesManifest 判断YAML文件是否为Kubernetes资源清单（同时声明 apiVersion 和 kind）
+func isKubernetesManifest(path string) bool {
+	var apiVersion, kind bool
+	for _, line := range readManifest(path) {
+		apiVersion = apiVersion || bytes.HasPrefix(line, []byte("apiVersion:"))
+		kind = kind || bytes.HasPrefix(line, []byte("kind:"))
+	}
+	return apiVersion && kind
+}
+
+// walkProject 遍历项目中深度不超过 scanDepth 的文件，跳过隐藏目录和依赖目录；
+// visit 收到文件路径、相对项目根目录的路径（以 / 分隔）和文件名
+func walkProject(rootPath string, visit func(path, rel, name string)) {
+	_ = filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(rootPath, path)
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != rootPath && (strings.HasPrefix(name, ".") || skippedDirs[name] || len(strings.Split(rel, "/")) > scanDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		visit(path, rel, entry.Name())
+		return nil
+	})
+}
+
+// readManifest 按行读取不超过 maxManifestSize 的配置文件，读取失败或文件过大时返回 nil
+func readManifest(path string) [][]byte {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxManifestSize {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return bytes.Split(content, []byte("\n"))
+}
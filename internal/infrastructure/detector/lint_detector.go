@@ -0,0 +1,63 @@
+package detector
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// lintConfigs 代码检查和格式化工具的配置文件到特性的映射
+var lintConfigs = []dependency{
+	{".golangci.yml", "golangci-lint"},
+	{".golangci.yaml", "golangci-lint"},
+	{".golangci.toml", "golangci-lint"},
+	{".golangci.json", "golangci-lint"},
+	{".eslintrc", "eslint"},
+	{".eslintrc.js", "eslint"},
+	{".eslintrc.cjs", "eslint"},
+	{".eslintrc.json", "eslint"},
+	{".eslintrc.yml", "eslint"},
+	{".eslintrc.yaml", "eslint"},
+	{"eslint.config.js", "eslint"},
+	{"eslint.config.mjs", "eslint"},
+	{"eslint.config.cjs", "eslint"},
+	{"eslint.config.ts", "eslint"},
+	{".prettierrc", "prettier"},
+	{".prettierrc.json", "prettier"},
+	{".prettierrc.yml", "prettier"},
+	{".prettierrc.yaml", "prettier"},
+	{".prettierrc.js", "prettier"},
+	{".prettierrc.cjs", "prettier"},
+	{"prettier.config.js", "prettier"},
+	{"prettier.config.cjs", "prettier"},
+	{"prettier.config.mjs", "prettier"},
+	{"ruff.toml", "ruff"},
+	{".ruff.toml", "ruff"},
+}
+
+// detectLinters 检测根目录及一级子目录中代码检查和格式化工具的配置，返回对应的特性：
+// golangci-lint、eslint、prettier、ruff、black
+func detectLinters(rootPath string) []domain.DetectionEvidence {
+	var features []domain.DetectionEvidence
+	for _, dir := range append([]string{"."}, subprojectDirs(rootPath)...) {
+		for _, f := range configFeatures(filepath.Join(rootPath, dir), lintConfigs) {
+			f.Source = path.Join(dir, f.Source)
+			features = appendFeature(features, f)
+		}
+
+		// pyproject.toml 中的工具配置
+		pyproject := path.Join(dir, "pyproject.toml")
+		if content, err := os.ReadFile(filepath.Join(rootPath, pyproject)); err == nil {
+			if strings.Contains(string(content), "[tool.ruff") {
+				features = appendFeature(features, feature("ruff", pyproject+": [tool.ruff]"))
+			}
+			if strings.Contains(string(content), "[tool.black]") {
+				features = appendFeature(features, feature("black", pyproject+": [tool.black]"))
+			}
+		}
+	}
+	return features
+}
@@ -0,0 +1,263 @@
+package detector
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// NodeDetector Node.js（TypeScript/JavaScript）项目检测器
+type NodeDetector struct{}
+
+// NewNodeDetector 创建Node.js检测器
+func NewNodeDetector() *NodeDetector {
+	return &NodeDetector{}
+}
+
+func (d *NodeDetector) Name() string {
+	return "typescript"
+}
+
+func (d *NodeDetector) Priority() int {
+	return 20
+}
+
+// packageJSON package.json 中检测需要的字段
+type packageJSON struct {
+	Bin             json.RawMessage   `json:"bin"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// Detect 检测Node.js项目，有 tsconfig.json 或依赖 typescript 时为 typescript，否则为 javascript
+func (d *NodeDetector) Detect(rootPath string) (*domain.ProjectInfo, error) {
+	packagePath := filepath.Join(rootPath, "package.json")
+
+	if !fileExists(packagePath) {
+		return &domain.ProjectInfo{Language: ""}, nil
+	}
+
+	info := &domain.ProjectInfo{
+		Language: "javascript",
+		RootPath: rootPath,
+		Features: []string{},
+	}
+	explain(info, "language", "javascript", "package.json")
+
+	// 读取package.json分析依赖
+	content, err := os.ReadFile(packagePath)
+	if err != nil {
+		return info, nil
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return info, nil
+	}
+	// 依赖的置信度：运行时依赖0.9，开发依赖0.5
+	deps := map[string]float64{}
+	for name := range pkg.DevDependencies {
+		deps[name] = 0.5
+	}
+	for name := range pkg.Dependencies {
+		deps[name] = 0.9
+	}
+
+	switch {
+	case fileExists(filepath.Join(rootPath, "tsconfig.json")):
+		info.Language = "typescript"
+		info.Evidence[0] = domain.DetectionEvidence{Kind: "language", Value: "typescript", Source: "tsconfig.json"}
+	case deps["typescript"] > 0:
+		info.Language = "typescript"
+		info.Evidence[0] = domain.DetectionEvidence{Kind: "language", Value: "typescript", Source: "package.json: typescript"}
+	}
+
+	// 检测框架：前端框架优先，同时使用后端框架时为全栈项目
+	backends := scoreDependencies(deps, "framework", "package.json", []dependency{
+		{"@nestjs/core", "nestjs"},
+		{"express", "express"},
+		{"fastify", "fastify"},
+		{"koa", "koa"},
+		{"hono", "hono"},
+	})
+	frontends := d.detectFrontend(rootPath, deps)
+	frontend := rank(info, frontends)
+	backend := rank(info, backends)
+	info.Framework = frontend
+	if info.Framework == "" {
+		info.Framework = backend
+	}
+
+	// 检测数据库
+	info.Database = rank(info, scoreDependencies(deps, "database", "package.json", []dependency{
+		{"pg", "postgresql"},
+		{"postgres", "postgresql"},
+		{"mysql", "mysql"},
+		{"mysql2", "mysql"},
+		{"mongodb", "mongodb"},
+		{"mongoose", "mongodb"},
+		{"redis", "redis"},
+		{"ioredis", "redis"},
+	}))
+
+	// 检测项目类型
+	var reason string
+	switch {
+	case frontend != "" && backend != "":
+		info.ProjectType, reason = "fullstack", "frontend and backend frameworks"
+	case frontend != "" && d.hasServerRoutes(rootPath, frontend):
+		info.ProjectType, reason = "fullstack", frontend+" server routes"
+	case frontend != "":
+		info.ProjectType, reason = "frontend", "frontend framework "+frontend
+	case backend != "":
+		info.ProjectType, reason = "backend", "backend framework "+backend
+	case len(pkg.Bin) > 0:
+		info.ProjectType, reason = "cli", "package.json: bin"
+	default:
+		info.ProjectType, reason = "library", "no framework or bin"
+	}
+	explain(info, "project_type", info.ProjectType, reason)
+
+	// 检测特性
+	addFeatures(info, allDependencies(deps, "package.json", []dependency{
+		{"@grpc/grpc-js", "grpc"},
+		{"swagger-ui-express", "swagger"},
+		{"jsonwebtoken", "jwt"},
+		{"prom-client", "prometheus"},
+		{"@opentelemetry/api", "opentelemetry"},
+		// 测试框架与覆盖率工具
+		{"jest", "jest"},
+		{"vitest", "vitest"},
+		{"mocha", "mocha"},
+		{"@playwright/test", "playwright"},
+		{"cypress", "cypress"},
+		{"nyc", "nyc"},
+		{"c8", "c8"},
+		// 代码检查与格式化工具
+		{"eslint", "eslint"},
+		{"prettier", "prettier"},
+		// 消息队列
+		{"kafkajs", "kafka"},
+		{"node-rdkafka", "kafka"},
+		{"nats", "nats"},
+		{"amqplib", "rabbitmq"},
+		{"amqp-connection-manager", "rabbitmq"},
+		{"@aws-sdk/client-sqs", "aws-sqs"},
+		{"@google-cloud/pubsub", "gcp-pubsub"},
+		{"@azure/service-bus", "azure-servicebus"},
+	}))
+	addFeatures(info, configFeatures(rootPath, []dependency{
+		{"jest.config.js", "jest"},
+		{"jest.config.ts", "jest"},
+		{"vitest.config.js", "vitest"},
+		{"vitest.config.ts", "vitest"},
+	}))
+
+	return info, nil
+}
+
+// frontendFramework 前端框架，依赖或配置文件任一存在即视为使用
+type frontendFramework struct {
+	name    string
+	pkg     string
+	configs []string
+}
+
+// frontendFrameworks 按优先级排列：元框架在其基础框架之前
+var frontendFrameworks = []frontendFramework{
+	{"nextjs", "next", []string{"next.config.js", "next.config.mjs", "next.config.ts"}},
+	{"nuxt", "nuxt", []string{"nuxt.config.ts", "nuxt.config.js"}},
+	{"sveltekit", "@sveltejs/kit", nil},
+	{"angular", "@angular/core", []string{"angular.json"}},
+	{"react", "react", nil},
+	{"vue", "vue", []string{"vue.config.js"}},
+	{"svelte", "svelte", []string{"svelte.config.js"}},
+}
+
+// detectFrontend 检测前端框架：依赖按其置信度，仅有配置文件时为0.6
+func (d *NodeDetector) detectFrontend(rootPath string, deps map[string]float64) []domain.DetectionCandidate {
+	var candidates []domain.DetectionCandidate
+	for _, framework := range frontendFrameworks {
+		confidence, source := deps[framework.pkg], "package.json: "+framework.pkg
+		for _, config := range framework.configs {
+			if confidence < 0.6 && fileExists(filepath.Join(rootPath, config)) {
+				confidence, source = 0.6, config
+			}
+		}
+		if confidence > 0 {
+			candidates = append(candidates, domain.DetectionCandidate{Kind: "framework", Value: framework.name, Confidence: confidence, Source: source})
+		}
+	}
+	return candidates
+}
+
+// hasServerRoutes 判断元框架项目是否包含服务端路由：Next.js 的 API 路由、
+// Nuxt 的 server/ 目录、SvelteKit 的 +server / +page.server 等文件
+func (d *NodeDetector) hasServerRoutes(rootPath, framework string) bool {
+	switch framework {
+	case "nextjs":
+		for _, dir := range []string{"pages/api", "app/api", "src/pages/api", "src/app/api"} {
+			if dirExists(filepath.Join(rootPath, dir)) {
+				return true
+			}
+		}
+	case "nuxt":
+		return dirExists(filepath.Join(rootPath, "server"))
+	case "sveltekit":
+		found := false
+		_ = filepath.WalkDir(filepath.Join(rootPath, "src"), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || found {
+				return filepath.SkipAll
+			}
+			name := entry.Name()
+			if !entry.IsDir() && (strings.HasPrefix(name, "+server.") || strings.Contains(name, ".server.")) {
+				found = true
+			}
+			return nil
+		})
+		return found
+	}
+	return false
+}
+
+// dependency 依赖包名（或配置文件名）到检测结果的映射，按顺序匹配
+type dependency struct {
+	pkg  string
+	name string
+}
+
+// scoreDependencies 返回存在的依赖对应的候选项，置信度取自 deps，来源为 manifest 中的依赖
+func scoreDependencies(deps map[string]float64, kind, manifest string, candidates []dependency) []domain.DetectionCandidate {
+	var scored []domain.DetectionCandidate
+	for _, c := range candidates {
+		if confidence := deps[c.pkg]; confidence > 0 {
+			scored = append(scored, domain.DetectionCandidate{Kind: kind, Value: c.name, Confidence: confidence, Source: manifest + ": " + c.pkg})
+		}
+	}
+	return scored
+}
+
+// allDependencies 返回所有存在的依赖对应的特性，同一特性只记录首个匹配的依赖
+func allDependencies(deps map[string]float64, manifest string, candidates []dependency) []domain.DetectionEvidence {
+	var features []domain.DetectionEvidence
+	for _, c := range candidates {
+		if deps[c.pkg] > 0 {
+			features = appendFeature(features, feature(c.name, manifest+": "+c.pkg))
+		}
+	}
+	return features
+}
+
+// configFeatures 返回项目目录中存在的配置文件对应的特性，同一特性只记录首个匹配的文件
+func configFeatures(rootPath string, configs []dependency) []domain.DetectionEvidence {
+	var features []domain.DetectionEvidence
+	for _, c := range configs {
+		if fileExists(filepath.Join(rootPath, c.pkg)) {
+			features = appendFeature(features, feature(c.name, c.pkg))
+		}
+	}
+	return features
+}
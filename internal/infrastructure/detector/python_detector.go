@@ -0,0 +1,134 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// PythonDetector Python项目检测器
+type PythonDetector struct{}
+
+// NewPythonDetector 创建Python检测器
+func NewPythonDetector() *PythonDetector {
+	return &PythonDetector{}
+}
+
+func (d *PythonDetector) Name() string {
+	return "python"
+}
+
+func (d *PythonDetector) Priority() int {
+	return 30
+}
+
+// pythonManifests 声明Python依赖的文件
+var pythonManifests = []string{"pyproject.toml", "requirements.txt", "setup.py", "setup.cfg", "Pipfile"}
+
+// pythonName 依赖声明中的包名
+var pythonName = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+// Detect 检测Python项目
+func (d *PythonDetector) Detect(rootPath string) (*domain.ProjectInfo, error) {
+	var manifests []string
+	for _, name := range pythonManifests {
+		if fileExists(filepath.Join(rootPath, name)) {
+			manifests = append(manifests, name)
+		}
+	}
+	if len(manifests) == 0 {
+		return &domain.ProjectInfo{Language: ""}, nil
+	}
+
+	info := &domain.ProjectInfo{
+		Language: "python",
+		RootPath: rootPath,
+		Features: []string{},
+	}
+	source := strings.Join(manifests, ", ")
+	explain(info, "language", "python", source)
+
+	// 读取依赖声明，收集出现的包名（忽略大小写，"_" 与 "-" 等价）
+	deps := map[string]float64{}
+	var manifestContent strings.Builder
+	for _, name := range manifests {
+		content, err := os.ReadFile(filepath.Join(rootPath, name))
+		if err != nil {
+			continue
+		}
+		manifestContent.Write(content)
+		for _, token := range pythonName.FindAllString(string(content), -1) {
+			deps[strings.ReplaceAll(strings.ToLower(token), "_", "-")] = 0.8
+		}
+	}
+
+	// 检测框架
+	info.Framework = rank(info, scoreDependencies(deps, "framework", source, []dependency{
+		{"django", "django"},
+		{"fastapi", "fastapi"},
+		{"flask", "flask"},
+	}))
+
+	// 检测数据库
+	info.Database = rank(info, scoreDependencies(deps, "database", source, []dependency{
+		{"psycopg2", "postgresql"},
+		{"psycopg2-binary", "postgresql"},
+		{"psycopg", "postgresql"},
+		{"asyncpg", "postgresql"},
+		{"pymysql", "mysql"},
+		{"mysqlclient", "mysql"},
+		{"pymongo", "mongodb"},
+		{"motor", "mongodb"},
+		{"redis", "redis"},
+	}))
+
+	// 检测项目类型
+	manifest := manifestContent.String()
+	var reason string
+	switch {
+	case info.Framework != "":
+		info.ProjectType, reason = "backend", "framework "+info.Framework
+	case strings.Contains(manifest, "[project.scripts]"):
+		info.ProjectType, reason = "cli", "[project.scripts]"
+	case strings.Contains(manifest, "console_scripts"):
+		info.ProjectType, reason = "cli", "console_scripts"
+	default:
+		info.ProjectType, reason = "library", "no framework or scripts"
+	}
+	explain(info, "project_type", info.ProjectType, reason)
+
+	// 检测特性
+	addFeatures(info, allDependencies(deps, source, []dependency{
+		{"grpcio", "grpc"},
+		{"pyjwt", "jwt"},
+		{"prometheus-client", "prometheus"},
+		{"opentelemetry-api", "opentelemetry"},
+		// 测试框架与覆盖率工具
+		{"pytest", "pytest"},
+		{"pytest-cov", "pytest-cov"},
+		{"coverage", "coverage"},
+		// 代码检查与格式化工具
+		{"ruff", "ruff"},
+		{"black", "black"},
+		// 消息队列
+		{"kafka-python", "kafka"},
+		{"confluent-kafka", "kafka"},
+		{"aiokafka", "kafka"},
+		{"nats-py", "nats"},
+		{"pika", "rabbitmq"},
+		{"aio-pika", "rabbitmq"},
+		{"celery", "celery"},
+		{"google-cloud-pubsub", "gcp-pubsub"},
+		{"azure-servicebus", "azure-servicebus"},
+	}))
+	addFeatures(info, configFeatures(rootPath, []dependency{
+		{"pytest.ini", "pytest"},
+		{"conftest.py", "pytest"},
+		{".coveragerc", "coverage"},
+	}))
+
+	return info, nil
+}
@@ -0,0 +1,153 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ErrNotConfigured is returned by New when no embedding provider is configured
+var ErrNotConfigured = errors.New("semantic search is not configured, run 'ohmymem config set embeddings.provider ollama' (local) or 'openai' (API)")
+
+// Supported providers
+const (
+	ProviderOllama = "ollama" // Local model served by Ollama
+	ProviderOpenAI = "openai" // OpenAI or any API compatible with its /embeddings endpoint
+)
+
+// Defaults applied when embeddings.model or embeddings.url is not set
+const (
+	DefaultOllamaModel = "nomic-embed-text"
+	DefaultOllamaURL   = "http://localhost:11434"
+	DefaultOpenAIModel = "text-embedding-3-small"
+	DefaultOpenAIURL   = "https://api.openai.com/v1"
+)
+
+// httpTimeout bounds each embedding request; local models can be slow to load
+const httpTimeout = 60 * time.Second
+
+// New returns the embedding provider configured by name ("ollama" or "openai"),
+// model, base URL and API key; empty model and URL use the provider defaults
+func New(provider, model, url, apiKey string) (domain.EmbeddingProvider, error) {
+	client := &http.Client{Timeout: httpTimeout}
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "":
+		return nil, ErrNotConfigured
+	case ProviderOllama:
+		return &OllamaProvider{model: orDefault(model, DefaultOllamaModel), url: orDefault(url, DefaultOllamaURL), client: client}, nil
+	case ProviderOpenAI:
+		if apiKey == "" && url == "" {
+			return nil, fmt.Errorf("the openai embedding provider needs embeddings.api_key or OHMYMEM_EMBEDDINGS_API_KEY")
+		}
+		return &OpenAIProvider{model: orDefault(model, DefaultOpenAIModel), url: orDefault(url, DefaultOpenAIURL), apiKey: apiKey, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q (expected %s or %s)", provider, ProviderOllama, ProviderOpenAI)
+	}
+}
+
+// OllamaProvider computes vectors with a local model through the Ollama /api/embed endpoint
+type OllamaProvider struct {
+	model  string
+	url    string
+	client *http.Client
+}
+
+// Model implements domain.EmbeddingProvider
+func (p *OllamaProvider) Model() string {
+	return ProviderOllama + ":" + p.model
+}
+
+// Embed implements domain.EmbeddingProvider
+func (p *OllamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	request := map[string]any{"model": p.model, "input": texts}
+	if err := postJSON(ctx, p.client, strings.TrimRight(p.url, "/")+"/api/embed", "", request, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+// OpenAIProvider computes vectors through an OpenAI-compatible /embeddings endpoint
+type OpenAIProvider struct {
+	model  string
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// Model implements domain.EmbeddingProvider
+func (p *OpenAIProvider) Model() string {
+	return ProviderOpenAI + ":" + p.model
+}
+
+// Embed implements domain.EmbeddingProvider
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	request := map[string]any{"model": p.model, "input": texts}
+	if err := postJSON(ctx, p.client, strings.TrimRight(p.url, "/")+"/embeddings", p.apiKey, request, &resp); err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(resp.Data))
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
+
+// postJSON sends request as JSON to url and decodes the JSON response into out
+func postJSON(ctx context.Context, client *http.Client, url, token string, request, out any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response of %s: %w", url, err)
+	}
+	return nil
+}
+
+// orDefault returns value, or def when value is blank
+func orDefault(value, def string) string {
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return def
+}
@@ -137,3 +137,71 @@ func PromptInput(label string, defaultValue string) (string, error) {
 
 	return value, nil
 }
+
+// CaptureInput holds the fields of the capture wizard; initial values are prefilled
+type CaptureInput struct {
+	Section   string
+	Tag       string
+	Content   string
+	Rationale string
+}
+
+// CaptureOptions configures the capture wizard
+type CaptureOptions struct {
+	Sections          []string
+	Tags              []string // Existing tags, suggested while typing the tag
+	ValidateTag       func(string) error
+	ValidateContent   func(string) error
+	ValidateRationale func(string) error
+}
+
+// CaptureWizard runs a multi-step form asking for the section, tag, content and
+// rationale of an entry, showing validation errors inline
+func CaptureWizard(input *CaptureInput, opts CaptureOptions) error {
+	sections := make([]huh.Option[string], len(opts.Sections))
+	for i, section := range opts.Sections {
+		sections[i] = huh.NewOption(section, section)
+	}
+
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Section").
+				Options(sections...).
+				Value(&input.Section),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Tag").
+				Description("Tab completes an existing tag; leave 'auto' to have one suggested").
+				Suggestions(opts.Tags).
+				Validate(orNil(opts.ValidateTag)).
+				Value(&input.Tag),
+			huh.NewText().
+				Title("Content").
+				Description("A single statement; line breaks are joined").
+				Validate(orNil(opts.ValidateContent)).
+				Value(&input.Content),
+			huh.NewInput().
+				Title("Rationale (optional)").
+				Validate(orNil(opts.ValidateRationale)).
+				Value(&input.Rationale),
+		),
+	).Run()
+
+	if err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return ErrCancelled
+		}
+		return fmt.Errorf("prompt failed: %w", err)
+	}
+	return nil
+}
+
+// orNil returns validate, or a validator accepting everything when it is nil
+func orNil(validate func(string) error) func(string) error {
+	if validate == nil {
+		return func(string) error { return nil }
+	}
+	return validate
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ArchivedEntry is an entry moved out of the memory file into an archive file
+type ArchivedEntry struct {
+	domain.Entry
+	Archive string // Archive name: the file name in .ohmymem/archive without .md
+}
+
+// ListArchived returns the entries of every archive file, by archive name then file order
+func (r *MarkdownMemoryRepository) ListArchived(ctx context.Context) ([]ArchivedEntry, error) {
+	names, err := r.archiveNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []ArchivedEntry
+	for _, name := range names {
+		data, err := os.ReadFile(r.ArchivePath(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive file: %w", err)
+		}
+		for _, entry := range ParseMemory(string(data)) {
+			archived = append(archived, ArchivedEntry{Entry: entry, Archive: name})
+		}
+	}
+	return archived, nil
+}
+
+// RestoreEntries moves the archived entries with the given IDs back to their sections
+// of the memory file and returns them. Archive files left without entries are removed.
+func (r *MarkdownMemoryRepository) RestoreEntries(ctx context.Context, ids []string) ([]ArchivedEntry, error) {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if start, _ := domain.FindEntryBlock(content, id); start != -1 {
+			return nil, fmt.Errorf("entry %s is already in the memory file", id)
+		}
+	}
+
+	names, err := r.archiveNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []ArchivedEntry
+	updates := map[string]string{}
+	for _, name := range names {
+		data, err := os.ReadFile(r.ArchivePath(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive file: %w", err)
+		}
+		archiveContent, entries, blocks := cutEntries(string(data), ids)
+		if len(entries) == 0 {
+			continue
+		}
+		for i, entry := range entries {
+			content = insertIntoSection(content, entry.Section.Title(), blocks[i])
+			restored = append(restored, ArchivedEntry{Entry: entry, Archive: name})
+		}
+		updates[name] = archiveContent
+	}
+	if len(restored) == 0 {
+		return nil, nil
+	}
+
+	// Write the memory file first so entries are never lost if an archive write fails
+	if err := r.atomicWrite(content); err != nil {
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+	for name, archiveContent := range updates {
+		path := r.ArchivePath(name)
+		if len(ParseMemory(archiveContent)) == 0 {
+			err = os.Remove(path)
+		} else {
+			err = atomicWriteFile(path, archiveContent)
+		}
+		if err != nil {
+			return restored, fmt.Errorf("failed to update archive file: %w", err)
+		}
+	}
+
+	slog.Debug("entries restored", "count", len(restored))
+
+	return restored, nil
+}
+
+// archiveNames returns the names of the archive files, sorted
+func (r *MarkdownMemoryRepository) archiveNames() ([]string, error) {
+	files, err := os.ReadDir(filepath.Join(r.DirPath(), ArchiveDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read archive dir: %w", err)
+	}
+
+	var names []string
+	for _, file := range files {
+		if name, ok := strings.CutSuffix(file.Name(), ".md"); ok && !file.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"strings"
+)
+
+// Anchors opening the entry blocks of captured and template entries
+const (
+	anchorOpener   = "<!-- entry-id:"
+	templateOpener = "<!-- template-entry"
+)
+
+// blockKind tells how an entry is stored in the memory file
+type blockKind int
+
+const (
+	legacyBlock   blockKind = iota // A bare "* **[Tag]** ..." bullet outside any block
+	anchoredBlock                  // An entry-id anchor, its bullet and the end marker
+	templateBlock                  // A template-entry anchor, its bullet and the end marker
+)
+
+// entryBlock locates one entry in the lines of a memory file
+type entryBlock struct {
+	kind        blockKind
+	first, last int  // Line indexes of the opener (or legacy bullet) and of the end marker
+	closed      bool // The end marker was found; always true for legacy bullets
+}
+
+// scanBlocks finds the entry blocks in lines, in order. A block runs from its opener to
+// the next end marker; one left open by another opener, a section header or the end of
+// the text is returned unclosed. Bullets inside a block, closed or not, are never
+// legacy entries, so migrate, inspect and the parser agree on what is legacy.
+func scanBlocks(lines []string) []entryBlock {
+	var (
+		blocks []entryBlock
+		open   *entryBlock
+	)
+	closeOpen := func(last int, closed bool) {
+		if open != nil {
+			open.last, open.closed = last, closed
+			blocks = append(blocks, *open)
+			open = nil
+		}
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, anchorOpener), strings.HasPrefix(trimmed, templateOpener):
+			closeOpen(i-1, false)
+			kind := anchoredBlock
+			if strings.HasPrefix(trimmed, templateOpener) {
+				kind = templateBlock
+			}
+			open = &entryBlock{kind: kind, first: i}
+		case trimmed == entryBlockEnd:
+			closeOpen(i, true)
+		case strings.HasPrefix(trimmed, "## "):
+			closeOpen(i-1, false)
+		case open == nil && legacyEntryRegex.MatchString(trimmed):
+			blocks = append(blocks, entryBlock{kind: legacyBlock, first: i, last: i, closed: true})
+		}
+	}
+	closeOpen(len(lines)-1, false)
+	return blocks
+}
+
+// isLegacyBullet reports whether the block is a legacy bullet, to be converted by
+// migrate and counted by check
+func (b entryBlock) isLegacyBullet() bool {
+	return b.kind == legacyBlock
+}
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+const (
+	// IndexDirName holds derived data under .ohmymem, such as entry embeddings
+	IndexDirName = "index"
+
+	// EmbeddingsFileName is the embedding index file in IndexDirName
+	EmbeddingsFileName = "embeddings.json"
+)
+
+// indexGitignore keeps the derived index out of version control
+const indexGitignore = "# Derived from memory.md by 'ohmymem index'; rebuilt on demand\n*\n"
+
+// EmbeddingStore keeps the embedding index of a project in .ohmymem/index/embeddings.json
+type EmbeddingStore struct {
+	path string
+}
+
+// NewEmbeddingStore creates the embedding store of the project at rootPath
+func NewEmbeddingStore(rootPath string) *EmbeddingStore {
+	return &EmbeddingStore{path: EmbeddingIndexPath(rootPath)}
+}
+
+// EmbeddingIndexPath returns the path of the embedding index of the project at rootPath
+func EmbeddingIndexPath(rootPath string) string {
+	return filepath.Join(rootPath, DirName, IndexDirName, EmbeddingsFileName)
+}
+
+// Load implements domain.EmbeddingStore
+func (s *EmbeddingStore) Load(ctx context.Context) (*domain.EmbeddingIndex, error) {
+	index := &domain.EmbeddingIndex{Entries: map[string]domain.EmbeddedEntry{}}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", EmbeddingsFileName, err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", EmbeddingsFileName, err)
+	}
+	return index, nil
+}
+
+// Save implements domain.EmbeddingStore, ignoring the index directory in git
+func (s *EmbeddingStore) Save(ctx context.Context, index *domain.EmbeddingIndex) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create index directory: %w", err)
+	}
+	gitignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(gitignore); os.IsNotExist(err) {
+		if err := os.WriteFile(gitignore, []byte(indexGitignore), 0644); err != nil {
+			return fmt.Errorf("write index .gitignore: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", EmbeddingsFileName, err)
+	}
+	return atomicWriteFile(s.path, string(data))
+}
@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// entryBlockStarts are the anchors opening an entry block
+var entryBlockStarts = []string{anchorOpener, templateOpener}
+
+// entryBlockEnd closes an entry block
+const entryBlockEnd = "<!-- entry-end -->"
+
+// FormatMemory normalizes memory file content: LF line endings, no trailing
+// whitespace, sections in their standard order with repeated headers merged, and one
+// blank line around headers and between entry blocks. Entry text is not changed.
+func FormatMemory(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	content = strings.Join(lines, "\n")
+
+	var sb strings.Builder
+	frontmatter, body, ok := splitFrontmatter(content)
+	if ok {
+		sb.WriteString("---\n" + frontmatter + "---\n")
+	}
+
+	// Split the body into the text before the first header and the sections
+	preamble := body
+	var titles []string
+	sections := map[string][]string{}
+	if start := headerIndex(body); start != -1 {
+		preamble = body[:start]
+		for _, chunk := range strings.Split("\n"+body[start:], "\n## ")[1:] {
+			title, text, _ := strings.Cut(chunk, "\n")
+			title = strings.TrimSpace(title)
+			if _, seen := sections[title]; !seen {
+				titles = append(titles, title)
+			}
+			sections[title] = append(sections[title], formatUnits(text)...)
+		}
+	}
+
+	writeUnits := func(units []string) {
+		for _, unit := range units {
+			sb.WriteString("\n" + unit + "\n")
+		}
+	}
+	writeUnits(formatUnits(preamble))
+	writeSection := func(title string) {
+		sb.WriteString("\n## " + title + "\n")
+		writeUnits(sections[title])
+	}
+	for _, sectionType := range domain.ValidSections() {
+		if _, ok := sections[sectionType.Title()]; ok {
+			writeSection(sectionType.Title())
+		}
+	}
+	for _, title := range titles {
+		if !isSectionTitle(title) {
+			writeSection(title)
+		}
+	}
+
+	return strings.TrimLeft(sb.String(), "\n")
+}
+
+// formatUnits splits text into entry blocks and paragraphs, dropping the blank lines
+// between them; blank lines inside an entry block are dropped too
+func formatUnits(text string) []string {
+	var units []string
+	var current []string
+	inBlock := false
+	flush := func() {
+		if len(current) > 0 {
+			units = append(units, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			if trimmed == "" {
+				continue
+			}
+			current = append(current, line)
+			if trimmed == entryBlockEnd {
+				flush()
+				inBlock = false
+			}
+		case isBlockStart(trimmed):
+			flush()
+			current = append(current, line)
+			inBlock = true
+		case trimmed == "":
+			flush()
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+	return units
+}
+
+// isBlockStart reports whether a line opens an entry block
+func isBlockStart(line string) bool {
+	for _, start := range entryBlockStarts {
+		if strings.HasPrefix(line, start) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSectionTitle reports whether title is the header of a section
+func isSectionTitle(title string) bool {
+	for _, sectionType := range domain.ValidSections() {
+		if title == sectionType.Title() {
+			return true
+		}
+	}
+	return false
+}
+
+// headerIndex returns the offset of the first "## " header line, or -1
+func headerIndex(content string) int {
+	if strings.HasPrefix(content, "## ") {
+		return 0
+	}
+	if i := strings.Index(content, "\n## "); i != -1 {
+		return i + 1
+	}
+	return -1
+}
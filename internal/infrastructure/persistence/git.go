@@ -0,0 +1,41 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// ReadRevision returns the memory file content as committed at a git revision
+// (branch, tag, commit or any other rev) of the repository containing basePath
+func ReadRevision(ctx context.Context, basePath, rev string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "show", rev+":./"+path.Join(DirName, FileName))
+	cmd.Dir = basePath
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git show %s: %s", rev, msg)
+		}
+		return "", fmt.Errorf("git show %s: %w", rev, err)
+	}
+	return stdout.String(), nil
+}
+
+// GitUserName returns the user.name configured for the repository containing
+// basePath, or for the user when basePath is not in a repository
+func GitUserName(ctx context.Context, basePath string) (string, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "config", "user.name")
+	cmd.Dir = basePath
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git config user.name: %w", err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
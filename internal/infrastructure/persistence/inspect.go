@@ -0,0 +1,272 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// Frontmatter keys every memory file is expected to declare
+const (
+	SchemaVersionKey = "schema_version"
+	EntryFormatKey   = "entry_format"
+
+	// CurrentSchemaVersion is the schema written by this version of ohmymem
+	CurrentSchemaVersion = "0.1"
+)
+
+// Inspection summarizes the structure of the memory file for diagnostics
+type Inspection struct {
+	Exists          bool
+	HasFrontmatter  bool
+	Frontmatter     map[string]any       // Parsed frontmatter; nil when absent or invalid
+	FrontmatterErr  error                // YAML error in the frontmatter block
+	MissingSections []domain.SectionType // Required section headers not found
+	UnknownSections []string             // "## " headers naming no section, entries under them are ignored
+	AnchoredEntries int                  // Entries parsed from anchored blocks
+	LegacyEntries   int                  // Inline bullets without an anchor
+	BrokenAnchors   int                  // Anchors that do not form a parseable entry block
+	UnparsedBullets int                  // Bullets inside sections that match no entry format
+	DuplicateIDs    []string             // Entry IDs used by more than one anchored entry
+	TempFileExists  bool                 // Leftover temp file from an interrupted atomic write
+	LockHeld        bool                 // Another process currently holds the write lock
+}
+
+// LockFilePath returns the path of the cross-process write lock
+func (r *MarkdownMemoryRepository) LockFilePath() string {
+	return r.lockFilePath
+}
+
+// LockHeld reports whether another process holds the write lock at lockPath, without waiting for it
+func LockHeld(lockPath string) (bool, error) {
+	if _, err := os.Stat(lockPath); err != nil {
+		return false, nil
+	}
+	fl := flock.New(lockPath)
+	locked, err := fl.TryLock()
+	if err != nil {
+		return false, fmt.Errorf("probe lock: %w", err)
+	}
+	if !locked {
+		return true, nil
+	}
+	return false, fl.Unlock()
+}
+
+// TempFilePath returns the path used for atomic writes of the memory file
+func (r *MarkdownMemoryRepository) TempFilePath() string {
+	return r.FilePath() + ".tmp"
+}
+
+// Inspect examines the memory file without modifying it
+func (r *MarkdownMemoryRepository) Inspect(ctx context.Context) (*Inspection, error) {
+	inspection := &Inspection{}
+
+	if _, err := os.Stat(r.TempFilePath()); err == nil {
+		inspection.TempFileExists = true
+	}
+	lockHeld, err := LockHeld(r.lockFilePath)
+	if err != nil {
+		return nil, err
+	}
+	inspection.LockHeld = lockHeld
+
+	data, err := os.ReadFile(r.FilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return inspection, nil
+		}
+		return nil, fmt.Errorf("failed to read memory file: %w", err)
+	}
+	inspectContent(inspection, string(data))
+	return inspection, nil
+}
+
+// InspectContent examines memory file content, such as a version staged in git
+func InspectContent(content string) *Inspection {
+	inspection := &Inspection{}
+	inspectContent(inspection, content)
+	return inspection
+}
+
+// inspectContent fills in the structure of the memory file content
+func inspectContent(inspection *Inspection, content string) {
+	inspection.Exists = true
+
+	if frontmatter, _, ok := splitFrontmatter(content); ok {
+		inspection.HasFrontmatter = true
+		var fields map[string]any
+		if err := yaml.Unmarshal([]byte(frontmatter), &fields); err != nil {
+			inspection.FrontmatterErr = err
+		} else {
+			if fields == nil {
+				fields = map[string]any{}
+			}
+			inspection.Frontmatter = fields
+		}
+	}
+
+	for _, sectionType := range domain.ValidSections() {
+		if findSectionStart(content, sectionType.Title()) == -1 {
+			if sectionType != domain.SectionNote {
+				inspection.MissingSections = append(inspection.MissingSections, sectionType)
+			}
+			continue
+		}
+
+		block := extractSection(content, sectionType)
+		inspection.AnchoredEntries += len(anchoredEntryRegex.FindAllString(block, -1))
+
+		lines := strings.Split(block, "\n")
+		bullets, entryBullets := 0, 0
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "* ") && !strings.HasPrefix(line, "- ") {
+				continue
+			}
+			if domain.ParseDecisionLine(&domain.DecisionRecord{}, line) {
+				continue
+			}
+			bullets++
+			if legacyEntryRegex.MatchString(line) {
+				entryBullets++
+			}
+		}
+		// Blocks left open (e.g. an anchor nested in another block), anchored blocks that
+		// do not parse and end markers closing no block are broken
+		closed := 0
+		for _, entry := range scanBlocks(lines) {
+			switch {
+			case entry.isLegacyBullet():
+				inspection.LegacyEntries++
+			case !entry.closed:
+				inspection.BrokenAnchors++
+			default:
+				closed++
+				text := strings.Join(lines[entry.first:entry.last+1], "\n")
+				if entry.kind == anchoredBlock && !anchoredEntryRegex.MatchString(text) {
+					inspection.BrokenAnchors++
+				}
+			}
+		}
+		inspection.BrokenAnchors += strings.Count(block, entryBlockEnd) - closed
+		inspection.UnparsedBullets += bullets - entryBullets
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		title, ok := strings.CutPrefix(line, "## ")
+		if !ok {
+			continue
+		}
+		title = strings.TrimSpace(title)
+		known := false
+		for _, sectionType := range domain.ValidSections() {
+			if title == sectionType.Title() {
+				known = true
+				break
+			}
+		}
+		if !known && !slices.Contains(inspection.UnknownSections, title) {
+			inspection.UnknownSections = append(inspection.UnknownSections, title)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range ParseMemory(content) {
+		if entry.ID == "" {
+			continue
+		}
+		if seen[entry.ID] && !slices.Contains(inspection.DuplicateIDs, entry.ID) {
+			inspection.DuplicateIDs = append(inspection.DuplicateIDs, entry.ID)
+		}
+		seen[entry.ID] = true
+	}
+}
+
+// RepairStructure adds missing frontmatter, frontmatter keys and required section
+// headers without touching existing entries. It reports whether the file changed.
+func (r *MarkdownMemoryRepository) RepairStructure(ctx context.Context) (bool, error) {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return false, err
+	}
+	if content == "" {
+		if err := r.atomicWrite(r.createInitialContent()); err != nil {
+			return false, fmt.Errorf("failed to write memory file: %w", err)
+		}
+		return true, nil
+	}
+	original := content
+
+	if frontmatter, body, ok := splitFrontmatter(content); ok {
+		var fields map[string]any
+		if err := yaml.Unmarshal([]byte(frontmatter), &fields); err == nil {
+			var missing strings.Builder
+			if _, ok := fields[SchemaVersionKey]; !ok {
+				fmt.Fprintf(&missing, "%s: %q\n", SchemaVersionKey, CurrentSchemaVersion)
+			}
+			if _, ok := fields[EntryFormatKey]; !ok {
+				fmt.Fprintf(&missing, "%s: %q\n", EntryFormatKey, domain.FormatAnchored)
+			}
+			content = "---\n" + missing.String() + frontmatter + "---\n" + body
+		}
+	} else {
+		content = fmt.Sprintf("---\n%s: %q\n%s: %q\n---\n\n", SchemaVersionKey, CurrentSchemaVersion, EntryFormatKey, domain.FormatAnchored) + content
+	}
+
+	for _, sectionType := range domain.ValidSections() {
+		if sectionType == domain.SectionNote || findSectionStart(content, sectionType.Title()) != -1 {
+			continue
+		}
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += fmt.Sprintf("\n## %s\n", sectionType.Title())
+	}
+
+	if content == original {
+		return false, nil
+	}
+	if err := r.atomicWrite(content); err != nil {
+		return false, fmt.Errorf("failed to write memory file: %w", err)
+	}
+	return true, nil
+}
+
+// splitFrontmatter separates a leading "---" delimited block from the body.
+// The returned frontmatter excludes the delimiters and ends with a newline.
+func splitFrontmatter(content string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return "", content, false
+	}
+	if strings.HasPrefix(rest, "---\n") {
+		return "", rest[len("---\n"):], true
+	}
+	end := strings.Index(rest, "\n---\n")
+	if end == -1 {
+		if strings.HasSuffix(rest, "\n---") {
+			return rest[:len(rest)-len("---")], "", true
+		}
+		return "", content, false
+	}
+	return rest[:end+1], rest[end+len("\n---\n"):], true
+}
@@ -11,14 +11,14 @@ import (
 
 	"log/slog"
 
-	"github.com/gofrs/flock"
-
 	"github.com/herewei/ohmymem-core/internal/domain"
 )
 
 const (
-	DirName  = ".ohmymem"
-	FileName = "memory.md"
+	DirName        = ".ohmymem"
+	FileName       = "memory.md"
+	ArchiveDirName = "archive"
+	LockFileName   = ".memory.lock"
 )
 
 // MarkdownMemoryRepository implements MemoryRepository using Markdown file-based storage with flock
@@ -27,6 +27,8 @@ type MarkdownMemoryRepository struct {
 	uuidGenerator domain.UUIDGenerator
 	timeProvider  domain.TimeProvider
 	lockFilePath  string
+	lockRetries   int
+	lockBackoff   time.Duration
 }
 
 // NewMemoryRepository creates a new Markdown-based memory repository
@@ -35,7 +37,9 @@ func NewMemoryRepository(basePath string, uuidGenerator domain.UUIDGenerator, ti
 		basePath:      basePath,
 		uuidGenerator: uuidGenerator,
 		timeProvider:  timeProvider,
-		lockFilePath:  filepath.Join(basePath, DirName, ".memory.lock"),
+		lockFilePath:  filepath.Join(basePath, DirName, LockFileName),
+		lockRetries:   DefaultLockRetries,
+		lockBackoff:   DefaultLockBackoff,
 	}
 }
 
@@ -54,34 +58,21 @@ func (r *MarkdownMemoryRepository) EnsureDir() error {
 	return os.MkdirAll(r.DirPath(), 0755)
 }
 
-// acquireLock acquires an exclusive file lock using flock
+// acquireLock queues behind other writers in this process, then takes the
+// exclusive file lock shared with other processes, backing off while it is held
 func (r *MarkdownMemoryRepository) acquireLock(ctx context.Context) (func() error, error) {
 	// Ensure lock file exists
 	if err := r.EnsureDir(); err != nil {
 		return nil, err
 	}
 
-	fl := flock.New(r.lockFilePath)
-
-	// Try to acquire lock with context support
-	locked := make(chan struct{})
-	var lockErr error
-
-	go func() {
-		lockErr = fl.Lock()
-		close(locked)
-	}()
+	return lockWithBackoff(ctx, r.lockFilePath, r.lockRetries, r.lockBackoff)
+}
 
-	select {
-	case <-ctx.Done():
-		fl.Unlock()
-		return nil, ctx.Err()
-	case <-locked:
-		if lockErr != nil {
-			return nil, fmt.Errorf("failed to acquire lock: %w", lockErr)
-		}
-		return fl.Unlock, nil
-	}
+// SetLockRetry configures how often a busy lock is retried and the initial backoff between attempts
+func (r *MarkdownMemoryRepository) SetLockRetry(retries int, backoff time.Duration) {
+	r.lockRetries = retries
+	r.lockBackoff = backoff
 }
 
 // readFile reads the entire memory file
@@ -101,16 +92,21 @@ func (r *MarkdownMemoryRepository) readFile() (string, error) {
 	return string(data), nil
 }
 
-// atomicWrite writes content atomically using rename
+// atomicWrite writes the memory file atomically using rename
 func (r *MarkdownMemoryRepository) atomicWrite(content string) error {
-	tmpPath := r.FilePath() + ".tmp"
+	return atomicWriteFile(r.FilePath(), content)
+}
+
+// atomicWriteFile writes content to path atomically using rename
+func atomicWriteFile(path, content string) error {
+	tmpPath := path + ".tmp"
 
 	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
 		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
 
-	if err := os.Rename(tmpPath, r.FilePath()); err != nil {
+	if err := os.Rename(tmpPath, path); err != nil {
 		os.Remove(tmpPath)
 		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
@@ -137,39 +133,61 @@ func (r *MarkdownMemoryRepository) GetSection(ctx context.Context, sectionType d
 		}, nil
 	}
 
-	// Extract section content
-	sectionBlock := extractSection(content, string(sectionType))
+	return &domain.Section{
+		Type:    sectionType,
+		Entries: parseSectionEntries(content, sectionType),
+	}, nil
+}
 
-	if sectionBlock == "" {
-		return &domain.Section{
-			Type:    sectionType,
-			Entries: []domain.Entry{},
-		}, nil
+// ParseMemory parses the entries of every section of a memory file's content,
+// in section order then file order
+func ParseMemory(content string) []domain.Entry {
+	var entries []domain.Entry
+	for _, sectionType := range domain.ValidSections() {
+		entries = append(entries, parseSectionEntries(content, sectionType)...)
 	}
+	return entries
+}
 
-	// Try V1 anchored format first
-	entries, err := parseV1Anchored(sectionBlock)
-	if err == nil {
-		return &domain.Section{
-			Type:    sectionType,
-			Entries: entries,
-		}, nil
+// parseSectionEntries parses the anchored, template and legacy entries of one section
+// in file order. Blocks left open and anchored blocks that do not parse are skipped.
+func parseSectionEntries(content string, sectionType domain.SectionType) []domain.Entry {
+	lines := strings.Split(extractSection(content, sectionType), "\n")
+	entries := []domain.Entry{}
+	legacy := 0
+	for _, block := range scanBlocks(lines) {
+		if !block.closed {
+			continue
+		}
+		blockLines := lines[block.first : block.last+1]
+		switch block.kind {
+		case anchoredBlock:
+			if parsed, err := parseV1Anchored(strings.Join(blockLines, "\n")); err == nil {
+				entries = append(entries, parsed...)
+			}
+		case templateBlock:
+			if entry, ok := parseTemplateBlock(blockLines); ok {
+				entries = append(entries, entry)
+			}
+		default:
+			if entry, ok := parseLegacyBullet(blockLines[0]); ok {
+				entries = append(entries, entry)
+				legacy++
+			}
+		}
 	}
-
-	// Fallback to legacy format
-	legacyEntries := parseLegacyInline(sectionBlock)
-	if len(legacyEntries) > 0 {
-		slog.Warn("legacy format detected", "section", sectionType)
-		return &domain.Section{
-			Type:    sectionType,
-			Entries: legacyEntries,
-		}, nil
+	if legacy > 0 {
+		slog.Warn("legacy format detected", "section", sectionType, "entries", legacy)
 	}
+	return withSection(entries, sectionType)
+}
 
-	return &domain.Section{
-		Type:    sectionType,
-		Entries: []domain.Entry{},
-	}, nil
+// withSection records the section each parsed entry belongs to
+func withSection(entries []domain.Entry, sectionType domain.SectionType) []domain.Entry {
+	for i := range entries {
+		entries[i].Section = sectionType
+	}
+	return entries
 }
 
 // AppendEntry implements MemoryRepository with flock
@@ -196,29 +214,12 @@ func (r *MarkdownMemoryRepository) AppendEntry(ctx context.Context, sectionType
 		content = r.createInitialContent()
 	}
 
-	// Render the new entry
+	// Render the new entry and insert it at the end of its section
 	renderedEntry := renderEntry(entry)
-
-	// Find section position
-	section := capitalize(string(sectionType))
-	sectionStart := findSectionStart(content, section)
-	if sectionStart == -1 {
-		return fmt.Errorf("section not found: %s", section)
-	}
-
-	// Find section end
-	sectionEnd := findSectionEnd(content, sectionStart)
-
-	// Build new content
-	var newContent strings.Builder
-	newContent.WriteString(content[:sectionStart])
-	newContent.WriteString(content[sectionStart:sectionEnd])
-	newContent.WriteString(renderedEntry)
-	newContent.WriteString("\n")
-	newContent.WriteString(content[sectionEnd:])
+	newContent := insertIntoSection(content, sectionType.Title(), renderedEntry)
 
 	// Atomic write
-	if err := r.atomicWrite(newContent.String()); err != nil {
+	if err := r.atomicWrite(newContent); err != nil {
 		return fmt.Errorf("failed to write memory file: %w", err)
 	}
 
@@ -230,6 +231,281 @@ func (r *MarkdownMemoryRepository) AppendEntry(ctx context.Context, sectionType
 	return nil
 }
 
+// AppendEntries implements MemoryRepository: all entries are written under one lock
+// and one atomic write, so either all of them land or none do
+func (r *MarkdownMemoryRepository) AppendEntries(ctx context.Context, entries []domain.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return err
+	}
+	if content == "" {
+		content = r.createInitialContent()
+	}
+
+	for i := range entries {
+		section := entries[i].Section
+		if section == "" {
+			section = domain.SectionNote
+		}
+		content = insertIntoSection(content, section.Title(), renderEntry(&entries[i]))
+	}
+
+	if err := r.atomicWrite(content); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Debug("entries appended", "count", len(entries))
+
+	return nil
+}
+
+// ArchiveEntries implements MemoryRepository: moves entries into .ohmymem/archive/<archiveName>.md
+func (r *MarkdownMemoryRepository) ArchiveEntries(ctx context.Context, ids []string, archiveName string) ([]domain.Entry, error) {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	archivePath := r.ArchivePath(archiveName)
+	archiveContent := ""
+	if data, err := os.ReadFile(archivePath); err == nil {
+		archiveContent = string(data)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+	if archiveContent == "" {
+		archiveContent = fmt.Sprintf("# OhMyMem archive: %s\n", archiveName)
+	}
+
+	content, archived, blocks := cutEntries(content, ids)
+	for i, entry := range archived {
+		archiveContent = insertIntoSection(archiveContent, entry.Section.Title(), blocks[i])
+	}
+
+	if len(archived) == 0 {
+		return nil, nil
+	}
+
+	// Write the archive first so entries are never lost if the second write fails
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive dir: %w", err)
+	}
+	if err := atomicWriteFile(archivePath, archiveContent); err != nil {
+		return nil, fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := r.atomicWrite(content); err != nil {
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Debug("entries archived", "count", len(archived), "archive", archivePath)
+
+	return archived, nil
+}
+
+// UpdateEntry implements MemoryRepository: replaces the entry's anchored block in place
+func (r *MarkdownMemoryRepository) UpdateEntry(ctx context.Context, entry *domain.Entry) error {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return err
+	}
+
+	newContent, err := replaceEntry(content, entry)
+	if err != nil {
+		return err
+	}
+
+	if err := r.atomicWrite(newContent); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Debug("entry updated", "id", entry.ID)
+
+	return nil
+}
+
+// UpdateEntries implements MemoryRepository: all blocks are rewritten under one lock
+// and one atomic write, and nothing is written if any entry is missing
+func (r *MarkdownMemoryRepository) UpdateEntries(ctx context.Context, entries []domain.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if content, err = replaceEntry(content, &entries[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := r.atomicWrite(content); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Debug("entries updated", "count", len(entries))
+
+	return nil
+}
+
+// ApplyEntries implements MemoryRepository: entries are appended and updated under one
+// lock and one atomic write, so either all changes land or none do, and nothing is
+// written if an updated entry is missing
+func (r *MarkdownMemoryRepository) ApplyEntries(ctx context.Context, appended, updated []domain.Entry) error {
+	if len(appended) == 0 && len(updated) == 0 {
+		return nil
+	}
+
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return err
+	}
+	if content == "" {
+		content = r.createInitialContent()
+	}
+
+	for i := range updated {
+		if content, err = replaceEntry(content, &updated[i]); err != nil {
+			return err
+		}
+	}
+	for i := range appended {
+		section := appended[i].Section
+		if section == "" {
+			section = domain.SectionNote
+		}
+		content = insertIntoSection(content, section.Title(), renderEntry(&appended[i]))
+	}
+
+	if err := r.atomicWrite(content); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Debug("entries applied", "appended", len(appended), "updated", len(updated))
+
+	return nil
+}
+
+// Rewrite replaces the memory file with what fn returns for its current content,
+// under the write lock; nothing is written when fn returns the content unchanged
+func (r *MarkdownMemoryRepository) Rewrite(ctx context.Context, fn func(content string) (string, error)) error {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return err
+	}
+
+	updated, err := fn(content)
+	if err != nil || updated == content {
+		return err
+	}
+	if err := r.atomicWrite(updated); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	return nil
+}
+
+// DeleteEntries implements MemoryRepository
+func (r *MarkdownMemoryRepository) DeleteEntries(ctx context.Context, ids []string) ([]domain.Entry, error) {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	content, err := r.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	content, deleted, _ := cutEntries(content, ids)
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	if err := r.atomicWrite(content); err != nil {
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Debug("entries deleted", "count", len(deleted))
+
+	return deleted, nil
+}
+
+// ArchivePath returns the path of the named archive file
+func (r *MarkdownMemoryRepository) ArchivePath(archiveName string) string {
+	return filepath.Join(r.DirPath(), ArchiveDirName, archiveName+".md")
+}
+
 // createInitialContent creates a new memory file with front matter
 func (r *MarkdownMemoryRepository) createInitialContent() string {
 	now := r.timeProvider.Now().Format(time.RFC3339)
@@ -251,11 +527,67 @@ created_at: "%s"
 
 // Helper functions
 
-func capitalize(s string) string {
-	if s == "" {
-		return s
+// cutEntries removes the anchored blocks of the given entry IDs from content,
+// returning the new content, the removed entries and their blocks
+func cutEntries(content string, ids []string) (string, []domain.Entry, []string) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
 	}
-	return strings.ToUpper(s[:1]) + s[1:]
+
+	var removed []domain.Entry
+	var blocks []string
+	for _, sectionType := range domain.ValidSections() {
+		entries, err := parseV1Anchored(extractSection(content, sectionType))
+		if err != nil {
+			continue
+		}
+		for _, entry := range withSection(entries, sectionType) {
+			if !wanted[entry.ID] {
+				continue
+			}
+			start, end := domain.FindEntryBlock(content, entry.ID)
+			if start == -1 {
+				continue
+			}
+			blocks = append(blocks, strings.TrimRight(content[start:end], "\n"))
+			content = content[:start] + content[end:]
+			removed = append(removed, entry)
+		}
+	}
+	return content, removed, blocks
+}
+
+// replaceEntry replaces the anchored block of entry in content with its new rendering
+func replaceEntry(content string, entry *domain.Entry) (string, error) {
+	start, end := domain.FindEntryBlock(content, entry.ID)
+	if start == -1 {
+		return "", fmt.Errorf("%w: %s", domain.ErrEntryNotFound, entry.ID)
+	}
+	return content[:start] + renderEntry(entry) + "\n" + content[end:], nil
+}
+
+// insertIntoSection appends a rendered block at the end of the named section,
+// creating the section header at the end of the content if it is missing
+func insertIntoSection(content, section, block string) string {
+	sectionStart := findSectionStart(content, section)
+	if sectionStart == -1 {
+		// Sections like Note are optional; create the header at the end of the file
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += fmt.Sprintf("\n## %s\n\n", section)
+		sectionStart = findSectionStart(content, section)
+	}
+
+	sectionEnd := findSectionEnd(content, sectionStart)
+
+	var newContent strings.Builder
+	newContent.WriteString(content[:sectionEnd])
+	newContent.WriteString(block)
+	newContent.WriteString("\n")
+	newContent.WriteString(content[sectionEnd:])
+	return newContent.String()
 }
 
 func findSectionStart(content, section string) int {
@@ -277,8 +609,8 @@ func findSectionEnd(content string, start int) int {
 	return len(content)
 }
 
-func extractSection(content, sectionType string) string {
-	section := capitalize(sectionType)
+func extractSection(content string, sectionType domain.SectionType) string {
+	section := sectionType.Title()
 	start := findSectionStart(content, section)
 	if start == -1 {
 		return ""
@@ -294,13 +626,36 @@ func extractSection(content, sectionType string) string {
 	return strings.TrimPrefix(sectionContent, header)
 }
 
-// V1 Parser (anchored format)
+// rationalePattern captures the optional rationale suffix of a bullet: "(*Rationale: ...*)",
+// or "(*理由: ...*)" as written by templates
+const rationalePattern = `(?: \(\*(?:Rationale|理由):(.+?)\*\))?`
+
+// V1 Parser (anchored format). Decisions may carry decision record lines
+// ("  * *Context:* ...") between the bullet and the end marker.
 var anchoredEntryRegex = regexp.MustCompile(
-	`(?m)^<!-- entry-id: ([a-f0-9-]+), tag: \[([^\]]+)\], time: ([^\n]+) -->` + "\n" +
-		`^\* \*\*\[([^\]]+)\]\*\* (.+?)(?: ` + regexp.QuoteMeta("(*Rationale:") + `(.+?)` + regexp.QuoteMeta("*)") + `)?` + "\n" +
+	`(?m)^<!-- (entry-id: [^\n]*?) -->` + "\n" +
+		`^\* \*\*\[([^\]]+)\]\*\* (.+?)` + rationalePattern + "\n" +
+		`((?:^  \* \*[A-Za-z]+:\* [^\n]*\n)*)` +
 		`^<!-- entry-end -->$`,
 )
 
+// anchorKeyRegex finds the "key: " markers inside anchor metadata
+var anchorKeyRegex = regexp.MustCompile(`(?:^|, )([a-z_-]+): `)
+
+// parseAnchorMeta splits "entry-id: X, tag: [T], time: Y" into its fields
+func parseAnchorMeta(meta string) map[string]string {
+	locs := anchorKeyRegex.FindAllStringSubmatchIndex(meta, -1)
+	fields := make(map[string]string, len(locs))
+	for i, loc := range locs {
+		end := len(meta)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		fields[meta[loc[2]:loc[3]]] = strings.TrimSpace(meta[loc[1]:end])
+	}
+	return fields
+}
+
 func parseV1Anchored(block string) ([]domain.Entry, error) {
 	matches := anchoredEntryRegex.FindAllStringSubmatch(block, -1)
 	if matches == nil {
@@ -313,59 +668,92 @@ func parseV1Anchored(block string) ([]domain.Entry, error) {
 			continue
 		}
 
+		meta := parseAnchorMeta(match[1])
 		tag := match[2]
+		createdAt, _ := time.Parse(time.RFC3339, meta["time"])
+		expiresAt, _ := time.Parse(time.RFC3339, meta["expires"])
 		entries = append(entries, domain.Entry{
-			ID:        match[1],
-			Tag:       "[" + tag + "]",
-			TagName:   tag,
-			Content:   match[3],
-			Rationale: match[4],
+			ID:           meta["entry-id"],
+			Tag:          "[" + tag + "]",
+			TagName:      tag,
+			Content:      domain.UnescapeContent(match[3]),
+			Rationale:    domain.UnescapeContent(strings.TrimSpace(match[4])),
+			CreatedAt:    createdAt,
+			ExpiresAt:    expiresAt,
+			Status:       domain.EntryStatus(meta["status"]),
+			SupersededBy: meta["superseded_by"],
+			Scope:        strings.Fields(meta["scope"]),
+			Author:       meta["author"],
+			ADR:          parseDecisionRecord(match[5]),
 		})
 	}
 
 	return entries, nil
 }
 
+// parseDecisionRecord reads the decision record lines of an anchored block
+func parseDecisionRecord(lines string) domain.DecisionRecord {
+	var record domain.DecisionRecord
+	for _, line := range strings.Split(lines, "\n") {
+		domain.ParseDecisionLine(&record, line)
+	}
+	return record
+}
+
 // Legacy Parser (inline format)
 var legacyEntryRegex = regexp.MustCompile(
-	`^\* \*\*\[([^\]]+)\]\*\* (.+?)(?: ` + regexp.QuoteMeta("(*Rationale:") + `(.+?)` + regexp.QuoteMeta("*)") + `)?$`,
+	`^\* \*\*\[([^\]]+)\]\*\* (.+?)` + rationalePattern + `$`,
 )
 
-func parseLegacyInline(block string) []domain.Entry {
-	lines := strings.Split(block, "\n")
-	var entries []domain.Entry
+// parseLegacyBullet parses a "* **[Tag]** content (*Rationale: r*)" line into an entry
+// without an ID
+func parseLegacyBullet(line string) (domain.Entry, bool) {
+	match := legacyEntryRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return domain.Entry{}, false
+	}
+	return domain.Entry{
+		Tag:       "[" + match[1] + "]",
+		TagName:   match[1],
+		Content:   domain.UnescapeContent(match[2]),
+		Rationale: domain.UnescapeContent(strings.TrimSpace(match[3])),
+	}, true
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "* **[") {
-			if match := legacyEntryRegex.FindStringSubmatch(line); match != nil {
-				entries = append(entries, domain.Entry{
-					Tag:       "[" + match[1] + "]",
-					TagName:   match[1],
-					Content:   match[2],
-					Rationale: match[3],
-				})
+// parseTemplateBlock parses a block injected from a template: its
+// "<!-- template-entry, tag: [T], source: S -->" anchor and its bullet
+func parseTemplateBlock(lines []string) (domain.Entry, bool) {
+	anchor := strings.TrimSpace(lines[0])
+	anchor = strings.TrimSuffix(strings.TrimPrefix(anchor, "<!-- "), " -->")
+	meta := parseAnchorMeta(anchor)
+	for _, line := range lines[1:] {
+		if entry, ok := parseLegacyBullet(line); ok {
+			entry.Template = meta["source"]
+			if entry.Template == "" {
+				entry.Template = "unknown"
 			}
+			return entry, true
 		}
 	}
-
-	return entries
+	return domain.Entry{}, false
 }
 
 // renderEntry renders an entry to the 4-line anchored format
 func renderEntry(entry *domain.Entry) string {
 	var buf strings.Builder
 
-	buf.WriteString(fmt.Sprintf("<!-- entry-id: %s, tag: %s, time: %s -->\n",
-		entry.ID, entry.Tag, entry.CreatedAt.Format(time.RFC3339)))
+	buf.WriteString(fmt.Sprintf("<!-- entry-id: %s, tag: %s, time: %s%s -->\n",
+		entry.ID, entry.Tag, entry.CreatedAt.Format(time.RFC3339), domain.AnchorExtras(*entry)))
 
-	buf.WriteString(fmt.Sprintf("* **[%s]** %s", entry.TagName, entry.Content))
+	buf.WriteString(fmt.Sprintf("* **[%s]** %s", entry.TagName, domain.EscapeContent(entry.Content)))
 
 	if entry.Rationale != "" {
-		buf.WriteString(fmt.Sprintf(" (*Rationale: %s*)", entry.Rationale))
+		buf.WriteString(fmt.Sprintf(" (*Rationale: %s*)", domain.EscapeContent(entry.Rationale)))
 	}
 
-	buf.WriteString("\n<!-- entry-end -->")
+	buf.WriteString("\n")
+	buf.WriteString(domain.RenderDecisionRecord(entry.ADR))
+	buf.WriteString("<!-- entry-end -->")
 
 	return buf.String()
 }
@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ApplyMerge applies the changes a merge takes from theirs to our memory file content,
+// leaving everything else in ours (frontmatter, order, prose) untouched
+func ApplyMerge(content string, result domain.MergeResult) string {
+	removed := make([]string, 0, len(result.Removed))
+	for _, entry := range result.Removed {
+		removed = append(removed, entry.ID)
+	}
+	content, _, _ = cutEntries(content, removed)
+
+	for _, entry := range result.Updated {
+		start, end := domain.FindEntryBlock(content, entry.ID)
+		if start == -1 {
+			continue
+		}
+		previous := parseSectionOf(content, entry.ID)
+		if previous == entry.Section {
+			content = content[:start] + renderEntry(&entry) + "\n" + content[end:]
+			continue
+		}
+		content = content[:start] + content[end:]
+		content = insertIntoSection(content, sectionOrNote(entry.Section).Title(), renderEntry(&entry))
+	}
+
+	for _, entry := range result.Added {
+		block := renderLegacy(entry)
+		switch {
+		case entry.ID != "":
+			block = renderEntry(&entry)
+		case entry.Template != "":
+			block = renderTemplateEntry(entry)
+		}
+		content = insertIntoSection(content, sectionOrNote(entry.Section).Title(), block)
+	}
+	return content
+}
+
+// parseSectionOf returns the section holding the entry with the given ID
+func parseSectionOf(content, id string) domain.SectionType {
+	for _, entry := range ParseMemory(content) {
+		if entry.ID == id {
+			return entry.Section
+		}
+	}
+	return ""
+}
+
+// sectionOrNote defaults an empty section to Note
+func sectionOrNote(section domain.SectionType) domain.SectionType {
+	if section == "" {
+		return domain.SectionNote
+	}
+	return section
+}
+
+// renderLegacy renders an entry without ID as a legacy inline bullet
+func renderLegacy(entry domain.Entry) string {
+	line := fmt.Sprintf("* **[%s]** %s", entry.TagName, domain.EscapeContent(entry.Content))
+	if entry.Rationale != "" {
+		line += fmt.Sprintf(" (*Rationale: %s*)", domain.EscapeContent(entry.Rationale))
+	}
+	return strings.TrimSpace(line)
+}
+
+// renderTemplateEntry renders an entry injected from a template as a template block
+func renderTemplateEntry(entry domain.Entry) string {
+	line := fmt.Sprintf("* **[%s]** %s", entry.TagName, domain.EscapeContent(entry.Content))
+	if entry.Rationale != "" {
+		line += fmt.Sprintf(" (*理由: %s*)", domain.EscapeContent(entry.Rationale))
+	}
+	return fmt.Sprintf("<!-- template-entry, tag: %s, source: %s -->\n%s\n%s", entry.Tag, entry.Template, line, entryBlockEnd)
+}
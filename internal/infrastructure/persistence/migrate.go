@@ -0,0 +1,184 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// BackupDirName holds copies of the memory file taken before migrations
+const BackupDirName = "backups"
+
+// frontmatterKeyRegex matches a top-level "key: value" frontmatter line
+var frontmatterKeyRegex = regexp.MustCompile(`(?m)^([a-z_]+):.*$`)
+
+// MigrationResult describes a migration of the memory file to the anchored format
+type MigrationResult struct {
+	Entries        []domain.Entry // Legacy entries converted to anchored blocks
+	SchemaUpgraded bool           // Frontmatter was added or updated
+	BackupPath     string         // Copy of the original file; empty on dry runs or when nothing changed
+}
+
+// Changed reports whether the migration rewrites the file
+func (m *MigrationResult) Changed() bool {
+	return len(m.Entries) > 0 || m.SchemaUpgraded
+}
+
+// MigrateLegacy converts legacy inline bullets to anchored entries with new IDs and the
+// current time, and declares the current schema in the frontmatter. The original file
+// is copied into .ohmymem/backups/ before it is rewritten. A dry run only reports.
+func (r *MarkdownMemoryRepository) MigrateLegacy(ctx context.Context, dryRun bool) (*MigrationResult, error) {
+	unlock, err := r.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			slog.Error("failed to unlock file", "error", err)
+		}
+	}()
+
+	original, err := r.readFile()
+	if err != nil {
+		return nil, err
+	}
+	if original == "" {
+		return &MigrationResult{}, nil
+	}
+
+	now := r.timeProvider.Now()
+	content, entries, err := r.anchorLegacyEntries(original, now)
+	if err != nil {
+		return nil, err
+	}
+	content = upgradeFrontmatter(content)
+
+	result := &MigrationResult{
+		Entries:        entries,
+		SchemaUpgraded: frontmatterOf(content) != frontmatterOf(original),
+	}
+	if dryRun || !result.Changed() {
+		return result, nil
+	}
+
+	backupPath := filepath.Join(r.DirPath(), BackupDirName, "memory-"+now.UTC().Format("20060102T150405Z")+".md")
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return nil, fmt.Errorf("create backup directory: %w", err)
+	}
+	if err := os.WriteFile(backupPath, []byte(original), 0644); err != nil {
+		return nil, fmt.Errorf("write backup: %w", err)
+	}
+	result.BackupPath = backupPath
+
+	if err := r.atomicWrite(content); err != nil {
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	slog.Info("memory file migrated", "entries", len(entries), "backup", backupPath)
+
+	return result, nil
+}
+
+// anchorLegacyEntries replaces every legacy bullet inside a known section with a
+// rendered anchored block. Bullets of anchored and template blocks are left alone.
+func (r *MarkdownMemoryRepository) anchorLegacyEntries(content string, now time.Time) (string, []domain.Entry, error) {
+	lines := strings.Split(content, "\n")
+	var entries []domain.Entry
+
+	for _, block := range scanBlocks(lines) {
+		if !block.isLegacyBullet() {
+			continue
+		}
+		section := sectionOfLine(lines, block.first)
+		if section == "" {
+			continue
+		}
+		i := block.first
+		match := legacyEntryRegex.FindStringSubmatch(strings.TrimSpace(lines[i]))
+
+		id, err := r.uuidGenerator.NewV7()
+		if err != nil {
+			return "", nil, fmt.Errorf("generate ID: %w", err)
+		}
+		entry := domain.Entry{
+			ID:        id,
+			Tag:       "[" + match[1] + "]",
+			TagName:   match[1],
+			Content:   domain.UnescapeContent(match[2]),
+			Rationale: domain.UnescapeContent(strings.TrimSpace(match[3])),
+			CreatedAt: now,
+			Section:   section,
+		}
+		lines[i] = renderEntry(&entry)
+		entries = append(entries, entry)
+	}
+
+	return strings.Join(lines, "\n"), entries, nil
+}
+
+// sectionOfLine returns the section whose header is the nearest above line i, or ""
+// when that header names no known section
+func sectionOfLine(lines []string, i int) domain.SectionType {
+	for ; i >= 0; i-- {
+		title, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), "## ")
+		if !ok {
+			continue
+		}
+		for _, candidate := range domain.ValidSections() {
+			if candidate.Title() == strings.TrimSpace(title) {
+				return candidate
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// upgradeFrontmatter sets schema_version and entry_format to the current values,
+// adding a frontmatter block when the file has none
+func upgradeFrontmatter(content string) string {
+	want := map[string]string{
+		SchemaVersionKey: fmt.Sprintf("%q", CurrentSchemaVersion),
+		EntryFormatKey:   fmt.Sprintf("%q", domain.FormatAnchored),
+	}
+
+	frontmatter, body, ok := splitFrontmatter(content)
+	if !ok {
+		return fmt.Sprintf("---\n%s: %s\n%s: %s\n---\n\n", SchemaVersionKey, want[SchemaVersionKey], EntryFormatKey, want[EntryFormatKey]) + content
+	}
+
+	seen := make(map[string]bool, len(want))
+	frontmatter = frontmatterKeyRegex.ReplaceAllStringFunc(frontmatter, func(line string) string {
+		key := line[:strings.Index(line, ":")]
+		value, managed := want[key]
+		if !managed {
+			return line
+		}
+		seen[key] = true
+		return key + ": " + value
+	})
+
+	var missing strings.Builder
+	for _, key := range []string{SchemaVersionKey, EntryFormatKey} {
+		if !seen[key] {
+			missing.WriteString(key + ": " + want[key] + "\n")
+		}
+	}
+	return "---\n" + missing.String() + frontmatter + "---\n" + body
+}
+
+// frontmatterOf returns the frontmatter block of content, or "" when it has none
+func frontmatterOf(content string) string {
+	frontmatter, _, ok := splitFrontmatter(content)
+	if !ok {
+		return ""
+	}
+	return "---\n" + frontmatter
+}
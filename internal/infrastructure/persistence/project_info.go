@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// ProjectFileName is the file under .ohmymem holding the detected project stack
+const ProjectFileName = "project.yaml"
+
+// projectFile is the YAML layout of project.yaml
+type projectFile struct {
+	DetectedAt  time.Time          `yaml:"detected_at"`
+	Language    string             `yaml:"language"`
+	Framework   string             `yaml:"framework,omitempty"`
+	Database    string             `yaml:"database,omitempty"`
+	ProjectType string             `yaml:"project_type,omitempty"`
+	Features    []string           `yaml:"features,omitempty"`
+	Modules     []string           `yaml:"modules,omitempty"`
+	Secondary   []projectStack     `yaml:"secondary,omitempty"`
+	Candidates  []projectCandidate `yaml:"candidates,omitempty"`
+}
+
+// projectStack is a secondary stack in project.yaml
+type projectStack struct {
+	Language    string `yaml:"language"`
+	Framework   string `yaml:"framework,omitempty"`
+	Database    string `yaml:"database,omitempty"`
+	ProjectType string `yaml:"project_type,omitempty"`
+	Path        string `yaml:"path"`
+}
+
+// projectCandidate is a scored detection candidate in project.yaml
+type projectCandidate struct {
+	Kind       string  `yaml:"kind"`
+	Value      string  `yaml:"value"`
+	Confidence float64 `yaml:"confidence"`
+	Source     string  `yaml:"source,omitempty"`
+}
+
+// ProjectFilePath returns the path of project.yaml for the project at rootPath
+func ProjectFilePath(rootPath string) string {
+	return filepath.Join(rootPath, ".ohmymem", ProjectFileName)
+}
+
+// MarshalProjectInfo renders the detected stack as project.yaml content
+func MarshalProjectInfo(info *domain.ProjectInfo, detectedAt time.Time) ([]byte, error) {
+	file := projectFile{
+		DetectedAt:  detectedAt.UTC().Truncate(time.Second),
+		Language:    info.Language,
+		Framework:   info.Framework,
+		Database:    info.Database,
+		ProjectType: info.ProjectType,
+		Features:    info.Features,
+		Modules:     info.Modules,
+	}
+	for _, stack := range info.Secondary {
+		file.Secondary = append(file.Secondary, projectStack(stack))
+	}
+	for _, c := range info.Candidates {
+		file.Candidates = append(file.Candidates, projectCandidate(c))
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", ProjectFileName, err)
+	}
+	header := "# Project stack detected by ohmymem; edit to correct it, or re-detect with 'ohmymem template update --redetect'\n"
+	return append([]byte(header), data...), nil
+}
+
+// SaveProjectInfo writes the detected stack to .ohmymem/project.yaml under rootPath
+func SaveProjectInfo(rootPath string, info *domain.ProjectInfo) error {
+	data, err := MarshalProjectInfo(info, time.Now())
+	if err != nil {
+		return err
+	}
+	path := ProjectFilePath(rootPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", ProjectFileName, err)
+	}
+	return nil
+}
+
+// LoadProjectInfo reads the stack saved in .ohmymem/project.yaml under rootPath and
+// when it was detected. The error wraps os.ErrNotExist when the file is missing.
+func LoadProjectInfo(rootPath string) (*domain.ProjectInfo, time.Time, error) {
+	data, err := os.ReadFile(ProjectFilePath(rootPath))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("read %s: %w", ProjectFileName, err)
+	}
+	var file projectFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, time.Time{}, fmt.Errorf("parse %s: %w", ProjectFileName, err)
+	}
+	info := &domain.ProjectInfo{
+		Language:    file.Language,
+		Framework:   file.Framework,
+		Database:    file.Database,
+		ProjectType: file.ProjectType,
+		Features:    file.Features,
+		Modules:     file.Modules,
+		RootPath:    rootPath,
+	}
+	for _, stack := range file.Secondary {
+		info.Secondary = append(info.Secondary, domain.ProjectStack(stack))
+	}
+	for _, c := range file.Candidates {
+		info.Candidates = append(info.Candidates, domain.DetectionCandidate(c))
+	}
+	return info, file.DetectedAt, nil
+}
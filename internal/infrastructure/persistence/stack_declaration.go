@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// StackFileName is the file under .ohmymem where users declare their stack
+const StackFileName = "stack.yaml"
+
+// stackFile is the YAML layout of stack.yaml
+type stackFile struct {
+	Language       string         `yaml:"language"`
+	Framework      string         `yaml:"framework"`
+	Database       string         `yaml:"database"`
+	ProjectType    string         `yaml:"project_type"`
+	Features       []string       `yaml:"features"`
+	IgnoreFeatures []string       `yaml:"ignore_features"`
+	Secondary      []projectStack `yaml:"secondary"`
+}
+
+// StackFilePath returns the path of stack.yaml for the project at rootPath
+func StackFilePath(rootPath string) string {
+	return filepath.Join(rootPath, ".ohmymem", StackFileName)
+}
+
+// LoadStackDeclaration reads the stack declared in .ohmymem/stack.yaml under rootPath.
+// The error wraps os.ErrNotExist when the file is missing.
+func LoadStackDeclaration(rootPath string) (domain.StackDeclaration, error) {
+	data, err := os.ReadFile(StackFilePath(rootPath))
+	if err != nil {
+		return domain.StackDeclaration{}, fmt.Errorf("read %s: %w", StackFileName, err)
+	}
+	var file stackFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return domain.StackDeclaration{}, fmt.Errorf("parse %s: %w", StackFileName, err)
+	}
+
+	normalize := func(value string) string { return strings.ToLower(strings.TrimSpace(value)) }
+	declaration := domain.StackDeclaration{
+		ProjectOverrides: domain.ProjectOverrides{
+			Language:    normalize(file.Language),
+			Framework:   normalize(file.Framework),
+			Database:    normalize(file.Database),
+			ProjectType: normalize(file.ProjectType),
+		},
+		Features:       file.Features,
+		IgnoreFeatures: file.IgnoreFeatures,
+	}
+	if declaration.ProjectType != "" && !slices.Contains(domain.ProjectTypes, declaration.ProjectType) {
+		return declaration, fmt.Errorf("%s: unknown project type %q (expected %s)", StackFileName, file.ProjectType, strings.Join(domain.ProjectTypes, ", "))
+	}
+	for _, stack := range file.Secondary {
+		if stack.Language == "" {
+			return declaration, fmt.Errorf("%s: secondary stack at %q has no language", StackFileName, stack.Path)
+		}
+		declaration.Secondary = append(declaration.Secondary, domain.ProjectStack(stack))
+	}
+	return declaration, nil
+}
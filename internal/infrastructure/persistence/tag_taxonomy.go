@@ -0,0 +1,55 @@
+package persistence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// TagsFileName is the file under .ohmymem where users extend the tag taxonomy
+const TagsFileName = "tags.yaml"
+
+// tagsFile is the YAML layout of tags.yaml
+type tagsFile struct {
+	Tags []domain.TagDefinition `yaml:"tags"`
+}
+
+// TagsFilePath returns the path of tags.yaml for the project at rootPath
+func TagsFilePath(rootPath string) string {
+	return filepath.Join(rootPath, DirName, TagsFileName)
+}
+
+// LoadTagTaxonomy reads the canonical tags and aliases declared in .ohmymem/tags.yaml
+// under rootPath. The error wraps os.ErrNotExist when the file is missing.
+func LoadTagTaxonomy(rootPath string) ([]domain.TagDefinition, error) {
+	data, err := os.ReadFile(TagsFilePath(rootPath))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", TagsFileName, err)
+	}
+	var file tagsFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse %s: %w", TagsFileName, err)
+	}
+
+	for _, definition := range file.Tags {
+		for _, tag := range append([]string{definition.Name}, definition.Aliases...) {
+			if err := domain.ValidateTag(strings.TrimSpace(tag)); err != nil {
+				return nil, fmt.Errorf("%s: %w", TagsFileName, err)
+			}
+			if strings.ContainsAny(tag, "[],") {
+				return nil, fmt.Errorf("%s: %w: %q cannot contain brackets or commas", TagsFileName, domain.ErrInvalidTag, tag)
+			}
+		}
+	}
+	return file.Tags, nil
+}
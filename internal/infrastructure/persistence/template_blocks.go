@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// templateBlockRegex matches a block injected from a template, with the blank lines after it
+var templateBlockRegex = regexp.MustCompile(`(?s)<!-- template-entry[^\n]*-->\n.*?<!-- entry-end -->\n*`)
+
+// TemplateBlock is an entry block injected from a template
+type TemplateBlock struct {
+	Section domain.SectionType
+	Text    string // From the template-entry anchor to the entry-end marker
+}
+
+// TemplateBlocks returns the template blocks of memory file content, each in the
+// section of the nearest header above it
+func TemplateBlocks(content string) []TemplateBlock {
+	var blocks []TemplateBlock
+	for _, loc := range templateBlockRegex.FindAllStringIndex(content, -1) {
+		blocks = append(blocks, TemplateBlock{
+			Section: sectionAt(content, loc[0]),
+			Text:    strings.TrimRight(content[loc[0]:loc[1]], "\n"),
+		})
+	}
+	return blocks
+}
+
+// ReplaceTemplateBlocks removes every template block from content and inserts blocks
+// at the top of their sections, leaving captured entries and other text untouched
+func ReplaceTemplateBlocks(content string, blocks []TemplateBlock) string {
+	content = templateBlockRegex.ReplaceAllString(content, "")
+
+	for _, sectionType := range domain.ValidSections() {
+		var texts []string
+		for _, block := range blocks {
+			if block.Section == sectionType {
+				texts = append(texts, block.Text+"\n\n")
+			}
+		}
+		if len(texts) == 0 {
+			continue
+		}
+
+		start := findSectionStart(content, sectionType.Title())
+		if start == -1 {
+			content = insertIntoSection(content, sectionType.Title(), strings.TrimSuffix(strings.Join(texts, ""), "\n"))
+			continue
+		}
+		// Insert after the header line and the blank lines following it
+		pos := start + strings.IndexByte(content[start:]+"\n", '\n') + 1
+		if pos > len(content) {
+			content += "\n"
+		}
+		for pos < len(content) && content[pos] == '\n' {
+			pos++
+		}
+		prefix := content[:pos]
+		if !strings.HasSuffix(prefix, "\n\n") {
+			prefix += "\n"
+		}
+		content = prefix + strings.Join(texts, "") + content[pos:]
+	}
+	return content
+}
+
+// sectionAt returns the section whose header is the nearest above offset
+func sectionAt(content string, offset int) domain.SectionType {
+	best, section := -1, domain.SectionType("")
+	for _, sectionType := range domain.ValidSections() {
+		header := "## " + sectionType.Title() + "\n"
+		if i := strings.LastIndex(content[:offset], header); i > best {
+			best, section = i, sectionType
+		}
+	}
+	return section
+}
@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// watchDebounce coalesces the burst of events one atomic write produces
+const watchDebounce = 100 * time.Millisecond
+
+// Watch calls onChange with the parsed entries each time the memory file changes,
+// until ctx is cancelled. The directory is watched rather than the file, because
+// atomic writes replace the file by renaming over it.
+func (r *MarkdownMemoryRepository) Watch(ctx context.Context, onChange func([]domain.Entry)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(r.DirPath()); err != nil {
+		return fmt.Errorf("watch %s: %w", r.DirPath(), err)
+	}
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Base(event.Name) == FileName && event.Has(fsnotify.Create|fsnotify.Write|fsnotify.Rename|fsnotify.Remove) {
+				settle = time.After(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watch memory file: %w", err)
+		case <-settle:
+			settle = nil
+			content, err := r.readFile()
+			if err != nil {
+				return err
+			}
+			onChange(ParseMemory(content))
+		}
+	}
+}
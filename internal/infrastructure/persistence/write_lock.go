@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// Defaults for cross-process lock contention
+const (
+	DefaultLockRetries    = 8
+	DefaultLockBackoff    = 25 * time.Millisecond
+	maxLockBackoffPerWait = 500 * time.Millisecond
+)
+
+// writeQueues serializes writers within this process, one slot per lock file
+var writeQueues sync.Map // lock path -> chan struct{}
+
+// writeQueue returns the single-slot queue for a lock file
+func writeQueue(lockPath string) chan struct{} {
+	queue, _ := writeQueues.LoadOrStore(lockPath, make(chan struct{}, 1))
+	return queue.(chan struct{})
+}
+
+// lockWithBackoff takes the in-process write slot, then the cross-process flock,
+// retrying with exponential backoff. It returns domain.ErrMemoryBusy once retries run out.
+func lockWithBackoff(ctx context.Context, lockPath string, retries int, backoff time.Duration) (func() error, error) {
+	queue := writeQueue(lockPath)
+	select {
+	case queue <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	releaseQueue := func() { <-queue }
+
+	fl := flock.New(lockPath)
+	wait := backoff
+	for attempt := 0; ; attempt++ {
+		locked, err := fl.TryLock()
+		if err != nil {
+			releaseQueue()
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if locked {
+			return func() error {
+				defer releaseQueue()
+				return fl.Unlock()
+			}, nil
+		}
+		if attempt >= retries {
+			releaseQueue()
+			return nil, fmt.Errorf("%w: another process holds %s, retried %d times", domain.ErrMemoryBusy, lockPath, retries)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			releaseQueue()
+			return nil, ctx.Err()
+		}
+		wait = min(wait*2, maxLockBackoffPerWait)
+	}
+}
@@ -0,0 +1,112 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// gitFileName is the path of the memory file inside the sync branch
+const gitFileName = "memory.md"
+
+// gitTrackingRef keeps the last fetched sync commit without touching the user's branches
+const gitTrackingRef = "refs/ohmymem/sync"
+
+// GitRemote stores the memory file alone on a dedicated branch of a git remote, so
+// it can be shared without pushing the project's own branches
+type GitRemote struct {
+	dir    string
+	remote string
+	branch string
+}
+
+// NewGitRemote returns a remote syncing through branch of the git remote (name or
+// URL) of the repository at dir
+func NewGitRemote(dir, remote, branch string) *GitRemote {
+	return &GitRemote{dir: dir, remote: remote, branch: branch}
+}
+
+// String implements Remote
+func (g *GitRemote) String() string {
+	return g.remote + " (branch " + g.branch + ")"
+}
+
+// Pull implements Remote; the version is the commit of the sync branch
+func (g *GitRemote) Pull(ctx context.Context) (Snapshot, error) {
+	_, err := g.git(ctx, nil, "fetch", "--quiet", "--no-tags", "--", g.remote, "+refs/heads/"+g.branch+":"+gitTrackingRef)
+	if err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			return Snapshot{}, nil
+		}
+		return Snapshot{}, err
+	}
+
+	commit, err := g.gitObject(ctx, nil, "rev-parse", gitTrackingRef)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	content, err := g.git(ctx, nil, "show", commit+":"+gitFileName)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{Content: content, Version: commit}, nil
+}
+
+// Push implements Remote by committing content on top of version and pushing it;
+// git rejects the push as non-fast-forward when someone else pushed meanwhile
+func (g *GitRemote) Push(ctx context.Context, content, version string) error {
+	blob, err := g.gitObject(ctx, strings.NewReader(content), "hash-object", "-w", "--stdin")
+	if err != nil {
+		return err
+	}
+	tree, err := g.gitObject(ctx, strings.NewReader("100644 blob "+blob+"\t"+gitFileName+"\n"), "mktree")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"commit-tree", tree, "-m", "ohmymem sync"}
+	if version != "" {
+		args = append(args, "-p", version)
+	}
+	commit, err := g.gitObject(ctx, nil, args...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := g.git(ctx, nil, "push", "--quiet", "--", g.remote, commit+":refs/heads/"+g.branch); err != nil {
+		msg := err.Error()
+		if strings.Contains(msg, "non-fast-forward") || strings.Contains(msg, "fetch first") || strings.Contains(msg, "rejected") {
+			return fmt.Errorf("%w: %s", ErrRemoteChanged, g)
+		}
+		return err
+	}
+	_, err = g.git(ctx, nil, "update-ref", gitTrackingRef, commit)
+	return err
+}
+
+// git runs a git command in the repository and returns its output
+func (g *GitRemote) git(ctx context.Context, stdin io.Reader, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.dir
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// gitObject runs a git command printing an object name and returns the name
+func (g *GitRemote) gitObject(ctx context.Context, stdin io.Reader, args ...string) (string, error) {
+	out, err := g.git(ctx, stdin, args...)
+	return strings.TrimSpace(out), err
+}
@@ -0,0 +1,98 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// httpTimeout bounds each request to an HTTP remote
+const httpTimeout = 30 * time.Second
+
+// HTTPRemote stores the memory file at a URL: GET returns it with an ETag (404 when
+// there is none yet) and PUT replaces it, honouring If-Match and If-None-Match
+type HTTPRemote struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPRemote returns a remote at url, authenticating with token when it is set
+func NewHTTPRemote(url, token string) *HTTPRemote {
+	return &HTTPRemote{url: url, token: token, client: &http.Client{Timeout: httpTimeout}}
+}
+
+// String implements Remote
+func (h *HTTPRemote) String() string {
+	return h.url
+}
+
+// Pull implements Remote; the version is the ETag of the response
+func (h *HTTPRemote) Pull(ctx context.Context) (Snapshot, error) {
+	resp, err := h.do(ctx, http.MethodGet, nil, nil)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("read %s: %w", h.url, err)
+		}
+		return Snapshot{Content: string(body), Version: resp.Header.Get("ETag")}, nil
+	case http.StatusNotFound:
+		return Snapshot{}, nil
+	default:
+		return Snapshot{}, fmt.Errorf("GET %s: %s", h.url, resp.Status)
+	}
+}
+
+// Push implements Remote
+func (h *HTTPRemote) Push(ctx context.Context, content, version string) error {
+	header := http.Header{}
+	header.Set("Content-Type", "text/markdown; charset=utf-8")
+	if version != "" {
+		header.Set("If-Match", version)
+	} else {
+		header.Set("If-None-Match", "*")
+	}
+
+	resp, err := h.do(ctx, http.MethodPut, header, strings.NewReader(content))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return fmt.Errorf("%w: %s", ErrRemoteChanged, h.url)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("PUT %s: %s", h.url, resp.Status)
+	}
+	return nil
+}
+
+// do sends a request to the remote URL
+func (h *HTTPRemote) do(ctx context.Context, method string, header http.Header, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, h.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, h.url, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,47 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRemoteChanged is returned by Push when the remote moved past the pulled version
+var ErrRemoteChanged = errors.New("remote changed since it was pulled")
+
+// Snapshot is the memory file as stored on a remote
+type Snapshot struct {
+	Content string
+	Version string // Opaque; empty when the remote has no memory file yet
+}
+
+// Remote stores a shared copy of the memory file
+type Remote interface {
+	// Pull returns the current memory file of the remote
+	Pull(ctx context.Context) (Snapshot, error)
+
+	// Push replaces the remote memory file with content, provided the remote is still
+	// at version; otherwise it returns ErrRemoteChanged
+	Push(ctx context.Context, content, version string) error
+
+	// String describes the remote for messages
+	String() string
+}
+
+// New returns the remote for a configured location: an http(s) URL is an HTTP
+// endpoint, anything else a git remote name or URL of the repository at basePath.
+// Locations starting with a dash are refused, as git would read them as options.
+func New(basePath, location, branch, token string) (Remote, error) {
+	location = strings.TrimSpace(location)
+	switch {
+	case location == "":
+		return nil, fmt.Errorf("no sync remote configured, pass --remote or run 'ohmymem config set sync.remote <remote>'")
+	case strings.HasPrefix(location, "-"):
+		return nil, fmt.Errorf("invalid sync remote %q: must not start with '-'", location)
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return NewHTTPRemote(location, token), nil
+	default:
+		return NewGitRemote(basePath, location, branch), nil
+	}
+}
@@ -0,0 +1,294 @@
+package template
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxArchiveSize bounds the download and the extracted size of a template archive
+const maxArchiveSize = 100 << 20
+
+// errArchiveTooLarge is returned once extracting an archive writes more than maxArchiveSize
+var errArchiveTooLarge = fmt.Errorf("archive larger than %d MB when extracted", maxArchiveSize>>20)
+
+// Archive formats of template repositories served over HTTP
+const (
+	formatTarGz = "tar.gz"
+	formatZip   = "zip"
+)
+
+// archiveFormat returns the archive format of an http(s) repository URL: a
+// .tar.gz, .tgz or .zip file, or a GitHub codeload URL such as
+// https://codeload.github.com/owner/repo/tar.gz/refs/heads/main. Other URLs give "".
+func archiveFormat(repoURL string) string {
+	u, err := url.Parse(repoURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	p := strings.ToLower(u.Path)
+	switch {
+	case strings.HasSuffix(p, ".tar.gz"), strings.HasSuffix(p, ".tgz"):
+		return formatTarGz
+	case strings.HasSuffix(p, ".zip"):
+		return formatZip
+	}
+	if strings.EqualFold(u.Host, "codeload.github.com") {
+		// /owner/repo/<format>/<ref>
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) >= 3 {
+			switch parts[2] {
+			case "tar.gz", "legacy.tar.gz":
+				return formatTarGz
+			case "zip", "legacy.zip":
+				return formatZip
+			}
+		}
+	}
+	return ""
+}
+
+// fetchArchive downloads a template archive, verifies the checksum given in the URL
+// fragment (#sha256=<hex>) and extracts it to a temporary directory. When the archive
+// holds a single top-level directory, as GitHub archives do, that directory is returned.
+func (g *GitRepoTemplateRepository) fetchArchive(ctx context.Context, repoURL, format string) (string, error) {
+	downloadURL, checksum, err := splitChecksum(repoURL)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp("", "ohmymem-templates-*."+format)
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	sum, err := g.download(ctx, downloadURL, file)
+	if err != nil {
+		return "", err
+	}
+	if checksum != "" && sum != checksum {
+		return "", fmt.Errorf("checksum mismatch for %s: expected sha256 %s, got %s", downloadURL, checksum, sum)
+	}
+
+	tempDir, err := os.MkdirTemp("", "ohmymem-templates-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
+	if format == formatZip {
+		err = extractZip(file, tempDir)
+	} else {
+		err = extractTarGz(file, tempDir)
+	}
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("extract %s: %w", downloadURL, err)
+	}
+
+	root, err := archiveRoot(tempDir)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", err
+	}
+	if root == tempDir {
+		return tempDir, nil
+	}
+	// Move the single top-level directory up so Cleanup removes everything
+	unwrapped, err := os.MkdirTemp("", "ohmymem-templates-*")
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
+	os.Remove(unwrapped)
+	if err := os.Rename(root, unwrapped); err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("move %s: %w", root, err)
+	}
+	os.RemoveAll(tempDir)
+	return unwrapped, nil
+}
+
+// download writes the body of url to w and returns its hex SHA-256
+func (g *GitRepoTemplateRepository) download(ctx context.Context, rawURL string, w io.Writer) (string, error) {
+	downloadCtx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(downloadCtx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if downloadCtx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("download timeout after %v: %w", g.timeout, err)
+		}
+		return "", fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: %s", rawURL, resp.Status)
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, hash), io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	if n > maxArchiveSize {
+		return "", fmt.Errorf("download %s: archive larger than %d MB", rawURL, maxArchiveSize>>20)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// splitChecksum removes a #sha256=<hex> fragment from repoURL, returning the URL to
+// download and the expected checksum ("" when none is given)
+func splitChecksum(repoURL string) (string, string, error) {
+	base, fragment, found := strings.Cut(repoURL, "#")
+	if !found {
+		return repoURL, "", nil
+	}
+	algo, sum, _ := strings.Cut(fragment, "=")
+	if algo != "sha256" {
+		return "", "", fmt.Errorf("unsupported checksum %q in %s, use #sha256=<hex>", algo, repoURL)
+	}
+	sum = strings.ToLower(sum)
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+		return "", "", fmt.Errorf("invalid sha256 checksum %q in %s", sum, repoURL)
+	}
+	return base, sum, nil
+}
+
+func extractTarGz(file *os.File, dst string) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	budget := int64(maxArchiveSize)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := archivePath(dst, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(target, tr, &budget); err != nil {
+				return err
+			}
+		}
+		// Links and other entry types are skipped: templates are plain files
+	}
+}
+
+func extractZip(file *os.File, dst string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	budget := int64(maxArchiveSize)
+	for _, f := range zr.File {
+		target, err := archivePath(dst, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeArchiveFile(target, rc, &budget)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archivePath returns where an archive entry is extracted under dst, rejecting
+// entries that would escape it
+func archivePath(dst, name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	clean := path.Clean(name)
+	if path.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid archive entry %q", name)
+	}
+	return filepath.Join(dst, filepath.FromSlash(clean)), nil
+}
+
+// writeArchiveFile writes an archive entry to target, charging the bytes actually
+// written to budget, the room left for the whole archive. The sizes an archive
+// declares for its entries are not trusted.
+func writeArchiveFile(target string, r io.Reader, budget *int64) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(r, *budget+1))
+	*budget -= n
+	if err == nil && *budget < 0 {
+		err = errArchiveTooLarge
+	}
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// archiveRoot returns the single top-level directory of an extracted archive, or
+// dir itself when the archive has files at its root or several directories
+func archiveRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", dir, err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
@@ -0,0 +1,223 @@
+package template
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// DefaultCacheTTL is how long a cached template checkout is used before it is fetched again
+const DefaultCacheTTL = 24 * time.Hour
+
+// defaultRef is the ref cached checkouts are keyed by when none is requested: the
+// default branch of the repository
+const defaultRef = "HEAD"
+
+// CacheOptions configures the template cache
+type CacheOptions struct {
+	Disabled bool          // Always clone to a temporary directory (--no-cache)
+	Refresh  bool          // Fetch again even when the cached checkout is fresh (--refresh)
+	TTL      time.Duration // Age after which a cached checkout is fetched again; zero uses DefaultCacheTTL
+	Dir      string        // Cache directory; empty uses DefaultCacheDir
+}
+
+// DefaultCacheDir returns the directory cached template checkouts are kept in,
+// ~/.ohmymem/cache/templates
+func DefaultCacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ohmymem", "cache", "templates")
+}
+
+// FetchOptions configures how template repositories are fetched
+type FetchOptions struct {
+	Ref    string // Branch, tag or commit to check out (--repo-ref); empty is the default branch
+	Cache  CacheOptions
+	Verify VerifyOptions
+}
+
+// NewTemplateRepository returns the git template repository, cached unless opts
+// disables it and verified against its integrity manifest unless verification is off
+func NewTemplateRepository(opts FetchOptions) domain.TemplateRepository {
+	git := NewGitRepoTemplateRepository(DefaultFetchTimeout).WithRef(opts.Ref)
+	var repo domain.TemplateRepository = git
+	if !opts.Cache.Disabled {
+		repo = NewCachedTemplateRepository(git, opts.Cache)
+	}
+	if opts.Verify.Mode == VerifyOff {
+		return repo
+	}
+	return NewVerifiedTemplateRepository(repo, opts.Verify)
+}
+
+// CachedTemplateRepository implements domain.TemplateRepository by keeping the
+// checkouts of a GitRepoTemplateRepository in a cache directory, keyed by URL and ref.
+// A checkout younger than the TTL is reused without network access; an older one is
+// still used when fetching it again fails, so init keeps working offline.
+type CachedTemplateRepository struct {
+	git     *GitRepoTemplateRepository
+	dir     string
+	ttl     time.Duration
+	refresh bool
+}
+
+// NewCachedTemplateRepository creates a CachedTemplateRepository over git
+func NewCachedTemplateRepository(git *GitRepoTemplateRepository, opts CacheOptions) *CachedTemplateRepository {
+	c := &CachedTemplateRepository{
+		git:     git,
+		dir:     opts.Dir,
+		ttl:     opts.TTL,
+		refresh: opts.Refresh,
+	}
+	if c.dir == "" {
+		c.dir = DefaultCacheDir()
+	}
+	if c.ttl <= 0 {
+		c.ttl = DefaultCacheTTL
+	}
+	return c
+}
+
+// IsGitAvailable checks if git command is available in the system
+func (c *CachedTemplateRepository) IsGitAvailable() bool {
+	return c.git.IsGitAvailable()
+}
+
+// Fetch returns the cached checkout of repoURL, fetching it when it is missing or
+// older than the TTL. Local directories are not cached.
+func (c *CachedTemplateRepository) Fetch(ctx context.Context, repoURL string) (string, error) {
+	if _, ok := localRepoPath(repoURL); ok {
+		return c.git.Fetch(ctx, repoURL)
+	}
+
+	path := c.entryPath(repoURL)
+	if !c.refresh && c.isFresh(path) {
+		return path, nil
+	}
+
+	fetched, err := c.git.Fetch(ctx, repoURL)
+	if err != nil {
+		// Offline: a stale checkout beats no templates, unless --refresh asked for new ones
+		if !c.refresh && dirExists(path) {
+			return path, nil
+		}
+		return "", err
+	}
+	return c.store(fetched, path), nil
+}
+
+// FetchWithFallback tries multiple repository URLs: first a fresh cached checkout of
+// any of them, then fetching each, fastest responding mirror first, then a stale
+// cached checkout
+func (c *CachedTemplateRepository) FetchWithFallback(ctx context.Context, repoURLs []string) (string, error) {
+	if len(repoURLs) == 0 {
+		return "", fmt.Errorf("no repository URLs provided")
+	}
+
+	if !c.refresh {
+		for _, repoURL := range repoURLs {
+			if path := c.entryPath(repoURL); c.isFresh(path) {
+				return path, nil
+			}
+		}
+	}
+
+	var allErrors []error
+	for _, repoURL := range OrderMirrors(ctx, repoURLs, DefaultProbeTimeout) {
+		fetched, err := c.git.Fetch(ctx, repoURL)
+		if err == nil {
+			if _, ok := localRepoPath(repoURL); ok {
+				return fetched, nil
+			}
+			return c.store(fetched, c.entryPath(repoURL)), nil
+		}
+		allErrors = append(allErrors, fmt.Errorf("%s: %w", repoURL, err))
+	}
+
+	if !c.refresh {
+		for _, repoURL := range repoURLs {
+			if path := c.entryPath(repoURL); dirExists(path) {
+				return path, nil
+			}
+		}
+	}
+	return "", &domain.TemplateFetchError{
+		RepoURLs: repoURLs,
+		Errors:   allErrors,
+	}
+}
+
+// Cleanup removes a temporary checkout; cached checkouts are kept
+func (c *CachedTemplateRepository) Cleanup(tempPath string) error {
+	if tempPath == "" || c.inCache(tempPath) {
+		return nil
+	}
+	return c.git.Cleanup(tempPath)
+}
+
+// store moves a fresh checkout into the cache at path and returns where it ended up.
+// When the cache cannot be written the temporary checkout is returned as is.
+func (c *CachedTemplateRepository) store(fetched, path string) string {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fetched
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fetched
+	}
+	if err := os.Rename(fetched, path); err != nil {
+		// Rename fails across filesystems: copy instead
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fetched
+		}
+		if err := copyDir(fetched, path); err != nil {
+			_ = os.RemoveAll(path)
+			return fetched
+		}
+		_ = os.RemoveAll(fetched)
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return path
+}
+
+// entryPath returns the cache directory of repoURL at the requested ref
+func (c *CachedTemplateRepository) entryPath(repoURL string) string {
+	ref := c.git.ref
+	if ref == "" {
+		ref = defaultRef
+	}
+	return filepath.Join(c.dir, cacheKey(repoURL, ref))
+}
+
+// isFresh reports whether the cached checkout at path exists and is younger than the
+// TTL; a checkout of a commit never changes, so it is always fresh
+func (c *CachedTemplateRepository) isFresh(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	return isCommitHash(c.git.ref) || time.Since(info.ModTime()) < c.ttl
+}
+
+// inCache reports whether path is inside the cache directory
+func (c *CachedTemplateRepository) inCache(path string) bool {
+	rel, err := filepath.Rel(c.dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cacheKey names the cache directory of a repository URL and ref
+func cacheKey(repoURL, ref string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(strings.TrimSpace(repoURL), "/") + "@" + ref))
+	return hex.EncodeToString(sum[:8])
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
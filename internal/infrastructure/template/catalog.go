@@ -0,0 +1,117 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// Info describes a template of a repository
+type Info struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"` // base, language, framework or context
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Dir         string   `json:"-"` // Directory of the template in the checkout
+}
+
+// Path returns the template's path in the repository, e.g. "languages/go"
+func (i Info) Path() string {
+	return filepath.ToSlash(filepath.Join(filepath.Base(filepath.Dir(i.Dir)), filepath.Base(i.Dir)))
+}
+
+// List returns the templates of the repository checked out at repoPath, by kind then ID
+func List(repoPath string) ([]Info, error) {
+	var infos []Info
+	for _, kd := range domain.TemplateKinds {
+		dirs, err := os.ReadDir(filepath.Join(repoPath, kd.Dir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", kd.Dir, err)
+		}
+
+		var kindInfos []Info
+		for _, dir := range dirs {
+			if !dir.IsDir() {
+				continue
+			}
+			info, err := readInfo(filepath.Join(repoPath, kd.Dir, dir.Name()), kd.Kind)
+			if err != nil {
+				return nil, err
+			}
+			kindInfos = append(kindInfos, info)
+		}
+		sort.Slice(kindInfos, func(i, j int) bool { return kindInfos[i].ID < kindInfos[j].ID })
+		infos = append(infos, kindInfos...)
+	}
+	return infos, nil
+}
+
+// Find returns the template named by its ID ("go") or path ("languages/go")
+func Find(repoPath, name string) (Info, error) {
+	infos, err := List(repoPath)
+	if err != nil {
+		return Info{}, err
+	}
+
+	var matches []Info
+	for _, info := range infos {
+		if info.Path() == name {
+			return info, nil
+		}
+		if strings.EqualFold(info.ID, name) {
+			matches = append(matches, info)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Info{}, fmt.Errorf("template %q not found, run 'ohmymem template list'", name)
+	case 1:
+		return matches[0], nil
+	default:
+		paths := make([]string, 0, len(matches))
+		for _, match := range matches {
+			paths = append(paths, match.Path())
+		}
+		return Info{}, fmt.Errorf("template %q is ambiguous: %s", name, strings.Join(paths, ", "))
+	}
+}
+
+// ReadMemory returns the memory.md content a template injects, including its
+// per-category files
+func ReadMemory(info Info) (string, error) {
+	content, _, err := domain.ReadTemplateContent(info.Dir)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", info.Path(), err)
+	}
+	return content, nil
+}
+
+// readInfo reads the meta.yaml of a template directory
+func readInfo(dir, kind string) (Info, error) {
+	info := Info{ID: filepath.Base(dir), Kind: kind, Dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, "meta.yaml"))
+	if err != nil && !os.IsNotExist(err) {
+		return info, fmt.Errorf("read meta.yaml of %s: %w", dir, err)
+	}
+	m, err := domain.ParseTemplateMeta(data, info.Path(), kind)
+	if err != nil {
+		return info, err
+	}
+
+	info.ID = m.ID
+	info.Name = m.Name
+	info.Version = m.Version
+	info.Description = m.Description
+	info.Tags = m.Tags
+	return info, nil
+}
@@ -0,0 +1,53 @@
+package template
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// embeddedTemplates is a minimal template repository built into the binary, used
+// when the template repositories cannot be fetched
+//
+//go:embed embedded
+var embeddedTemplates embed.FS
+
+// IsDefaultRepo reports whether repoURL is one of the built-in default repositories
+func IsDefaultRepo(repoURL string) bool {
+	return slices.Contains([]string{DefaultGitHubRepo, DefaultGiteeRepo}, repoURL)
+}
+
+// ExtractEmbedded writes the built-in templates to a temporary directory laid out
+// like a template repository. The caller is responsible for removing it.
+func ExtractEmbedded() (string, error) {
+	tempDir, err := os.MkdirTemp("", "ohmymem-templates-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
+
+	root, err := fs.Sub(embeddedTemplates, "embedded")
+	if err == nil {
+		err = fs.WalkDir(root, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			target := filepath.Join(tempDir, filepath.FromSlash(path))
+			if d.IsDir() {
+				return os.MkdirAll(target, 0755)
+			}
+			data, err := fs.ReadFile(root, path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, data, 0644)
+		})
+	}
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("extract built-in templates: %w", err)
+	}
+	return tempDir, nil
+}
@@ -6,9 +6,12 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
 )
 
 // Default template repository URLs
@@ -20,9 +23,11 @@ const (
 // DefaultFetchTimeout is the timeout for git clone operations
 const DefaultFetchTimeout = 15 * time.Second
 
-// GitRepoTemplateRepository implements domain.TemplateRepository using git clone
+// GitRepoTemplateRepository implements domain.TemplateRepository using git clone,
+// through go-git or the git binary
 type GitRepoTemplateRepository struct {
 	timeout time.Duration
+	ref     string // Branch, tag or commit to check out; empty is the default branch
 }
 
 // NewGitRepoTemplateRepository creates a new GitRepoTemplateRepository with the specified timeout
@@ -32,21 +37,28 @@ func NewGitRepoTemplateRepository(timeout time.Duration) *GitRepoTemplateReposit
 	}
 }
 
+// WithRef checks out ref (a branch, tag or commit) instead of the default branch
+func (g *GitRepoTemplateRepository) WithRef(ref string) *GitRepoTemplateRepository {
+	g.ref = ref
+	return g
+}
+
 // IsGitAvailable checks if git command is available in the system
 func (g *GitRepoTemplateRepository) IsGitAvailable() bool {
 	_, err := exec.LookPath("git")
 	return err == nil
 }
 
-// Fetch clones a template repository to a local temporary directory
+// Fetch clones a template repository to a local temporary directory; an http(s)
+// URL of a .tar.gz or .zip archive is downloaded and extracted instead, and a local
+// directory is copied unless a ref of its git repository is requested
 // The caller is responsible for cleaning up the returned directory
 func (g *GitRepoTemplateRepository) Fetch(ctx context.Context, repoURL string) (string, error) {
-	if repoPath, ok := localRepoPath(repoURL); ok {
+	if repoPath, ok := localRepoPath(repoURL); ok && (g.ref == "" || !dirExists(filepath.Join(repoPath, ".git"))) {
 		return copyLocalRepo(repoPath)
 	}
-
-	if !g.IsGitAvailable() {
-		return "", fmt.Errorf("git command not found, please install git")
+	if format := archiveFormat(repoURL); format != "" {
+		return g.fetchArchive(ctx, repoURL, format)
 	}
 
 	// Create temporary directory for cloning
@@ -59,32 +71,98 @@ func (g *GitRepoTemplateRepository) Fetch(ctx context.Context, repoURL string) (
 	cloneCtx, cancel := context.WithTimeout(ctx, g.timeout)
 	defer cancel()
 
-	// Build git clone command with --depth 1 for shallow clone
-	cmd := exec.CommandContext(cloneCtx, "git", "clone", "--depth", "1", repoURL, tempDir)
-
-	// Capture stderr for error reporting
-	var stderr []byte
-	cmd.Stderr = &stderrWriter{data: &stderr}
-
-	if err := cmd.Run(); err != nil {
+	if err := g.clone(cloneCtx, repoURL, tempDir); err != nil {
 		// Clean up temp directory on failure
 		os.RemoveAll(tempDir)
 
 		if cloneCtx.Err() == context.DeadlineExceeded {
 			return "", fmt.Errorf("git clone timeout after %v: %w", g.timeout, err)
 		}
+		return "", err
+	}
+
+	return tempDir, nil
+}
+
+// clone clones repoURL at the requested ref into dir with go-git, retrying with the
+// git binary when go-git fails and git is installed, since git also supports
+// credential helpers and transports go-git lacks
+func (g *GitRepoTemplateRepository) clone(ctx context.Context, repoURL, dir string) error {
+	err := g.cloneGoGit(ctx, repoURL, dir)
+	if err == nil || ctx.Err() != nil || !g.IsGitAvailable() {
+		return err
+	}
+	if err := resetDir(dir); err != nil {
+		return err
+	}
+	return g.cloneExec(ctx, repoURL, dir)
+}
+
+// cloneExec shallow-clones repoURL at the requested ref into dir with the git binary
+func (g *GitRepoTemplateRepository) cloneExec(ctx context.Context, repoURL, dir string) error {
+	switch {
+	case g.ref == "":
+		return runGit(ctx, "", "clone", "--depth", "1", repoURL, dir)
+	case !isCommitHash(g.ref):
+		return runGit(ctx, "", "clone", "--depth", "1", "--branch", g.ref, repoURL, dir)
+	}
 
-		stderrMsg := string(stderr)
+	// A commit cannot be cloned by name: fetch it alone, or every branch and tag
+	// when the server does not serve unadvertised commits
+	if err := runGit(ctx, dir, "init", "-q"); err != nil {
+		return err
+	}
+	if err := runGit(ctx, dir, "fetch", "-q", "--depth", "1", repoURL, g.ref); err == nil {
+		return runGit(ctx, dir, "checkout", "-q", "FETCH_HEAD")
+	}
+	if err := runGit(ctx, dir, "fetch", "-q", repoURL, "+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"); err != nil {
+		return err
+	}
+	return runGit(ctx, dir, "checkout", "-q", g.ref)
+}
+
+// resetDir empties dir after a failed clone attempt
+func resetDir(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clean %s: %w", dir, err)
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// runGit runs a git command in dir (the working directory when empty), reporting
+// its stderr on failure
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	// Capture stderr for error reporting
+	var stderr []byte
+	cmd.Stderr = &stderrWriter{data: &stderr}
+
+	if err := cmd.Run(); err != nil {
+		stderrMsg := strings.TrimSpace(string(stderr))
 		if stderrMsg != "" {
-			return "", fmt.Errorf("git clone failed: %s: %w", stderrMsg, err)
+			return fmt.Errorf("git %s failed: %s: %w", args[0], stderrMsg, err)
 		}
-		return "", fmt.Errorf("git clone failed: %w", err)
+		return fmt.Errorf("git %s failed: %w", args[0], err)
 	}
+	return nil
+}
 
-	return tempDir, nil
+// isCommitHash reports whether ref looks like an abbreviated or full commit hash
+func isCommitHash(ref string) bool {
+	if len(ref) < 7 || len(ref) > 40 {
+		return false
+	}
+	for _, r := range ref {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
 }
 
-// FetchWithFallback tries multiple repository URLs in order
+// FetchWithFallback tries multiple repository URLs, fastest responding mirror first
 // Returns the local path from the first successful clone
 func (g *GitRepoTemplateRepository) FetchWithFallback(ctx context.Context, repoURLs []string) (string, error) {
 	if len(repoURLs) == 0 {
@@ -93,7 +171,7 @@ func (g *GitRepoTemplateRepository) FetchWithFallback(ctx context.Context, repoU
 
 	var allErrors []error
 
-	for _, repoURL := range repoURLs {
+	for _, repoURL := range OrderMirrors(ctx, repoURLs, DefaultProbeTimeout) {
 		path, err := g.Fetch(ctx, repoURL)
 		if err == nil {
 			return path, nil
@@ -125,14 +203,40 @@ func (g *GitRepoTemplateRepository) Cleanup(tempPath string) error {
 	return os.RemoveAll(tempPath)
 }
 
-// GetDefaultRepoURLs returns the default repository URLs in priority order
+// GetDefaultRepoURLs returns the default repository URLs in priority order: those
+// configured with OHMYMEM_TEMPLATE_REPO or template.repo, else GitHub then Gitee
 func GetDefaultRepoURLs() []string {
+	if urls := ConfiguredRepoURLs(); len(urls) > 0 {
+		return urls
+	}
 	return []string{
 		DefaultGitHubRepo,
 		DefaultGiteeRepo,
 	}
 }
 
+// ConfiguredRepoURLs returns the repositories replacing the defaults, from the
+// OHMYMEM_TEMPLATE_REPO environment variable or the template.repo setting; nil when
+// none is configured or the configuration cannot be read
+func ConfiguredRepoURLs() []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.Template.RepoURLs()
+}
+
+// ConfiguredLayers returns the template repositories of template.layers, composed on
+// top of the template repository; nil when none is configured or the configuration
+// cannot be read
+func ConfiguredLayers() []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.Template.LayerURLs()
+}
+
 func localRepoPath(repoURL string) (string, bool) {
 	if repoURL == "" {
 		return "", false
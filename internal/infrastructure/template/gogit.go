@@ -0,0 +1,121 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// cloneGoGit clones repoURL at the requested ref into dir with go-git, without the
+// git binary. Branches and tags are shallow-cloned; a commit needs the full history.
+// Local repositories are exported from their object database instead, since go-git
+// runs git-upload-pack to clone them.
+func (g *GitRepoTemplateRepository) cloneGoGit(ctx context.Context, repoURL, dir string) error {
+	if path, ok := localGitPath(repoURL); ok {
+		return goGitError(g.exportLocal(path, dir))
+	}
+	if g.ref == "" {
+		_, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+			URL:          repoURL,
+			Depth:        1,
+			SingleBranch: true,
+		})
+		return goGitError(err)
+	}
+
+	if !isCommitHash(g.ref) {
+		var err error
+		for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(g.ref), plumbing.NewTagReferenceName(g.ref)} {
+			_, err = git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+				URL:           repoURL,
+				ReferenceName: name,
+				Depth:         1,
+				SingleBranch:  true,
+			})
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+			if err := resetDir(dir); err != nil {
+				return err
+			}
+		}
+		return goGitError(err)
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{URL: repoURL, Tags: git.AllTags})
+	if err != nil {
+		return goGitError(err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(g.ref))
+	if err != nil {
+		return goGitError(fmt.Errorf("resolve %s: %w", g.ref, err))
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return goGitError(err)
+	}
+	return goGitError(worktree.Checkout(&git.CheckoutOptions{Hash: *hash}))
+}
+
+// exportLocal writes the files of the local repository at path, at the requested
+// ref or HEAD, into dir
+func (g *GitRepoTemplateRepository) exportLocal(path, dir string) error {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return err
+	}
+	ref := g.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	budget := int64(maxArchiveSize)
+	return tree.Files().ForEach(func(f *object.File) error {
+		if !f.Mode.IsFile() {
+			return nil // Symlinks and submodules are not template content
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		target, err := archivePath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		return writeArchiveFile(target, strings.NewReader(content), &budget)
+	})
+}
+
+// localGitPath returns the directory of a file:// URL or local path
+func localGitPath(repoURL string) (string, bool) {
+	path := strings.TrimPrefix(repoURL, "file://")
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// goGitError wraps go-git errors like the git binary's
+func goGitError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("git clone failed: %w", err)
+}
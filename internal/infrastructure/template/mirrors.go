@@ -0,0 +1,87 @@
+package template
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultProbeTimeout bounds how long a mirror is probed before it is deemed unreachable
+const DefaultProbeTimeout = 3 * time.Second
+
+// unreachable orders mirrors that did not answer their probe last
+const unreachable = time.Duration(1<<63 - 1)
+
+// OrderMirrors returns repoURLs ordered fastest first, probing the hosts of http(s)
+// URLs concurrently so a slow or blocked mirror does not delay a fast one. Other URLs
+// (local paths, ssh) are not probed and keep their place ahead of probed mirrors;
+// mirrors that do not answer within timeout keep their relative order at the end.
+func OrderMirrors(ctx context.Context, repoURLs []string, timeout time.Duration) []string {
+	hosts := map[string]string{} // repo URL -> probed scheme://host
+	for _, repoURL := range repoURLs {
+		u, err := url.Parse(repoURL)
+		if err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			hosts[repoURL] = u.Scheme + "://" + u.Host
+		}
+	}
+	ordered := append([]string(nil), repoURLs...)
+	if len(hosts) < 2 {
+		return ordered
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	latency := map[string]time.Duration{} // probed host -> response time
+	for _, host := range hosts {
+		if _, ok := latency[host]; ok {
+			continue
+		}
+		latency[host] = unreachable
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			if d, ok := probe(probeCtx, host); ok {
+				mu.Lock()
+				latency[host] = d
+				mu.Unlock()
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return mirrorLatency(ordered[i], hosts, latency) < mirrorLatency(ordered[j], hosts, latency)
+	})
+	return ordered
+}
+
+// mirrorLatency is the probed response time of repoURL, zero when it was not probed
+func mirrorLatency(repoURL string, hosts map[string]string, latency map[string]time.Duration) time.Duration {
+	host, ok := hosts[repoURL]
+	if !ok {
+		return 0
+	}
+	return latency[host]
+}
+
+// probe sends a HEAD request to host and returns how long it took to answer; any
+// HTTP response, whatever its status, means the mirror is reachable
+func probe(ctx context.Context, host string) (time.Duration, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, host+"/", nil)
+	if err != nil {
+		return 0, false
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	resp.Body.Close()
+	return time.Since(start), true
+}
@@ -0,0 +1,75 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// Scaffold creates a template repository in dir with the common base and a template
+// for language, ready to be edited, and returns the files written. dir must be
+// missing or empty.
+func Scaffold(dir, language string) ([]string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "" || strings.ContainsAny(language, `/\`) || language == "." || language == ".." {
+		return nil, fmt.Errorf("invalid language %q", language)
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", dir)
+	} else if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+
+	files := []struct {
+		path    string
+		content string
+	}{
+		{"agents.md", domain.DefaultAgentsContent()},
+		{"bases/common/meta.yaml", scaffoldMeta("common", "Common", "base", "Rules for every project")},
+		{"bases/common/memory.md", scaffoldMemory("common", "documentation", "Code changes should be reflected in relevant documentation")},
+		{"languages/" + language + "/meta.yaml", scaffoldMeta(language, language, "language", "Conventions of "+language+" projects")},
+		{"languages/" + language + "/memory.md", scaffoldMemory(language, language, "Describe a "+language+" rule every project must follow")},
+	}
+
+	var written []string
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file.path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(file.content), 0644); err != nil {
+			return written, fmt.Errorf("write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// scaffoldMeta renders the meta.yaml of a scaffolded template
+func scaffoldMeta(id, name, kind, description string) string {
+	return fmt.Sprintf(`name: %q
+id: %q
+type: %q
+version: "0.1.0"
+description: %q
+`, name, id, kind, description)
+}
+
+// scaffoldMemory renders the memory.md of a scaffolded template with one constraint
+func scaffoldMemory(source, tag, rule string) string {
+	return fmt.Sprintf(`## Constraints
+
+<!-- template-entry, tag: [%[2]s], source: %[1]s -->
+* **[%[2]s]** %[3]s (*理由: Template default*)
+<!-- entry-end -->
+
+## Decisions
+
+## Patterns
+
+## Anti-Patterns
+`, source, tag, rule)
+}
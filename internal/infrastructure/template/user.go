@@ -0,0 +1,50 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// LocalRepo is the repository name selecting the user templates directory alone,
+// as in --repo local
+const LocalRepo = "local"
+
+// UserTemplatesDir returns the directory personal and team templates are kept in,
+// ~/.ohmymem/templates, laid out like a template repository
+func UserTemplatesDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ohmymem", "templates")
+}
+
+// ResolveRepoURLs replaces LocalRepo in repoURLs with the user templates directory,
+// which must exist
+func ResolveRepoURLs(repoURLs []string) ([]string, error) {
+	resolved := make([]string, 0, len(repoURLs))
+	for _, repoURL := range repoURLs {
+		if repoURL == LocalRepo {
+			repoURL = UserTemplatesDir()
+			if !dirExists(repoURL) {
+				return nil, fmt.Errorf("no local templates: create %s laid out like a template repository", repoURL)
+			}
+		}
+		resolved = append(resolved, repoURL)
+	}
+	return resolved, nil
+}
+
+// Layers returns the repositories to compose on top of repoURLs: the layers, with
+// LocalRepo resolved, then the user templates directory when it exists and is not
+// already used
+func Layers(repoURLs, layers []string) ([]string, error) {
+	resolved, err := ResolveRepoURLs(layers)
+	if err != nil {
+		return nil, err
+	}
+	dir := UserTemplatesDir()
+	if dirExists(dir) && !slices.Contains(repoURLs, dir) && !slices.Contains(resolved, dir) {
+		resolved = append(resolved, dir)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,211 @@
+package template
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// Integrity manifest files at the root of a template repository
+const (
+	ManifestFileName  = "SHA256SUMS"     // sha256sum output: "<hex>  <path>" per file
+	SignatureFileName = "SHA256SUMS.sig" // base64 ed25519 signature of the manifest
+)
+
+// Verification modes of template.verify
+const (
+	VerifyStrict = "strict" // Refuse templates failing verification
+	VerifyWarn   = "warn"   // Log a warning and use them anyway
+	VerifyOff    = "off"
+)
+
+// VerifyModes lists the accepted verification modes
+var VerifyModes = []string{VerifyStrict, VerifyWarn, VerifyOff}
+
+// VerifyOptions configures template integrity verification
+type VerifyOptions struct {
+	Mode string // strict, warn or off; empty is strict
+	// PublicKey is a base64 ed25519 key; when set, the manifest and its signature
+	// are required and the signature must verify
+	PublicKey string
+}
+
+// VerifyChecksums checks a template checkout against its SHA256SUMS manifest, and the
+// manifest against its signature when publicKey is set. A checkout without a manifest
+// passes unless a key is required. It returns the problems found.
+func VerifyChecksums(repoPath, publicKey string) ([]string, error) {
+	manifest, err := os.ReadFile(filepath.Join(repoPath, ManifestFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read %s: %w", ManifestFileName, err)
+		}
+		if publicKey != "" {
+			return []string{ManifestFileName + " is missing but a template public key is configured"}, nil
+		}
+		return nil, nil
+	}
+
+	var problems []string
+	if publicKey != "" {
+		if problem := verifySignature(repoPath, manifest, publicKey); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	sums, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	files, err := checkoutFiles(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		want, ok := sums[file]
+		if !ok {
+			problems = append(problems, file+" is not listed in "+ManifestFileName)
+			continue
+		}
+		delete(sums, file)
+		data, err := os.ReadFile(filepath.Join(repoPath, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", file, err)
+		}
+		if got := sha256.Sum256(data); hex.EncodeToString(got[:]) != want {
+			problems = append(problems, file+" does not match its checksum")
+		}
+	}
+	missing := make([]string, 0, len(sums))
+	for file := range sums {
+		missing = append(missing, file)
+	}
+	sort.Strings(missing)
+	for _, file := range missing {
+		problems = append(problems, file+" is listed in "+ManifestFileName+" but missing")
+	}
+	return problems, nil
+}
+
+// verifySignature checks the ed25519 signature of the manifest, returning a problem
+// or "" when it verifies
+func verifySignature(repoPath string, manifest []byte, publicKey string) string {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return "template public key is not a base64 ed25519 key"
+	}
+	data, err := os.ReadFile(filepath.Join(repoPath, SignatureFileName))
+	if err != nil {
+		return SignatureFileName + " is missing"
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(key), manifest, signature) {
+		return SignatureFileName + " does not verify " + ManifestFileName
+	}
+	return ""
+}
+
+// parseManifest reads sha256sum lines into checksums by slash-separated path
+func parseManifest(manifest []byte) (map[string]string, error) {
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		sum, file, ok := strings.Cut(text, " ")
+		file = strings.TrimPrefix(strings.TrimLeft(file, " "), "*")
+		if _, err := hex.DecodeString(sum); !ok || err != nil || len(sum) != sha256.Size*2 || file == "" {
+			return nil, fmt.Errorf("%s line %d: expected \"<sha256>  <path>\"", ManifestFileName, line)
+		}
+		sums[strings.TrimPrefix(filepath.ToSlash(file), "./")] = strings.ToLower(sum)
+	}
+	return sums, scanner.Err()
+}
+
+// checkoutFiles lists the regular files of a checkout by slash-separated path,
+// skipping .git and the manifest files
+func checkoutFiles(repoPath string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != ManifestFileName && rel != SignatureFileName {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// VerifiedTemplateRepository implements domain.TemplateRepository by checking every
+// checkout of another repository against its integrity manifest
+type VerifiedTemplateRepository struct {
+	domain.TemplateRepository
+	opts VerifyOptions
+}
+
+// NewVerifiedTemplateRepository wraps repo with integrity verification
+func NewVerifiedTemplateRepository(repo domain.TemplateRepository, opts VerifyOptions) *VerifiedTemplateRepository {
+	return &VerifiedTemplateRepository{TemplateRepository: repo, opts: opts}
+}
+
+// Fetch fetches repoURL and verifies the checkout
+func (v *VerifiedTemplateRepository) Fetch(ctx context.Context, repoURL string) (string, error) {
+	path, err := v.TemplateRepository.Fetch(ctx, repoURL)
+	if err != nil {
+		return "", err
+	}
+	return v.verify(path, repoURL)
+}
+
+// FetchWithFallback fetches the first reachable of repoURLs and verifies the checkout
+func (v *VerifiedTemplateRepository) FetchWithFallback(ctx context.Context, repoURLs []string) (string, error) {
+	path, err := v.TemplateRepository.FetchWithFallback(ctx, repoURLs)
+	if err != nil {
+		return "", err
+	}
+	return v.verify(path, strings.Join(repoURLs, ", "))
+}
+
+// verify applies the verification mode to the checkout at path
+func (v *VerifiedTemplateRepository) verify(path, source string) (string, error) {
+	problems, err := VerifyChecksums(path, v.opts.PublicKey)
+	if err == nil && len(problems) == 0 {
+		return path, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	if v.opts.Mode == VerifyWarn {
+		slog.Warn("template verification failed", "repo", source, "error", err)
+		return path, nil
+	}
+	_ = v.Cleanup(path)
+	return "", fmt.Errorf("verify templates from %s: %w (set template.verify to warn to use them anyway)", source, err)
+}
@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultHTTPAddr is the default listen address of the HTTP transport (loopback only)
+const DefaultHTTPAddr = "127.0.0.1:8765"
+
+// MCPEndpointPath is the path serving the streamable HTTP MCP endpoint
+const MCPEndpointPath = "/mcp"
+
+// HealthEndpointPath is the unauthenticated liveness endpoint
+const HealthEndpointPath = "/healthz"
+
+// NewHTTPHandler returns the HTTP handler for the MCP server.
+// When authToken is non-empty every MCP request must carry "Authorization: Bearer <token>";
+// the optional health handler stays reachable without a token so probes can use it.
+func NewHTTPHandler(s *server.MCPServer, authToken string, health http.Handler) http.Handler {
+	var mcpHandler http.Handler = server.NewStreamableHTTPServer(s,
+		server.WithEndpointPath(MCPEndpointPath),
+		server.WithHTTPContextFunc(WithClientAddr),
+	)
+	if authToken != "" {
+		mcpHandler = BearerAuth(authToken, mcpHandler)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MCPEndpointPath, mcpHandler)
+	if health != nil {
+		mux.Handle(HealthEndpointPath, health)
+	}
+	return mux
+}
+
+// clientAddrKey is the context key of the host an HTTP request came from
+type clientAddrKey struct{}
+
+// WithClientAddr records the host r came from in ctx, so tool calls can be limited per
+// client: over HTTP the session ID is chosen by the client
+func WithClientAddr(ctx context.Context, r *http.Request) context.Context {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return context.WithValue(ctx, clientAddrKey{}, host)
+}
+
+// ClientAddr returns the host recorded by WithClientAddr, or "" for other transports
+func ClientAddr(ctx context.Context) string {
+	addr, _ := ctx.Value(clientAddrKey{}).(string)
+	return addr
+}
+
+// BearerAuth rejects requests that don't present the expected bearer token
+func BearerAuth(token string, next http.Handler) http.Handler {
+	expected := []byte(token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(provided)), expected) != 1 {
+			slog.Warn("rejected unauthenticated request", "remote", r.RemoteAddr, "path", r.URL.Path)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ohmymem"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsLoopbackAddr reports whether a listen address only accepts local connections
+func IsLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,399 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// detailHeight is the number of lines reserved for the selected entry and the help bar
+const detailHeight = 12
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	activeTab     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("75")).Underline(true)
+	inactiveTab   = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	labelStyle    = lipgloss.NewStyle().Bold(true).Width(11)
+	messageStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// EditCommand returns the command that edits an entry in the user's editor. The
+// browser suspends while it runs and reloads the entries afterwards.
+type EditCommand func(id string) *exec.Cmd
+
+// pendingAction is a destructive action waiting for confirmation
+type pendingAction string
+
+const (
+	actionNone      pendingAction = ""
+	actionDeprecate pendingAction = "deprecate"
+	actionDelete    pendingAction = "delete"
+)
+
+// editDoneMsg is sent when the editor exits
+type editDoneMsg struct{ err error }
+
+// model is the bubbletea model of the browser
+type model struct {
+	ctx     context.Context
+	svc     *domain.MemoryService
+	edit    EditCommand
+	all     []domain.Entry
+	visible []domain.Entry
+	tabs    []domain.SectionType // Empty section is the "All" tab
+	tab     int
+	cursor  int
+	offset  int
+	search  textinput.Model
+	typing  bool
+	pending pendingAction
+	message string
+	width   int
+	height  int
+}
+
+// Run opens the interactive browser over the memory entries until the user quits
+func Run(ctx context.Context, svc *domain.MemoryService, edit EditCommand) error {
+	search := textinput.New()
+	search.Prompt = "/ "
+	search.Placeholder = "fuzzy search tag, content and rationale"
+
+	m := &model{
+		ctx:    ctx,
+		svc:    svc,
+		edit:   edit,
+		tabs:   append([]domain.SectionType{""}, domain.ValidSections()...),
+		search: search,
+		height: 24,
+		width:  80,
+	}
+	if err := m.reload(); err != nil {
+		return err
+	}
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	return err
+}
+
+// Init implements tea.Model
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.search.Width = msg.Width - 4
+		return m, nil
+	case editDoneMsg:
+		m.message = "Entry edited."
+		if msg.err != nil {
+			m.message = "Edit failed: " + msg.err.Error()
+		}
+		if err := m.reload(); err != nil {
+			m.message = err.Error()
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.typing {
+			return m.updateSearch(msg)
+		}
+		if m.pending != actionNone {
+			return m.confirm(msg.String()), nil
+		}
+		return m.handleKey(msg.String())
+	}
+	return m, nil
+}
+
+// updateSearch feeds keys to the search box, filtering as the user types
+func (m *model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.search.SetValue("")
+		m.typing = false
+		m.search.Blur()
+		m.applyFilter()
+		return m, nil
+	case "enter", "up", "down":
+		m.typing = false
+		m.search.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	m.applyFilter()
+	return m, cmd
+}
+
+// handleKey runs the browser's navigation and action keys
+func (m *model) handleKey(key string) (tea.Model, tea.Cmd) {
+	m.message = ""
+	switch key {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.listHeight())
+	case "pgdown":
+		m.move(m.listHeight())
+	case "tab", "right", "l":
+		m.tab = (m.tab + 1) % len(m.tabs)
+		m.applyFilter()
+	case "shift+tab", "left", "h":
+		m.tab = (m.tab + len(m.tabs) - 1) % len(m.tabs)
+		m.applyFilter()
+	case "/":
+		m.typing = true
+		return m, m.search.Focus()
+	case "e":
+		if entry, ok := m.selected(); ok && entry.ID != "" {
+			return m, tea.ExecProcess(m.edit(entry.ID), func(err error) tea.Msg { return editDoneMsg{err: err} })
+		}
+	case "d":
+		if entry, ok := m.selected(); ok && entry.ID != "" && entry.IsActive() {
+			m.pending = actionDeprecate
+		}
+	case "x":
+		if entry, ok := m.selected(); ok && entry.ID != "" {
+			m.pending = actionDelete
+		}
+	}
+	return m, nil
+}
+
+// confirm applies or cancels the pending action
+func (m *model) confirm(key string) tea.Model {
+	action := m.pending
+	m.pending = actionNone
+	if key != "y" {
+		m.message = "Cancelled."
+		return m
+	}
+
+	entry, ok := m.selected()
+	if !ok {
+		return m
+	}
+	var err error
+	switch action {
+	case actionDeprecate:
+		_, err = m.svc.DeprecateEntries(m.ctx, []domain.Entry{entry})
+		m.message = "Deprecated " + entry.ID
+	case actionDelete:
+		_, err = m.svc.DeleteEntry(m.ctx, entry.ID)
+		m.message = "Deleted " + entry.ID
+	}
+	if err != nil {
+		m.message = fmt.Sprintf("%s failed: %v", action, err)
+	}
+	if err := m.reload(); err != nil {
+		m.message = err.Error()
+	}
+	return m
+}
+
+// reload reads the entries again and reapplies the current filter
+func (m *model) reload() error {
+	entries, err := m.svc.ListEntries(m.ctx, domain.EntryFilter{IncludeExpired: true})
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+	m.all = entries
+	m.applyFilter()
+	return nil
+}
+
+// applyFilter narrows the entries to the current tab and search query, best matches first
+func (m *model) applyFilter() {
+	section := m.tabs[m.tab]
+	query := m.search.Value()
+
+	type scored struct {
+		entry domain.Entry
+		score int
+	}
+	var matches []scored
+	for _, entry := range m.all {
+		if section != "" && entry.Section != section {
+			continue
+		}
+		score, ok := fuzzyScore(query, entry.TagName+" "+entry.Content+" "+entry.Rationale)
+		if !ok {
+			continue
+		}
+		matches = append(matches, scored{entry: entry, score: score})
+	}
+	if strings.TrimSpace(query) != "" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	}
+
+	m.visible = m.visible[:0]
+	for _, match := range matches {
+		m.visible = append(m.visible, match.entry)
+	}
+	m.cursor = min(m.cursor, max(len(m.visible)-1, 0))
+	m.move(0)
+}
+
+// move shifts the cursor by delta, keeping it inside the list and on screen
+func (m *model) move(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(len(m.visible)-1, 0))
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+// selected returns the entry under the cursor
+func (m *model) selected() (domain.Entry, bool) {
+	if len(m.visible) == 0 {
+		return domain.Entry{}, false
+	}
+	return m.visible[m.cursor], true
+}
+
+// listHeight is the number of entry rows that fit on screen
+func (m *model) listHeight() int {
+	return max(m.height-detailHeight-4, 3)
+}
+
+// View implements tea.Model
+func (m *model) View() string {
+	var sb strings.Builder
+
+	tabs := make([]string, 0, len(m.tabs))
+	for i, section := range m.tabs {
+		name := "All"
+		if section != "" {
+			name = section.Title()
+		}
+		if i == m.tab {
+			tabs = append(tabs, activeTab.Render(name))
+		} else {
+			tabs = append(tabs, inactiveTab.Render(name))
+		}
+	}
+	sb.WriteString(titleStyle.Render("ohmymem") + "  " + strings.Join(tabs, "  ") + "\n")
+
+	if m.typing || m.search.Value() != "" {
+		sb.WriteString(m.search.View() + "\n")
+	} else {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("%d entries", len(m.visible))) + "\n")
+	}
+
+	height := m.listHeight()
+	for i := m.offset; i < min(m.offset+height, len(m.visible)); i++ {
+		sb.WriteString(m.row(i) + "\n")
+	}
+	for i := len(m.visible) - m.offset; i < height; i++ {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(dimStyle.Render(strings.Repeat("─", max(m.width, 10))) + "\n")
+	sb.WriteString(m.detail())
+	sb.WriteString("\n" + m.footer())
+	return sb.String()
+}
+
+// row renders one entry of the list
+func (m *model) row(i int) string {
+	entry := m.visible[i]
+	content := entry.Content
+	if !entry.IsActive() {
+		content = fmt.Sprintf("(%s) %s", entry.Status, content)
+	}
+	line := fmt.Sprintf("%-14s %s %s", entry.Section, entry.Tag, content)
+	line = truncate(line, max(m.width-2, 20))
+	if i == m.cursor {
+		return selectedStyle.Render("› " + line)
+	}
+	return "  " + line
+}
+
+// detail renders every field of the selected entry
+func (m *model) detail() string {
+	entry, ok := m.selected()
+	if !ok {
+		return dimStyle.Render("No entries match.") + "\n"
+	}
+
+	width := max(m.width-12, 20)
+	field := func(label, value string) string {
+		if value == "" {
+			value = dimStyle.Render("-")
+		}
+		return labelStyle.Render(label) + lipgloss.NewStyle().Width(width).Render(value) + "\n"
+	}
+
+	id := entry.ID
+	if id == "" {
+		id = "(legacy entry, run 'ohmymem migrate')"
+	}
+	expires := ""
+	if !entry.ExpiresAt.IsZero() {
+		expires = entry.ExpiresAt.Local().Format(time.DateTime)
+	}
+	created := ""
+	if !entry.CreatedAt.IsZero() {
+		created = entry.CreatedAt.Local().Format(time.DateTime)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(field("ID", id))
+	sb.WriteString(field("Section", entry.Section.Title()+"  "+entry.Tag))
+	sb.WriteString(field("Created", created))
+	sb.WriteString(field("Expires", expires))
+	sb.WriteString(field("Status", entry.StatusLabel()))
+	sb.WriteString(field("Scope", strings.Join(entry.Scope, ", ")))
+	sb.WriteString(field("Content", entry.Content))
+	sb.WriteString(field("Rationale", entry.Rationale))
+	return sb.String()
+}
+
+// footer renders the confirmation prompt, the last message or the key help
+func (m *model) footer() string {
+	switch {
+	case m.pending != actionNone:
+		entry, _ := m.selected()
+		return messageStyle.Render(fmt.Sprintf("%s %s? (y/N)", strings.ToUpper(string(m.pending[:1]))+string(m.pending[1:]), entry.ID))
+	case m.message != "":
+		return messageStyle.Render(m.message)
+	case m.typing:
+		return dimStyle.Render("type to filter • enter keep filter • esc clear")
+	default:
+		return dimStyle.Render("↑/↓ move • tab section • / search • e edit • d deprecate • x delete • q quit")
+	}
+}
+
+// truncate shortens s to at most width runes
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
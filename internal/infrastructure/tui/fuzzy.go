@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore matches every word of query as a case-insensitive subsequence of text.
+// Consecutive matches and matches at word starts score higher; ok is false when any
+// word does not match.
+func fuzzyScore(query, text string) (int, bool) {
+	lower := strings.ToLower(text)
+	total := 0
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		score, ok := subsequenceScore([]rune(term), lower)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+// subsequenceScore scores the first left-to-right match of needle within text
+func subsequenceScore(needle []rune, text string) (int, bool) {
+	score, run, pos := 0, 0, 0
+	prev := ' '
+	for _, r := range text {
+		if pos == len(needle) {
+			break
+		}
+		if r == needle[pos] {
+			run++
+			score += run
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 2
+			}
+			pos++
+		} else {
+			run = 0
+		}
+		prev = r
+	}
+	return score, pos == len(needle)
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+)
+
+// pagerModel is the bubbletea model of the Markdown pager
+type pagerModel struct {
+	title    string
+	content  string // Rendered Markdown
+	viewport viewport.Model
+	ready    bool
+}
+
+// Page renders markdown and shows it in a scrollable pager until the user quits
+func Page(title, markdown string) error {
+	renderer, err := glamour.NewTermRenderer(glamour.WithAutoStyle(), glamour.WithWordWrap(100))
+	if err != nil {
+		return fmt.Errorf("create renderer: %w", err)
+	}
+	rendered, err := renderer.Render(markdown)
+	if err != nil {
+		return fmt.Errorf("render %s: %w", title, err)
+	}
+
+	_, err = tea.NewProgram(&pagerModel{title: title, content: rendered}, tea.WithAltScreen()).Run()
+	return err
+}
+
+// Init implements tea.Model
+func (m *pagerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *pagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Title line and help bar
+		height := msg.Height - 2
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, height)
+			m.viewport.SetContent(m.content)
+			m.ready = true
+		} else {
+			m.viewport.Width, m.viewport.Height = msg.Width, height
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "enter", "ctrl+c":
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *pagerModel) View() string {
+	if !m.ready {
+		return ""
+	}
+	help := fmt.Sprintf("%3.f%% · ↑/↓ pgup/pgdn scroll · enter/q continue", m.viewport.ScrollPercent()*100)
+	return strings.Join([]string{titleStyle.Render(m.title), m.viewport.View(), dimStyle.Render(help)}, "\n")
+}
@@ -1,5 +1,74 @@
 package version
 
-// Version is the current application version.
-// It can be overridden at build time via -ldflags.
-var Version = "v0.1.0"
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata. They can be overridden at build time via -ldflags, e.g.
+// -X github.com/herewei/ohmymem-core/internal/version.Commit=abc1234
+var (
+	// Version is the current application version.
+	Version = "v0.1.0"
+	// Commit is the git commit the binary was built from
+	Commit = ""
+	// Date is the build date in RFC3339
+	Date = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build metadata, falling back to the VCS information Go embeds
+// when the ldflags were not set (e.g. go install)
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" && len(setting.Value) >= 7 {
+					info.Commit = setting.Value[:7]
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && Commit == "" && info.Commit != "" {
+			info.Commit += "-dirty"
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+// String renders the build metadata for humans
+func (i Info) String() string {
+	return fmt.Sprintf("ohmymem %s\n  commit:   %s\n  built:    %s\n  go:       %s\n  platform: %s\n",
+		i.Version, i.Commit, i.Date, i.GoVersion, i.Platform)
+}
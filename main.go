@@ -2,8 +2,40 @@ package main
 
 import (
 	"github.com/herewei/ohmymem-core/cmd"
+	_ "github.com/herewei/ohmymem-core/cmd/add"
+	_ "github.com/herewei/ohmymem-core/cmd/archive"
+	_ "github.com/herewei/ohmymem-core/cmd/check"
+	_ "github.com/herewei/ohmymem-core/cmd/config"
+	_ "github.com/herewei/ohmymem-core/cmd/dedupe"
+	_ "github.com/herewei/ohmymem-core/cmd/detect"
+	_ "github.com/herewei/ohmymem-core/cmd/diff"
+	_ "github.com/herewei/ohmymem-core/cmd/doctor"
+	_ "github.com/herewei/ohmymem-core/cmd/edit"
+	_ "github.com/herewei/ohmymem-core/cmd/export"
+	_ "github.com/herewei/ohmymem-core/cmd/fmt"
+	_ "github.com/herewei/ohmymem-core/cmd/hook"
+	_ "github.com/herewei/ohmymem-core/cmd/import"
+	_ "github.com/herewei/ohmymem-core/cmd/index"
 	_ "github.com/herewei/ohmymem-core/cmd/init"
+	_ "github.com/herewei/ohmymem-core/cmd/list"
 	_ "github.com/herewei/ohmymem-core/cmd/mcp"
+	_ "github.com/herewei/ohmymem-core/cmd/merge"
+	_ "github.com/herewei/ohmymem-core/cmd/migrate"
+	_ "github.com/herewei/ohmymem-core/cmd/prune"
+	_ "github.com/herewei/ohmymem-core/cmd/rm"
+	_ "github.com/herewei/ohmymem-core/cmd/search"
+	_ "github.com/herewei/ohmymem-core/cmd/serve"
+	_ "github.com/herewei/ohmymem-core/cmd/show"
+	_ "github.com/herewei/ohmymem-core/cmd/stats"
+	_ "github.com/herewei/ohmymem-core/cmd/status"
+	_ "github.com/herewei/ohmymem-core/cmd/sync"
+	_ "github.com/herewei/ohmymem-core/cmd/tags"
+	_ "github.com/herewei/ohmymem-core/cmd/template"
+	_ "github.com/herewei/ohmymem-core/cmd/tui"
+	_ "github.com/herewei/ohmymem-core/cmd/uninit"
+	_ "github.com/herewei/ohmymem-core/cmd/validate"
+	_ "github.com/herewei/ohmymem-core/cmd/version"
+	_ "github.com/herewei/ohmymem-core/cmd/watch"
 )
 
 func main() {
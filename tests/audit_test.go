@@ -0,0 +1,161 @@
+package main_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/audit"
+)
+
+// auditSession is a client session that reports its client info, as stdio and HTTP sessions do
+type auditSession struct {
+	info mcp.Implementation
+}
+
+func (s *auditSession) Initialize()                                         {}
+func (s *auditSession) Initialized() bool                                   { return true }
+func (s *auditSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *auditSession) SessionID() string                                   { return "session-1" }
+func (s *auditSession) GetClientInfo() mcp.Implementation                   { return s.info }
+func (s *auditSession) SetClientInfo(info mcp.Implementation)               { s.info = info }
+func (s *auditSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{}
+}
+func (s *auditSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+
+// readAuditLog returns the records of the audit log at path, one per line
+func readAuditLog(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("expected one JSON object per line, got %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAuditMiddleware_Records(t *testing.T) {
+	dir := t.TempDir()
+	logger := audit.NewJSONLAuditLogger(dir)
+	clock := &testClock{}
+
+	handler := usecase.AuditMiddleware(logger, clock)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch request.GetString("outcome", "") {
+		case "error":
+			return mcp.NewToolResultError("Validation failed"), nil
+		case "failed":
+			return nil, errors.New("boom")
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	session := &auditSession{info: mcp.Implementation{Name: "test-client", Version: "1.2.3"}}
+	ctx := server.NewMCPServer("test", "0.0.0").WithContext(context.Background(), session)
+	call := func(args map[string]any) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "ohmymem_capture"
+		request.Params.Arguments = args
+		_, _ = handler(ctx, request)
+	}
+
+	secret := "the staging password is hunter2"
+	call(map[string]any{"category": "constraints", "tag": "Auth", "content": secret, "project": "api"})
+	call(map[string]any{"category": "constraints", "tag": "Auth", "content": secret, "project": "api"})
+	call(map[string]any{"category": "constraints", "tag": "Auth", "content": "Rotate keys monthly"})
+	call(map[string]any{"outcome": "error"})
+	call(map[string]any{"outcome": "failed"})
+
+	data, err := os.ReadFile(logger.Path())
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "Rotate keys") {
+		t.Errorf("expected arguments to be logged as a hash only, got:\n%s", data)
+	}
+
+	records := readAuditLog(t, filepath.Join(dir, audit.FileName))
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(records))
+	}
+
+	first := records[0]
+	expected := map[string]any{
+		"time":           clock.Now().Format(time.RFC3339),
+		"tool":           "ohmymem_capture",
+		"project":        "api",
+		"status":         "ok",
+		"session_id":     "session-1",
+		"client_name":    "test-client",
+		"client_version": "1.2.3",
+	}
+	for field, value := range expected {
+		if first[field] != value {
+			t.Errorf("expected %s %v, got %v", field, value, first[field])
+		}
+	}
+	if _, ok := first["duration_ms"].(float64); !ok {
+		t.Errorf("expected a duration_ms, got %v", first["duration_ms"])
+	}
+
+	// Equal arguments hash equally, other arguments differently
+	hash, _ := first["args_hash"].(string)
+	if len(hash) != 16 {
+		t.Errorf("expected a 16 digit args_hash, got %q", hash)
+	}
+	if records[1]["args_hash"] != hash {
+		t.Error("expected equal arguments to have the same hash")
+	}
+	if records[2]["args_hash"] == hash {
+		t.Error("expected other arguments to have another hash")
+	}
+	if _, ok := records[2]["project"]; ok {
+		t.Errorf("expected no project without a project argument, got %v", records[2]["project"])
+	}
+
+	if records[3]["status"] != "error" || records[4]["status"] != "failed" {
+		t.Errorf("expected error and failed statuses, got %v and %v", records[3]["status"], records[4]["status"])
+	}
+}
+
+func TestNewServer_Audit(t *testing.T) {
+	for _, disable := range []bool{false, true} {
+		basePath := setupInitializedProject(t)
+		s, _, err := usecase.NewServer(basePath, usecase.ServerOptions{DisableAudit: disable})
+		if err != nil {
+			t.Fatalf("NewServer: %v", err)
+		}
+		callTool(t, s, "ohmymem_read", map[string]any{})
+
+		_, err = os.Stat(filepath.Join(basePath, ".ohmymem", audit.FileName))
+		switch {
+		case disable && err == nil:
+			t.Error("expected no audit log with --no-audit")
+		case !disable && err != nil:
+			t.Errorf("expected an audit log, got %v", err)
+		}
+	}
+}
+
+// Compile-time check that the test session reports client info like real sessions
+var _ server.SessionWithClientInfo = (*auditSession)(nil)
@@ -0,0 +1,150 @@
+package main_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
+)
+
+func TestConfig_SetValueLayers(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.EnvAuthToken, "")
+	t.Chdir(project)
+
+	if err := config.SetValue(config.GetConfigPath(), "init.yes", "true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := config.SetValue(config.GetConfigPath(), "mcp.auth_token", "global"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := config.SetValue(config.ProjectConfigPath("."), "mcp.auth_token", "project"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := config.SetValue(config.GetConfigPath(), "init.yes", "maybe"); err == nil {
+		t.Error("expected error for non-boolean value")
+	}
+	if err := config.SetValue(config.GetConfigPath(), "init.unknown", "x"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Init.Yes || cfg.MCP.AuthToken != "project" {
+		t.Errorf("expected project to override global only where set, got %+v", cfg)
+	}
+
+	values, err := config.ReadValues(filepath.Join(project, ".ohmymem", "config.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 1 || values["mcp.auth_token"] != "project" {
+		t.Errorf("unexpected project values: %v", values)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".ohmymem", "config.yaml")); err != nil {
+		t.Errorf("expected global config file: %v", err)
+	}
+}
+
+func TestGetDefaultRepoURLs_Override(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	t.Setenv(config.EnvTemplateRepo, "")
+
+	if got := template.GetDefaultRepoURLs(); len(got) != 2 || got[0] != template.DefaultGitHubRepo {
+		t.Errorf("GetDefaultRepoURLs() = %v, want the GitHub and Gitee repositories", got)
+	}
+
+	if err := config.SetValue(config.GetConfigPath(), "template.repo", "https://git.example.com/a.git, https://git.example.com/b.git"); err != nil {
+		t.Fatal(err)
+	}
+	if got := template.GetDefaultRepoURLs(); len(got) != 2 || got[1] != "https://git.example.com/b.git" {
+		t.Errorf("GetDefaultRepoURLs() = %v, want template.repo", got)
+	}
+
+	t.Setenv(config.EnvTemplateRepo, "/srv/templates")
+	if got := template.GetDefaultRepoURLs(); len(got) != 1 || got[0] != "/srv/templates" {
+		t.Errorf("GetDefaultRepoURLs() = %v, want %s", got, config.EnvTemplateRepo)
+	}
+}
+
+func TestConfig_ValidationPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+
+	cfg, err := config.LoadProject(project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy := cfg.Validation.Policy(); policy.MaxContentLength != domain.MaxContentLength || policy.BannedWords != nil {
+		t.Errorf("expected the default policy, got %+v", policy)
+	}
+
+	if err := config.SetValue(config.ProjectConfigPath(project), "validation.max_content_length", "280"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetValue(config.ProjectConfigPath(project), "validation.banned_words", "password, secret"); err != nil {
+		t.Fatal(err)
+	}
+	svc := usecase.NewMemoryUseCase(project).Service()
+	policy := svc.Rules().Policy()
+	if policy.MaxContentLength != 280 || len(policy.BannedWords) != 2 || policy.BannedWords[1] != "secret" {
+		t.Errorf("expected the project policy, got %+v", policy)
+	}
+	if err := svc.ValidateInput(domain.AppendInput{Tag: "Security", Content: "The secret is in vault"}); !errors.Is(err, domain.ErrForbiddenContent) {
+		t.Errorf("expected ErrForbiddenContent, got %v", err)
+	}
+}
+
+func TestConfig_ProjectEndpointSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.EnvEmbeddingsAPIKey, "env-key")
+	project := t.TempDir()
+	set := func(path, key, value string) {
+		t.Helper()
+		if err := config.SetValue(path, key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	load := func() *config.Config {
+		t.Helper()
+		cfg, err := config.LoadProject(project)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return cfg
+	}
+
+	set(config.GetConfigPath(), "embeddings.url", "https://embeddings.example.com/v1")
+	if cfg := load(); cfg.Embeddings.APIKey != "env-key" {
+		t.Errorf("expected the user's key, got %q", cfg.Embeddings.APIKey)
+	}
+
+	// A project pointing an endpoint elsewhere does not get the user's credential
+	set(config.ProjectConfigPath(project), "embeddings.url", "https://attacker.example.com")
+	if cfg := load(); cfg.Embeddings.APIKey != "" {
+		t.Errorf("expected no key for a project endpoint, got %q", cfg.Embeddings.APIKey)
+	}
+
+	// unless the project provides it, and the environment does not override it there
+	set(config.ProjectConfigPath(project), "embeddings.api_key", "project-key")
+	if cfg := load(); cfg.Embeddings.APIKey != "project-key" {
+		t.Errorf("expected the project key, got %q", cfg.Embeddings.APIKey)
+	}
+
+	// Endpoints the global config agrees on keep the user's credential
+	set(config.ProjectConfigPath(project), "embeddings.url", "https://embeddings.example.com/v1")
+	if cfg := load(); cfg.Embeddings.APIKey != "env-key" {
+		t.Errorf("expected the user's key, got %q", cfg.Embeddings.APIKey)
+	}
+}
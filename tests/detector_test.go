@@ -0,0 +1,550 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/detector"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// writeProject writes files, keyed by slash path, into a new project directory
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCompositeDetector_MultiLanguage(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":                  "module example.com/api\n\nrequire github.com/labstack/echo/v4 v4.11.0\n",
+		"package.json":            `{"devDependencies": {"typescript": "^5.0.0"}}`,
+		"ml/requirements.txt":     "fastapi==0.110.0\npsycopg2-binary>=2.9\n",
+		"node_modules/x/setup.py": "",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Language != "go" || info.Framework != "echo" {
+		t.Errorf("primary = %s/%s, want go/echo", info.Language, info.Framework)
+	}
+	want := []domain.ProjectStack{
+		{Language: "typescript", ProjectType: "library", Path: "."},
+		{Language: "python", Framework: "fastapi", Database: "postgresql", ProjectType: "backend", Path: "ml"},
+	}
+	if len(info.Secondary) != len(want) {
+		t.Fatalf("secondary = %+v, want %+v", info.Secondary, want)
+	}
+	for i := range want {
+		if info.Secondary[i] != want[i] {
+			t.Errorf("secondary[%d] = %+v, want %+v", i, info.Secondary[i], want[i])
+		}
+	}
+
+	// Templates of every detected stack apply
+	for _, meta := range []domain.TemplateMeta{
+		{ID: "go", Type: "language"},
+		{ID: "typescript", Type: "language"},
+		{ID: "fastapi", Type: "framework", Parent: "python"},
+		{ID: "database-postgresql", Type: "context"},
+	} {
+		if !meta.Applies(info) {
+			t.Errorf("template %s does not apply to %v", meta.ID, info.Languages())
+		}
+	}
+	if (domain.TemplateMeta{ID: "rust", Type: "language"}).Applies(info) {
+		t.Error("rust template applies to a project without Rust")
+	}
+}
+
+func TestCompositeDetector_Infrastructure(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":                      "module example.com/api\n",
+		"Dockerfile":                  "FROM golang:1.24\n",
+		"deploy/k8s/deployment.yaml":  "apiVersion: apps/v1\nkind: Deployment\n",
+		"infra/terraform/main.tf":     "provider \"aws\" {}\n",
+		"config/app.yaml":             "port: 8080\n",
+		"node_modules/x/compose.yaml": "services: {}\n",
+		".cache/pod.yaml":             "apiVersion: v1\nkind: Pod\n",
+		"a/b/c/d/charts/x/Chart.yaml": "name: x\n",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	want := []string{"docker", "kubernetes", "terraform"}
+	if strings.Join(info.Features, ",") != strings.Join(want, ",") {
+		t.Errorf("features = %v, want %v", info.Features, want)
+	}
+
+	// Context templates named after a feature, or requiring one, apply
+	if !(domain.TemplateMeta{ID: "docker", Type: "context"}).Applies(info) {
+		t.Error("docker context does not apply")
+	}
+	if (domain.TemplateMeta{ID: "helm", Type: "context"}).Applies(info) {
+		t.Error("helm context applies without a chart")
+	}
+	meta := domain.TemplateMeta{ID: "cloud", Type: "context", AppliesTo: domain.TemplateApplicability{Features: []string{"terraform", "pulumi"}}}
+	if !meta.Applies(info) {
+		t.Error("template requiring terraform does not apply")
+	}
+}
+
+func TestCompositeDetector_Frontend(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         map[string]string
+		framework     string
+		projectType   string
+		secondaryType string
+	}{
+		{
+			name:        "react",
+			files:       map[string]string{"package.json": `{"dependencies": {"react": "^18.0.0", "react-dom": "^18.0.0"}}`},
+			framework:   "react",
+			projectType: "frontend",
+		},
+		{
+			name:        "next with api routes",
+			files:       map[string]string{"package.json": `{"dependencies": {"next": "14", "react": "18"}}`, "pages/api/hello.ts": ""},
+			framework:   "nextjs",
+			projectType: "fullstack",
+		},
+		{
+			name:        "nuxt by config",
+			files:       map[string]string{"package.json": `{}`, "nuxt.config.ts": ""},
+			framework:   "nuxt",
+			projectType: "frontend",
+		},
+		{
+			name:        "sveltekit with server routes",
+			files:       map[string]string{"package.json": `{"devDependencies": {"@sveltejs/kit": "2", "svelte": "4"}}`, "src/routes/api/+server.ts": ""},
+			framework:   "sveltekit",
+			projectType: "fullstack",
+		},
+		{
+			name:        "angular",
+			files:       map[string]string{"package.json": `{"dependencies": {"@angular/core": "17"}}`},
+			framework:   "angular",
+			projectType: "frontend",
+		},
+		{
+			name:        "vue with express",
+			files:       map[string]string{"package.json": `{"dependencies": {"vue": "3", "express": "4"}}`},
+			framework:   "vue",
+			projectType: "fullstack",
+		},
+		{
+			name: "go backend with web frontend",
+			files: map[string]string{
+				"go.mod":           "module example.com/app\n\nrequire github.com/gin-gonic/gin v1.9.0\n",
+				"cmd/app/main.go":  "package main\n",
+				"web/package.json": `{"dependencies": {"svelte": "4"}}`,
+			},
+			framework:     "gin",
+			projectType:   "fullstack",
+			secondaryType: "frontend",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := detector.NewCompositeDetector().Detect(writeProject(t, tt.files))
+			if err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			if info.Framework != tt.framework || info.ProjectType != tt.projectType {
+				t.Errorf("detected %s/%s, want %s/%s", info.Framework, info.ProjectType, tt.framework, tt.projectType)
+			}
+			if tt.secondaryType != "" && (len(info.Secondary) != 1 || info.Secondary[0].ProjectType != tt.secondaryType) {
+				t.Errorf("secondary = %+v, want one %s stack", info.Secondary, tt.secondaryType)
+			}
+		})
+	}
+}
+
+func TestCompositeDetector_TestFrameworks(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":             "module example.com/api\n\nrequire github.com/stretchr/testify v1.9.0\n",
+		"web/package.json":   `{"devDependencies": {"vitest": "1", "c8": "9"}}`,
+		"web/jest.config.ts": "",
+		"ml/pyproject.toml":  "[project]\ndependencies = [\"pytest-cov>=4\"]\n",
+		"ml/conftest.py":     "",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for _, feature := range []string{"testify", "vitest", "c8", "jest", "pytest-cov", "pytest"} {
+		if !slices.Contains(info.Features, feature) {
+			t.Errorf("features = %v, missing %s", info.Features, feature)
+		}
+	}
+	if slices.Contains(info.Features, "ginkgo") {
+		t.Errorf("features = %v, want no ginkgo", info.Features)
+	}
+}
+
+func TestCompositeDetector_CIProviders(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":                    "module example.com/api\n",
+		".github/workflows/ci.yaml": "on: push\n",
+		".gitlab-ci.yml":            "test:\n  script: go test ./...\n",
+		"Jenkinsfile":               "pipeline {}\n",
+		"web/.circleci/config.yml":  "version: 2.1\n",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	want := []string{"github-actions", "gitlab-ci", "jenkins"}
+	if strings.Join(info.Features, ",") != strings.Join(want, ",") {
+		t.Errorf("features = %v, want %v", info.Features, want)
+	}
+}
+
+func TestCompositeDetector_Candidates(t *testing.T) {
+	goMod := `module example.com/api
+
+require (
+	github.com/labstack/echo/v4 v4.11.0 // indirect
+	github.com/gin-gonic/gin v1.9.0
+	github.com/jackc/pgx/v5 v5.5.0
+	github.com/lib/pq v1.10.9 // indirect
+)
+`
+	info, err := detector.NewCompositeDetector().Detect(writeProject(t, map[string]string{"go.mod": goMod}))
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Framework != "gin" || info.Database != "postgresql" {
+		t.Errorf("detected %s/%s, want gin/postgresql", info.Framework, info.Database)
+	}
+	want := []domain.DetectionCandidate{
+		{Kind: "framework", Value: "gin", Confidence: 0.9, Source: "go.mod: github.com/gin-gonic/gin"},
+		{Kind: "framework", Value: "echo", Confidence: 0.2, Source: "go.mod: github.com/labstack/echo"},
+	}
+	if got := info.CandidatesOf("framework"); !slices.Equal(got, want) {
+		t.Errorf("framework candidates = %+v, want %+v", got, want)
+	}
+	if got := info.CandidatesOf("database"); len(got) != 1 || got[0].Confidence != 0.9 {
+		t.Errorf("database candidates = %+v, want postgresql merged at 0.9", got)
+	}
+
+	// Runtime dependencies outrank development ones
+	info, err = detector.NewCompositeDetector().Detect(writeProject(t, map[string]string{
+		"package.json": `{"dependencies": {"mongoose": "8"}, "devDependencies": {"pg": "8"}}`,
+	}))
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Database != "mongodb" || len(info.CandidatesOf("database")) != 2 {
+		t.Errorf("database = %s, candidates %+v, want mongodb ahead of postgresql", info.Database, info.Candidates)
+	}
+}
+
+func TestCachedDetector(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":             "module example.com/api\n\nrequire github.com/gin-gonic/gin v1.9.0\n",
+		".ohmymem/memory.md": "",
+	})
+
+	// Without a saved stack the project is detected and the result saved
+	info, err := detector.NewCachedDetector(detector.NewCompositeDetector()).Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Framework != "gin" {
+		t.Fatalf("framework = %q, want gin", info.Framework)
+	}
+	saved, _, err := persistence.LoadProjectInfo(projectDir)
+	if err != nil || saved.Language != "go" || saved.Framework != "gin" || len(saved.Candidates) != 1 {
+		t.Fatalf("saved = %+v, %v, want the detected stack", saved, err)
+	}
+
+	// The saved stack is reused, corrections included, until a refresh
+	saved.Framework = "echo"
+	if err := persistence.SaveProjectInfo(projectDir, saved); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = detector.NewCachedDetector(detector.NewCompositeDetector()).Detect(projectDir); err != nil || info.Framework != "echo" {
+		t.Errorf("cached framework = %q, %v, want echo", info.Framework, err)
+	}
+	if info, err = detector.NewCachedDetector(detector.NewCompositeDetector()).WithRefresh(true).WithSave(false).Detect(projectDir); err != nil || info.Framework != "gin" {
+		t.Errorf("refreshed framework = %q, %v, want gin", info.Framework, err)
+	}
+	if saved, _, _ = persistence.LoadProjectInfo(projectDir); saved.Framework != "echo" {
+		t.Errorf("saved framework = %q after a refresh without saving, want echo", saved.Framework)
+	}
+}
+
+func TestCompositeDetector_APISpecs(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":                   "module example.com/api\n",
+		"api/openapi.yaml":         "openapi: 3.1.0\ninfo:\n  title: API\n",
+		"proto/user/v1/user.proto": "syntax = \"proto3\";\n",
+		"config/settings.json":     `{"openapi": "not scanned"}`,
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	want := []string{"openapi", "protobuf"}
+	if strings.Join(info.Features, ",") != strings.Join(want, ",") {
+		t.Errorf("features = %v, want %v", info.Features, want)
+	}
+
+	info, err = detector.NewCompositeDetector().Detect(writeProject(t, map[string]string{
+		"package.json":       `{}`,
+		"docs/swagger.json":  "{\n  \"swagger\": \"2.0\"\n}\n",
+		"src/schema.graphql": "type Query { user: User }\n",
+	}))
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	want = []string{"openapi", "graphql"}
+	if strings.Join(info.Features, ",") != strings.Join(want, ",") {
+		t.Errorf("features = %v, want %v", info.Features, want)
+	}
+}
+
+func TestCompositeDetector_GoWorkspace(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.work":                   "go 1.24\n\nuse (\n\t./api // HTTP service\n\t./tools/gen\n)\n\nuse ./lib\n",
+		"api/go.mod":                "module example.com/api\n\nrequire github.com/go-chi/chi/v5 v5.0.0\n",
+		"api/cmd/server/main.go":    "package main\n",
+		"tools/gen/go.mod":          "module example.com/gen\n",
+		"tools/gen/cmd/gen/main.go": "package main\n",
+		"lib/go.mod":                "module example.com/lib\n",
+		"lib/vendor/modules.txt":    "",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Language != "go" || info.Framework != "chi" || info.ProjectType != "backend" {
+		t.Errorf("detected %s/%s/%s, want go/chi/backend", info.Language, info.Framework, info.ProjectType)
+	}
+	if want := []string{"api", "tools/gen", "lib"}; !slices.Equal(info.Modules, want) {
+		t.Errorf("modules = %v, want %v", info.Modules, want)
+	}
+	for _, feature := range []string{"go-workspace", "vendor"} {
+		if !slices.Contains(info.Features, feature) {
+			t.Errorf("features = %v, missing %s", info.Features, feature)
+		}
+	}
+	if len(info.Secondary) != 0 {
+		t.Errorf("secondary = %+v, want the members folded into the workspace", info.Secondary)
+	}
+}
+
+func TestCompositeDetector_Linters(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":               "module example.com/api\n",
+		".golangci.yml":        "linters:\n  enable: [errcheck]\n",
+		"web/package.json":     `{"devDependencies": {"prettier": "3"}}`,
+		"web/eslint.config.js": "export default []\n",
+		"ml/pyproject.toml":    "[project]\nname = \"ml\"\n\n[tool.ruff]\nline-length = 100\n",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for _, feature := range []string{"golangci-lint", "eslint", "prettier", "ruff"} {
+		if !slices.Contains(info.Features, feature) {
+			t.Errorf("features = %v, missing %s", info.Features, feature)
+		}
+	}
+	if slices.Contains(info.Features, "black") {
+		t.Errorf("features = %v, want no black", info.Features)
+	}
+}
+
+func TestCompositeDetector_MessageQueues(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":                "module example.com/api\n\nrequire (\n\tgithub.com/IBM/sarama v1.43.0\n\tgithub.com/segmentio/kafka-go v0.4.47\n)\n",
+		"worker/package.json":   `{"dependencies": {"amqplib": "0.10", "@aws-sdk/client-sqs": "3"}}`,
+		"jobs/requirements.txt": "celery[redis]==5.3\nnats-py\n",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for _, feature := range []string{"kafka", "rabbitmq", "aws-sqs", "celery", "nats"} {
+		if !slices.Contains(info.Features, feature) {
+			t.Errorf("features = %v, missing %s", info.Features, feature)
+		}
+	}
+	if n := len(slices.DeleteFunc(slices.Clone(info.Features), func(f string) bool { return f != "kafka" })); n != 1 {
+		t.Errorf("features = %v, want kafka once", info.Features)
+	}
+}
+
+func TestCompositeDetector_GoModParsing(t *testing.T) {
+	goMod := `module example.com/tool // not a gin or echo service
+
+go 1.24
+
+require (
+	github.com/spf13/cobra v1.10.2
+	github.com/stretchr/testify v1.9.0 // indirect
+)
+
+replace github.com/labstack/echo/v4 => ../echo
+`
+	projectDir := writeProject(t, map[string]string{"go.mod": goMod, "cmd/tool/main.go": "package main\n"})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Framework != "" || len(info.Candidates) != 0 {
+		t.Errorf("framework = %q, candidates %+v, want none from comments or replace directives", info.Framework, info.Candidates)
+	}
+	if info.ProjectType != "cli" {
+		t.Errorf("type = %q, want cli", info.ProjectType)
+	}
+	if slices.Contains(info.Features, "testify") {
+		t.Errorf("features = %v, want no indirect testify", info.Features)
+	}
+}
+
+func TestCompositeDetector_StackDeclaration(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":           "module example.com/api\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.0\n\tgithub.com/labstack/echo/v4 v4.11.0\n)\n",
+		"Dockerfile":       "FROM scratch\n",
+		"web/package.json": `{"dependencies": {"react": "18"}}`,
+		".ohmymem/stack.yaml": `framework: echo
+project_type: backend
+features: [kafka]
+ignore_features: [docker]
+secondary:
+  - language: typescript
+    framework: nextjs
+    project_type: fullstack
+    path: web
+`,
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Language != "go" || info.Framework != "echo" || info.ProjectType != "backend" {
+		t.Errorf("detected %s/%s/%s, want go with the declared echo/backend", info.Language, info.Framework, info.ProjectType)
+	}
+	if len(info.CandidatesOf("framework")) != 0 {
+		t.Errorf("framework candidates = %+v, want none once declared", info.CandidatesOf("framework"))
+	}
+	if !slices.Contains(info.Features, "kafka") || slices.Contains(info.Features, "docker") {
+		t.Errorf("features = %v, want kafka added and docker ignored", info.Features)
+	}
+	want := domain.ProjectStack{Language: "typescript", Framework: "nextjs", ProjectType: "fullstack", Path: "web"}
+	if len(info.Secondary) != 1 || info.Secondary[0] != want {
+		t.Errorf("secondary = %+v, want %+v", info.Secondary, want)
+	}
+
+	// Flags still win over the declaration
+	info, err = detector.NewCompositeDetector().WithOverrides(domain.ProjectOverrides{Framework: "gin"}).Detect(projectDir)
+	if err != nil || info.Framework != "gin" {
+		t.Errorf("framework = %q, %v, want the gin override", info.Framework, err)
+	}
+
+	// A malformed declaration is reported, not ignored
+	if err := os.WriteFile(filepath.Join(projectDir, ".ohmymem", "stack.yaml"), []byte("framewrok: echo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := detector.NewCompositeDetector().Detect(projectDir); err == nil {
+		t.Error("Detect accepted an unknown stack.yaml key")
+	}
+}
+
+func TestCompositeDetector_Explain(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"go.mod":              "module example.com/api\n\nrequire github.com/gin-gonic/gin v1.9.0\n",
+		"cmd/api/main.go":     "package main\n",
+		"deploy/Dockerfile":   "FROM scratch\n",
+		"web/package.json":    `{"dependencies": {"react": "18"}}`,
+		"web/tsconfig.json":   "{}",
+		".ohmymem/stack.yaml": "features: [kafka]\n",
+	})
+
+	info, err := detector.NewCompositeDetector().Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Evidence != nil {
+		t.Errorf("evidence = %+v, want none without explain", info.Evidence)
+	}
+
+	info, err = detector.NewCompositeDetector().WithExplain(true).Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for _, want := range []domain.DetectionEvidence{
+		{Kind: "language", Value: "go", Source: "go.mod"},
+		{Kind: "framework", Value: "gin", Source: "go.mod: github.com/gin-gonic/gin", Confidence: 0.9},
+		{Kind: "project_type", Value: "backend", Source: "cmd/ with framework gin"},
+		{Kind: "language", Value: "typescript", Source: "web/tsconfig.json"},
+		{Kind: "framework", Value: "react", Source: "web/package.json: react", Confidence: 0.9},
+		{Kind: "project_type", Value: "fullstack", Source: "backend and frontend stacks"},
+		{Kind: "feature", Value: "docker", Source: "deploy/Dockerfile"},
+		{Kind: "feature", Value: "kafka", Source: ".ohmymem/stack.yaml"},
+	} {
+		if !slices.Contains(info.Evidence, want) {
+			t.Errorf("evidence is missing %+v, got %+v", want, info.Evidence)
+		}
+	}
+}
+
+func TestProjectInfo_JSON(t *testing.T) {
+	info, err := detector.NewCompositeDetector().Detect(writeProject(t, map[string]string{
+		"go.mod":           "module example.com/api\n\nrequire github.com/gin-gonic/gin v1.9.0\n",
+		"cmd/api/main.go":  "package main\n",
+		"web/package.json": `{"dependencies": {"react": "18"}}`,
+	}))
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded["language"] != "go" || decoded["framework"] != "gin" || decoded["project_type"] != "fullstack" {
+		t.Errorf("JSON = %s, want snake_case go/gin/fullstack", data)
+	}
+	if _, ok := decoded["evidence"]; ok {
+		t.Errorf("JSON = %s, want no evidence without explain", data)
+	}
+	secondary, _ := decoded["secondary"].([]any)
+	if len(secondary) != 1 || secondary[0].(map[string]any)["path"] != "web" {
+		t.Errorf("secondary = %v, want the web stack", decoded["secondary"])
+	}
+}
@@ -0,0 +1,153 @@
+package main_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+// doctorStatuses maps check names to their status
+func doctorStatuses(report *usecase.DoctorReport) map[string]string {
+	statuses := make(map[string]string, len(report.Checks))
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestDoctor_Fix(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	memoryPath := filepath.Join(projectDir, ".ohmymem", "memory.md")
+	legacy := "## Constraints\n\n* **[Go]** Use Go 1.24\n"
+	if err := os.WriteFile(memoryPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write memory.md: %v", err)
+	}
+	if err := os.WriteFile(memoryPath+".tmp", []byte("partial"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := os.Symlink("MISSING.md", filepath.Join(projectDir, "CLAUDE.md")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	doctor := usecase.NewDoctorUseCase(projectDir)
+	ctx := context.Background()
+
+	report, err := doctor.Run(ctx, false)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	statuses := doctorStatuses(report)
+	want := map[string]string{
+		"Frontmatter":     usecase.CheckWarn,
+		"Sections":        usecase.CheckWarn,
+		"Entry format":    usecase.CheckWarn,
+		"Locks":           usecase.CheckWarn,
+		"AGENTS.md block": usecase.CheckWarn,
+		"CLAUDE.md":       usecase.CheckFail,
+	}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("%s: got %q, want %q", name, statuses[name], status)
+		}
+	}
+	if report.Healthy() {
+		t.Error("expected unhealthy report before fixing")
+	}
+
+	if _, err := doctor.Run(ctx, true); err != nil {
+		t.Fatalf("Run with fix failed: %v", err)
+	}
+
+	report, err = doctor.Run(ctx, false)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, check := range report.Checks {
+		// Legacy entries are left for migrate
+		if check.Name != "Entry format" && check.Status != usecase.CheckOK {
+			t.Errorf("%s still %s after fix: %s", check.Name, check.Status, check.Detail)
+		}
+	}
+
+	data, err := os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatalf("failed to read memory.md: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "---\nschema_version:") || !strings.Contains(string(data), "* **[Go]** Use Go 1.24") {
+		t.Errorf("unexpected repaired content:\n%s", data)
+	}
+	if target, _ := os.Readlink(filepath.Join(projectDir, "CLAUDE.md")); target != "AGENTS.md" {
+		t.Errorf("CLAUDE.md links to %q, want AGENTS.md", target)
+	}
+}
+
+func TestUninit(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	agents := "# Team rules\n\nKeep PRs small.\n\n<!-- ohmymem:start -->\nmanaged\n<!-- ohmymem:end -->\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "AGENTS.md"), []byte(agents), 0644); err != nil {
+		t.Fatalf("failed to write AGENTS.md: %v", err)
+	}
+	if err := os.Symlink("AGENTS.md", filepath.Join(projectDir, "CLAUDE.md")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, ".cursorrules"), []byte("own rules"), 0644); err != nil {
+		t.Fatalf("failed to write .cursorrules: %v", err)
+	}
+
+	uninit := usecase.NewUninitUseCase(projectDir)
+	steps, err := uninit.Plan(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected symlink and AGENTS.md steps with --keep-memory, got %+v", steps)
+	}
+	if err := uninit.Apply(steps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(projectDir, "AGENTS.md"))
+	if string(data) != "# Team rules\n\nKeep PRs small.\n" {
+		t.Errorf("unexpected AGENTS.md after uninit: %q", data)
+	}
+	if _, err := os.Lstat(filepath.Join(projectDir, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Error("expected CLAUDE.md symlink to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".cursorrules")); err != nil {
+		t.Error("expected regular .cursorrules to be kept")
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".ohmymem", "memory.md")); err != nil {
+		t.Error("expected memory to be kept")
+	}
+
+	steps, _ = uninit.Plan(false)
+	if err := uninit.Apply(steps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".ohmymem")); !os.IsNotExist(err) {
+		t.Error("expected .ohmymem to be removed")
+	}
+}
+
+func TestResolveEditors(t *testing.T) {
+	editors, err := usecase.ResolveEditors(nil)
+	if err != nil || len(editors) != len(usecase.DefaultEditors) {
+		t.Fatalf("defaults = %v, %v", editors, err)
+	}
+
+	editors, err = usecase.ResolveEditors([]string{"Copilot", "cline", "copilot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(editors) != 2 || editors[0].Path != filepath.Join(".github", "copilot-instructions.md") || editors[1].Path != ".clinerules" {
+		t.Errorf("got %+v", editors)
+	}
+
+	if _, err := usecase.ResolveEditors([]string{"vim"}); err == nil {
+		t.Error("expected error for unknown editor")
+	}
+}
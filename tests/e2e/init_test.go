@@ -163,3 +163,97 @@ func TestInit_WithCustomRepo(t *testing.T) {
 		t.Logf("stderr: %s", result.Stderr)
 	}
 }
+
+// TestInit_PathFlag tests initializing another directory with --path
+// Given: 空目录 project，命令在另一个目录中运行
+// When:  ohmymem init --yes --path <project>
+// Then:
+//   - 退出码 = 0
+//   - project/.ohmymem/memory.md 和 project/AGENTS.md 存在
+//   - 当前目录没有 memory.md 和 AGENTS.md
+func TestInit_PathFlag(t *testing.T) {
+	cwd := setupTestEnv(t, "")
+	project := setupTestEnv(t, "")
+
+	result := runCmd(cwd, "init", "--yes", "--path", project)
+
+	// 验证命令成功
+	if result.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", result.ExitCode)
+		t.Errorf("stdout: %s", result.Stdout)
+		t.Errorf("stderr: %s", result.Stderr)
+	}
+
+	// 验证初始化的是 --path 指定的目录
+	assertFileContains(t, filepath.Join(project, ".ohmymem", "memory.md"), "schema_version")
+	assertFileExists(t, filepath.Join(project, "AGENTS.md"))
+	assertFileNotExists(t, filepath.Join(cwd, ".ohmymem", "memory.md"))
+	assertFileNotExists(t, filepath.Join(cwd, "AGENTS.md"))
+}
+
+// TestInit_PathArgument tests initializing another directory given as argument
+// Given: 目录 work 包含空子目录 project
+// When:  在 work 中运行 ohmymem init --yes project
+// Then:
+//   - 退出码 = 0
+//   - work/project/.ohmymem/memory.md 存在
+//   - work/AGENTS.md 不存在
+func TestInit_PathArgument(t *testing.T) {
+	work := setupTestEnv(t, "")
+	project := filepath.Join(work, "project")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+
+	result := runCmd(work, "init", "--yes", "project")
+
+	// 验证命令成功
+	if result.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", result.ExitCode)
+		t.Errorf("stdout: %s", result.Stdout)
+		t.Errorf("stderr: %s", result.Stderr)
+	}
+
+	// 验证相对路径按当前目录解析
+	assertFileContains(t, filepath.Join(project, ".ohmymem", "memory.md"), "schema_version")
+	assertFileExists(t, filepath.Join(project, "AGENTS.md"))
+	assertFileNotExists(t, filepath.Join(work, "AGENTS.md"))
+}
+
+// TestInit_PathErrors tests invalid project paths
+// Given: 空目录
+// When:  ohmymem init --yes 指定不存在的目录，或参数与 --path 冲突
+// Then:
+//   - 退出码 = 1
+//   - stderr 说明原因
+//   - 没有文件被写入
+func TestInit_PathErrors(t *testing.T) {
+	dir := setupTestEnv(t, "")
+	other := setupTestEnv(t, "")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"missing directory", []string{"init", "--yes", "--path", filepath.Join(dir, "missing")}, "project directory"},
+		{"conflicting paths", []string{"init", "--yes", "--path", other, dir}, "conflicting project paths"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCmd(dir, tt.args...)
+
+			// 验证命令失败
+			if result.ExitCode != 1 {
+				t.Errorf("expected exit code 1, got %d", result.ExitCode)
+			}
+			if !strings.Contains(result.Stderr, tt.expected) {
+				t.Errorf("stderr should contain %q, got: %s", tt.expected, result.Stderr)
+			}
+
+			// 验证没有写入文件
+			assertFileNotExists(t, filepath.Join(dir, "AGENTS.md"))
+			assertFileNotExists(t, filepath.Join(other, "AGENTS.md"))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+func exchangeEntries() []domain.Entry {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []domain.Entry{
+		{ID: "id-1", Section: domain.SectionDecisions, TagName: "DB", Content: "Use Postgres, not MySQL", Rationale: "JSONB", CreatedAt: created,
+			ADR: domain.DecisionRecord{Context: "Need JSONB", Options: []string{"Postgres", "MySQL"}, Consequences: "Run Postgres"}},
+		{ID: "id-2", Section: domain.SectionPatterns, TagName: "API", Content: "Wrap errors", Status: domain.StatusDeprecated, Scope: []string{"internal/api/**", "cmd"}},
+	}
+}
+
+func TestParseExchangeFormat(t *testing.T) {
+	for input, want := range map[string]usecase.ExchangeFormat{"json": usecase.FormatJSON, "YML": usecase.FormatYAML, "csv": usecase.FormatCSV} {
+		got, err := usecase.ParseExchangeFormat(input)
+		if err != nil || got != want {
+			t.Errorf("ParseExchangeFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := usecase.ParseExchangeFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteEntries_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := usecase.WriteEntries(&buf, usecase.FormatCSV, exchangeEntries()); err != nil {
+		t.Fatalf("WriteEntries failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d", len(records))
+	}
+	if got := strings.Join(records[1], "|"); got != "id-1|decisions|DB|Use Postgres, not MySQL|JSONB||2026-01-02T03:04:05Z|||||Need JSONB|Postgres; MySQL|Run Postgres" {
+		t.Errorf("unexpected first row: %s", got)
+	}
+	if records[2][5] != "deprecated" {
+		t.Errorf("expected deprecated status, got %q", records[2][5])
+	}
+	if records[2][8] != "internal/api/** cmd" {
+		t.Errorf("expected space-separated scope, got %q", records[2][8])
+	}
+}
+
+func TestWriteEntries_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := usecase.WriteEntries(&buf, usecase.FormatYAML, exchangeEntries()); err != nil {
+		t.Fatalf("WriteEntries failed: %v", err)
+	}
+	for _, want := range []string{"- id: id-1", "section: decisions", "created_at: \"2026-01-02T03:04:05Z\"", "status: deprecated", "context: Need JSONB", "- MySQL"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("YAML output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestReadEntries_Markdown(t *testing.T) {
+	data := []byte(`# Team notes
+## Decisions
+- **[API]** Use REST for public endpoints (*Rationale: client compatibility*)
+  * *Options:* REST; gRPC
+- [Cache] Cache responses in Redis
+## Misc
+* Prefer table-driven tests
+plain text is ignored
+`)
+	if got := usecase.DetectExchangeFormat(data); got != usecase.FormatMarkdown {
+		t.Fatalf("DetectExchangeFormat = %q, want markdown", got)
+	}
+
+	records, err := usecase.ReadEntries(data, usecase.FormatMarkdown)
+	if err != nil {
+		t.Fatalf("ReadEntries failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	first := records[0].Entry
+	if first.Section != "decisions" || first.Tag != "API" || first.Content != "Use REST for public endpoints" || first.Rationale != "client compatibility" {
+		t.Errorf("unexpected first record: %+v", first)
+	}
+	if records[1].Entry.Tag != "Cache" || records[1].Entry.Content != "Cache responses in Redis" {
+		t.Errorf("unexpected second record: %+v", records[1].Entry)
+	}
+	if !reflect.DeepEqual(first.Options, []string{"REST", "gRPC"}) {
+		t.Errorf("expected the nested options on the first record, got %+v", first.DecisionRecord)
+	}
+	if records[2].Entry.Section != "" || records[2].Source != "line 7" {
+		t.Errorf("unexpected third record: %+v", records[2])
+	}
+}
+
+func TestReadEntries_RoundTrip(t *testing.T) {
+	for _, format := range []usecase.ExchangeFormat{usecase.FormatJSON, usecase.FormatYAML, usecase.FormatCSV} {
+		var buf bytes.Buffer
+		if err := usecase.WriteEntries(&buf, format, exchangeEntries()); err != nil {
+			t.Fatalf("%s: WriteEntries failed: %v", format, err)
+		}
+		if got := usecase.DetectExchangeFormat(buf.Bytes()); got != format {
+			t.Errorf("%s: detected as %q", format, got)
+		}
+
+		records, err := usecase.ReadEntries(buf.Bytes(), format)
+		if err != nil {
+			t.Fatalf("%s: ReadEntries failed: %v", format, err)
+		}
+		want := usecase.ToEntriesJSON(exchangeEntries())
+		if len(records) != len(want) {
+			t.Fatalf("%s: expected %d records, got %d", format, len(want), len(records))
+		}
+		for i := range want {
+			if !reflect.DeepEqual(records[i].Entry, want[i]) {
+				t.Errorf("%s: record %d = %+v, want %+v", format, i, records[i].Entry, want[i])
+			}
+		}
+	}
+}
+
+func TestMemoryUseCase_Import(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	uc := usecase.NewMemoryUseCase(projectDir)
+	ctx := context.Background()
+
+	records := []usecase.ImportRecord{
+		{Source: "item 1", Entry: usecase.EntryJSON{Section: "decisions", Tag: "DB", Content: "Use Postgres for storage", CreatedAt: "2025-05-01T10:00:00Z"}},
+		{Source: "item 2", Entry: usecase.EntryJSON{Tag: "Go", Content: "Wrap errors with context"}},
+		{Source: "item 3", Entry: usecase.EntryJSON{Section: "decisions", Tag: "DB", Content: "Use Postgres for storage!"}},
+		{Source: "item 4", Entry: usecase.EntryJSON{Tag: "Old", Content: "Expired hint", ExpiresAt: "2020-01-01T00:00:00Z"}},
+	}
+
+	result, err := uc.Import(ctx, records, usecase.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(result.Entries) != 2 || len(result.Skipped) != 2 {
+		t.Fatalf("expected 2 entries and 2 skipped, got %d and %d", len(result.Entries), len(result.Skipped))
+	}
+	if entries, _ := uc.Service().ListEntries(ctx, domain.EntryFilter{}); len(entries) != 0 {
+		t.Fatalf("dry run wrote %d entries", len(entries))
+	}
+
+	if _, err := uc.Import(ctx, records, usecase.ImportOptions{}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	entries, err := uc.Service().ListEntries(ctx, domain.EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 imported entries, got %d", len(entries))
+	}
+	if entries[0].Section != domain.SectionDecisions || !entries[0].CreatedAt.Equal(time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Section != domain.SectionNote || entries[0].ID == "" || entries[0].ID == entries[1].ID {
+		t.Errorf("unexpected IDs or sections: %+v", entries)
+	}
+
+	invalid := []usecase.ImportRecord{
+		{Source: "item 1", Entry: usecase.EntryJSON{Tag: "Ok", Content: "A valid entry"}},
+		{Source: "item 2", Entry: usecase.EntryJSON{Tag: "Bad", Content: "has <!-- a comment -->"}},
+	}
+	result, err = uc.Import(ctx, invalid, usecase.ImportOptions{})
+	if !errors.Is(err, domain.ErrInvalidImport) {
+		t.Fatalf("expected ErrInvalidImport, got %v", err)
+	}
+	if len(result.Invalid) != 1 || result.Invalid[0].Source != "item 2" {
+		t.Errorf("unexpected invalid records: %+v", result.Invalid)
+	}
+	if entries, _ := uc.Service().ListEntries(ctx, domain.EntryFilter{}); len(entries) != 2 {
+		t.Errorf("invalid import wrote entries: got %d", len(entries))
+	}
+}
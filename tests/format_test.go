@@ -0,0 +1,58 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+func TestFormatMemory(t *testing.T) {
+	content := "---\r\nschema_version: \"0.1\"\r\n---\r\n" +
+		"## Patterns   \r\n" +
+		"<!-- entry-id: 2, tag: [api], time: 2025-01-01T00:00:00Z -->\r\n" +
+		"* **[api]** Return problem details\r\n" +
+		"<!-- entry-end -->\r\n" +
+		"<!-- entry-id: 3, tag: [api], time: 2025-01-01T00:00:00Z -->\r\n" +
+		"* **[api]** Version the routes\r\n" +
+		"<!-- entry-end -->\r\n\r\n\r\n" +
+		"## Constraints\r\n\r\n" +
+		"<!-- entry-id: 1, tag: [go], time: 2025-01-01T00:00:00Z -->\r\n" +
+		"* **[go]** Go 1.24 or higher\t\r\n" +
+		"<!-- entry-end -->\r\n" +
+		"## Patterns\r\n" +
+		"<!-- entry-id: 4, tag: [log], time: 2025-01-01T00:00:00Z -->\r\n" +
+		"* **[log]** Use slog\r\n" +
+		"<!-- entry-end -->"
+
+	want := `---
+schema_version: "0.1"
+---
+
+## Constraints
+
+<!-- entry-id: 1, tag: [go], time: 2025-01-01T00:00:00Z -->
+* **[go]** Go 1.24 or higher
+<!-- entry-end -->
+
+## Patterns
+
+<!-- entry-id: 2, tag: [api], time: 2025-01-01T00:00:00Z -->
+* **[api]** Return problem details
+<!-- entry-end -->
+
+<!-- entry-id: 3, tag: [api], time: 2025-01-01T00:00:00Z -->
+* **[api]** Version the routes
+<!-- entry-end -->
+
+<!-- entry-id: 4, tag: [log], time: 2025-01-01T00:00:00Z -->
+* **[log]** Use slog
+<!-- entry-end -->
+`
+	got := persistence.FormatMemory(content)
+	if got != want {
+		t.Errorf("FormatMemory =\n%s\nwant\n%s", got, want)
+	}
+	if again := persistence.FormatMemory(got); again != got {
+		t.Errorf("FormatMemory not idempotent:\n%s", again)
+	}
+}
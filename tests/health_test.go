@@ -0,0 +1,114 @@
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofrs/flock"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		breaks   func(t *testing.T, basePath string)
+		status   string
+		httpCode int
+		check    func(report usecase.HealthReport) bool
+	}{
+		{
+			name:     "healthy",
+			status:   usecase.HealthStatusOK,
+			httpCode: http.StatusOK,
+			check: func(report usecase.HealthReport) bool {
+				return report.Readable && report.Writable && !report.Locked
+			},
+		},
+		{
+			name: "missing memory file",
+			breaks: func(t *testing.T, basePath string) {
+				if err := os.Remove(filepath.Join(basePath, ".ohmymem", "memory.md")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			status:   usecase.HealthStatusDegraded,
+			httpCode: http.StatusServiceUnavailable,
+			check: func(report usecase.HealthReport) bool {
+				return !report.Readable && !report.Writable
+			},
+		},
+		{
+			name: "locked memory file",
+			breaks: func(t *testing.T, basePath string) {
+				// Simulate another process holding the write lock
+				other := flock.New(filepath.Join(basePath, ".ohmymem", ".memory.lock"))
+				if err := other.Lock(); err != nil {
+					t.Fatalf("failed to take lock: %v", err)
+				}
+				t.Cleanup(func() { other.Unlock() })
+			},
+			status:   usecase.HealthStatusDegraded,
+			httpCode: http.StatusServiceUnavailable,
+			check: func(report usecase.HealthReport) bool {
+				return report.Readable && report.Writable && report.Locked
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basePath := setupInitializedProject(t)
+			health := usecase.NewHealthChecker(basePath, &testClock{})
+			s, _, err := usecase.NewServer(basePath, usecase.ServerOptions{Health: health, DisableAudit: true})
+			if err != nil {
+				t.Fatalf("NewServer: %v", err)
+			}
+			if tt.breaks != nil {
+				tt.breaks(t, basePath)
+			}
+
+			report := health.Check()
+			if report.Status != tt.status || !tt.check(report) {
+				t.Errorf("expected status %s, got %+v", tt.status, report)
+			}
+
+			// GET /healthz answers with the status code and leaves out the paths
+			rec := httptest.NewRecorder()
+			usecase.NewHealthHandler(health).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != tt.httpCode {
+				t.Errorf("expected /healthz status %d, got %d", tt.httpCode, rec.Code)
+			}
+			var served usecase.HealthReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+				t.Fatalf("expected JSON, got %v:\n%s", err, rec.Body)
+			}
+			if served.Status != tt.status || !tt.check(served) {
+				t.Errorf("expected /healthz status %s, got %+v", tt.status, served)
+			}
+			if served.BasePath != "" || served.MemoryPath != "" {
+				t.Errorf("expected no paths from /healthz, got %+v", served)
+			}
+
+			// ohmymem_ping reports the same, paths included
+			text, isError := callTool(t, s, "ohmymem_ping", map[string]any{})
+			if isError {
+				t.Fatalf("ping failed: %s", text)
+			}
+			var pinged usecase.HealthReport
+			if err := json.Unmarshal([]byte(text), &pinged); err != nil {
+				t.Fatalf("expected JSON, got %v:\n%s", err, text)
+			}
+			if pinged.Status != tt.status || !tt.check(pinged) {
+				t.Errorf("expected ping status %s, got %+v", tt.status, pinged)
+			}
+			if pinged.MemoryPath != filepath.Join(basePath, ".ohmymem", "memory.md") {
+				t.Errorf("expected the memory path, got %q", pinged.MemoryPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// gitRun runs git in dir, failing the test on error
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestHookUseCase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	projectDir := setupInitializedProject(t)
+	gitRun(t, projectDir, "init", "-q")
+	ctx := context.Background()
+	hooks := usecase.NewHookUseCase(projectDir)
+
+	installed, err := hooks.Install(ctx, usecase.Hooks, false)
+	if err != nil || len(installed) != 2 {
+		t.Fatalf("Install = %v, %v", installed, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".git", "hooks", usecase.HookPreCommit)); err != nil {
+		t.Errorf("pre-commit hook not written: %v", err)
+	}
+
+	uc := usecase.NewMemoryUseCase(projectDir)
+	if _, err := uc.Add(ctx, domain.AppendInput{Category: "decisions", Tag: "DB", Content: "Use Postgres for storage"}, usecase.AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, projectDir, "add", "-A")
+
+	problems, err := hooks.PreCommit(ctx)
+	if err != nil || len(problems) != 0 {
+		t.Errorf("PreCommit on a valid file = %v, %v", problems, err)
+	}
+
+	msgFile := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(msgFile, []byte("Add storage\n# Please enter the commit message\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := hooks.PrepareCommitMsg(ctx, msgFile, "message"); err != nil {
+		t.Fatalf("PrepareCommitMsg: %v", err)
+	}
+	msg, _ := os.ReadFile(msgFile)
+	if !strings.Contains(string(msg), usecase.DecisionTrailer+": [Database] Use Postgres for storage") || !strings.HasSuffix(string(msg), "# Please enter the commit message\n") {
+		t.Errorf("trailer not inserted before the comments:\n%s", msg)
+	}
+
+	memoryPath := filepath.Join(projectDir, ".ohmymem", "memory.md")
+	content, _ := os.ReadFile(memoryPath)
+	if err := os.WriteFile(memoryPath, []byte(string(content)+"<!-- entry-id: broken -->\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, projectDir, "add", "-A")
+	if problems, err := hooks.PreCommit(ctx); err != nil || len(problems) == 0 {
+		t.Errorf("PreCommit on a broken anchor = %v, %v; want problems", problems, err)
+	}
+
+	if removed, err := hooks.Uninstall(ctx); err != nil || len(removed) != 2 {
+		t.Errorf("Uninstall = %v, %v", removed, err)
+	}
+}
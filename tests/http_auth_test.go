@@ -0,0 +1,73 @@
+package main_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/infrastructure/transport"
+)
+
+func TestBearerAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := transport.BearerAuth("s3cret", next)
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"missing header", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", "Basic s3cret", http.StatusUnauthorized},
+		{"valid token", "Bearer s3cret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithClientAddr(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.RemoteAddr = "192.0.2.7:51234"
+	if got := transport.ClientAddr(transport.WithClientAddr(context.Background(), req)); got != "192.0.2.7" {
+		t.Errorf("ClientAddr() = %q, want the host without its port", got)
+	}
+	if got := transport.ClientAddr(context.Background()); got != "" {
+		t.Errorf("ClientAddr() = %q without HTTP, want empty", got)
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:8766", true},
+		{"localhost:8765", true},
+		{"[::1]:8765", true},
+		{"0.0.0.0:8766", false},
+		{":8766", false},
+		{"192.168.1.10:8766", false},
+		{"127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := transport.IsLoopbackAddr(tt.addr); got != tt.want {
+			t.Errorf("IsLoopbackAddr(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
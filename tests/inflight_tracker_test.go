@@ -0,0 +1,45 @@
+package main_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestInFlightTracker_Drain(t *testing.T) {
+	tracker := usecase.NewInFlightTracker()
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	handler := tracker.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	go handler(context.Background(), mcp.CallToolRequest{})
+	<-started
+
+	// Drain times out while the call is still running
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tracker.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Drain() error = %v, want deadline exceeded", err)
+	}
+
+	// New calls are rejected once draining started
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil || result == nil || !result.IsError {
+		t.Fatalf("expected shutdown error result, got %+v, %v", result, err)
+	}
+
+	close(release)
+	if err := tracker.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package main_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/detector"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+func TestInitUseCase_DryRun(t *testing.T) {
+	projectDir := t.TempDir()
+	agentsPath := filepath.Join(projectDir, "AGENTS.md")
+	if err := os.WriteFile(agentsPath, []byte("# Rules\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := usecase.NewInitUseCase(detector.NewCompositeDetector()).Execute(usecase.InitOptions{
+		RootPath:    projectDir,
+		ProjectInfo: &domain.ProjectInfo{Language: "go"},
+		RepoURLs:    []string{templates},
+		Editors:     []string{"copilot"},
+		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	actions := map[string]string{}
+	for _, change := range result.Changes {
+		actions[filepath.Base(change.Path)] = change.Action
+		if filepath.Base(change.Path) == "AGENTS.md" && !strings.Contains(change.Diff, "+ <!-- ohmymem:start -->") {
+			t.Errorf("AGENTS.md diff missing the added block:\n%s", change.Diff)
+		}
+	}
+	if !strings.HasPrefix(actions["memory.md"], "create") || actions["AGENTS.md"] != "append ohmymem block" || actions["copilot-instructions.md"] != "symlink → ../AGENTS.md" {
+		t.Errorf("unexpected plan: %v", actions)
+	}
+
+	if _, err := os.Stat(filepath.Join(projectDir, ".ohmymem")); !os.IsNotExist(err) {
+		t.Error("dry run created .ohmymem")
+	}
+	if data, _ := os.ReadFile(agentsPath); string(data) != "# Rules\n" {
+		t.Errorf("dry run modified AGENTS.md: %q", data)
+	}
+}
+
+func TestInitUseCase_Confirm(t *testing.T) {
+	projectDir := t.TempDir()
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var previewed string
+	opts := usecase.InitOptions{
+		RootPath:    projectDir,
+		ProjectInfo: &domain.ProjectInfo{Language: "go"},
+		RepoURLs:    []string{templates},
+		Editors:     []string{},
+		Confirm: func(memoryContent, agentsBlock string) (bool, error) {
+			previewed = memoryContent
+			return false, nil
+		},
+	}
+	if _, err := usecase.NewInitUseCase(detector.NewCompositeDetector()).Execute(opts); !errors.Is(err, usecase.ErrInitCancelled) {
+		t.Fatalf("Execute declined = %v, want ErrInitCancelled", err)
+	}
+	if !strings.Contains(previewed, "## Constraints") {
+		t.Errorf("Confirm got %q", previewed)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".ohmymem")); !os.IsNotExist(err) {
+		t.Error("declined init created .ohmymem")
+	}
+
+	opts.Confirm = func(string, string) (bool, error) { return true, nil }
+	if _, err := usecase.NewInitUseCase(detector.NewCompositeDetector()).Execute(opts); err != nil {
+		t.Fatalf("Execute confirmed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(projectDir, ".ohmymem", "memory.md")); string(data) != previewed {
+		t.Error("written memory.md differs from the preview")
+	}
+}
+
+func TestCompositeDetector_WithOverrides(t *testing.T) {
+	projectDir := t.TempDir()
+	goMod := "module example.com/api\n\nrequire github.com/labstack/echo/v4 v4.11.0\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := detector.NewCompositeDetector().WithOverrides(domain.ProjectOverrides{
+		Database:    "mysql",
+		ProjectType: "cli",
+	}).Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Language != "go" || info.Framework != "echo" || info.Database != "mysql" || info.ProjectType != "cli" {
+		t.Errorf("info = %+v, want detected go/echo with overridden database and type", info)
+	}
+
+	// A language override skips the detectors of other languages
+	info, err = detector.NewCompositeDetector().WithOverrides(domain.ProjectOverrides{Language: "python"}).Detect(projectDir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Language != "python" || info.Framework != "" {
+		t.Errorf("info = %+v, want python without the Go framework", info)
+	}
+}
+
+func TestInitUseCase_MigrateIsNoop(t *testing.T) {
+	projectDir := t.TempDir()
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := usecase.NewInitUseCase(detector.NewCompositeDetector()).Execute(usecase.InitOptions{
+		RootPath:    projectDir,
+		ProjectInfo: &domain.ProjectInfo{Language: "go"},
+		RepoURLs:    []string{templates},
+		Editors:     []string{},
+	}); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	memoryPath := filepath.Join(projectDir, ".ohmymem", "memory.md")
+	initialized, err := os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(initialized), "<!-- template-entry") {
+		t.Fatalf("expected template entries in the initialized memory:\n%s", initialized)
+	}
+
+	// Template blocks are neither legacy entries for check nor for migrate
+	if inspection := persistence.InspectContent(string(initialized)); inspection.LegacyEntries != 0 || inspection.BrokenAnchors != 0 {
+		t.Errorf("unexpected inspection of the initialized memory: %+v", inspection)
+	}
+	repo := persistence.NewMemoryRepository(projectDir, &testUUID{}, &testClock{})
+	result, err := repo.MigrateLegacy(context.Background(), false)
+	if err != nil {
+		t.Fatalf("MigrateLegacy: %v", err)
+	}
+	if result.Changed() {
+		t.Errorf("expected migrating init output to be a no-op, got %+v", result)
+	}
+	if data, _ := os.ReadFile(memoryPath); string(data) != string(initialized) {
+		t.Errorf("migrate rewrote the initialized memory:\n%s", data)
+	}
+
+	// An anchor nested in a template block, as older versions of migrate wrote, is broken
+	nested := strings.Replace(string(initialized), "<!-- template-entry", "<!-- template-entry, tag: [x], source: go -->\n<!-- entry-id: 1, tag: [x], time: 2025-01-01T00:00:00Z -->\n* **[x]** Nested\n<!-- entry-end -->\n<!-- entry-end -->\n\n<!-- template-entry", 1)
+	if report := usecase.ValidateMemory(nested); report.Valid(false) {
+		t.Errorf("expected nested anchors to be reported, got %+v", report)
+	}
+}
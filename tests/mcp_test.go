@@ -0,0 +1,315 @@
+package main_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// callTool calls a tool through the server the way a client does and returns the text
+// of its result
+func callTool(t *testing.T, s *server.MCPServer, name string, args map[string]any) (string, bool) {
+	t.Helper()
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, ok := s.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("%s: expected a result, got %+v", name, response)
+	}
+	result, ok := response.Result.(mcp.CallToolResult)
+	if !ok || len(result.Content) == 0 {
+		t.Fatalf("%s: expected a tool result, got %+v", name, response.Result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("%s: expected text content, got %+v", name, result.Content[0])
+	}
+	return text.Text, result.IsError
+}
+
+// newTestServer creates an MCP server on an initialized project, without audit log
+func newTestServer(t *testing.T) (*server.MCPServer, string) {
+	t.Helper()
+	basePath := setupInitializedProject(t)
+	s, _, err := usecase.NewServer(basePath, usecase.ServerOptions{DisableAudit: true})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	return s, basePath
+}
+
+func TestMcpTools_Annotations(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	tools := s.ListTools()
+	if len(tools) == 0 {
+		t.Fatal("expected registered tools")
+	}
+	for name, tool := range tools {
+		annotations := tool.Tool.Annotations
+		if annotations.Title == "" || annotations.ReadOnlyHint == nil || annotations.DestructiveHint == nil {
+			t.Errorf("%s: expected a title, readOnlyHint and destructiveHint, got %+v", name, annotations)
+			continue
+		}
+		readOnly := name != "ohmymem_capture"
+		if *annotations.ReadOnlyHint != readOnly {
+			t.Errorf("%s: expected readOnlyHint %v", name, readOnly)
+		}
+		// Capturing only appends, so no tool is destructive yet
+		if *annotations.DestructiveHint {
+			t.Errorf("%s: expected no destructiveHint", name)
+		}
+	}
+}
+
+func TestMcpTools_ReadJSON(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	if text, isError := callTool(t, s, "ohmymem_capture", map[string]any{
+		"category": "constraints",
+		"tag":      "API",
+		"content":  "Handlers return problem+json errors",
+	}); isError {
+		t.Fatalf("capture failed: %s", text)
+	}
+
+	// Markdown stays the default
+	text, _ := callTool(t, s, "ohmymem_read", map[string]any{})
+	if !strings.Contains(text, "## Constraints") || json.Valid([]byte(text)) {
+		t.Errorf("expected Markdown by default, got:\n%s", text)
+	}
+
+	text, isError := callTool(t, s, "ohmymem_read", map[string]any{"format": "json"})
+	if isError {
+		t.Fatalf("read failed: %s", text)
+	}
+	var memory struct {
+		Sections []struct {
+			Category string              `json:"category"`
+			Entries  []usecase.EntryJSON `json:"entries"`
+		} `json:"sections"`
+	}
+	if err := json.Unmarshal([]byte(text), &memory); err != nil {
+		t.Fatalf("expected JSON, got %v:\n%s", err, text)
+	}
+	var captured *usecase.EntryJSON
+	for _, section := range memory.Sections {
+		for i, entry := range section.Entries {
+			if entry.Content == "Handlers return problem+json errors" {
+				captured = &section.Entries[i]
+			}
+		}
+	}
+	if captured == nil {
+		t.Fatalf("expected the captured entry, got:\n%s", text)
+	}
+	if captured.ID == "" || captured.CreatedAt == "" || captured.Section != "constraints" || captured.Tag != "API" {
+		t.Errorf("expected the ID, timestamp, section and tag of the entry, got %+v", *captured)
+	}
+
+	text, isError = callTool(t, s, "ohmymem_read_section", map[string]any{"category": "constraints", "format": "json"})
+	if isError {
+		t.Fatalf("read section failed: %s", text)
+	}
+	var section struct {
+		Category string              `json:"category"`
+		Entries  []usecase.EntryJSON `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(text), &section); err != nil {
+		t.Fatalf("expected JSON, got %v:\n%s", err, text)
+	}
+	if section.Category != "constraints" || len(section.Entries) != 1 || section.Entries[0].ID != captured.ID {
+		t.Errorf("expected the section with the captured entry, got:\n%s", text)
+	}
+}
+
+func TestMcpTools_CaptureDryRun(t *testing.T) {
+	s, basePath := newTestServer(t)
+	memoryPath := filepath.Join(basePath, ".ohmymem", "memory.md")
+
+	capture := map[string]any{
+		"category":  "constraints",
+		"tag":       "API",
+		"content":   "Handlers return problem+json errors",
+		"rationale": "Clients parse one error shape",
+	}
+	if text, isError := callTool(t, s, "ohmymem_capture", capture); isError {
+		t.Fatalf("capture failed: %s", text)
+	}
+	before, err := os.ReadFile(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		isError  bool
+		expected string
+	}{
+		{
+			name:     "new entry",
+			args:     map[string]any{"category": "patterns", "tag": "Docs", "content": "Describe every exported identifier"},
+			expected: "<!-- entry-id:",
+		},
+		{
+			name:     "duplicate",
+			args:     capture,
+			expected: "A real capture would be rejected unless force: true is passed",
+		},
+		{
+			name:     "invalid",
+			args:     map[string]any{"category": "constraints", "tag": "API", "content": "two\nlines"},
+			isError:  true,
+			expected: "Validation failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"dry_run": true}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+			text, isError := callTool(t, s, "ohmymem_capture", args)
+			if isError != tt.isError || !strings.Contains(text, tt.expected) {
+				t.Errorf("expected %q (error %v), got (error %v):\n%s", tt.expected, tt.isError, isError, text)
+			}
+			if !tt.isError && !strings.Contains(text, "nothing was written") {
+				t.Errorf("expected a dry run notice, got:\n%s", text)
+			}
+
+			after, err := os.ReadFile(memoryPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(after) != string(before) {
+				t.Errorf("expected a dry run to leave the memory file unchanged, got:\n%s", after)
+			}
+		})
+	}
+}
+
+// getPrompt gets a prompt through the server the way a client does and returns the
+// text of its first message
+func getPrompt(t *testing.T, s *server.MCPServer, name string, args map[string]string) string {
+	t.Helper()
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "prompts/get",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, ok := s.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("%s: expected a result, got %+v", name, response)
+	}
+	result, ok := response.Result.(mcp.GetPromptResult)
+	if !ok || len(result.Messages) == 0 {
+		t.Fatalf("%s: expected a prompt result, got %+v", name, response.Result)
+	}
+	text, ok := result.Messages[0].Content.(mcp.TextContent)
+	if !ok {
+		t.Fatalf("%s: expected text content, got %+v", name, result.Messages[0].Content)
+	}
+	return text.Text
+}
+
+func TestMcpTools_InactiveConstraints(t *testing.T) {
+	s, basePath := newTestServer(t)
+	capture := func(args map[string]any) {
+		t.Helper()
+		args["category"] = "constraints"
+		if text, isError := callTool(t, s, "ohmymem_capture", args); isError {
+			t.Fatalf("capture failed: %s", text)
+		}
+	}
+
+	capture(map[string]any{"tag": "API", "content": "Handlers return plain text errors"})
+	capture(map[string]any{"tag": "Auth", "content": "Sessions expire after one hour"})
+	svc := domain.NewMemoryService(persistence.NewMemoryRepository(basePath, &testUUID{}, &testClock{}))
+	ctx := context.Background()
+	section, err := svc.ReadSection(ctx, domain.SectionConstraints)
+	if err != nil || len(section.Entries) != 2 {
+		t.Fatalf("expected 2 constraints, got %+v (%v)", section, err)
+	}
+	superseded, deprecated := section.Entries[0], section.Entries[1]
+	capture(map[string]any{"tag": "API", "content": "Handlers return problem+json errors", "supersedes": superseded.ID})
+	if _, err := svc.DeprecateEntries(ctx, []domain.Entry{deprecated}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// read_section collapses superseded entries like read does
+	text, _ := callTool(t, s, "ohmymem_read_section", map[string]any{"category": "constraints"})
+	if strings.Contains(text, "plain text errors") || !strings.Contains(text, "<!-- superseded: "+superseded.ID) {
+		t.Errorf("expected the superseded constraint collapsed, got:\n%s", text)
+	}
+	if !strings.Contains(text, "problem+json") {
+		t.Errorf("expected the active constraint, got:\n%s", text)
+	}
+
+	// The boot prompt only lists the constraints in force
+	prompt := getPrompt(t, s, usecase.BootPromptName, nil)
+	for _, content := range []string{"plain text errors", superseded.ID, "Sessions expire"} {
+		if strings.Contains(prompt, content) {
+			t.Errorf("expected %q left out of the boot prompt, got:\n%s", content, prompt)
+		}
+	}
+	if !strings.Contains(prompt, "problem+json") {
+		t.Errorf("expected the active constraint in the boot prompt, got:\n%s", prompt)
+	}
+}
+
+func TestMcpPrompts_Boot(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	prompt := getPrompt(t, s, usecase.BootPromptName, nil)
+	if !strings.HasPrefix(prompt, domain.BootProtocol) {
+		t.Errorf("expected the boot protocol first, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "## Constraints\n\nNo constraints recorded yet.") {
+		t.Errorf("expected a note that no constraints are recorded, got:\n%s", prompt)
+	}
+
+	for _, args := range []map[string]any{
+		{"category": "constraints", "tag": "API", "content": "Handlers return problem+json errors"},
+		{"category": "decisions", "tag": "DB", "content": "Use PostgreSQL for storage"},
+	} {
+		if text, isError := callTool(t, s, "ohmymem_capture", args); isError {
+			t.Fatalf("capture failed: %s", text)
+		}
+	}
+
+	prompt = getPrompt(t, s, usecase.BootPromptName, nil)
+	if !strings.HasPrefix(prompt, domain.BootProtocol) || !strings.Contains(prompt, "## Constraints\n\n<!-- entry-id:") {
+		t.Errorf("expected the boot protocol and the Constraints section, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "* **[API]** Handlers return problem+json errors") || strings.Contains(prompt, "No constraints recorded yet") {
+		t.Errorf("expected the constraint rendered, got:\n%s", prompt)
+	}
+	// Only constraints are injected
+	if strings.Contains(prompt, "PostgreSQL") || strings.Contains(prompt, "## Decisions") {
+		t.Errorf("expected no other sections, got:\n%s", prompt)
+	}
+}
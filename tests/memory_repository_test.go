@@ -2,12 +2,17 @@ package main_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/gofrs/flock"
+
 	"github.com/herewei/ohmymem-core/internal/domain"
 	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
 )
@@ -133,6 +138,49 @@ func TestMemoryRepository_AppendEntry(t *testing.T) {
 	}
 }
 
+func TestMemoryRepository_ApplyEntries(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	timeProvider := &testClock{}
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, timeProvider)
+	ctx := context.Background()
+
+	old := domain.Entry{ID: "id-old", Section: domain.SectionConstraints, Tag: "[API]", TagName: "API", Content: "Handlers return plain text errors", CreatedAt: timeProvider.Now()}
+	if err := repo.AppendEntry(ctx, domain.SectionConstraints, &old); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, err := repo.ReadAll(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	successor := domain.Entry{ID: "id-new", Section: domain.SectionConstraints, Tag: "[API]", TagName: "API", Content: "Handlers return problem+json errors", CreatedAt: timeProvider.Now()}
+	missing := domain.Entry{ID: "id-missing", Section: domain.SectionConstraints, Tag: "[API]", TagName: "API", Content: "Gone", CreatedAt: timeProvider.Now()}
+
+	// A missing update fails the whole write, the append included
+	if err := repo.ApplyEntries(ctx, []domain.Entry{successor}, []domain.Entry{missing}); err == nil {
+		t.Error("expected an error for a missing entry")
+	}
+	if after, _ := repo.ReadAll(ctx); after != before {
+		t.Errorf("expected nothing written, got:\n%s", after)
+	}
+
+	superseded := old
+	superseded.Status = domain.StatusSuperseded
+	superseded.SupersededBy = successor.ID
+	if err := repo.ApplyEntries(ctx, []domain.Entry{successor}, []domain.Entry{superseded}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	section, err := repo.GetSection(ctx, domain.SectionConstraints)
+	if err != nil || len(section.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v (%v)", section, err)
+	}
+	if !section.Entries[0].IsSuperseded() || section.Entries[0].SupersededBy != "id-new" || section.Entries[1].ID != "id-new" {
+		t.Errorf("expected the old entry superseded by the new one, got %+v", section.Entries)
+	}
+}
+
 func TestMemoryRepository_AppendMultipleEntries(t *testing.T) {
 	tmpDir := setupTestDir(t)
 	defer os.RemoveAll(tmpDir)
@@ -298,3 +346,378 @@ func TestMemoryRepository_DirPath(t *testing.T) {
 		t.Errorf("expected dir path %s, got %s", expectedDir, repo.DirPath())
 	}
 }
+
+func TestMemoryRepository_GetSection_ParsesAnchoredFields(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	timeProvider := &testClock{}
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, timeProvider)
+
+	entry := &domain.Entry{
+		ID:        "0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b",
+		Tag:       "[Testing]",
+		TagName:   "Testing",
+		Content:   "Prefer table-driven tests",
+		Rationale: "Keeps cases readable",
+		CreatedAt: timeProvider.Now(),
+		Scope:     []string{"internal/**"},
+		Author:    "agent:Claude Sonnet 4 via claude-code 1.0.3",
+	}
+	if err := repo.AppendEntry(context.Background(), domain.SectionAntiPatterns, entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	section, err := repo.GetSection(context.Background(), domain.SectionAntiPatterns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(section.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(section.Entries))
+	}
+
+	got := section.Entries[0]
+	if got.ID != entry.ID {
+		t.Errorf("expected ID %s, got %s", entry.ID, got.ID)
+	}
+	if got.Content != entry.Content {
+		t.Errorf("expected content %q, got %q", entry.Content, got.Content)
+	}
+	if got.Rationale != entry.Rationale {
+		t.Errorf("expected rationale %q, got %q", entry.Rationale, got.Rationale)
+	}
+	if !got.CreatedAt.Equal(entry.CreatedAt) {
+		t.Errorf("expected time %v, got %v", entry.CreatedAt, got.CreatedAt)
+	}
+	if len(got.Scope) != 1 || got.Scope[0] != "internal/**" {
+		t.Errorf("expected scope [internal/**], got %v", got.Scope)
+	}
+	if got.Author != entry.Author || got.AuthorKind() != domain.AuthorAgent {
+		t.Errorf("expected author %q, got %q", entry.Author, got.Author)
+	}
+}
+
+func TestMemoryRepository_GetSection_MixedBlocks(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	if err := repo.EnsureDir(); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	memory := `## Constraints
+
+<!-- template-entry, tag: [go, error], source: go -->
+* **[go, error]** Wrap errors with context (*理由: Template default*)
+<!-- entry-end -->
+
+<!-- entry-id: 0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b, tag: [DB], time: 2024-01-01T00:00:00Z -->
+* **[DB]** Use Postgres
+<!-- entry-end -->
+
+* **[Go]** Use Go 1.24 (*Rationale: generics*)
+
+## Decisions
+`
+	if err := os.WriteFile(repo.FilePath(), []byte(memory), 0644); err != nil {
+		t.Fatalf("failed to write memory file: %v", err)
+	}
+
+	section, err := repo.GetSection(context.Background(), domain.SectionConstraints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(section.Entries) != 3 {
+		t.Fatalf("expected template, anchored and legacy entries, got %+v", section.Entries)
+	}
+	template, anchored, legacy := section.Entries[0], section.Entries[1], section.Entries[2]
+	if template.Template != "go" || template.Content != "Wrap errors with context" || template.Rationale != "Template default" || template.Origin() != "template:go" {
+		t.Errorf("unexpected template entry %+v", template)
+	}
+	if anchored.ID != "0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b" || anchored.Template != "" {
+		t.Errorf("unexpected anchored entry %+v", anchored)
+	}
+	if legacy.ID != "" || legacy.Rationale != "generics" || legacy.Origin() != "legacy" {
+		t.Errorf("unexpected legacy entry %+v", legacy)
+	}
+	if entries := persistence.ParseMemory(memory); len(entries) != 3 {
+		t.Errorf("expected ParseMemory to read every block, got %d entries", len(entries))
+	}
+
+	// Each entry renders back to the block it was read from
+	svc := domain.NewMemoryService(repo)
+	for _, entry := range section.Entries {
+		rendered, err := svc.RenderEntry(entry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(memory, rendered+"\n") {
+			t.Errorf("expected %s entry to render as stored, got:\n%s", entry.Origin(), rendered)
+		}
+	}
+}
+
+func TestApplyMerge_AddsTemplateBlock(t *testing.T) {
+	theirs := `## Constraints
+
+<!-- template-entry, tag: [go, error], source: go -->
+* **[go, error]** Wrap errors with context (*理由: Template default*)
+<!-- entry-end -->
+
+## Decisions
+`
+	ours := "## Constraints\n\n## Decisions\n"
+	result := domain.MergeEntries(nil, persistence.ParseMemory(ours), persistence.ParseMemory(theirs))
+	merged := persistence.ApplyMerge(ours, result)
+
+	entries := persistence.ParseMemory(merged)
+	if len(entries) != 1 || entries[0].Template != "go" || entries[0].Rationale != "Template default" {
+		t.Errorf("expected the template entry to stay a template block, got:\n%s", merged)
+	}
+}
+
+func TestMemoryRepository_AppendEntry_ConcurrentWriters(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repo.AppendEntry(context.Background(), domain.SectionNote, &domain.Entry{
+				ID:        fmt.Sprintf("writer-%d", i),
+				Tag:       "[Concurrency]",
+				TagName:   "Concurrency",
+				Content:   fmt.Sprintf("Entry from writer %d", i),
+				CreatedAt: createdAt,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	section, err := repo.GetSection(context.Background(), domain.SectionNote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(section.Entries) != writers {
+		t.Errorf("expected %d entries, got %d", writers, len(section.Entries))
+	}
+}
+
+func TestMemoryRepository_AppendEntry_BusyLock(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	repo.SetLockRetry(2, time.Millisecond)
+	if err := repo.EnsureDir(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulate another process holding the lock
+	other := flock.New(filepath.Join(repo.DirPath(), ".memory.lock"))
+	if err := other.Lock(); err != nil {
+		t.Fatalf("failed to take lock: %v", err)
+	}
+	defer other.Unlock()
+
+	err := repo.AppendEntry(context.Background(), domain.SectionNote, &domain.Entry{
+		ID:      "busy-1",
+		Tag:     "[Lock]",
+		TagName: "Lock",
+		Content: "Should not be written",
+	})
+	if !errors.Is(err, domain.ErrMemoryBusy) {
+		t.Fatalf("expected ErrMemoryBusy, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "retried 2 times") {
+		t.Errorf("expected retry count in error, got %q", err.Error())
+	}
+}
+
+func TestMemoryRepository_MigrateLegacy(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	if err := repo.EnsureDir(); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	legacy := `## Constraints
+
+* **[Go]** Use Go 1.24 (*Rationale: generics*)
+<!-- entry-id: kept, tag: [A], time: 2024-01-01T00:00:00Z -->
+* **[A]** Already anchored
+<!-- entry-end -->
+
+## Decisions
+
+* **[DB]** Use Postgres
+`
+	if err := os.WriteFile(repo.FilePath(), []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write memory file: %v", err)
+	}
+	ctx := context.Background()
+
+	result, err := repo.MigrateLegacy(ctx, true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(result.Entries) != 2 || !result.SchemaUpgraded || result.BackupPath != "" {
+		t.Fatalf("unexpected dry run result: %+v", result)
+	}
+	if data, _ := os.ReadFile(repo.FilePath()); string(data) != legacy {
+		t.Fatal("dry run modified the memory file")
+	}
+
+	result, err = repo.MigrateLegacy(ctx, false)
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if backup, err := os.ReadFile(result.BackupPath); err != nil || string(backup) != legacy {
+		t.Errorf("backup missing or different: %v", err)
+	}
+
+	section, err := repo.GetSection(ctx, domain.SectionConstraints)
+	if err != nil {
+		t.Fatalf("GetSection failed: %v", err)
+	}
+	if len(section.Entries) != 2 || section.Entries[0].Rationale != "generics" || section.Entries[1].ID != "kept" {
+		t.Errorf("unexpected constraints after migration: %+v", section.Entries)
+	}
+	section, err = repo.GetSection(ctx, domain.SectionDecisions)
+	if err != nil {
+		t.Fatalf("GetSection failed: %v", err)
+	}
+	if len(section.Entries) != 1 || section.Entries[0].ID == "" {
+		t.Errorf("unexpected decisions after migration: %+v", section.Entries)
+	}
+
+	content, _ := repo.ReadAll(ctx)
+	if !strings.HasPrefix(content, "---\nschema_version: \"0.1\"\nentry_format: \"anchored\"\n---\n") {
+		t.Errorf("frontmatter not upgraded:\n%s", content)
+	}
+
+	result, err = repo.MigrateLegacy(ctx, false)
+	if err != nil {
+		t.Fatalf("second migrate failed: %v", err)
+	}
+	if result.Changed() {
+		t.Errorf("expected second migration to be a no-op, got %+v", result)
+	}
+}
+
+func TestMemoryRepository_AppendEntry_EscapesContent(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	ctx := context.Background()
+
+	entries := []domain.Entry{
+		{ID: "test-uuid-1", Tag: "[Go]", TagName: "Go", Content: "Pass `context.Context` first", Rationale: "Use `<-ctx.Done()` to cancel"},
+		{ID: "test-uuid-2", Tag: "[Go]", TagName: "Go", Content: "Return Map<K, V> rather than <nil>", Rationale: "Callers range over it"},
+		{ID: "test-uuid-3", Tag: "[Web]", TagName: "Web", Content: "Write &lt; in templates, not <", Rationale: "a > b"},
+	}
+	for i := range entries {
+		if err := repo.AppendEntry(ctx, domain.SectionConstraints, &entries[i]); err != nil {
+			t.Fatalf("AppendEntry failed: %v", err)
+		}
+	}
+
+	raw, err := os.ReadFile(repo.FilePath())
+	if err != nil {
+		t.Fatalf("failed to read memory file: %v", err)
+	}
+	for _, want := range []string{"Pass `context.Context` first", "Map&lt;K, V&gt; rather than &lt;nil&gt;", "Write &amp;lt; in templates, not &lt;", "`<-ctx.Done()`"} {
+		if !strings.Contains(string(raw), want) {
+			t.Errorf("expected memory file to contain %q:\n%s", want, raw)
+		}
+	}
+
+	section, err := repo.GetSection(ctx, domain.SectionConstraints)
+	if err != nil {
+		t.Fatalf("GetSection failed: %v", err)
+	}
+	if len(section.Entries) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(section.Entries))
+	}
+	for i, entry := range section.Entries {
+		if entry.Content != entries[i].Content || entry.Rationale != entries[i].Rationale {
+			t.Errorf("entry %d round-tripped as %q (%q), want %q (%q)", i, entry.Content, entry.Rationale, entries[i].Content, entries[i].Rationale)
+		}
+	}
+}
+
+func TestMemoryRepository_DecisionRecord(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	ctx := context.Background()
+
+	entry := &domain.Entry{
+		ID:        "test-uuid-1",
+		Tag:       "[Database]",
+		TagName:   "Database",
+		Content:   "Use PostgreSQL as the primary database",
+		Rationale: "ACID compliance",
+		ADR: domain.DecisionRecord{
+			Context:      "Need JSONB and transactions for Map<K, V> payloads",
+			Options:      []string{"PostgreSQL", "MySQL", "`mongo`"},
+			Consequences: "Operations must run PostgreSQL 16",
+		},
+	}
+	if err := repo.AppendEntry(ctx, domain.SectionDecisions, entry); err != nil {
+		t.Fatalf("AppendEntry failed: %v", err)
+	}
+	plain := &domain.Entry{ID: "test-uuid-2", Tag: "[API]", TagName: "API", Content: "Use REST"}
+	if err := repo.AppendEntry(ctx, domain.SectionDecisions, plain); err != nil {
+		t.Fatalf("AppendEntry failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(repo.FilePath())
+	if err != nil {
+		t.Fatalf("failed to read memory file: %v", err)
+	}
+	want := "* **[Database]** Use PostgreSQL as the primary database (*Rationale: ACID compliance*)\n" +
+		"  * *Context:* Need JSONB and transactions for Map&lt;K, V&gt; payloads\n" +
+		"  * *Options:* PostgreSQL; MySQL; `mongo`\n" +
+		"  * *Consequences:* Operations must run PostgreSQL 16\n" +
+		"<!-- entry-end -->"
+	if !strings.Contains(string(raw), want) {
+		t.Errorf("expected memory file to contain:\n%s\ngot:\n%s", want, raw)
+	}
+
+	section, err := repo.GetSection(ctx, domain.SectionDecisions)
+	if err != nil {
+		t.Fatalf("GetSection failed: %v", err)
+	}
+	if len(section.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(section.Entries))
+	}
+	if got := section.Entries[0]; got.Rationale != entry.Rationale || got.ADR.Context != entry.ADR.Context ||
+		strings.Join(got.ADR.Options, "|") != "PostgreSQL|MySQL|`mongo`" || got.ADR.Consequences != entry.ADR.Consequences {
+		t.Errorf("decision record round-tripped as %+v", got)
+	}
+	if !section.Entries[1].ADR.IsZero() {
+		t.Errorf("expected no decision record on the second entry, got %+v", section.Entries[1].ADR)
+	}
+
+	if inspection := persistence.InspectContent(string(raw)); inspection.UnparsedBullets != 0 || inspection.BrokenAnchors != 0 {
+		t.Errorf("decision record lines reported as unparsed bullets or broken anchors: %+v", inspection)
+	}
+}
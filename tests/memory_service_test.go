@@ -0,0 +1,1256 @@
+package main_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+func TestMemoryService_ReadMemoryWithOptions_Budget(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	inputs := []struct {
+		section domain.SectionType
+		id      string
+		content string
+		age     time.Duration
+	}{
+		{domain.SectionPatterns, "00000000-0000-7000-8000-000000000001", strings.Repeat("p", 200), 0},
+		{domain.SectionConstraints, "00000000-0000-7000-8000-000000000002", "Old constraint", 2 * time.Hour},
+		{domain.SectionConstraints, "00000000-0000-7000-8000-000000000003", "New constraint", time.Hour},
+	}
+	if err := repo.EnsureDir(); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	template := "## Constraints\n\n<!-- template-entry, tag: [go], source: go -->\n* **[go]** Template constraint (*理由: Template default*)\n<!-- entry-end -->\n\n## Patterns\n"
+	if err := os.WriteFile(repo.FilePath(), []byte(template), 0644); err != nil {
+		t.Fatalf("failed to write memory file: %v", err)
+	}
+	for _, in := range inputs {
+		entry := &domain.Entry{
+			ID:        in.id,
+			Tag:       "[Test]",
+			TagName:   "Test",
+			Content:   in.content,
+			CreatedAt: base.Add(-in.age),
+		}
+		if err := repo.AppendEntry(ctx, in.section, entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	full, err := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(full, "truncated") {
+		t.Error("expected untrimmed content when budget is disabled")
+	}
+
+	const maxChars = 600
+	trimmed, err := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{MaxChars: maxChars})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(trimmed, "truncated: showing 3 of 4 entries") {
+		t.Errorf("expected truncation notice, got:\n%s", trimmed)
+	}
+	for budget := 100; budget <= maxChars; budget += 7 {
+		if out, _ := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{MaxChars: budget}); len(out) > budget {
+			t.Errorf("expected at most %d characters, got %d:\n%s", budget, len(out), out)
+		}
+	}
+	if !strings.Contains(trimmed, "<!-- template-entry, tag: [go], source: go -->") {
+		t.Errorf("expected the template constraint to be kept, got:\n%s", trimmed)
+	}
+	if strings.Contains(trimmed, strings.Repeat("p", 200)) {
+		t.Error("expected low-priority pattern entry to be dropped")
+	}
+	newIdx := strings.Index(trimmed, "New constraint")
+	oldIdx := strings.Index(trimmed, "Old constraint")
+	if newIdx == -1 || oldIdx == -1 || newIdx > oldIdx {
+		t.Errorf("expected constraints ordered most recent first, got:\n%s", trimmed)
+	}
+}
+
+func TestMemoryService_FindDuplicate(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+
+	existing := domain.AppendInput{Category: "note", Tag: "DB", Content: "Use PostgreSQL for all persistent storage"}
+	if err := svc.AppendMemory(ctx, existing, "00000000-0000-7000-8000-000000000001", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dup, score, err := svc.FindDuplicate(ctx, domain.AppendInput{Tag: "DB", Content: "use postgresql for all persistent storage!"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dup == nil {
+		t.Fatal("expected duplicate to be found in default note section")
+	}
+	if dup.ID != "00000000-0000-7000-8000-000000000001" || score < domain.DuplicateThreshold {
+		t.Errorf("unexpected duplicate %s with score %.2f", dup.ID, score)
+	}
+
+	dup, _, err = svc.FindDuplicate(ctx, domain.AppendInput{Category: "note", Tag: "API", Content: "Version every REST endpoint"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dup != nil {
+		t.Errorf("expected no duplicate, got %s", dup.ID)
+	}
+}
+
+func TestDuplicateDetector(t *testing.T) {
+	entries := []domain.Entry{
+		{ID: "a", Content: "Use PostgreSQL for all persistent storage"},
+		{ID: "b", Content: "Version every REST endpoint"},
+		{ID: "c", Content: "use postgresql for all persistent storage!"},
+		{ID: "d", Content: "Use PostgreSQL for persistent storage"},
+	}
+
+	for _, method := range []domain.DuplicateMethod{domain.DuplicateOverlap, domain.DuplicateMinHash} {
+		t.Run(string(method), func(t *testing.T) {
+			detector, err := domain.NewDuplicateDetector(method, 0.7)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if score := detector.Score(entries[0].Content, entries[2].Content); score != 1 {
+				t.Errorf("expected identical normalized content to score 1, got %.2f", score)
+			}
+			if score := detector.Score(entries[0].Content, entries[1].Content); score > 0.2 {
+				t.Errorf("expected unrelated content to score low, got %.2f", score)
+			}
+
+			candidates := detector.Candidates("Use PostgreSQL for all persistent storage", entries)
+			if len(candidates) != 3 || candidates[0].Entry.ID != "a" || candidates[2].Entry.ID != "d" {
+				t.Fatalf("expected a, c and d most similar first, got %+v", candidates)
+			}
+			if candidates[2].Score >= candidates[1].Score {
+				t.Errorf("expected scores in decreasing order, got %+v", candidates)
+			}
+
+			if pairs := detector.Pairs(entries); len(pairs) != 3 {
+				t.Errorf("expected the pairs a-c, a-d and c-d, got %+v", pairs)
+			}
+		})
+	}
+
+	if _, err := domain.NewDuplicateDetector("fuzzy", 0.8); err == nil {
+		t.Error("expected error for unknown method")
+	}
+	if _, err := domain.NewDuplicateDetector(domain.DuplicateOverlap, 1.5); err == nil {
+		t.Error("expected error for threshold above 1")
+	}
+}
+
+func TestDuplicateDetector_MinHashPairs(t *testing.T) {
+	// Distinct sentences, each followed by a variant missing one word, and two empty entries
+	var entries []domain.Entry
+	for i := 0; i < 100; i++ {
+		words := make([]string, 12)
+		for w := range words {
+			words[w] = fmt.Sprintf("w%d", (i*37+w*11)%997)
+		}
+		entries = append(entries,
+			domain.Entry{Content: strings.Join(words, " ")},
+			domain.Entry{Content: strings.Join(words[1:], " ")})
+	}
+	entries = append(entries, domain.Entry{Content: "!"}, domain.Entry{Content: "?"})
+
+	detector, err := domain.NewDuplicateDetector(domain.DuplicateMinHash, 0.7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var want []domain.DuplicatePair
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			if score := detector.Score(entries[i].Content, entries[j].Content); score >= detector.Threshold() {
+				want = append(want, domain.DuplicatePair{I: i, J: j, Score: score})
+			}
+		}
+	}
+	if len(want) < 101 {
+		t.Fatalf("expected every variant and the empty entries to pair up, got %d pairs", len(want))
+	}
+	if got := detector.Pairs(entries); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected banding to find the %d pairs of a full comparison, got %d", len(want), len(got))
+	}
+}
+
+func TestMemoryService_ListTags(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+
+	inputs := []domain.AppendInput{
+		{Category: "constraints", Tag: "API", Content: "Version every endpoint"},
+		{Category: "decisions", Tag: "DB", Content: "Use PostgreSQL"},
+		{Category: "patterns", Tag: "DB", Content: "Wrap queries in repositories"},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, time.Now()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tags, err := svc.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %d", len(tags))
+	}
+	if tags[0].Name != "DB" || tags[0].Count != 2 || len(tags[0].Sections) != 2 {
+		t.Errorf("expected DB used twice across 2 sections first, got %+v", tags[0])
+	}
+	if tags[1].Name != "API" || tags[1].Count != 1 {
+		t.Errorf("expected API used once, got %+v", tags[1])
+	}
+}
+
+func TestMemoryService_SuggestTag(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+
+	tag, err := svc.SuggestTag(ctx, "Mock the repository in unit tests")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag != "Testing" {
+		t.Errorf("expected Testing, got %s", tag)
+	}
+
+	tag, _ = svc.SuggestTag(ctx, "Remember to water the plants")
+	if tag != domain.DefaultSuggestedTag {
+		t.Errorf("expected %s, got %s", domain.DefaultSuggestedTag, tag)
+	}
+
+	// Existing spelling wins over the built-in keyword tag
+	input := domain.AppendInput{Category: "decisions", Tag: "testing", Content: "Run e2e suite nightly"}
+	if err := svc.AppendMemory(ctx, input, "00000000-0000-7000-8000-000000000001", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tag, _ = svc.SuggestTag(ctx, "Add coverage for the parser")
+	if tag != "testing" {
+		t.Errorf("expected existing tag spelling 'testing', got %s", tag)
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	expiry, err := domain.ParseExpiry("", 7, now)
+	if err != nil || !expiry.Equal(now.AddDate(0, 0, 7)) {
+		t.Errorf("expected ttl expiry a week out, got %v (err %v)", expiry, err)
+	}
+
+	expiry, err = domain.ParseExpiry("2024-02-01", 0, now)
+	if err != nil || expiry.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("expected date expiry, got %v (err %v)", expiry, err)
+	}
+
+	if expiry, err := domain.ParseExpiry("", 0, now); err != nil || !expiry.IsZero() {
+		t.Errorf("expected no expiry, got %v (err %v)", expiry, err)
+	}
+
+	for _, tc := range []struct {
+		expiresAt string
+		ttlDays   int
+	}{
+		{"2023-12-31", 0},
+		{"next week", 0},
+		{"2024-02-01", 3},
+		{"", -1},
+	} {
+		if _, err := domain.ParseExpiry(tc.expiresAt, tc.ttlDays, now); !errors.Is(err, domain.ErrInvalidExpiry) {
+			t.Errorf("expected ErrInvalidExpiry for %q/%d, got %v", tc.expiresAt, tc.ttlDays, err)
+		}
+	}
+}
+
+func TestMemoryService_PruneExpired(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "note", Tag: "Release", Content: "Code freeze this week", ExpiresAt: now.Add(time.Hour)},
+		{Category: "note", Tag: "Release", Content: "Ship on Fridays"},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	later := now.Add(2 * time.Hour)
+	visible, err := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{ExcludeExpired: true, Now: later})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(visible, "Code freeze") || !strings.Contains(visible, "Ship on Fridays") {
+		t.Errorf("expected only the expired entry to be hidden, got:\n%s", visible)
+	}
+
+	pruned, err := svc.PruneExpired(ctx, later)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0].Content != "Code freeze this week" {
+		t.Fatalf("expected the expired entry to be pruned, got %+v", pruned)
+	}
+
+	section, _ := svc.ReadSection(ctx, domain.SectionNote)
+	if len(section.Entries) != 1 {
+		t.Errorf("expected 1 remaining entry, got %d", len(section.Entries))
+	}
+	archive, err := os.ReadFile(repo.ArchivePath("expired-2024-01"))
+	if err != nil {
+		t.Fatalf("expected archive file: %v", err)
+	}
+	if !strings.Contains(string(archive), "Code freeze this week") {
+		t.Errorf("expected archived entry in archive file, got:\n%s", archive)
+	}
+}
+
+func TestMemoryService_Summarize(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "constraints", Tag: "DB", Content: "Use PostgreSQL only"},
+		{Category: "constraints", Tag: "DB", Content: "use postgresql only!"},
+		{Category: "constraints", Tag: "DB", Content: "Never drop columns in place"},
+		{Category: "patterns", Tag: "API", Content: strings.Repeat("long pattern text ", 20)},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, base.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	summary, err := svc.Summarize(ctx, domain.SummaryOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(summary, "- [DB] Never drop columns in place; use postgresql only!\n") {
+		t.Errorf("expected deduplicated DB line with most recent first, got:\n%s", summary)
+	}
+
+	capped, err := svc.Summarize(ctx, domain.SummaryOptions{MaxChars: 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(capped) > 200 || !strings.Contains(capped, "1 more entries omitted") {
+		t.Errorf("expected capped summary with omission notice, got (%d chars):\n%s", len(capped), capped)
+	}
+}
+
+func TestMemoryService_ListEntries(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	appends := []struct {
+		input domain.AppendInput
+		at    time.Time
+	}{
+		{domain.AppendInput{Category: "constraints", Tag: "API", Content: "Use RESTful conventions"}, now.AddDate(0, 0, -60)},
+		{domain.AppendInput{Category: "decisions", Tag: "DB", Content: "Use PostgreSQL"}, now.AddDate(0, 0, -5)},
+		{domain.AppendInput{Category: "note", Tag: "api", Content: "Document pagination"}, now.AddDate(0, 0, -1)},
+		{domain.AppendInput{Category: "note", Tag: "Release", Content: "Code freeze", ExpiresAt: now.Add(-time.Hour)}, now.AddDate(0, 0, -2)},
+	}
+	for i, a := range appends {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, a.input, id, a.at); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	since, err := domain.ParseSince("30d", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.EntryFilter
+		want   []string
+	}{
+		{"all active", domain.EntryFilter{Now: now}, []string{"Use RESTful conventions", "Use PostgreSQL", "Document pagination"}},
+		{"include expired", domain.EntryFilter{Now: now, IncludeExpired: true}, []string{"Use RESTful conventions", "Use PostgreSQL", "Document pagination", "Code freeze"}},
+		{"by section", domain.EntryFilter{Now: now, Sections: []domain.SectionType{domain.SectionDecisions}}, []string{"Use PostgreSQL"}},
+		{"by tag case-insensitive", domain.EntryFilter{Now: now, Tag: "[API]"}, []string{"Use RESTful conventions", "Document pagination"}},
+		{"since", domain.EntryFilter{Now: now, Since: since}, []string{"Use PostgreSQL", "Document pagination"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := svc.ListEntries(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Content)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"12h", now.Add(-12 * time.Hour), false},
+		{"30d", now.AddDate(0, 0, -30), false},
+		{"2w", now.AddDate(0, 0, -14), false},
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryService_Search(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "decisions", Tag: "DB", Content: "Use PostgreSQL as primary database", Rationale: "Postgres has JSONB"},
+		{Category: "decisions", Tag: "Storage", Content: "Postgres backups run nightly, postgres replicas hourly, postgres logs weekly"},
+		{Category: "constraints", Tag: "API", Content: "Use RESTful conventions"},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	hits, err := svc.Search(ctx, "postgres", domain.EntryFilter{Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("expected 2 hits, got %d", len(hits))
+	}
+	// The entry with more occurrences ranks first
+	if hits[0].Entry.TagName != "Storage" || len(hits[0].ContentMatches) != 3 {
+		t.Errorf("unexpected first hit: %+v", hits[0])
+	}
+	if span := hits[1].RationaleMatches[0]; hits[1].Entry.Rationale[span.Start:span.End] != "Postgres" {
+		t.Errorf("unexpected rationale span: %+v", span)
+	}
+
+	// Every term must match; tags count as matches
+	hits, err = svc.Search(ctx, "api restful", domain.EntryFilter{Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 || !hits[0].TagMatch {
+		t.Errorf("expected one tag-matching hit, got %+v", hits)
+	}
+
+	hits, _ = svc.Search(ctx, "postgres", domain.EntryFilter{Now: now, Tag: "DB"})
+	if len(hits) != 1 {
+		t.Errorf("expected filter to narrow hits to 1, got %d", len(hits))
+	}
+}
+
+func TestQueryService_Search(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// UUIDv7 IDs embed their capture time in the first 48 bits
+	idAt := func(t time.Time, n int) string {
+		ms := t.UnixMilli()
+		return fmt.Sprintf("%08x-%04x-7000-8000-%012d", ms>>16, ms&0xffff, n)
+	}
+	old, recent := now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1)
+	inputs := []struct {
+		id    string
+		input domain.AppendInput
+	}{
+		{idAt(old, 1), domain.AppendInput{Category: "decisions", Tag: "Cache", Content: "Cache sessions in Redis"}},
+		{idAt(recent, 2), domain.AppendInput{Category: "decisions", Tag: "Queue", Content: "Cache sessions in Redis"}},
+		{idAt(old, 3), domain.AppendInput{Category: "patterns", Tag: "Redis", Content: "Use one Redis client per process"}},
+		{idAt(old, 4), domain.AppendInput{Category: "patterns", Tag: "Style", Content: "Use one client per process"}},
+	}
+	for _, in := range inputs {
+		if err := svc.AppendMemory(ctx, in.input, in.id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	query := domain.NewQueryService(svc)
+	hits, err := query.Search(ctx, "redis", domain.EntryFilter{Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 3 {
+		t.Fatalf("expected 3 hits, got %d", len(hits))
+	}
+	// A tag match outweighs a content mention; the recent entry outranks its old twin
+	if hits[0].Entry.TagName != "Redis" || hits[1].Entry.TagName != "Queue" || hits[2].Entry.TagName != "Cache" {
+		t.Errorf("unexpected ranking: %s, %s, %s", hits[0].Entry.TagName, hits[1].Entry.TagName, hits[2].Entry.TagName)
+	}
+	if !hits[0].Entry.CapturedAt().Equal(old.Truncate(time.Millisecond)) {
+		t.Errorf("expected the capture time from the UUIDv7 ID, got %s", hits[0].Entry.CapturedAt())
+	}
+
+	// The MemoryService search ranks the same way
+	same, err := svc.Search(ctx, "redis", domain.EntryFilter{Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range hits {
+		if same[i].Entry.ID != hits[i].Entry.ID || same[i].Score != hits[i].Score {
+			t.Errorf("hit %d differs: %+v vs %+v", i, same[i], hits[i])
+		}
+	}
+}
+
+func TestMemoryService_DeleteAndDeprecate(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "decisions", Tag: "DB", Content: "Use PostgreSQL"},
+		{Category: "patterns", Tag: "Legacy", Content: "Wrap errors with pkg/errors"},
+		{Category: "patterns", Tag: "Style", Content: "Use gofmt"},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Unique prefixes resolve, shared ones are ambiguous
+	if _, err := svc.FindEntry(ctx, "00000000-0000"); !errors.Is(err, domain.ErrAmbiguousID) {
+		t.Errorf("expected ErrAmbiguousID, got %v", err)
+	}
+	entry, err := svc.FindEntry(ctx, "00000000-0000-7000-8000-000000000002")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deprecated, err := svc.DeprecateEntries(ctx, []domain.Entry{*entry})
+	if err != nil || len(deprecated) != 1 {
+		t.Fatalf("expected 1 deprecated entry, got %d (%v)", len(deprecated), err)
+	}
+	entry, _ = svc.FindEntry(ctx, entry.ID)
+	if entry.IsActive() || entry.Content != "Wrap errors with pkg/errors" {
+		t.Errorf("expected deprecated entry to keep its content, got %+v", entry)
+	}
+
+	// Entries are deprecated together: a missing one leaves the others active
+	style, err := svc.FindEntry(ctx, "00000000-0000-7000-8000-000000000003")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	missing := *style
+	missing.ID = "00000000-0000-7000-8000-000000000009"
+	if _, err := svc.DeprecateEntries(ctx, []domain.Entry{*style, missing}); err == nil {
+		t.Error("expected an error for a missing entry")
+	}
+	if style, _ = svc.FindEntry(ctx, style.ID); !style.IsActive() {
+		t.Errorf("expected the entry to stay active after a failed deprecation, got %+v", style)
+	}
+
+	deleted, err := svc.DeleteEntry(ctx, "00000000-0000-7000-8000-000000000001")
+	if err != nil || deleted.Section != domain.SectionDecisions {
+		t.Fatalf("unexpected delete result: %+v, %v", deleted, err)
+	}
+	if _, err := svc.DeleteEntry(ctx, "00000000-0000-7000-8000-000000000001"); !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+
+	entries, _ := svc.ListEntries(ctx, domain.EntryFilter{Now: now})
+	if len(entries) != 2 {
+		t.Errorf("expected 2 remaining entries, got %d", len(entries))
+	}
+}
+
+func TestMemoryService_EditEntry(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	id := "00000000-0000-7000-8000-000000000001"
+	if err := svc.AppendMemory(ctx, domain.AppendInput{Category: "decisions", Tag: "DB", Content: "Use PostgreSQL"}, id, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, err := svc.FindEntry(ctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.EditEntry(ctx, *entry, domain.AppendInput{Tag: "DB", Content: "line one\nline two"}); !errors.Is(err, domain.ErrForbiddenContent) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+
+	edited, err := svc.EditEntry(ctx, *entry, domain.AppendInput{Tag: "Database", Content: "Use PostgreSQL 16", Rationale: "LTS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if edited.ID != id || edited.Section != domain.SectionDecisions || !edited.CreatedAt.Equal(now) {
+		t.Errorf("expected identity to be kept, got %+v", edited)
+	}
+
+	reread, _ := svc.FindEntry(ctx, id)
+	if reread.Tag != "[Database]" || reread.Content != "Use PostgreSQL 16" || reread.Rationale != "LTS" {
+		t.Errorf("unexpected entry after edit: %+v", reread)
+	}
+}
+
+func TestMemoryService_RenameTag(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "decisions", Tag: "db", Content: "Use PostgreSQL"},
+		{Category: "patterns", Tag: "DB", Content: "Wrap queries in repositories"},
+		{Category: "note", Tag: "API", Content: "Version every endpoint"},
+	}
+	for i, input := range inputs {
+		id := fmt.Sprintf("00000000-0000-7000-8000-00000000000%d", i+1)
+		if err := svc.AppendMemory(ctx, input, id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := svc.RenameTag(ctx, "db", "[Data]base"); !errors.Is(err, domain.ErrInvalidTag) {
+		t.Errorf("expected invalid tag error, got %v", err)
+	}
+
+	result, err := svc.RenameTag(ctx, "[db]", "Database")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Renamed) != 2 {
+		t.Errorf("expected 2 renamed entries, got %d", len(result.Renamed))
+	}
+
+	tags, _ := svc.ListTags(ctx)
+	if len(tags) != 2 || tags[0].Name != "Database" || tags[0].Count != 2 {
+		t.Errorf("unexpected tags after rename: %+v", tags)
+	}
+}
+
+func TestPruneCriteria_Matches(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := domain.Entry{ID: "old", Section: domain.SectionNote, TagName: "Misc", CreatedAt: now.AddDate(0, -7, 0)}
+	fresh := domain.Entry{ID: "fresh", Section: domain.SectionNote, TagName: "Misc", CreatedAt: now.AddDate(0, 0, -1)}
+	expired := domain.Entry{ID: "expired", Section: domain.SectionDecisions, CreatedAt: now, ExpiresAt: now.Add(-time.Hour)}
+	deprecated := domain.Entry{ID: "deprecated", Section: domain.SectionDecisions, CreatedAt: now, Status: domain.StatusDeprecated}
+	oldDecision := domain.Entry{ID: "old-decision", Section: domain.SectionDecisions, CreatedAt: now.AddDate(-1, 0, 0)}
+
+	tests := []struct {
+		name     string
+		criteria domain.PruneCriteria
+		want     []string
+	}{
+		{"older than in section", domain.PruneCriteria{OlderThan: now.AddDate(0, 0, -180), Sections: []domain.SectionType{domain.SectionNote}}, []string{"old"}},
+		{"expired or deprecated", domain.PruneCriteria{Expired: true, Deprecated: true}, []string{"expired", "deprecated"}},
+		{"older than any section", domain.PruneCriteria{OlderThan: now.AddDate(0, 0, -180)}, []string{"old", "old-decision"}},
+		{"no criteria", domain.PruneCriteria{Sections: []domain.SectionType{domain.SectionNote}}, nil},
+	}
+	for _, tt := range tests {
+		tt.criteria.Now = now
+		var got []string
+		for _, entry := range []domain.Entry{old, fresh, expired, deprecated, oldDecision} {
+			if tt.criteria.Matches(entry) {
+				got = append(got, entry.ID)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompactionService_MergeGroup(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	compaction := domain.NewCompactionService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "note", Tag: "DB", Content: "Use Postgres for all storage", Rationale: "Team knows it"},
+		{Category: "decisions", Tag: "DB", Content: "use postgres for all storage!", Rationale: "ACID"},
+		{Category: "patterns", Tag: "Go", Content: "Wrap errors with context"},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	groups, err := compaction.FindDuplicateGroups(ctx, domain.DefaultDuplicateDetector())
+	if err != nil {
+		t.Fatalf("FindDuplicateGroups failed: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Entries) != 2 {
+		t.Fatalf("expected one group of two, got %+v", groups)
+	}
+	if keeper := groups[0].Keeper(); keeper.Section != domain.SectionDecisions {
+		t.Errorf("expected the decision to be kept, got %s", keeper.Section)
+	}
+
+	keeper, err := compaction.MergeGroup(ctx, groups[0])
+	if err != nil {
+		t.Fatalf("MergeGroup failed: %v", err)
+	}
+	if keeper.Rationale != "ACID; Team knows it" {
+		t.Errorf("unexpected merged rationale %q", keeper.Rationale)
+	}
+
+	entries, err := svc.ListEntries(ctx, domain.EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Rationale != "ACID; Team knows it" || entries[1].TagName != "Go" {
+		t.Errorf("unexpected entries after merge: %+v", entries)
+	}
+}
+
+func TestDiffEntries(t *testing.T) {
+	kept := domain.Entry{ID: "kept", Section: domain.SectionNote, Tag: "[Misc]", Content: "Unchanged"}
+	edited := domain.Entry{ID: "edited", Section: domain.SectionNote, Tag: "[Misc]", Content: "Before", Rationale: "r"}
+	removed := domain.Entry{ID: "removed", Section: domain.SectionDecisions, Tag: "[Misc]", Content: "Gone"}
+	legacy := domain.Entry{Section: domain.SectionPatterns, Tag: "[Old]", Content: "Legacy bullet"}
+
+	after := edited
+	after.Content = "After"
+	after.Status = domain.StatusDeprecated
+	added := domain.Entry{ID: "added", Section: domain.SectionNote, Tag: "[Misc]", Content: "New"}
+
+	diff := domain.DiffEntries(
+		[]domain.Entry{kept, edited, removed, legacy},
+		[]domain.Entry{kept, after, added, legacy},
+	)
+
+	if len(diff.Added) != 1 || diff.Added[0].ID != "added" {
+		t.Errorf("Added = %+v, want [added]", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "removed" {
+		t.Errorf("Removed = %+v, want [removed]", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].New.ID != "edited" {
+		t.Fatalf("Changed = %+v, want [edited]", diff.Changed)
+	}
+	if got := strings.Join(diff.Changed[0].Fields, ","); got != "content,status" {
+		t.Errorf("Fields = %s, want content,status", got)
+	}
+	if !domain.DiffEntries([]domain.Entry{kept, legacy}, []domain.Entry{kept, legacy}).IsEmpty() {
+		t.Error("identical versions should produce an empty diff")
+	}
+}
+
+func TestMergeEntries(t *testing.T) {
+	entry := func(id string, section domain.SectionType, content string) domain.Entry {
+		return domain.Entry{ID: id, Section: section, Tag: "[Misc]", TagName: "Misc", Content: content}
+	}
+	base := []domain.Entry{
+		entry("same", domain.SectionNote, "Untouched"),
+		entry("theirs-edit", domain.SectionNote, "Before"),
+		entry("theirs-delete", domain.SectionNote, "Delete me"),
+		entry("both-edit", domain.SectionNote, "Original"),
+	}
+	ours := []domain.Entry{
+		base[0], base[1], base[2],
+		entry("both-edit", domain.SectionNote, "Ours"),
+		entry("ours-new", domain.SectionDecisions, "Use PostgreSQL for persistence"),
+	}
+	theirs := []domain.Entry{
+		base[0],
+		entry("theirs-edit", domain.SectionNote, "After"),
+		entry("both-edit", domain.SectionNote, "Theirs"),
+		entry("theirs-dup", domain.SectionDecisions, "Use PostgreSQL for persistence"),
+		entry("theirs-new", domain.SectionPatterns, "Table-driven tests"),
+	}
+
+	result := domain.MergeEntries(base, ours, theirs)
+
+	ids := func(entries []domain.Entry) string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := ids(result.Added); got != "theirs-new" {
+		t.Errorf("Added = %s, want theirs-new", got)
+	}
+	if got := ids(result.Updated); got != "theirs-edit" {
+		t.Errorf("Updated = %s, want theirs-edit", got)
+	}
+	if got := ids(result.Removed); got != "theirs-delete" {
+		t.Errorf("Removed = %s, want theirs-delete", got)
+	}
+	if got := ids(result.Duplicates); got != "theirs-dup" {
+		t.Errorf("Duplicates = %s, want theirs-dup", got)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Ours.ID != "both-edit" {
+		t.Errorf("Conflicts = %+v, want [both-edit]", result.Conflicts)
+	}
+}
+
+func TestMatchScope(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"internal/api/**", "internal/api/users.go", true},
+		{"internal/api/**", "internal/api/v2/users.go", true},
+		{"internal/api/**", "internal/apix/users.go", false},
+		{"internal/api", "internal/api/users.go", true},
+		{"internal/api", "./internal/api", true},
+		{"internal/api", "internal/apiserver/main.go", false},
+		{"cmd/*.go", "cmd/root.go", true},
+		{"cmd/*.go", "cmd/add/add.go", false},
+		{"**/*_test.go", "tests/memory_test.go", true},
+		{"**/*_test.go", "memory_test.go", true},
+		{"web/**/*.tsx", "web/src/App.tsx", true},
+		{"web/**/*.tsx", "api/App.tsx", false},
+	}
+	for _, tt := range tests {
+		if got := domain.MatchScope(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchScope(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	scope, err := domain.ParseScope(" ./internal/api/**, cmd  internal/api/** ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(scope, "|") != "internal/api/**|cmd" {
+		t.Errorf("scope = %v, want cleaned and deduplicated patterns", scope)
+	}
+
+	for _, value := range []string{"/etc/**", "../other", "internal/[api"} {
+		if _, err := domain.ParseScope(value); !errors.Is(err, domain.ErrInvalidScope) {
+			t.Errorf("ParseScope(%q) error = %v, want ErrInvalidScope", value, err)
+		}
+	}
+}
+
+func TestMemoryService_Scope(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "constraints", Tag: "API", Content: "Handlers return problem JSON", Scope: []string{"internal/api/**"}},
+		{Category: "constraints", Tag: "Web", Content: "Components are typed", Scope: []string{"web"}},
+		{Category: "constraints", Tag: "Docs", Content: "Keep the README current"},
+	}
+	for i, input := range inputs {
+		if err := svc.AppendMemory(ctx, input, fmt.Sprintf("00000000-0000-7000-8000-00000000000%d", i+1), now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The scope survives the round trip through the anchor metadata
+	section, err := svc.ReadSection(ctx, domain.SectionConstraints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(section.Entries) != 3 || strings.Join(section.Entries[0].Scope, "|") != "internal/api/**" {
+		t.Fatalf("entries = %+v, want the API entry scoped to internal/api/**", section.Entries)
+	}
+
+	entries, err := svc.ListEntries(ctx, domain.EntryFilter{Now: now, Files: []string{"internal/api/users.go"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Content)
+	}
+	if strings.Join(got, "|") != "Handlers return problem JSON|Keep the README current" {
+		t.Errorf("entries for internal/api = %v, want the API entry and the unscoped one", got)
+	}
+
+	content, err := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{Now: now, Files: []string{"web/src/App.tsx"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(content, "problem JSON") || !strings.Contains(content, "Components are typed") || !strings.Contains(content, "README") {
+		t.Errorf("memory for web/src/App.tsx:\n%s\nwant the web and unscoped entries only", content)
+	}
+}
+
+func TestContradicts(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Never use an ORM for database access", "Use an ORM for database access", true},
+		{"Don't call the payments API from handlers", "Call the payments API from handlers", true},
+		{"Use an ORM for database access", "Do not use an ORM for database access", true},
+		{"Do not store secrets in the repo", "Store secrets in the repo", true},
+		{"Use GORM as the ORM for database access", "Do not use an ORM for database access", false},
+		{"Use PostgreSQL for storage", "Never use MySQL for storage", false},
+		{"Never use an ORM for database access", "Never use an ORM in tests", false},
+		{"Use an ORM for database access", "Wrap errors with context", false},
+		{"No global state in handlers", "Keep global state in the config package", false},
+	}
+	for _, tt := range tests {
+		if got := domain.Contradicts(tt.a, tt.b); got != tt.want {
+			t.Errorf("Contradicts(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTagService(t *testing.T) {
+	tags := domain.NewTagService([]domain.TagDefinition{
+		{Name: "Frontend", Aliases: []string{"fe", "ui"}},
+		{Name: "database", Aliases: []string{"pg"}},
+		{Name: "Infra", Aliases: []string{"ci", "Build"}},
+	})
+
+	tests := []struct {
+		tag, want string
+	}{
+		{"arch", "Architecture"},
+		{"DB", "database"},
+		{"[db]", "database"},
+		{"pg", "database"},
+		{"UI", "Frontend"},
+		{"ci", "Infra"},
+		{"Build", "Build"},
+		{"error_handling", "error_handling"},
+		{" Storage ", "Storage"},
+	}
+	for _, tt := range tests {
+		if got := tags.Normalize(tt.tag); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+
+	for _, definition := range tags.Taxonomy() {
+		switch definition.Name {
+		case "Build":
+			if strings.Join(definition.Aliases, ",") != "cicd" {
+				t.Errorf("expected ci to move from Build to Infra, got %v", definition.Aliases)
+			}
+		case "Database":
+			t.Error("expected the custom spelling to replace Database")
+		}
+	}
+}
+
+func TestValidateContent(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr error
+	}{
+		{"Pass `context.Context` as the first argument", nil},
+		{"Return Map<K, V> from the cache, never nil", nil},
+		{"Use ``a ` b`` for nested backticks", nil},
+		{"Escape &lt; in templates", nil},
+		{"Line one\nline two", domain.ErrForbiddenContent},
+		{"Hide <!-- anchors", domain.ErrForbiddenContent},
+		{"Close anchors -->", domain.ErrForbiddenContent},
+		{"Use `context.Context everywhere", domain.ErrForbiddenContent},
+		{"Odd `a`` spans", domain.ErrForbiddenContent},
+		{"- Looks like a list item", domain.ErrListItem},
+	}
+	for _, tt := range tests {
+		err := domain.ValidateContent(tt.content)
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("ValidateContent(%q) = %v, want nil", tt.content, err)
+		} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("ValidateContent(%q) = %v, want %v", tt.content, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEscapeContent(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Return Map<K, V>", "Return Map&lt;K, V&gt;"},
+		{"Use `Map<K, V>` here", "Use `Map<K, V>` here"},
+		{"Literal &lt; stays literal", "Literal &amp;lt; stays literal"},
+		{"A & B", "A & B"},
+	}
+	for _, tt := range tests {
+		got := domain.EscapeContent(tt.text)
+		if got != tt.want {
+			t.Errorf("EscapeContent(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		if back := domain.UnescapeContent(got); back != tt.text {
+			t.Errorf("UnescapeContent(%q) = %q, want %q", got, back, tt.text)
+		}
+	}
+}
+
+func TestMemoryService_ValidateInput_CountsRunes(t *testing.T) {
+	svc := domain.NewMemoryService(nil)
+
+	// 2000 CJK characters are 6000 bytes but within the limit
+	content := strings.Repeat("使用依赖注入", domain.MaxContentLength/6)
+	input := domain.AppendInput{Category: "decisions", Tag: "架构设计", Content: content, Rationale: strings.Repeat("😀", domain.MaxRationaleLength)}
+	if err := svc.ValidateInput(input); err != nil {
+		t.Fatalf("expected CJK content at the limit to be valid, got %v", err)
+	}
+
+	input.Content = content + strings.Repeat("长", domain.MaxContentLength-utf8.RuneCountInString(content)+1)
+	err := svc.ValidateInput(input)
+	if !errors.Is(err, domain.ErrInvalidContent) || !strings.Contains(err.Error(), fmt.Sprintf("got %d", domain.MaxContentLength+1)) {
+		t.Errorf("expected ErrInvalidContent counting %d characters, got %v", domain.MaxContentLength+1, err)
+	}
+
+	input.Content = content
+	input.Rationale += "😀"
+	if err := svc.ValidateInput(input); !errors.Is(err, domain.ErrInvalidRationale) {
+		t.Errorf("expected ErrInvalidRationale, got %v", err)
+	}
+
+	input.Rationale = ""
+	input.Tag = strings.Repeat("标", domain.MaxTagLength)
+	if err := svc.ValidateInput(input); err != nil {
+		t.Errorf("expected a %d-character CJK tag to be valid, got %v", domain.MaxTagLength, err)
+	}
+	input.Tag += "签"
+	if err := svc.ValidateInput(input); !errors.Is(err, domain.ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+}
+
+func TestNewAuthor(t *testing.T) {
+	tests := []struct {
+		kind, name, want string
+	}{
+		{domain.AuthorUser, "Jane Doe", "user:Jane Doe"},
+		{domain.AuthorAgent, "  cursor \t 1.2 ", "agent:cursor 1.2"},
+		{domain.AuthorUser, "Doe, Jane", "user:Doe Jane"},
+		{domain.AuthorAgent, "evil --> <!-- x", "agent:evil x"},
+		{domain.AuthorUser, "   ", ""},
+	}
+	for _, tt := range tests {
+		if got := domain.NewAuthor(tt.kind, tt.name); got != tt.want {
+			t.Errorf("NewAuthor(%q, %q) = %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+
+	long := domain.NewAuthor(domain.AuthorAgent, strings.Repeat("模", 2*domain.MaxAuthorLength))
+	if n := utf8.RuneCountInString(domain.NormalizeAuthor(long)); n != domain.MaxAuthorLength {
+		t.Errorf("expected author truncated to %d characters, got %d", domain.MaxAuthorLength, n)
+	}
+
+	entry := domain.Entry{Author: "user:Jane Doe"}
+	if entry.AuthorKind() != domain.AuthorUser || entry.AuthorName() != "Jane Doe" {
+		t.Errorf("unexpected kind %q and name %q", entry.AuthorKind(), entry.AuthorName())
+	}
+	legacy := domain.Entry{Author: "Jane"}
+	if legacy.AuthorKind() != "" || legacy.AuthorName() != "Jane" {
+		t.Errorf("unexpected kind %q and name %q", legacy.AuthorKind(), legacy.AuthorName())
+	}
+}
+
+func TestEntryFilter_Author(t *testing.T) {
+	human := domain.Entry{Author: "user:Jane Doe"}
+	agent := domain.Entry{Author: "agent:claude-code 1.0.3"}
+	unknown := domain.Entry{}
+
+	tests := []struct {
+		author string
+		entry  domain.Entry
+		want   bool
+	}{
+		{"", unknown, true},
+		{"user", human, true},
+		{"user", agent, false},
+		{"Agent", agent, true},
+		{"agent", unknown, false},
+		{"jane", human, true},
+		{"claude", human, false},
+	}
+	for _, tt := range tests {
+		filter := domain.EntryFilter{Author: tt.author, IncludeExpired: true}
+		if got := filter.Matches(tt.entry); got != tt.want {
+			t.Errorf("Author %q matching %q = %v, want %v", tt.author, tt.entry.Author, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDecisionRecord(t *testing.T) {
+	valid := domain.DecisionRecord{Context: "Need `JSONB`", Options: []string{"PostgreSQL", "MySQL"}, Consequences: "Run PostgreSQL"}
+	if err := domain.ValidateDecisionRecord(domain.SectionDecisions, valid); err != nil {
+		t.Errorf("expected a valid record, got %v", err)
+	}
+	if err := domain.ValidateDecisionRecord(domain.SectionPatterns, domain.DecisionRecord{}); err != nil {
+		t.Errorf("expected an empty record to be valid anywhere, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		section domain.SectionType
+		record  domain.DecisionRecord
+	}{
+		{"outside decisions", domain.SectionConstraints, valid},
+		{"multi-line context", domain.SectionDecisions, domain.DecisionRecord{Context: "one\ntwo"}},
+		{"long consequences", domain.SectionDecisions, domain.DecisionRecord{Consequences: strings.Repeat("x", domain.MaxRationaleLength+1)}},
+		{"semicolon in option", domain.SectionDecisions, domain.DecisionRecord{Options: []string{"A; B"}}},
+		{"too many options", domain.SectionDecisions, domain.DecisionRecord{Options: strings.Fields(strings.Repeat("opt ", domain.MaxDecisionOptions+1))}},
+	}
+	for _, tt := range tests {
+		if err := domain.ValidateDecisionRecord(tt.section, tt.record); !errors.Is(err, domain.ErrInvalidDecisionRecord) {
+			t.Errorf("%s: expected ErrInvalidDecisionRecord, got %v", tt.name, err)
+		}
+	}
+
+	if got := domain.ParseOptions(" PostgreSQL ;; MySQL;"); strings.Join(got, "|") != "PostgreSQL|MySQL" {
+		t.Errorf("ParseOptions = %q", got)
+	}
+}
+
+func TestMemoryService_SetValidationPolicy(t *testing.T) {
+	svc := domain.NewMemoryService(nil)
+	err := svc.SetValidationPolicy(domain.ValidationPolicy{
+		MaxContentLength:  20,
+		AllowedCharacters: `\p{Latin}\p{N}\p{P} `,
+		BannedWords:       []string{"TODO", " hack "},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy := svc.Rules().Policy(); policy.MaxTagLength != domain.MaxTagLength || policy.MaxContentLength != 20 {
+		t.Errorf("expected unset limits to keep their defaults, got %+v", policy)
+	}
+
+	tests := []struct {
+		name    string
+		input   domain.AppendInput
+		wantErr error
+	}{
+		{"valid", domain.AppendInput{Tag: "API", Content: "Use REST, hacky."}, nil},
+		{"content too long", domain.AppendInput{Tag: "API", Content: strings.Repeat("a", 21)}, domain.ErrInvalidContent},
+		{"disallowed character", domain.AppendInput{Tag: "API", Content: "使用 REST"}, domain.ErrForbiddenContent},
+		{"disallowed tag character", domain.AppendInput{Tag: "架构", Content: "Use REST"}, domain.ErrForbiddenContent},
+		{"banned word", domain.AppendInput{Tag: "API", Content: "todo: use REST"}, domain.ErrForbiddenContent},
+		{"banned word in rationale", domain.AppendInput{Tag: "API", Content: "Use REST", Rationale: "a hack"}, domain.ErrForbiddenContent},
+		{"banned word in decision record", domain.AppendInput{Category: "decisions", Tag: "API", Content: "Use REST",
+			ADR: domain.DecisionRecord{Options: []string{"GraphQL", "Hack"}}}, domain.ErrForbiddenContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.ValidateInput(tt.input)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	for _, policy := range []domain.ValidationPolicy{{MaxTagLength: -1}, {AllowedCharacters: `\p{Unknown}`}} {
+		if err := svc.SetValidationPolicy(policy); !errors.Is(err, domain.ErrInvalidPolicy) {
+			t.Errorf("expected ErrInvalidPolicy for %+v, got %v", policy, err)
+		}
+	}
+	if svc.Rules().Policy().MaxContentLength != 20 {
+		t.Error("expected an invalid policy to keep the previous rules")
+	}
+}
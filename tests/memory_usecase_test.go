@@ -0,0 +1,355 @@
+package main_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+)
+
+// setupInitializedProject creates a temp project with an empty memory file
+func setupInitializedProject(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".ohmymem"), 0755); err != nil {
+		t.Fatalf("failed to create .ohmymem: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".ohmymem", "memory.md"), []byte(""), 0644); err != nil {
+		t.Fatalf("failed to create memory.md: %v", err)
+	}
+	return tmpDir
+}
+
+func TestMemoryUseCase_EnsureInitialized(t *testing.T) {
+	if err := usecase.NewMemoryUseCase(t.TempDir()).EnsureInitialized(); err == nil {
+		t.Error("expected error for project without memory file")
+	}
+	if err := usecase.NewMemoryUseCase(setupInitializedProject(t)).EnsureInitialized(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMemoryUseCase_Add(t *testing.T) {
+	uc := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	ctx := context.Background()
+
+	input := domain.AppendInput{Category: "decisions", Tag: "Storage", Content: "Use Postgres for storage"}
+	result, err := uc.Add(ctx, input, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Section != domain.SectionDecisions || result.Entry.Tag != "[Storage]" || result.AutoTagged {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// Near-duplicates are rejected unless forced
+	if _, err := uc.Add(ctx, input, usecase.AddOptions{}); !errors.Is(err, domain.ErrDuplicateEntry) {
+		t.Errorf("expected ErrDuplicateEntry, got %v", err)
+	}
+	if _, err := uc.Add(ctx, input, usecase.AddOptions{Force: true}); err != nil {
+		t.Errorf("unexpected error with force: %v", err)
+	}
+
+	// Missing category and tag fall back to note and a suggested tag
+	result, err = uc.Add(ctx, domain.AppendInput{Content: "Write table driven tests"}, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Section != domain.SectionNote || !result.AutoTagged {
+		t.Errorf("expected auto-tagged note, got %+v", result)
+	}
+
+	section, err := uc.Service().ReadSection(ctx, domain.SectionDecisions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(section.Entries) != 2 {
+		t.Errorf("expected 2 decisions, got %d", len(section.Entries))
+	}
+}
+
+func TestMemoryUseCase_AddDryRun(t *testing.T) {
+	uc := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	ctx := context.Background()
+
+	constraint := domain.AppendInput{Category: "constraints", Tag: "DB", Content: "Never use an ORM for database access"}
+	if _, err := uc.Add(ctx, constraint, usecase.AddOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, err := uc.Service().ReadMemory(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Rejections come with the entries at fault
+	result, err := uc.Add(ctx, constraint, usecase.AddOptions{})
+	if !errors.Is(err, domain.ErrDuplicateEntry) || result == nil || len(result.Duplicates) != 1 {
+		t.Fatalf("expected a duplicate rejection with its candidate, got %+v, %v", result, err)
+	}
+
+	// and a dry run reports them without writing
+	result, err = uc.Add(ctx, constraint, usecase.AddOptions{DryRun: true})
+	if err != nil || len(result.Duplicates) != 1 || result.Entry.ID == "" {
+		t.Errorf("expected a prepared entry and its duplicate, got %+v, %v", result, err)
+	}
+	decision := domain.AppendInput{Category: "decisions", Tag: "DB", Content: "Use an ORM for database access"}
+	result, err = uc.Add(ctx, decision, usecase.AddOptions{DryRun: true})
+	if err != nil || result.Contradicted == nil {
+		t.Errorf("expected the contradicted constraint, got %+v, %v", result, err)
+	}
+	if _, err := uc.Add(ctx, domain.AppendInput{Category: "bogus", Content: "x"}, usecase.AddOptions{DryRun: true}); !errors.Is(err, domain.ErrInvalidCategory) {
+		t.Errorf("expected a dry run to validate, got %v", err)
+	}
+
+	after, err := uc.Service().ReadMemory(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after != before {
+		t.Errorf("expected dry runs to leave the memory file unchanged, got:\n%s", after)
+	}
+}
+
+func TestMemoryUseCase_AddNormalizesTags(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	taxonomy := "tags:\n  - name: Frontend\n    aliases: [fe, ui]\n"
+	if err := os.WriteFile(filepath.Join(projectDir, ".ohmymem", "tags.yaml"), []byte(taxonomy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewMemoryUseCase(projectDir)
+	ctx := context.Background()
+
+	for _, tt := range []struct{ tag, want, alias string }{
+		{"db", "[Database]", "db"},
+		{"fe", "[Frontend]", "fe"},
+		{"Frontend", "[Frontend]", ""},
+		{"Storage", "[Storage]", ""},
+	} {
+		result, err := uc.Add(ctx, domain.AppendInput{Category: "note", Tag: tt.tag, Content: "Entry tagged " + tt.tag}, usecase.AddOptions{Force: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Entry.Tag != tt.want || result.TagAlias != tt.alias {
+			t.Errorf("Add with tag %q: got %s (alias %q), want %s (alias %q)", tt.tag, result.Entry.Tag, result.TagAlias, tt.want, tt.alias)
+		}
+	}
+}
+
+func TestMemoryUseCase_AddSupersedes(t *testing.T) {
+	uc := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	ctx := context.Background()
+
+	constraint, err := uc.Add(ctx, domain.AppendInput{Category: "constraints", Tag: "DB", Content: "Never use an ORM for database access"}, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A contradicting decision is rejected, even when forced, until it supersedes the constraint
+	decision := domain.AppendInput{Category: "decisions", Tag: "DB", Content: "Use an ORM for database access"}
+	for _, opts := range []usecase.AddOptions{{}, {Force: true}} {
+		if _, err := uc.Add(ctx, decision, opts); !errors.Is(err, domain.ErrContradiction) {
+			t.Errorf("expected ErrContradiction, got %v", err)
+		}
+	}
+
+	decision.Supersedes = constraint.Entry.ID[:8]
+	result, err := uc.Add(ctx, decision, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Superseded == nil || result.Superseded.ID != constraint.Entry.ID {
+		t.Fatalf("expected the constraint to be superseded, got %+v", result.Superseded)
+	}
+
+	superseded, err := uc.Service().FindEntry(ctx, constraint.Entry.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !superseded.IsSuperseded() || superseded.SupersededBy != result.Entry.ID {
+		t.Errorf("expected constraint superseded by %s, got %+v", result.Entry.ID, superseded)
+	}
+
+	// Only active entries can be superseded
+	decision.Content = "Use sqlc for database access"
+	decision.Supersedes = constraint.Entry.ID
+	if _, err := uc.Add(ctx, decision, usecase.AddOptions{}); !errors.Is(err, domain.ErrInactiveEntry) {
+		t.Errorf("expected ErrInactiveEntry, got %v", err)
+	}
+
+	// Reads collapse the superseded constraint to a pointer at its replacement
+	content, err := uc.Service().ReadMemoryWithOptions(ctx, domain.ReadOptions{CollapseSuperseded: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(content, "Never use an ORM") || !strings.Contains(content, domain.CollapseSuperseded(*superseded)) {
+		t.Errorf("expected collapsed constraint, got:\n%s", content)
+	}
+}
+
+func TestMemoryUseCase_Status(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	uc := usecase.NewMemoryUseCase(projectDir)
+	ctx := context.Background()
+
+	for _, input := range []domain.AppendInput{
+		{Category: "decisions", Tag: "DB", Content: "Use Postgres for storage"},
+		{Category: "decisions", Tag: "DB", Content: "Run migrations with goose"},
+		{Category: "patterns", Tag: "Go", Content: "Wrap errors with context"},
+	} {
+		if _, err := uc.Add(ctx, input, usecase.AddOptions{}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	agents := "# Agents\n\n<!-- ohmymem:start -->\nLast updated: 2026-01-01T00:00:00Z\n" + domain.BootProtocol + "<!-- ohmymem:end -->\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "AGENTS.md"), []byte(agents), 0644); err != nil {
+		t.Fatalf("failed to write AGENTS.md: %v", err)
+	}
+
+	report, err := uc.Status(ctx, 1)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if report.TotalEntries != 3 || report.SchemaVersion != "0.1" || report.SizeBytes == 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.TopTags) != 1 || report.TopTags[0] != (usecase.TagCount{Name: "Database", Count: 2}) {
+		t.Errorf("unexpected top tags: %+v", report.TopTags)
+	}
+	if !report.Agents.Present || !report.Agents.UpToDate || report.Agents.LastUpdated != "2026-01-01T00:00:00Z" {
+		t.Errorf("unexpected AGENTS.md status: %+v", report.Agents)
+	}
+}
+
+func TestMemoryUseCase_ArchiveRestore(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	uc := usecase.NewMemoryUseCase(projectDir)
+	ctx := context.Background()
+
+	result, err := uc.Add(ctx, domain.AppendInput{Category: "decisions", Tag: "Storage", Content: "Use Postgres for storage"}, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := uc.Service().ArchiveEntries(ctx, []domain.Entry{result.Entry}, uc.ArchiveName()); err != nil {
+		t.Fatalf("ArchiveEntries: %v", err)
+	}
+
+	archived, err := uc.ListArchived(ctx)
+	if err != nil {
+		t.Fatalf("ListArchived: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != result.Entry.ID || archived[0].Archive != uc.ArchiveName() {
+		t.Fatalf("ListArchived = %+v", archived)
+	}
+
+	restored, err := uc.Restore(ctx, []string{result.Entry.ID[:8]})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(restored) != 1 || restored[0].Section != domain.SectionDecisions {
+		t.Errorf("Restore = %+v", restored)
+	}
+	if _, err := uc.Service().FindEntry(ctx, result.Entry.ID); err != nil {
+		t.Errorf("restored entry not in memory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".ohmymem", "archive", uc.ArchiveName()+".md")); !os.IsNotExist(err) {
+		t.Errorf("empty archive file kept: %v", err)
+	}
+	if _, err := uc.Restore(ctx, []string{result.Entry.ID}); !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestMemoryUseCase_Stats(t *testing.T) {
+	uc := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	ctx := context.Background()
+
+	inputs := []domain.AppendInput{
+		{Category: "decisions", Tag: "Storage", Content: "Use Postgres for storage"},
+		{Category: "decisions", Tag: "storage", Content: "Keep migrations in the repository under db"},
+		{Category: "patterns", Tag: "API", Content: "Return problem details on errors"},
+	}
+	for _, input := range inputs {
+		if _, err := uc.Add(ctx, input, usecase.AddOptions{Force: true}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	report, err := uc.Stats(ctx, 1)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if report.TotalEntries != 3 || report.Undated != 0 {
+		t.Errorf("TotalEntries = %d, Undated = %d", report.TotalEntries, report.Undated)
+	}
+	if len(report.Growth) != 1 || report.Growth[0].Total != 3 {
+		t.Errorf("Growth = %+v", report.Growth)
+	}
+	if len(report.BySection) != 2 || report.BySection[0].Name != "decisions" || report.BySection[0].Entries != 2 {
+		t.Errorf("BySection = %+v", report.BySection)
+	}
+	if len(report.ByTag) != 1 || report.ByTag[0].Name != "Storage" || report.ByTag[0].Entries != 2 {
+		t.Errorf("ByTag = %+v, want the merged storage tags only", report.ByTag)
+	}
+	if len(report.Largest) != 1 || report.Largest[0].Content != inputs[1].Content {
+		t.Errorf("Largest = %+v", report.Largest)
+	}
+}
+
+func TestMemoryUseCase_AddRefs(t *testing.T) {
+	uc := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	ctx := context.Background()
+
+	decision, err := uc.Add(ctx, domain.AppendInput{Category: "decisions", Tag: "Database", Content: "Use PostgreSQL as the primary database"}, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := decision.Entry.ID
+
+	// A unique prefix is expanded to the full ID
+	result, err := uc.Add(ctx, domain.AppendInput{
+		Category:  "constraints",
+		Tag:       "Database",
+		Content:   "Queries go through sqlc, see ref:" + domain.ShortID(id),
+		Rationale: "Follows ref:" + id,
+	}, usecase.AddOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Queries go through sqlc, see ref:" + id; result.Entry.Content != want {
+		t.Errorf("content = %q, want %q", result.Entry.Content, want)
+	}
+	if refs := result.Entry.Refs(); len(refs) != 1 || refs[0] != id {
+		t.Errorf("Refs() = %v, want [%s]", refs, id)
+	}
+
+	for _, content := range []string{"Unknown ref:0123456789ab", "Too short ref:" + id[:4]} {
+		if _, err := uc.Add(ctx, domain.AppendInput{Category: "note", Tag: "Go", Content: content}, usecase.AddOptions{}); !errors.Is(err, domain.ErrInvalidReference) {
+			t.Errorf("%q: expected ErrInvalidReference, got %v", content, err)
+		}
+	}
+
+	referencing, err := uc.Service().ListEntries(ctx, domain.EntryFilter{References: id[:8]})
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	if len(referencing) != 1 || referencing[0].ID != result.Entry.ID {
+		t.Errorf("expected the constraint to reference the decision, got %+v", referencing)
+	}
+
+	all, err := uc.Service().ListEntries(ctx, domain.EntryFilter{IncludeExpired: true})
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	index := domain.NewRefIndex(all)
+	if got, want := index.Describe(result.Entry.Content), "Queries go through sqlc, see ref:"+domain.ShortID(id)+" [Database]"; got != want {
+		t.Errorf("Describe = %q, want %q", got, want)
+	}
+	if got := index.Describe("Gone ref:0123456789ab"); got != "Gone ref:0123456789ab (missing)" {
+		t.Errorf("Describe of a missing entry = %q", got)
+	}
+}
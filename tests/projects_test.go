@@ -0,0 +1,46 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestProjectRegistry_Resolve(t *testing.T) {
+	api := setupInitializedProject(t)
+	other := setupInitializedProject(t)
+	registry := usecase.NewProjectRegistry(map[string]string{"api": api}, &testUUID{}, &testClock{})
+
+	svc, err := registry.Resolve("api")
+	if err != nil || svc == nil {
+		t.Fatalf("expected the configured project to resolve, got %v", err)
+	}
+	if again, _ := registry.Resolve("api"); again != svc {
+		t.Error("expected the service of a project to be reused")
+	}
+
+	// Paths, even of initialized projects, are only routed when allowed
+	for _, project := range []string{other, api, "web"} {
+		if _, err := registry.Resolve(project); err == nil {
+			t.Errorf("expected %q to be rejected without path routing", project)
+		}
+	}
+
+	registry.SetAllowPaths(true)
+	byPath, err := registry.Resolve(api)
+	if err != nil {
+		t.Fatalf("expected an initialized path to resolve, got %v", err)
+	}
+	if byPath != svc {
+		t.Error("expected a configured project and its path to share a service")
+	}
+	if _, err := registry.Resolve(other); err != nil {
+		t.Errorf("expected an initialized path to resolve, got %v", err)
+	}
+	if _, err := registry.Resolve(t.TempDir()); err == nil {
+		t.Error("expected an uninitialized path to be rejected")
+	}
+	if _, err := registry.Resolve("relative/path"); err == nil {
+		t.Error("expected a relative path to be rejected")
+	}
+}
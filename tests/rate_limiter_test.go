@@ -0,0 +1,70 @@
+package main_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestRateLimiter_BurstAndRefill(t *testing.T) {
+	clock := &testClock{}
+	limiter := usecase.NewRateLimiter(60, 2, clock)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("s1"); !ok {
+			t.Fatalf("expected call %d within burst to be allowed", i+1)
+		}
+	}
+
+	ok, retryAfter := limiter.Allow("s1")
+	if ok {
+		t.Fatal("expected call beyond burst to be rejected")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("expected retry within 1s, got %v", retryAfter)
+	}
+
+	// Other sessions have their own bucket
+	if ok, _ := limiter.Allow("s2"); !ok {
+		t.Error("expected a different session to be allowed")
+	}
+
+	clock.currentTime = clock.Now().Add(time.Second)
+	if ok, _ := limiter.Allow("s1"); !ok {
+		t.Error("expected a token to be refilled after one second")
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	limiter := usecase.NewRateLimiter(0, 0, &testClock{})
+	for i := 0; i < 100; i++ {
+		if ok, _ := limiter.Allow("s1"); !ok {
+			t.Fatal("expected disabled limiter to allow all calls")
+		}
+	}
+}
+
+func TestRateLimiter_EvictsRefilledBuckets(t *testing.T) {
+	clock := &testClock{}
+	limiter := usecase.NewRateLimiter(60, 2, clock)
+
+	for i := 0; i < 100; i++ {
+		limiter.Allow(fmt.Sprintf("client-%d", i))
+	}
+	limiter.Allow("busy")
+	limiter.Allow("busy")
+	if got := limiter.Tracked(); got != 101 {
+		t.Fatalf("expected 101 buckets, got %d", got)
+	}
+
+	// Idle clients refill and are forgotten; the busy one is still short of its burst
+	clock.currentTime = clock.Now().Add(1500 * time.Millisecond)
+	limiter.Allow("busy")
+	clock.currentTime = clock.Now().Add(time.Second)
+	limiter.Allow("other")
+	if got := limiter.Tracked(); got != 2 {
+		t.Errorf("expected only the busy and new buckets to be kept, got %d", got)
+	}
+}
@@ -0,0 +1,96 @@
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestRESTHandler(t *testing.T) {
+	handler := usecase.NewRESTHandler(usecase.NewMemoryUseCase(setupInitializedProject(t)))
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/entries", `{"section":"decisions","tag":"Storage","content":"Use Postgres for storage","rationale":"Need JSONB","author":"review-bot"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /entries status = %d, body %s", rec.Code, rec.Body)
+	}
+	var created usecase.EntryJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || created.ID == "" {
+		t.Fatalf("POST /entries returned %s (%v)", rec.Body, err)
+	}
+	if created.Author != "agent:review-bot" {
+		t.Errorf("POST /entries author = %q, want agent:review-bot", created.Author)
+	}
+
+	if rec := do(http.MethodPost, "/entries", `{"section":"bogus","content":"x"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid section status = %d, want 400", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/sections/decisions", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Use Postgres") {
+		t.Errorf("GET /sections/decisions = %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodGet, "/sections/bogus", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown section status = %d, want 404", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/memory?format=markdown", ""); !strings.Contains(rec.Body.String(), "## Decisions") {
+		t.Errorf("GET /memory?format=markdown = %s", rec.Body)
+	}
+
+	if rec := do(http.MethodDelete, "/entries/"+created.ID[:8], ""); rec.Code != http.StatusOK {
+		t.Errorf("DELETE status = %d, body %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodDelete, "/entries/"+created.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second DELETE status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRESTHandler_RefusesCrossSiteWrites(t *testing.T) {
+	handler := usecase.NewRESTHandler(usecase.NewMemoryUseCase(setupInitializedProject(t)))
+	body := `{"section":"decisions","tag":"Storage","content":"Use Postgres for storage","rationale":"Need JSONB"}`
+	post := func(contentType, origin string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:8766/entries", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Bodies a form or fetch can send without a preflight
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		if code := post(contentType, ""); code != http.StatusUnsupportedMediaType {
+			t.Errorf("Content-Type %q status = %d, want 415", contentType, code)
+		}
+	}
+	for _, origin := range []string{"https://evil.example.com", "null", "http://localhost:9999"} {
+		if code := post("application/json", origin); code != http.StatusForbidden {
+			t.Errorf("Origin %q status = %d, want 403", origin, code)
+		}
+	}
+	if code := post("application/json; charset=utf-8", "http://localhost:8766"); code != http.StatusCreated {
+		t.Errorf("same-origin POST status = %d, want 201", code)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "http://localhost:8766/entries/0123", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("cross-origin DELETE status = %d, want 403", rec.Code)
+	}
+}
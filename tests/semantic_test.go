@@ -0,0 +1,189 @@
+package main_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/embeddings"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+// topicProvider embeds texts as counts of a few topic words, so texts about the same
+// topic get similar vectors
+type topicProvider struct {
+	model    string
+	embedded int
+}
+
+var topics = [][]string{
+	{"cache", "caching", "redis", "ttl"},
+	{"postgres", "database", "sql"},
+	{"test", "tests", "testing"},
+}
+
+func (p *topicProvider) Model() string {
+	return p.model
+}
+
+func (p *topicProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(topics))
+		for _, word := range strings.Fields(domain.NormalizeContent(text)) {
+			for t, topic := range topics {
+				for _, w := range topic {
+					if word == w {
+						vectors[i][t]++
+					}
+				}
+			}
+		}
+	}
+	p.embedded += len(texts)
+	return vectors, nil
+}
+
+func TestSemanticService(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "decisions", Tag: "Performance", Content: "Keep sessions in Redis with a one hour TTL"},
+		{Category: "decisions", Tag: "DB", Content: "Use Postgres for every service"},
+		{Category: "patterns", Tag: "Testing", Content: "Run tests against a real database"},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	provider := &topicProvider{model: "topics-v1"}
+	store := persistence.NewEmbeddingStore(tmpDir)
+	semantic := domain.NewSemanticService(svc, provider, store)
+
+	stats, err := semantic.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Embedded != 3 || stats.Total != 3 {
+		t.Errorf("expected every entry embedded, got %+v", stats)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".ohmymem", "index", ".gitignore")); err != nil {
+		t.Errorf("expected the index to be ignored by git: %v", err)
+	}
+
+	hits, err := semantic.Search(ctx, "anything about caching?", domain.EntryFilter{Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Entry.TagName != "Performance" {
+		t.Errorf("expected the Redis entry, got %+v", hits)
+	}
+	hits, _ = semantic.Search(ctx, "database", domain.EntryFilter{Now: now, Sections: []domain.SectionType{domain.SectionPatterns}})
+	if len(hits) != 1 || hits[0].Entry.TagName != "Testing" {
+		t.Errorf("expected the filter to keep the Testing entry, got %+v", hits)
+	}
+	if provider.embedded != 5 {
+		t.Errorf("expected searches to embed only their queries, got %d embeddings", provider.embedded)
+	}
+
+	// Only edited and new entries are embedded again; deleted ones leave the index
+	entry, err := svc.FindEntry(ctx, "00000000-0000-7000-8000-000000000002")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.EditEntry(ctx, *entry, domain.AppendInput{Category: "decisions", Tag: "DB", Content: "Use Postgres and SQL migrations"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.DeleteEntries(ctx, []string{"00000000-0000-7000-8000-000000000003"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats, _ = semantic.Refresh(ctx); stats.Embedded != 1 || stats.Removed != 1 || stats.Total != 2 {
+		t.Errorf("expected one entry embedded and one removed, got %+v", stats)
+	}
+
+	// A different model reindexes everything
+	other := domain.NewSemanticService(svc, &topicProvider{model: "topics-v2"}, store)
+	if stats, _ = other.Refresh(ctx); stats.Embedded != 2 {
+		t.Errorf("expected a model change to reindex every entry, got %+v", stats)
+	}
+}
+
+func TestEmbeddingProviders(t *testing.T) {
+	if _, err := embeddings.New("", "", "", ""); !errors.Is(err, embeddings.ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+	if _, err := embeddings.New("word2vec", "", "", ""); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/embed":
+			json.NewEncoder(w).Encode(map[string]any{"embeddings": [][]float32{{1, 0}, {0, 1}}})
+		case "/v1/embeddings":
+			if r.Header.Get("Authorization") != "Bearer k3y" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			// Out of order on purpose: vectors are placed by index
+			json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"index": 1, "embedding": []float32{0, 1}},
+				{"index": 0, "embedding": []float32{1, 0}},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ollama, err := embeddings.New(embeddings.ProviderOllama, "", server.URL, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ollama.Model() != "ollama:"+embeddings.DefaultOllamaModel {
+		t.Errorf("unexpected model %s", ollama.Model())
+	}
+	openai, err := embeddings.New(embeddings.ProviderOpenAI, "text-embedding-3-large", server.URL+"/v1", "k3y")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, provider := range []domain.EmbeddingProvider{ollama, openai} {
+		vectors, err := provider.Embed(context.Background(), []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", provider.Model(), err)
+		}
+		if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+			t.Errorf("%s: unexpected vectors %v", provider.Model(), vectors)
+		}
+	}
+
+	unauthorized, _ := embeddings.New(embeddings.ProviderOpenAI, "", server.URL+"/v1", "wrong")
+	if _, err := unauthorized.Embed(context.Background(), []string{"a"}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the 401 to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestParseStdin(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		category  string
+		rationale string
+		content   string
+		wantErr   bool
+	}{
+		{"no header", "Use Postgres\nfor storage\n", "", "", "Use Postgres\nfor storage\n", false},
+		{"header", "---\ncategory: decisions\nrationale: Need JSONB\n---\nUse Postgres\n", "decisions", "Need JSONB", "Use Postgres\n", false},
+		{"windows line endings", "---\r\ncategory: decisions\r\nrationale: Need JSONB\r\n---\r\nUse Postgres\r\n", "decisions", "Need JSONB", "Use Postgres\n", false},
+		{"leading blank lines", "\r\n\n---\ncategory: patterns\n---\nWrap errors", "patterns", "", "Wrap errors", false},
+		{"dashes inside values", "---\nrationale: a --- b\ncontext: |\n  ---\n---\nUse Postgres", "", "a --- b", "Use Postgres", false},
+		{"empty header", "---\n---\nUse Postgres", "", "", "Use Postgres", false},
+		{"unclosed header", "---\ncategory: decisions\nUse Postgres\n", "", "", "", true},
+		{"closed only by a longer rule", "---\ncategory: decisions\n----\nUse Postgres\n", "", "", "", true},
+		{"invalid header", "---\ncategory: [decisions\n---\nUse Postgres\n", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, content, err := usecase.ParseStdin(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got header %+v and content %q", header, content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if header.Category != tt.category || header.Rationale != tt.rationale {
+				t.Errorf("header = %+v, want category %q and rationale %q", header, tt.category, tt.rationale)
+			}
+			if content != tt.content || strings.Contains(content, "\r") {
+				t.Errorf("content = %q, want %q", content, tt.content)
+			}
+		})
+	}
+}
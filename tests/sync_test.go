@@ -0,0 +1,191 @@
+package main_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/config"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/remote"
+)
+
+// memoryServer is an HTTP sync remote keeping the memory file in memory
+type memoryServer struct {
+	mu      sync.Mutex
+	content string
+	version int
+}
+
+func (s *memoryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag := fmt.Sprintf(`"%d"`, s.version)
+
+	switch r.Method {
+	case http.MethodGet:
+		if s.version == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		io.WriteString(w, s.content)
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); (match != "" && match != etag) || (r.Header.Get("If-None-Match") == "*" && s.version != 0) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.content = string(body)
+		s.version++
+	}
+}
+
+func TestMemoryUseCase_SyncHTTP(t *testing.T) {
+	server := httptest.NewServer(&memoryServer{})
+	defer server.Close()
+	r := remote.NewHTTPRemote(server.URL, "")
+	ctx := context.Background()
+
+	alice := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	bob := usecase.NewMemoryUseCase(setupInitializedProject(t))
+	add := func(uc *usecase.MemoryUseCase, category, content string) {
+		t.Helper()
+		if _, err := uc.Add(ctx, domain.AppendInput{Category: category, Tag: "Sync", Content: content}, usecase.AddOptions{}); err != nil {
+			t.Fatalf("add %q: %v", content, err)
+		}
+	}
+
+	add(alice, "decisions", "Use Postgres for storage")
+	if result, err := alice.Sync(ctx, r, usecase.SyncOptions{}); err != nil || len(result.Pushed.Added) != 1 {
+		t.Fatalf("first sync pushed %+v, err %v", result, err)
+	}
+
+	add(bob, "patterns", "Wrap errors with context")
+	result, err := bob.Sync(ctx, r, usecase.SyncOptions{})
+	if err != nil {
+		t.Fatalf("bob sync: %v", err)
+	}
+	if len(result.Pulled.Added) != 1 || len(result.Pushed.Added) != 1 {
+		t.Errorf("bob pulled %d and pushed %d entries, want 1 and 1", len(result.Pulled.Added), len(result.Pushed.Added))
+	}
+
+	if _, err := alice.Sync(ctx, r, usecase.SyncOptions{}); err != nil {
+		t.Fatalf("alice second sync: %v", err)
+	}
+	content, err := alice.Service().ReadMemory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(content, "Wrap errors with context") {
+		t.Errorf("alice did not receive bob's entry:\n%s", content)
+	}
+
+	// A stale push is rejected instead of overwriting the remote
+	if err := r.Push(ctx, "stale", `"1"`); !errors.Is(err, remote.ErrRemoteChanged) {
+		t.Errorf("stale push error = %v, want ErrRemoteChanged", err)
+	}
+}
+
+func TestConfig_ProjectSyncRemoteToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.EnvSyncToken, "env-token")
+	project := t.TempDir()
+	if err := config.SetValue(config.GetConfigPath(), "sync.remote", "https://memory.example.com/api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetValue(config.ProjectConfigPath(project), "sync.remote", "https://attacker.example.com/memory"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadProject(project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Sync.Token != "" {
+		t.Errorf("expected no token for a remote set by the project, got %q", cfg.Sync.Token)
+	}
+
+	// A git remote name shared with the global config keeps the token
+	for _, path := range []string{config.GetConfigPath(), config.ProjectConfigPath(project)} {
+		if err := config.SetValue(path, "sync.remote", "origin"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cfg, _ = config.LoadProject(project); cfg.Sync.Token != "env-token" {
+		t.Errorf("expected the user's token, got %q", cfg.Sync.Token)
+	}
+}
+
+func TestRemote_RefusesOptionLikeLocations(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "PWNED")
+	location := "--upload-pack=touch " + marker + ";"
+
+	if _, err := remote.New(dir, location, config.DefaultSyncBranch, ""); err == nil {
+		t.Error("expected a remote starting with a dash to be refused")
+	}
+
+	// git itself must read the remote as a repository, never as an option
+	if err := exec.Command("git", "init", "--quiet", dir).Run(); err != nil {
+		t.Skipf("git unavailable: %v", err)
+	}
+	r := remote.NewGitRemote(dir, location, config.DefaultSyncBranch)
+	if _, err := r.Pull(context.Background()); err == nil {
+		t.Error("expected pulling from an option-like remote to fail")
+	}
+	if err := r.Push(context.Background(), "# Memory\n", ""); err == nil {
+		t.Error("expected pushing to an option-like remote to fail")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("expected the remote not to run a command")
+	}
+}
+
+func TestConfig_ProjectGitSyncRemote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+
+	tests := []struct {
+		name            string
+		global, local   string
+		remote          string
+		untrustedRemote string
+	}{
+		{"git remote set by the project alone", "", "--upload-pack=touch /tmp/PWNED;", "", "--upload-pack=touch /tmp/PWNED;"},
+		{"git remote overriding the global one", "origin", "upstream", "origin", "upstream"},
+		{"git remote shared with the global config", "origin", "origin", "origin", ""},
+		{"http remote set by the project", "", "https://memory.example.com/api", "https://memory.example.com/api", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(config.GetConfigPath())
+			if tt.global != "" {
+				if err := config.SetValue(config.GetConfigPath(), "sync.remote", tt.global); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := config.SetValue(config.ProjectConfigPath(project), "sync.remote", tt.local); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := config.LoadProject(project)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Sync.Remote != tt.remote || cfg.UntrustedSyncRemote() != tt.untrustedRemote {
+				t.Errorf("expected remote %q and untrusted %q, got %q and %q", tt.remote, tt.untrustedRemote, cfg.Sync.Remote, cfg.UntrustedSyncRemote())
+			}
+		})
+	}
+}
@@ -0,0 +1,759 @@
+package main_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/detector"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/template"
+)
+
+func TestReplaceTemplateBlocks(t *testing.T) {
+	content := `---
+schema_version: "0.1"
+---
+
+## Constraints
+
+<!-- template-entry, tag: [old], source: common -->
+* **[old]** Stale template rule
+<!-- entry-end -->
+
+<!-- entry-id: 1, tag: [api], time: 2025-01-01T00:00:00Z -->
+* **[api]** Captured by a user
+<!-- entry-end -->
+
+## Patterns
+`
+	blocks := []persistence.TemplateBlock{
+		{Section: domain.SectionConstraints, Text: "<!-- template-entry, tag: [new], source: common -->\n* **[new]** Fresh template rule\n<!-- entry-end -->"},
+		{Section: domain.SectionAntiPatterns, Text: "<!-- template-entry, tag: [init], source: go -->\n* **[init]** Avoid init functions\n<!-- entry-end -->"},
+	}
+
+	updated := persistence.ReplaceTemplateBlocks(content, blocks)
+	if strings.Contains(updated, "Stale template rule") {
+		t.Errorf("old template block kept:\n%s", updated)
+	}
+	if !strings.Contains(updated, "Captured by a user") {
+		t.Errorf("captured entry removed:\n%s", updated)
+	}
+	got := persistence.TemplateBlocks(updated)
+	if len(got) != 2 || got[0] != blocks[0] || got[1] != blocks[1] {
+		t.Errorf("TemplateBlocks = %+v, want %+v", got, blocks)
+	}
+	if again := persistence.ReplaceTemplateBlocks(updated, blocks); again != updated {
+		t.Errorf("replace not idempotent:\n%s\n---\n%s", updated, again)
+	}
+}
+
+func TestTemplateUseCase_List(t *testing.T) {
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewTemplateUseCase(detector.NewCompositeDetector(), []string{templates}, template.FetchOptions{Cache: template.CacheOptions{Disabled: true}})
+
+	infos, err := uc.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var paths []string
+	for _, info := range infos {
+		paths = append(paths, info.Path())
+	}
+	want := "bases/common languages/go frameworks/go-echo contexts/database-postgresql contexts/rest-api"
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("paths = %q, want %q", got, want)
+	}
+
+	info, blocks, err := uc.Preview(context.Background(), "go")
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if info.Kind != "language" || len(blocks) == 0 || blocks[0].Section != domain.SectionConstraints {
+		t.Errorf("Preview = %+v, %d blocks", info, len(blocks))
+	}
+}
+
+func TestCachedTemplateRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	source := t.TempDir()
+	if err := os.WriteFile(filepath.Join(source, "agents.md"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, source, "init", "-q")
+	gitRun(t, source, "add", "-A")
+	gitRun(t, source, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "v1")
+	repoURL := "file://" + filepath.ToSlash(source)
+
+	ctx := context.Background()
+	opts := template.CacheOptions{Dir: t.TempDir(), TTL: time.Hour}
+	git := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout)
+	repo := template.NewCachedTemplateRepository(git, opts)
+
+	path, err := repo.Fetch(ctx, repoURL)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if err := repo.Cleanup(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "agents.md")); err != nil {
+		t.Fatalf("cached checkout removed by Cleanup: %v", err)
+	}
+
+	// Offline: the cached checkout is used, fresh or stale
+	if err := os.RemoveAll(source); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := repo.Fetch(ctx, repoURL); err != nil || again != path {
+		t.Fatalf("Fetch (fresh) = %q, %v; want %q", again, err, path)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := repo.FetchWithFallback(ctx, []string{repoURL}); err != nil || again != path {
+		t.Fatalf("FetchWithFallback (stale) = %q, %v; want %q", again, err, path)
+	}
+
+	// --refresh insists on fetching
+	opts.Refresh = true
+	if _, err := template.NewCachedTemplateRepository(git, opts).Fetch(ctx, repoURL); err == nil {
+		t.Error("Fetch with Refresh succeeded without the source repository")
+	}
+}
+
+func TestGitRepoTemplateRepository_FetchArchive(t *testing.T) {
+	files := map[string]string{
+		"templates-main/agents.md":                "agents",
+		"templates-main/bases/common/memory.md":   "common",
+		"templates-main/languages/go/meta.yaml":   "id: go",
+		"templates-main/languages/go/memory.md":   "go",
+		"templates-main/frameworks/go-echo/.keep": "",
+	}
+	var tgz, zipped bytes.Buffer
+	gz := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gz)
+	zw := zip.NewWriter(&zipped)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	zw.Close()
+
+	var evil bytes.Buffer
+	gz = gzip.NewWriter(&evil)
+	tw = tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../escape.md", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	gz.Close()
+
+	// Entries declaring one byte each but holding far more
+	var lying bytes.Buffer
+	zw = zip.NewWriter(&lying)
+	data := bytes.Repeat([]byte("x"), 64<<10)
+	for _, name := range []string{"templates-main/agents.md", "templates-main/bases/common/memory.md"} {
+		w, err := zw.CreateRaw(&zip.FileHeader{
+			Name:               name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE(data),
+			CompressedSize64:   uint64(len(data)),
+			UncompressedSize64: 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	zw.Close()
+
+	archives := map[string][]byte{
+		"/templates.tar.gz": tgz.Bytes(),
+		"/templates.zip":    zipped.Bytes(),
+		"/evil.tgz":         evil.Bytes(),
+		"/lying.zip":        lying.Bytes(),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	repo := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout)
+	ctx := context.Background()
+	sum := sha256.Sum256(tgz.Bytes())
+
+	for _, repoURL := range []string{
+		server.URL + "/templates.tar.gz#sha256=" + hex.EncodeToString(sum[:]),
+		server.URL + "/templates.zip",
+	} {
+		path, err := repo.Fetch(ctx, repoURL)
+		if err != nil {
+			t.Fatalf("Fetch(%s): %v", repoURL, err)
+		}
+		infos, err := template.List(path)
+		repo.Cleanup(path)
+		if err != nil || len(infos) != 3 {
+			t.Errorf("List(%s) = %+v, %v; want 3 templates", repoURL, infos, err)
+		}
+	}
+
+	if _, err := repo.Fetch(ctx, server.URL+"/templates.tar.gz#sha256="+strings.Repeat("0", 64)); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Fetch with wrong checksum: %v", err)
+	}
+	if _, err := repo.Fetch(ctx, server.URL+"/evil.tgz"); err == nil {
+		t.Error("Fetch extracted an entry outside the checkout")
+	}
+	if _, err := repo.Fetch(ctx, server.URL+"/lying.zip"); err == nil {
+		t.Error("Fetch extracted entries larger than their declared sizes")
+	}
+	if _, err := repo.Fetch(ctx, server.URL+"/missing.zip"); err == nil {
+		t.Error("Fetch of a missing archive succeeded")
+	}
+}
+
+func TestTemplateMeta(t *testing.T) {
+	meta, err := domain.ParseTemplateMeta([]byte(`
+id: security
+category: patterns
+categories:
+  Rules: constraints
+tags: [security]
+applies_to:
+  languages: [go, rust]
+  project_types: [backend]
+`), "contexts/security", "context")
+	if err != nil {
+		t.Fatalf("ParseTemplateMeta: %v", err)
+	}
+	if !meta.Applies(&domain.ProjectInfo{Language: "go", ProjectType: "backend"}) {
+		t.Error("applies_to should match go backend")
+	}
+	if meta.Applies(&domain.ProjectInfo{Language: "go", ProjectType: "cli"}) {
+		t.Error("applies_to should not match go cli")
+	}
+
+	sections := meta.SplitSections("* loose\n\n## Rules\n\n* rule\n\n## Anti-Patterns\n\n* avoid\n")
+	if sections[domain.SectionPatterns] != "* loose" || sections[domain.SectionConstraints] != "* rule" || sections[domain.SectionAntiPatterns] != "* avoid" {
+		t.Errorf("SplitSections = %q", sections)
+	}
+
+	if _, err := domain.ParseTemplateMeta([]byte("category: rules"), "bases/common", "base"); err == nil {
+		t.Error("ParseTemplateMeta accepted an unknown category")
+	}
+
+	// Without applies_to, templates apply by kind and ID
+	echo, _ := domain.ParseTemplateMeta([]byte(`id: go-echo
+parent: go`), "frameworks/go-echo", "framework")
+	if !echo.Applies(&domain.ProjectInfo{Language: "go", Framework: "echo"}) || echo.Applies(&domain.ProjectInfo{Language: "go", Framework: "gin"}) {
+		t.Error("framework template should apply to its framework only")
+	}
+	restAPI, _ := domain.ParseTemplateMeta(nil, "contexts/rest-api", "context")
+	if restAPI.Applies(&domain.ProjectInfo{Language: "go", Framework: "echo"}) {
+		t.Error("contexts other than databases are opt-in")
+	}
+}
+
+func TestTemplateService_InitTemplate(t *testing.T) {
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader())
+
+	content, _, err := service.InitTemplate(context.Background(), &domain.ProjectInfo{Language: "go", Framework: "echo", Database: "postgresql"}, []string{templates})
+	if err != nil {
+		t.Fatalf("InitTemplate: %v", err)
+	}
+	for _, source := range []string{"common", "go", "go-echo", "database-postgresql"} {
+		if !strings.Contains(content, "source: "+source+" -->") {
+			t.Errorf("missing entries of %s", source)
+		}
+	}
+	if strings.Contains(content, "source: rest-api") {
+		t.Error("opt-in context rest-api applied")
+	}
+	if n := strings.Count(content, "## Constraints"); n != 1 {
+		t.Errorf("%d Constraints headers, want 1:\n%s", n, content)
+	}
+	blocks := persistence.TemplateBlocks(content)
+	if len(blocks) == 0 || blocks[0].Section != domain.SectionConstraints {
+		t.Errorf("TemplateBlocks = %+v", blocks)
+	}
+}
+
+func TestGitRepoTemplateRepository_WithRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	source := t.TempDir()
+	agentsPath := filepath.Join(source, "agents.md")
+	commit := func(content string) string {
+		t.Helper()
+		if err := os.WriteFile(agentsPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, source, "add", "-A")
+		gitRun(t, source, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", content)
+		out, err := exec.Command("git", "-C", source, "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	gitRun(t, source, "init", "-q")
+	first := commit("v1")
+	gitRun(t, source, "tag", "v1")
+	commit("v2")
+	repoURL := "file://" + filepath.ToSlash(source)
+
+	for ref, want := range map[string]string{"": "v2", "v1": "v1", first: "v1", first[:10]: "v1"} {
+		repo := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout).WithRef(ref)
+		path, err := repo.Fetch(context.Background(), repoURL)
+		if err != nil {
+			t.Fatalf("Fetch at %q: %v", ref, err)
+		}
+		data, _ := os.ReadFile(filepath.Join(path, "agents.md"))
+		repo.Cleanup(path)
+		if string(data) != want {
+			t.Errorf("Fetch at %q = %q, want %q", ref, data, want)
+		}
+	}
+
+	if _, err := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout).WithRef("missing").Fetch(context.Background(), repoURL); err == nil {
+		t.Error("Fetch of a missing ref succeeded")
+	}
+}
+
+// bareTemplateRepo creates a bare repository whose one commit, tagged v1, holds
+// agents.md, and returns its file:// URL and the commit hash
+func bareTemplateRepo(t *testing.T, initArgs ...string) (string, string) {
+	t.Helper()
+	work, bare := t.TempDir(), filepath.Join(t.TempDir(), "templates.git")
+	gitRun(t, work, append([]string{"init", "-q"}, initArgs...)...)
+	if err := os.WriteFile(filepath.Join(work, "agents.md"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, work, "add", "-A")
+	gitRun(t, work, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "v1")
+	gitRun(t, work, "tag", "v1")
+	gitRun(t, work, "clone", "-q", "--bare", work, bare)
+	out, err := exec.Command("git", "-C", bare, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "file://" + filepath.ToSlash(bare), strings.TrimSpace(string(out))
+}
+
+func TestGitRepoTemplateRepository_GoGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repoURL, commit := bareTemplateRepo(t)
+	// go-git cannot read SHA-256 repositories, the git binary can
+	sha256URL, _ := bareTemplateRepo(t, "--object-format=sha256")
+
+	fetch := func(ref, repoURL string) (string, error) {
+		t.Helper()
+		repo := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout).WithRef(ref)
+		path, err := repo.Fetch(context.Background(), repoURL)
+		if err != nil {
+			return "", err
+		}
+		defer repo.Cleanup(path)
+		if _, err := os.Stat(filepath.Join(path, "HEAD")); err == nil {
+			t.Errorf("Fetch at %q copied the bare repository instead of checking it out", ref)
+		}
+		data, err := os.ReadFile(filepath.Join(path, "agents.md"))
+		return string(data), err
+	}
+
+	// The git binary falls back for what go-git cannot clone
+	if data, err := fetch("", sha256URL); err != nil || data != "v1" {
+		t.Errorf("Fetch with fallback = %q, %v; want v1", data, err)
+	}
+
+	// Without the git binary, only go-git can clone
+	t.Setenv("PATH", "")
+	for _, ref := range []string{"", "v1", commit, commit[:10]} {
+		if data, err := fetch(ref, repoURL); err != nil || data != "v1" {
+			t.Errorf("Fetch at %q = %q, %v; want v1", ref, data, err)
+		}
+	}
+	if _, err := fetch("missing", repoURL); err == nil {
+		t.Error("Fetch of a missing ref succeeded")
+	}
+	if _, err := fetch("", sha256URL); err == nil {
+		t.Error("Fetch of a SHA-256 repository succeeded without the git binary")
+	}
+}
+
+func TestExtractEmbedded(t *testing.T) {
+	path, err := template.ExtractEmbedded()
+	if err != nil {
+		t.Fatalf("ExtractEmbedded: %v", err)
+	}
+	defer os.RemoveAll(path)
+
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader())
+	content, agents, err := service.GenerateFromPath(path, &domain.ProjectInfo{Language: "go"}, nil)
+	if err != nil {
+		t.Fatalf("GenerateFromPath: %v", err)
+	}
+	if !strings.Contains(content, "source: common -->") || !strings.Contains(content, "source: go -->") {
+		t.Errorf("built-in templates missing common or go entries:\n%s", content)
+	}
+	if !strings.Contains(agents, "Boot Protocol") {
+		t.Errorf("agents content = %q", agents)
+	}
+	if !template.IsDefaultRepo(template.DefaultGiteeRepo) || template.IsDefaultRepo("https://example.com/t.git") {
+		t.Error("IsDefaultRepo misclassifies repositories")
+	}
+}
+
+func TestTemplateService_TemplateChoices(t *testing.T) {
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader())
+	info := &domain.ProjectInfo{Language: "go", Framework: "echo"}
+
+	choices, err := service.TemplateChoices(context.Background(), info, []string{templates})
+	if err != nil {
+		t.Fatalf("TemplateChoices: %v", err)
+	}
+	var got []string
+	for _, choice := range choices {
+		got = append(got, fmt.Sprintf("%s:%t", choice.ID, choice.Selected))
+	}
+	want := "go:true go-echo:true database-postgresql:false rest-api:false"
+	if strings.Join(got, " ") != want {
+		t.Errorf("choices = %v, want %s", got, want)
+	}
+
+	// Only the bases and the selected templates are composed
+	content, _, err := service.InitSelectedTemplates(context.Background(), info, []string{templates}, []string{"go", "rest-api"})
+	if err != nil {
+		t.Fatalf("InitSelectedTemplates: %v", err)
+	}
+	for source, want := range map[string]bool{"common": true, "go": true, "rest-api": true, "go-echo": false} {
+		if got := strings.Contains(content, "source: "+source+" -->"); got != want {
+			t.Errorf("entries of %s composed = %t, want %t", source, got, want)
+		}
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	repoPath := t.TempDir()
+	files := map[string]string{"agents.md": "agents", "bases/common/memory.md": "common"}
+	var manifest strings.Builder
+	for _, name := range []string{"agents.md", "bases/common/memory.md"} {
+		path := filepath.Join(repoPath, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256([]byte(files[name]))
+		fmt.Fprintf(&manifest, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, template.ManifestFileName), []byte(manifest.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(manifest.String())))
+	if err := os.WriteFile(filepath.Join(repoPath, template.SignatureFileName), []byte(signature), 0644); err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString(publicKey)
+
+	if problems, err := template.VerifyChecksums(repoPath, key); err != nil || len(problems) != 0 {
+		t.Fatalf("VerifyChecksums = %v, %v; want no problems", problems, err)
+	}
+
+	// Tampered and injected files are reported, as is a signature from another key
+	os.WriteFile(filepath.Join(repoPath, "agents.md"), []byte("tampered"), 0644)
+	os.WriteFile(filepath.Join(repoPath, "bases", "common", "meta.yaml"), []byte("id: common"), 0644)
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	problems, err := template.VerifyChecksums(repoPath, base64.StdEncoding.EncodeToString(otherKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(problems, "; "); !strings.Contains(got, "SHA256SUMS.sig does not verify") ||
+		!strings.Contains(got, "agents.md does not match") || !strings.Contains(got, "meta.yaml is not listed") {
+		t.Errorf("problems = %v", problems)
+	}
+
+	ctx := context.Background()
+	git := template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout)
+	if _, err := template.NewVerifiedTemplateRepository(git, template.VerifyOptions{}).Fetch(ctx, repoPath); err == nil {
+		t.Error("strict verification accepted tampered templates")
+	}
+	path, err := template.NewVerifiedTemplateRepository(git, template.VerifyOptions{Mode: template.VerifyWarn}).Fetch(ctx, repoPath)
+	if err != nil {
+		t.Errorf("warn verification refused tampered templates: %v", err)
+	}
+	git.Cleanup(path)
+}
+
+func TestOrderMirrors(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer fast.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	local := t.TempDir()
+	repoURLs := []string{down.URL + "/templates.git", slow.URL + "/templates.git", local, fast.URL + "/templates.git"}
+	got := template.OrderMirrors(context.Background(), repoURLs, 2*time.Second)
+	want := []string{local, fast.URL + "/templates.git", slow.URL + "/templates.git", down.URL + "/templates.git"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("OrderMirrors = %v, want %v", got, want)
+	}
+}
+
+func TestTemplateService_Layers(t *testing.T) {
+	templates, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := t.TempDir()
+	for name, content := range map[string]string{
+		"languages/go/memory.md":      "- Team Go rule",
+		"contexts/team/meta.yaml":     "applies_to:\n  languages: [go]\n",
+		"contexts/team/memory.md":     "- Team review rule",
+		"agents.md":                   "team agents",
+		"frameworks/.ignored-file":    "",
+		"contexts/empty/.placeholder": "",
+	} {
+		path := filepath.Join(layer, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader()).WithLayers([]string{layer})
+	content, agents, err := service.InitTemplate(context.Background(), &domain.ProjectInfo{Language: "go"}, []string{templates})
+	if err != nil {
+		t.Fatalf("InitTemplate: %v", err)
+	}
+	if !strings.Contains(content, "Team Go rule") || strings.Contains(content, "source: go -->") {
+		t.Errorf("layer did not replace the go template:\n%s", content)
+	}
+	if !strings.Contains(content, "Team review rule") || !strings.Contains(content, "source: common -->") {
+		t.Errorf("layer templates not merged with the repository:\n%s", content)
+	}
+	if agents != "team agents" {
+		t.Errorf("agents = %q, want the layer agents.md", agents)
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+	files, err := template.Scaffold(dir, "rust")
+	if err != nil {
+		t.Fatalf("Scaffold: %v", err)
+	}
+	if len(files) != 5 {
+		t.Errorf("Scaffold wrote %v", files)
+	}
+	infos, err := template.List(dir)
+	if err != nil || len(infos) != 2 || infos[1].ID != "rust" || infos[1].Kind != "language" {
+		t.Errorf("List = %+v, %v", infos, err)
+	}
+
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader())
+	content, _, err := service.InitTemplate(context.Background(), &domain.ProjectInfo{Language: "rust"}, []string{dir})
+	if err != nil || len(persistence.TemplateBlocks(content)) != 2 {
+		t.Errorf("InitTemplate = %q, %v; want the common and rust entries", content, err)
+	}
+
+	if _, err := template.Scaffold(dir, "rust"); err == nil {
+		t.Error("Scaffold overwrote a non-empty directory")
+	}
+	if _, err := template.Scaffold(t.TempDir(), "../go"); err == nil {
+		t.Error("Scaffold accepted a language escaping languages/")
+	}
+}
+
+func TestMemoryUseCase_UpdateTemplateBlocks(t *testing.T) {
+	root := setupInitializedProject(t)
+	old := "<!-- template-entry, tag: [go], source: go -->\n* **[go]** Old rule (*理由: Template default*)\n<!-- entry-end -->"
+	kept := "<!-- template-entry, tag: [go], source: go -->\n* **[go]** Kept rule (*理由: Template default*)\n<!-- entry-end -->"
+	added := "<!-- template-entry, tag: [go], source: go -->\n* **[go]** New rule (*理由: Template default*)\n<!-- entry-end -->"
+	content := "## Constraints\n\n" + old + "\n\n" + kept + "\n\n## Decisions\n\n## Patterns\n\n## Anti-Patterns\n"
+	if err := os.WriteFile(filepath.Join(root, ".ohmymem", "memory.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	memory := usecase.NewMemoryUseCase(root)
+	ctx := context.Background()
+	if _, err := memory.Add(ctx, domain.AppendInput{Category: "patterns", Tag: "Captured", Content: "Captured by a user"}, usecase.AddOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := []persistence.TemplateBlock{
+		{Section: domain.SectionConstraints, Text: kept},
+		{Section: domain.SectionConstraints, Text: added},
+	}
+	update, err := memory.UpdateTemplateBlocks(ctx, blocks, false)
+	if err != nil {
+		t.Fatalf("UpdateTemplateBlocks: %v", err)
+	}
+	if update.Added != 1 || update.Removed != 1 || update.Kept != 1 ||
+		len(update.AddedBlocks) != 1 || update.AddedBlocks[0].Text != added ||
+		len(update.RemovedBlocks) != 1 || update.RemovedBlocks[0].Text != old {
+		t.Errorf("update = %+v", update)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, ".ohmymem", "memory.md"))
+	if got := string(data); strings.Contains(got, "Old rule") || !strings.Contains(got, "New rule") || !strings.Contains(got, "Captured by a user") {
+		t.Errorf("memory after update:\n%s", got)
+	}
+}
+
+func TestTemplateService_PerCategoryFiles(t *testing.T) {
+	repo := t.TempDir()
+	for name, content := range map[string]string{
+		"bases/common/memory.md":            "- Common rule",
+		"languages/go/patterns.md":          "- Go pattern",
+		"languages/go/anti-patterns.md":     "- Go anti-pattern",
+		"languages/go/decisions.md":         "- Go decision",
+		"contexts/database-mysql/note.md":   "- Not applied",
+		"frameworks/go-echo/constraints.md": "- Not applied",
+	} {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader())
+	content, _, err := service.GenerateFromPath(repo, &domain.ProjectInfo{Language: "go"}, nil)
+	if err != nil {
+		t.Fatalf("GenerateFromPath: %v", err)
+	}
+	want := "## Constraints\n\n- Common rule\n\n## Decisions\n\n- Go decision\n\n## Patterns\n\n- Go pattern\n\n## Anti-Patterns\n\n- Go anti-pattern\n"
+	if !strings.HasSuffix(content, want) {
+		t.Errorf("content = %q, want suffix %q", content, want)
+	}
+}
+
+func TestTemplateHooks(t *testing.T) {
+	for _, bad := range []string{
+		"post_init:\n  - create: ../outside\n    from: f\n",
+		"post_init:\n  - create: /etc/x\n    from: f\n",
+		"post_init:\n  - create: .editorconfig\n",
+		"post_init:\n  - create: a\n    from: f\n    run: [true]\n",
+		"post_init:\n  - name: nothing\n",
+	} {
+		if _, err := domain.ParseTemplateMeta([]byte(bad), "bases/common", "base"); err == nil {
+			t.Errorf("ParseTemplateMeta accepted %q", bad)
+		}
+	}
+
+	repo := t.TempDir()
+	for name, content := range map[string]string{
+		"bases/common/memory.md":    "- Common rule",
+		"bases/common/editorconfig": "root = true\n",
+		"bases/common/meta.yaml":    "post_init:\n  - create: .editorconfig\n    from: editorconfig\n",
+		"contexts/other/meta.yaml":  "post_init:\n  - run: [\"false\"]\n",
+	} {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service := domain.NewTemplateService(template.NewGitRepoTemplateRepository(template.DefaultFetchTimeout), domain.NewLocalTemplateLoader())
+	generated, err := service.Generate(repo, &domain.ProjectInfo{Language: "go"}, nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(generated.Hooks) != 1 || generated.Hooks[0].Template != "common" || generated.Hooks[0].Content != "root = true\n" {
+		t.Fatalf("Hooks = %+v, want the common hook only", generated.Hooks)
+	}
+
+	project := t.TempDir()
+	ctx := context.Background()
+	if done, err := usecase.RunHook(ctx, project, generated.Hooks[0]); err != nil || done != "created .editorconfig" {
+		t.Errorf("RunHook = %q, %v", done, err)
+	}
+	os.WriteFile(filepath.Join(project, ".editorconfig"), []byte("mine"), 0644)
+	if _, err := usecase.RunHook(ctx, project, generated.Hooks[0]); err != nil {
+		t.Errorf("RunHook on an existing file: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(project, ".editorconfig")); string(data) != "mine" {
+		t.Error("RunHook overwrote an existing file")
+	}
+
+	// Files are never written through a symlinked directory leading out of the project
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(project, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	for _, create := range []string{"link/.editorconfig", "link/sub/.editorconfig"} {
+		hook := domain.TemplateHook{Create: create, Content: "root = true\n"}
+		if _, err := usecase.RunHook(ctx, project, hook); err == nil {
+			t.Errorf("RunHook created %s through a symlink", create)
+		}
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Errorf("RunHook wrote outside the project: %v", entries)
+	}
+
+	// Symlinks staying inside the project are fine
+	os.Mkdir(filepath.Join(project, "config"), 0755)
+	if err := os.Symlink("config", filepath.Join(project, "inner")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := usecase.RunHook(ctx, project, domain.TemplateHook{Create: "inner/app.yaml", Content: "x"}); err != nil {
+		t.Errorf("RunHook through an inner symlink: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "config", "app.yaml")); err != nil {
+		t.Errorf("expected the file written through the inner symlink: %v", err)
+	}
+}
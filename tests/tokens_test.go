@@ -0,0 +1,115 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herewei/ohmymem-core/internal/domain"
+	"github.com/herewei/ohmymem-core/internal/infrastructure/persistence"
+)
+
+func TestTokenEstimator(t *testing.T) {
+	estimator := domain.NewTokenEstimator()
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"Use Postgres", 3}, // "Use", " Post", "gres"
+		{"Don't", 2},
+		{"2024", 2},
+		{"数据库", 3},
+		{"**[DB]**", 3},
+		{"internationalization", 4},
+	}
+	for _, tt := range tests {
+		if got := estimator.Count(tt.text); got != tt.expected {
+			t.Errorf("Count(%q) = %d, expected %d", tt.text, got, tt.expected)
+		}
+	}
+
+	// English prose lands near the usual four characters per token
+	prose := strings.Repeat("Handlers return problem+json errors and never panic on bad input. ", 10)
+	if got, chars := estimator.Count(prose), len(prose); got < chars/6 || got > chars/3 {
+		t.Errorf("expected about %d tokens for %d characters, got %d", chars/4, chars, got)
+	}
+}
+
+func TestMemoryService_TokenBudget(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	repo := persistence.NewMemoryRepository(tmpDir, &testUUID{}, &testClock{})
+	svc := domain.NewMemoryService(repo)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	inputs := []domain.AppendInput{
+		{Category: "constraints", Tag: "API", Content: "Handlers return problem+json errors"},
+		{Category: "patterns", Tag: "Docs", Content: strings.Repeat("Describe every exported identifier. ", 20)},
+	}
+	for i, input := range inputs {
+		id := "00000000-0000-7000-8000-00000000000" + string(rune('1'+i))
+		if err := svc.AppendMemory(ctx, input, id, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	usage, err := svc.TokenUsage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	perSection := map[domain.SectionType]int{}
+	sum := 0
+	for _, section := range usage.Sections {
+		perSection[section.Section] = section.Tokens
+		sum += section.Tokens
+	}
+	// Sections are measured on the file itself: with the frontmatter they make the total
+	content, err := repo.ReadAll(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frontmatter := content[:strings.Index(content, "## ")]; sum+svc.Tokens().Count(frontmatter) != usage.Total {
+		t.Errorf("expected sections (%d) and frontmatter to add up to the total %d", sum, usage.Total)
+	}
+	if perSection[domain.SectionPatterns] <= perSection[domain.SectionConstraints] || usage.Total < perSection[domain.SectionPatterns] {
+		t.Errorf("unexpected token usage %+v", usage)
+	}
+	if usage.OverBudget() {
+		t.Error("expected no warning without a budget")
+	}
+
+	svc.SetTokenBudget(usage.Total - 1)
+	if usage, _ = svc.TokenUsage(ctx); !usage.OverBudget() {
+		t.Errorf("expected the memory over a budget of %d tokens", usage.Budget)
+	}
+
+	// Token budgets trim reads and digests like character budgets, notice included
+	maxTokens := perSection[domain.SectionConstraints] + 40
+	trimmed, err := svc.ReadMemoryWithOptions(ctx, domain.ReadOptions{MaxTokens: maxTokens})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(trimmed, "problem+json") || strings.Contains(trimmed, "exported identifier") {
+		t.Errorf("expected only the constraint to fit, got:\n%s", trimmed)
+	}
+	if !strings.Contains(trimmed, fmt.Sprintf("to fit a %d token budget", maxTokens)) {
+		t.Errorf("expected a token truncation notice, got:\n%s", trimmed)
+	}
+	if got := svc.Tokens().Count(trimmed); got > maxTokens {
+		t.Errorf("expected at most %d tokens, got %d", maxTokens, got)
+	}
+
+	summary, err := svc.Summarize(ctx, domain.SummaryOptions{MaxTokens: 40, Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.Tokens().Count(summary) > 40 || !strings.Contains(summary, "1 more entries omitted to fit 40 tokens") {
+		t.Errorf("expected capped summary with omission notice, got (%d tokens):\n%s", svc.Tokens().Count(summary), summary)
+	}
+}
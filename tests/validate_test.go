@@ -0,0 +1,88 @@
+package main_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/herewei/ohmymem-core/internal/application/usecase"
+)
+
+func TestValidateMemory(t *testing.T) {
+	valid := `---
+schema_version: "0.1"
+entry_format: "anchored"
+---
+
+## Constraints
+
+<!-- template-entry, tag: [git], source: common -->
+* **[git]** Write clear commit messages (*理由: Template default*)
+<!-- entry-end -->
+
+## Decisions
+
+<!-- entry-id: 1, tag: [db], time: 2025-01-01T00:00:00Z -->
+* **[db]** Use PostgreSQL for persistence
+<!-- entry-end -->
+
+## Patterns
+
+## Anti-Patterns
+`
+	report := usecase.ValidateMemory(valid)
+	if !report.Valid(true) {
+		t.Fatalf("valid memory reported %+v", report)
+	}
+
+	invalid := strings.Replace(valid, "## Patterns", "## Misc\n\n<!-- entry-id: 1, tag: [db], time: 2025-01-01T00:00:00Z -->\n* **[db]** Again\n<!-- entry-end -->\n\n## Patterns", 1)
+	invalid = strings.Replace(invalid, "## Decisions\n", "## Decisions\n\n<!-- entry-id: 1, tag: [db], time: 2025-01-01T00:00:00Z -->\n* **[db]** Duplicate\n<!-- entry-end -->\n", 1)
+	invalid = strings.Replace(invalid, "entry_format: \"anchored\"\n", "", 1)
+
+	report = usecase.ValidateMemory(invalid)
+	errors := strings.Join(report.Errors, "\n")
+	for _, want := range []string{"unknown section ## Misc", "duplicate entry ID 1"} {
+		if !strings.Contains(errors, want) {
+			t.Errorf("errors missing %q:\n%s", want, errors)
+		}
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "entry_format") {
+		t.Errorf("warnings = %v, want missing entry_format", report.Warnings)
+	}
+	if report.Valid(false) {
+		t.Error("invalid memory reported valid")
+	}
+}
+
+func TestMemoryUseCase_Check(t *testing.T) {
+	projectDir := setupInitializedProject(t)
+	uc := usecase.NewMemoryUseCase(projectDir)
+	ctx := context.Background()
+
+	memory := "---\nschema_version: \"0.1\"\nentry_format: \"anchored\"\n---\n\n## Constraints\n\n* **[go]** Legacy bullet\n<<<<<<< HEAD\n=======\n>>>>>>> main\n\n## Decisions\n\n## Patterns\n\n## Anti-Patterns\n"
+	if err := os.WriteFile(filepath.Join(projectDir, ".ohmymem", "memory.md"), []byte(memory), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := uc.Check(ctx, usecase.CheckOptions{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if report.Passed() || len(report.ConflictMarkers) != 3 || report.LegacyEntries != 1 || report.Agents.Present {
+		t.Errorf("Check = %+v", report)
+	}
+
+	clean := strings.Replace(memory, "* **[go]** Legacy bullet\n<<<<<<< HEAD\n=======\n>>>>>>> main\n", "", 1)
+	if err := os.WriteFile(filepath.Join(projectDir, ".ohmymem", "memory.md"), []byte(clean), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err = uc.Check(ctx, usecase.CheckOptions{SkipAgents: true})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !report.Passed() {
+		t.Errorf("clean memory failed: %v", report.Problems)
+	}
+}